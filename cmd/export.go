@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/exporter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NewExportCommand creates a cobra command for export.
+func NewExportCommand(ctx context.Context) *cobra.Command {
+	opts := newExportOptions()
+	var command = &cobra.Command{
+		Use:   "export",
+		Short: "exports the keys of a backup in the snapshot store",
+		Long: `Restores a full snapshot and its delta snapshots, optionally up to a target revision, into a scratch
+directory, and writes the restored keys with their values, create and mod revisions and lease IDs as
+newline delimited JSON, or only the keys, to a file or stdout. The keys can be filtered by prefix.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			printVersionInfo()
+			logger := logrus.NewEntry(logger)
+			runtimelog.SetLogger(logr.New(runtimelog.NullLogSink{}))
+			if err := opts.validate(); err != nil {
+				logger.Fatalf("failed to validate the options: %v", err)
+			}
+			opts.complete()
+
+			store, err := snapstore.GetSnapstore(opts.snapstoreConfig)
+			if err != nil {
+				logger.Fatalf("failed to create snapstore from configured storage provider: %v", err)
+			}
+
+			var (
+				w      io.Writer = os.Stdout
+				output           = opts.exporterConfig.Output
+				f      *os.File
+			)
+			if len(output) != 0 && output != "-" {
+				if f, err = os.Create(output); err != nil { // #nosec G304 -- the output file is chosen by the user.
+					logger.Fatalf("failed to create output file %s: %v", output, err)
+				}
+				w = f
+			}
+
+			x := exporter.NewExporter(store, opts.exporterConfig, brtypes.NewRestorationConfig(), logger)
+			result, err := x.Export(ctx, w)
+			if err != nil {
+				logger.Fatalf("failed to export the backup: %v", err)
+			}
+			if f != nil {
+				if err := f.Close(); err != nil {
+					logger.Fatalf("failed to close output file %s: %v", output, err)
+				}
+			}
+			logger.Infof("Exported %d keys at revision %d of full snapshot %s and %d delta snapshots.", result.KeyCount, result.Revision, result.BaseSnapshot, result.DeltaSnapshots)
+		},
+	}
+	opts.addFlags(command.Flags())
+	return command
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NewGarbageCollectCommand creates a cobra command for gc.
+func NewGarbageCollectCommand(_ context.Context) *cobra.Command {
+	opts := newGarbageCollectorOptions()
+	var command = &cobra.Command{
+		Use:   "gc",
+		Short: "reports which backups in the snapshot store would be garbage collected",
+		Long: `Evaluates the garbage collection policy against the snapshots in the snapshot store, and prints a JSON report
+of which snapshots would be deleted, kept or skipped and why. Nothing is deleted, so --dry-run must be set.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			printVersionInfo()
+			logger := logrus.NewEntry(logger)
+			runtimelog.SetLogger(logr.New(runtimelog.NullLogSink{}))
+			if err := opts.validate(); err != nil {
+				logger.Fatalf("failed to validate the options: %v", err)
+			}
+			opts.complete()
+
+			store, err := snapstore.GetSnapstore(opts.snapstoreConfig)
+			if err != nil {
+				logger.Fatalf("failed to create snapstore from configured storage provider: %v", err)
+			}
+
+			ssr, err := snapshotter.NewSnapshotter(logger, opts.snapshotterConfig, store, brtypes.NewEtcdConnectionConfig(), compressor.NewCompressorConfig(), brtypes.NewHealthConfig(), opts.snapstoreConfig)
+			if err != nil {
+				logger.Fatalf("failed to create snapshotter: %v", err)
+			}
+
+			report, err := ssr.GarbageCollectionDryRun()
+			if err != nil {
+				logger.Fatalf("failed to evaluate the garbage collection policy: %v", err)
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				logger.Fatalf("failed to write the report: %v", err)
+			}
+		},
+	}
+	opts.addFlags(command.Flags())
+	return command
+}
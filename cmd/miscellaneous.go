@@ -47,8 +47,12 @@ func BuildRestoreOptionsAndStore(opts *restorerOptions) (*brtypes.RestoreOptions
 		logger.Fatalf("failed to create restore snapstore from configured storage provider: %v", err)
 	}
 
-	logger.Info("Finding latest set of snapshot to recover from...")
-	baseSnap, deltaSnapList, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+	if opts.restorationConfig.IsPointInTimeRestore() {
+		logger.Infof("Finding set of snapshot to recover up to target revision %d, target time %q and target snapshot labels %q...", opts.restorationConfig.TargetRevision, opts.restorationConfig.TargetTime, opts.restorationConfig.TargetSnapshotLabels)
+	} else {
+		logger.Info("Finding latest set of snapshot to recover from...")
+	}
+	baseSnap, deltaSnapList, err := miscellaneous.GetFullSnapshotAndDeltaSnapListForRestore(store, opts.restorationConfig)
 	if err != nil {
 		logger.Fatalf("failed to get latest snapshot: %v", err)
 	}
@@ -5,12 +5,19 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gardener/etcd-backup-restore/pkg/compressor"
 	"github.com/gardener/etcd-backup-restore/pkg/initializer/validator"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
 	"github.com/gardener/etcd-backup-restore/pkg/server"
 	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
 	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
@@ -24,6 +31,16 @@ import (
 	"sigs.k8s.io/yaml"
 )
 
+const (
+	// configEnvPrefix is the prefix of the environment variables which override the configuration of the server, e.g.
+	// ETCDBR_STORAGE_PROVIDER overrides the storage provider.
+	configEnvPrefix = "ETCDBR_"
+	// configFileWatchInterval is the interval in which the config file is checked for changes to reload the configuration.
+	configFileWatchInterval = 10 * time.Second
+	// defaultServerLogLevel is the default verbosity level of the logs of the server.
+	defaultServerLogLevel = 4
+)
+
 type serverOptions struct {
 	Logger     *logrus.Logger
 	Config     *server.BackupRestoreComponentConfig
@@ -38,7 +55,7 @@ func newServerOptions() *serverOptions {
 	runtimelog.SetLogger(logr.New(runtimelog.NullLogSink{}))
 
 	return &serverOptions{
-		LogLevel: 4,
+		LogLevel: defaultServerLogLevel,
 		Version:  false,
 		Config:   server.NewBackupRestoreComponentConfig(),
 		Logger:   logger,
@@ -60,30 +77,105 @@ func (o *serverOptions) complete() {
 	o.Logger.SetLevel(logrus.Level(o.LogLevel))
 }
 
-func (o *serverOptions) loadConfigFromFile() error {
+// loadConfig layers the configuration of the server: the defaults are overridden by the config file, which is
+// overridden by the environment variables, which are overridden by the flags explicitly set on the command line.
+func (o *serverOptions) loadConfig(cmdFlags *flag.FlagSet) error {
+	// bind the flags to the config before loading the file, so that the flags set their defaults and the environment
+	// variables and the flags set on the command line override the file
+	config := server.NewBackupRestoreComponentConfig()
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.Uint32Var(&o.LogLevel, "log-level", o.LogLevel, "verbosity level of logs")
+	config.AddFlags(fs)
+
 	if len(o.ConfigFile) != 0 {
 		data, err := os.ReadFile(o.ConfigFile)
 		if err != nil {
 			return err
 		}
-		config := server.NewBackupRestoreComponentConfig()
+		// the sub-configs are decoded into the structs the flags are bound to
 		if err := yaml.Unmarshal(data, config); err != nil {
 			return err
 		}
-		o.Config = config
 	}
-	// TODO: Overwrite config with flags
+	if err := miscellaneous.OverrideFlags(fs, cmdFlags, configEnvPrefix); err != nil {
+		return err
+	}
+	o.Config = config
 	return nil
 }
 
-func (o *serverOptions) run(ctx context.Context) error {
+func (o *serverOptions) run(ctx context.Context, cmdFlags *flag.FlagSet) error {
 	brServer, err := server.NewBackupRestoreServer(o.Logger, o.Config)
 	if err != nil {
 		return err
 	}
+	go o.reloadConfigOnChange(ctx, brServer, cmdFlags)
 	return brServer.Run(ctx)
 }
 
+// reloadConfigOnChange reloads the configuration of the server on SIGHUP, and whenever the content of the config file
+// changes.
+func (o *serverOptions) reloadConfigOnChange(ctx context.Context, brServer *server.BackupRestoreServer, cmdFlags *flag.FlagSet) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	ticker := time.NewTicker(configFileWatchInterval)
+	defer ticker.Stop()
+
+	var configFileData []byte
+	if len(o.ConfigFile) != 0 {
+		// a failure to read the file is retried by the watch
+		configFileData, _ = os.ReadFile(o.ConfigFile)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hupCh:
+			o.Logger.Info("Received SIGHUP, reloading the config...")
+		case <-ticker.C:
+			if len(o.ConfigFile) == 0 {
+				continue
+			}
+			data, err := os.ReadFile(o.ConfigFile)
+			if err != nil {
+				o.Logger.Warnf("Failed to read the config file to watch it for changes: %v", err)
+				continue
+			}
+			if bytes.Equal(data, configFileData) {
+				continue
+			}
+			configFileData = data
+			o.Logger.Info("Config file has changed, reloading the config...")
+		}
+		if err := o.reloadConfig(brServer, cmdFlags); err != nil {
+			o.Logger.Errorf("Failed to reload the config, keeping the current config: %v", err)
+		}
+	}
+}
+
+// reloadConfig loads the configuration again and applies the parts of it which can be changed at runtime, including
+// the log level, to the server.
+func (o *serverOptions) reloadConfig(brServer *server.BackupRestoreServer, cmdFlags *flag.FlagSet) error {
+	reloaded := &serverOptions{
+		Logger:     o.Logger,
+		ConfigFile: o.ConfigFile,
+		LogLevel:   defaultServerLogLevel,
+	}
+	if err := reloaded.loadConfig(cmdFlags); err != nil {
+		return err
+	}
+	if err := reloaded.validate(); err != nil {
+		return err
+	}
+	reloaded.Config.Complete()
+	if err := brServer.ReloadConfig(reloaded.Config); err != nil {
+		return err
+	}
+	o.Logger.SetLevel(logrus.Level(reloaded.LogLevel))
+	return nil
+}
+
 type initializerOptions struct {
 	validatorOptions     *validatorOptions
 	restorerOptions      *restorerOptions
@@ -116,7 +208,11 @@ func (c *initializerOptions) validate() error {
 		return err
 	}
 
-	return c.restorerOptions.validate()
+	if err := c.restorerOptions.validate(); err != nil {
+		return err
+	}
+
+	return c.restorerOptions.restorationConfig.ValidateInitialization()
 }
 
 // Complete completes the config.
@@ -149,6 +245,12 @@ func (c *compactOptions) addFlags(fs *flag.FlagSet) {
 
 // Validate validates the config.
 func (c *compactOptions) validate() error {
+	if c.restorationConfig.IsPointInTimeRestore() {
+		return errors.New("parameters target-revision, target-time and target-snapshot-labels are not supported for compaction")
+	}
+	if !c.restorationConfig.KeyFilter().IsEmpty() {
+		return errors.New("parameters restore-include-key-prefixes and restore-exclude-key-prefixes are not supported for compaction")
+	}
 	return c.compactorConfig.Validate()
 }
 
@@ -185,6 +287,199 @@ func (c *restorerOptions) complete() {
 	c.snapstoreConfig.Complete()
 }
 
+type verifyOptions struct {
+	snapstoreConfig *brtypes.SnapstoreConfig
+	verifierConfig  *brtypes.VerifierConfig
+}
+
+// newVerifyOptions returns the verify options.
+func newVerifyOptions() *verifyOptions {
+	return &verifyOptions{
+		snapstoreConfig: snapstore.NewSnapstoreConfig(),
+		verifierConfig:  brtypes.NewVerifierConfig(),
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *verifyOptions) addFlags(fs *flag.FlagSet) {
+	c.snapstoreConfig.AddFlags(fs)
+	c.verifierConfig.AddFlags(fs)
+}
+
+// Validate validates the config.
+func (c *verifyOptions) validate() error {
+	if err := c.snapstoreConfig.Validate(); err != nil {
+		return err
+	}
+
+	return c.verifierConfig.Validate()
+}
+
+// complete completes the config.
+func (c *verifyOptions) complete() {
+	c.snapstoreConfig.Complete()
+}
+
+type exportOptions struct {
+	snapstoreConfig *brtypes.SnapstoreConfig
+	exporterConfig  *brtypes.ExporterConfig
+}
+
+// newExportOptions returns the export options.
+func newExportOptions() *exportOptions {
+	return &exportOptions{
+		snapstoreConfig: snapstore.NewSnapstoreConfig(),
+		exporterConfig:  brtypes.NewExporterConfig(),
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *exportOptions) addFlags(fs *flag.FlagSet) {
+	c.snapstoreConfig.AddFlags(fs)
+	c.exporterConfig.AddFlags(fs)
+}
+
+// Validate validates the config.
+func (c *exportOptions) validate() error {
+	if err := c.snapstoreConfig.Validate(); err != nil {
+		return err
+	}
+
+	return c.exporterConfig.Validate()
+}
+
+// complete completes the config.
+func (c *exportOptions) complete() {
+	c.snapstoreConfig.Complete()
+}
+
+type garbageCollectorOptions struct {
+	snapstoreConfig   *brtypes.SnapstoreConfig
+	snapshotterConfig *brtypes.SnapshotterConfig
+	dryRun            bool
+}
+
+// newGarbageCollectorOptions returns the garbage collector options.
+func newGarbageCollectorOptions() *garbageCollectorOptions {
+	return &garbageCollectorOptions{
+		snapstoreConfig:   snapstore.NewSnapstoreConfig(),
+		snapshotterConfig: snapshotter.NewSnapshotterConfig(),
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *garbageCollectorOptions) addFlags(fs *flag.FlagSet) {
+	c.snapstoreConfig.AddFlags(fs)
+	c.snapshotterConfig.AddFlags(fs)
+	fs.BoolVar(&c.dryRun, "dry-run", c.dryRun, "report which snapshots would be deleted, kept or skipped by the garbage collection policy without deleting any of them. Required, as snapshots are only garbage collected by the backup-restore server")
+}
+
+// Validate validates the config.
+func (c *garbageCollectorOptions) validate() error {
+	if !c.dryRun {
+		// the garbage collection is left to the backup-restore server, which knows about the snapshots in upload.
+		return fmt.Errorf("--dry-run is required, snapshots are only garbage collected by the backup-restore server")
+	}
+	if err := c.snapstoreConfig.Validate(); err != nil {
+		return err
+	}
+
+	return c.snapshotterConfig.Validate()
+}
+
+// complete completes the config.
+func (c *garbageCollectorOptions) complete() {
+	c.snapstoreConfig.Complete()
+}
+
+type pinOptions struct {
+	snapstoreConfig *brtypes.SnapstoreConfig
+	until           string
+	expiry          time.Time
+}
+
+// newPinOptions returns the pin options.
+func newPinOptions() *pinOptions {
+	return &pinOptions{
+		snapstoreConfig: snapstore.NewSnapstoreConfig(),
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *pinOptions) addFlags(fs *flag.FlagSet, withExpiry bool) {
+	c.snapstoreConfig.AddFlags(fs)
+	if withExpiry {
+		fs.StringVar(&c.until, "until", c.until, "time in RFC3339 format until which the snapshot is pinned, the snapshot is pinned indefinitely if not set")
+	}
+}
+
+// Validate validates the config.
+func (c *pinOptions) validate() error {
+	if len(c.until) != 0 {
+		if _, err := time.Parse(time.RFC3339, c.until); err != nil {
+			return fmt.Errorf("invalid pin expiry time %s: %v", c.until, err)
+		}
+	}
+	return c.snapstoreConfig.Validate()
+}
+
+// complete completes the config.
+func (c *pinOptions) complete() {
+	c.snapstoreConfig.Complete()
+	if len(c.until) != 0 {
+		// the expiry time is validated in validate.
+		c.expiry, _ = time.Parse(time.RFC3339, c.until)
+	}
+}
+
+// defaultServerAddress is the default address of the backup-restore server to send requests to.
+const defaultServerAddress = "http://localhost:8080"
+
+type pauseOptions struct {
+	serverAddress string
+	tokenFile     string
+	caFile        string
+	until         string
+	duration      time.Duration
+}
+
+// newPauseOptions returns the pause options.
+func newPauseOptions() *pauseOptions {
+	return &pauseOptions{
+		serverAddress: defaultServerAddress,
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *pauseOptions) addFlags(fs *flag.FlagSet, withDeadline bool) {
+	fs.StringVar(&c.serverAddress, "server-address", c.serverAddress, "address of the backup-restore server, requests to members other than the leader are forwarded to the leader")
+	fs.StringVar(&c.tokenFile, "token-file", c.tokenFile, "file containing the bearer token to authenticate with the backup-restore server")
+	fs.StringVar(&c.caFile, "ca-file", c.caFile, "file containing the CA certificate to verify the TLS certificate of the backup-restore server")
+	if withDeadline {
+		fs.StringVar(&c.until, "until", c.until, "time in RFC3339 format at which the snapshotting is resumed automatically, the snapshotting stays paused until resumed if neither this nor --duration is set")
+		fs.DurationVar(&c.duration, "duration", c.duration, "duration after which the snapshotting is resumed automatically")
+	}
+}
+
+// Validate validates the config.
+func (c *pauseOptions) validate() error {
+	if _, err := url.ParseRequestURI(c.serverAddress); err != nil {
+		return fmt.Errorf("invalid server address %s: %v", c.serverAddress, err)
+	}
+	if len(c.until) != 0 {
+		if c.duration != 0 {
+			return fmt.Errorf("only one of until and duration can be set")
+		}
+		if _, err := time.Parse(time.RFC3339, c.until); err != nil {
+			return fmt.Errorf("invalid pause deadline %s: %v", c.until, err)
+		}
+	}
+	if c.duration < 0 {
+		return fmt.Errorf("pause duration %s must not be negative", c.duration)
+	}
+	return nil
+}
+
 type validatorOptions struct {
 	ValidationMode string `json:"validationMode,omitempty"`
 }
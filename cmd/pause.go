@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewPauseCommand creates a cobra command for pause.
+func NewPauseCommand(ctx context.Context) *cobra.Command {
+	opts := newPauseOptions()
+	var command = &cobra.Command{
+		Use:   "pause",
+		Short: "pauses the scheduled snapshots and the garbage collection of a running backup-restore server",
+		Long: `Pauses the scheduled full and delta snapshots and the garbage collection of the backup-restore leader,
+which then stops writing to the snapshot store, e.g. while migrating the bucket or rotating its credentials.
+With --duration or --until, the snapshotting is resumed automatically at the deadline.`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			query := url.Values{}
+			if len(opts.until) != 0 {
+				query.Set("until", opts.until)
+			}
+			if opts.duration != 0 {
+				query.Set("duration", opts.duration.String())
+			}
+			runPauseUpdate(ctx, opts, "pause", query)
+		},
+	}
+	opts.addFlags(command.Flags(), true)
+	return command
+}
+
+// NewResumeCommand creates a cobra command for resume.
+func NewResumeCommand(ctx context.Context) *cobra.Command {
+	opts := newPauseOptions()
+	var command = &cobra.Command{
+		Use:   "resume",
+		Short: "resumes the scheduled snapshots and the garbage collection of a running backup-restore server",
+		Long: `Resumes the scheduled full and delta snapshots and the garbage collection of the backup-restore leader.
+A full snapshot scheduled while paused is taken right away.`,
+		Args: cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			runPauseUpdate(ctx, opts, "resume", url.Values{})
+		},
+	}
+	opts.addFlags(command.Flags(), false)
+	return command
+}
+
+// runPauseUpdate sends the given pause update to the backup-restore server, and prints the resulting pause status.
+func runPauseUpdate(ctx context.Context, opts *pauseOptions, action string, query url.Values) {
+	logger := logrus.NewEntry(logger)
+	if err := opts.validate(); err != nil {
+		logger.Fatalf("failed to validate the options: %v", err)
+	}
+	status, err := sendPauseUpdate(ctx, opts, action, query)
+	if err != nil {
+		logger.Fatalf("failed to %s snapshotting: %v", action, err)
+	}
+	if _, err := os.Stdout.Write(append(status, '\n')); err != nil {
+		logger.Fatalf("failed to write the pause status: %v", err)
+	}
+}
+
+// sendPauseUpdate sends the given pause update to the backup-restore server, and returns the resulting pause status.
+func sendPauseUpdate(ctx context.Context, opts *pauseOptions, action string, query url.Values) ([]byte, error) {
+	client := &http.Client{}
+	if len(opts.caFile) != 0 {
+		caCert, err := os.ReadFile(opts.caFile) // #nosec G304 -- this is a trusted CA file.
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file: %v", err)
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(caCert)
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{ // #nosec G402 -- TLSClientConfig.MinVersion=1.2 by default.
+				RootCAs: caCertPool,
+			},
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s/snapshot/%s", strings.TrimSuffix(opts.serverAddress, "/"), action)
+	if len(query) != 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.tokenFile) != 0 {
+		token, err := os.ReadFile(opts.tokenFile) // #nosec G304 -- this is a trusted token file.
+		if err != nil {
+			return nil, fmt.Errorf("unable to read token file: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("backup-restore server responded with status %s", response.Status)
+	}
+	return body, nil
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewPinCommand creates a cobra command for pin.
+func NewPinCommand(_ context.Context) *cobra.Command {
+	opts := newPinOptions()
+	var command = &cobra.Command{
+		Use:   "pin SNAPSHOT",
+		Short: "pins a full snapshot in the snapshot store",
+		Long: `Pins a full snapshot in the snapshot store, which protects it from garbage collection
+irrespective of the garbage collection policy. With --until, the pin expires at the given time,
+after which the snapshot is garbage collected as per the garbage collection policy.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			runPinUpdate(opts, args[0], "pin", func(store brtypes.SnapStore, snap *brtypes.Snapshot) error {
+				return snapstore.PinSnapshot(store, snap, opts.expiry)
+			})
+		},
+	}
+	opts.addFlags(command.Flags(), true)
+	return command
+}
+
+// NewUnpinCommand creates a cobra command for unpin.
+func NewUnpinCommand(_ context.Context) *cobra.Command {
+	opts := newPinOptions()
+	var command = &cobra.Command{
+		Use:   "unpin SNAPSHOT",
+		Short: "removes the pin of a full snapshot in the snapshot store",
+		Long:  `Removes the pin of a full snapshot in the snapshot store, after which it is garbage collected as per the garbage collection policy.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			runPinUpdate(opts, args[0], "unpin", snapstore.UnpinSnapshot)
+		},
+	}
+	opts.addFlags(command.Flags(), false)
+	return command
+}
+
+// runPinUpdate applies the given pin update to the snapshot with the given name, and prints the updated snapshot.
+func runPinUpdate(opts *pinOptions, name, action string, update func(brtypes.SnapStore, *brtypes.Snapshot) error) {
+	printVersionInfo()
+	logger := logrus.NewEntry(logger)
+	if err := opts.validate(); err != nil {
+		logger.Fatalf("failed to validate the options: %v", err)
+	}
+	opts.complete()
+
+	store, err := snapstore.GetSnapstore(opts.snapstoreConfig)
+	if err != nil {
+		logger.Fatalf("failed to create snapstore from configured storage provider: %v", err)
+	}
+	snap, err := miscellaneous.GetSnapshot(store, name)
+	if err != nil {
+		logger.Fatalf("failed to get snapshot %s: %v", name, err)
+	}
+	if err := update(store, snap); err != nil {
+		logger.Fatalf("failed to %s snapshot: %v", action, err)
+	}
+	logger.Infof("Successfully updated the pin of snapshot %s", name)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(snap); err != nil {
+		logger.Fatalf("failed to write the snapshot: %v", err)
+	}
+}
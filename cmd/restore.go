@@ -16,7 +16,7 @@ import (
 )
 
 // NewRestoreCommand returns the command to restore
-func NewRestoreCommand(_ context.Context) *cobra.Command {
+func NewRestoreCommand(ctx context.Context) *cobra.Command {
 	opts := newRestorerOptions()
 	// restoreCmd represents the restore command
 	restoreCmd := &cobra.Command{
@@ -39,7 +39,7 @@ func NewRestoreCommand(_ context.Context) *cobra.Command {
 			if err != nil {
 				logger.Fatalf("failed to create restorer object: %v", err)
 			}
-			if err := rs.RestoreAndStopEtcd(*options, nil); err != nil {
+			if err := rs.RestoreAndStopEtcd(ctx, *options, nil); err != nil {
 				logger.Fatalf("Failed to restore snapshot: %v", err)
 				return
 			}
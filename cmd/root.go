@@ -31,6 +31,13 @@ from previously taken snapshot.`,
 		NewCompactCommand(ctx),
 		NewInitializeCommand(ctx),
 		NewServerCommand(ctx),
-		NewCopyCommand(ctx))
+		NewCopyCommand(ctx),
+		NewVerifyCommand(ctx),
+		NewExportCommand(ctx),
+		NewGarbageCollectCommand(ctx),
+		NewPinCommand(ctx),
+		NewUnpinCommand(ctx),
+		NewPauseCommand(ctx),
+		NewResumeCommand(ctx))
 	return RootCmd
 }
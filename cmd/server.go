@@ -18,11 +18,11 @@ func NewServerCommand(ctx context.Context) *cobra.Command {
 		Use:   "server",
 		Short: "start the http server with backup scheduler.",
 		Long:  `Server will keep listening for http request to deliver its functionality through http endpoints.`,
-		Run: func(_ *cobra.Command, _ []string) {
+		Run: func(cmd *cobra.Command, _ []string) {
 			printVersionInfo()
 
-			if err := opts.loadConfigFromFile(); err != nil {
-				opts.Logger.Fatalf("failed to load the config from file: %v", err)
+			if err := opts.loadConfig(cmd.Flags()); err != nil {
+				opts.Logger.Fatalf("failed to load the config: %v", err)
 				return
 			}
 
@@ -40,7 +40,7 @@ func NewServerCommand(ctx context.Context) *cobra.Command {
 			}
 			opts.Logger.Infof("%s", optsJSON)
 
-			if err := opts.run(ctx); err != nil {
+			if err := opts.run(ctx, cmd.Flags()); err != nil {
 				opts.Logger.Fatalf("failed to run server: %v", err)
 			}
 		},
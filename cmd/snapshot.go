@@ -57,6 +57,7 @@ storing snapshots on various cloud storage providers as well as local disk locat
 			go defragmentor.DefragDataPeriodically(ctx, opts.etcdConnectionConfig, defragSchedule, ssr.TriggerFullSnapshot, logger)
 
 			go ssr.RunGarbageCollector(ctx.Done())
+			go ssr.RunDeltaSnapshotSpoolUploader(ctx.Done())
 			if err := ssr.Run(ctx.Done(), true); err != nil {
 				logger.Fatalf("Snapshotter failed with error: %v", err)
 			}
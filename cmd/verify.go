@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/verifier"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+
+	"github.com/go-logr/logr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// NewVerifyCommand creates a cobra command for verify.
+func NewVerifyCommand(ctx context.Context) *cobra.Command {
+	opts := newVerifyOptions()
+	var command = &cobra.Command{
+		Use:   "verify",
+		Short: "verifies the backups in the snapshot store",
+		Long: `Verifies that the delta snapshots of every full snapshot in the snapshot store follow each other
+without revision gaps or overlaps, looks for orphaned chunks, and checks the contents of every snapshot
+against its SHA256 hash. The result is printed as a JSON report.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			printVersionInfo()
+			logger := logrus.NewEntry(logger)
+			runtimelog.SetLogger(logr.New(runtimelog.NullLogSink{}))
+			if err := opts.validate(); err != nil {
+				logger.Fatalf("failed to validate the options: %v", err)
+			}
+			opts.complete()
+
+			store, err := snapstore.GetSnapstore(opts.snapstoreConfig)
+			if err != nil {
+				logger.Fatalf("failed to create snapstore from configured storage provider: %v", err)
+			}
+
+			v, err := verifier.NewVerifier(store, opts.verifierConfig, logger)
+			if err != nil {
+				logger.Fatalf("failed to create verifier: %v", err)
+			}
+			report, err := v.Verify(ctx)
+			if err != nil {
+				logger.Fatalf("failed to verify the backups: %v", err)
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				logger.Fatalf("failed to write the report: %v", err)
+			}
+			if !report.Healthy {
+				logger.Fatalf("Found %d problems with the backups", len(report.Problems))
+			}
+			logger.Info("Backups verified successfully.")
+		},
+	}
+	opts.addFlags(command.Flags())
+	return command
+}
@@ -12,24 +12,34 @@ export TOOLS_BIN_DIR := $(TOOLS_BIN_DIR)
 export PATH          := $(abspath $(TOOLS_BIN_DIR)):$(PATH)
 
 # Tool Binaries
-GOSEC             := $(TOOLS_BIN_DIR)/gosec
-GINKGO            := $(TOOLS_BIN_DIR)/ginkgo
-HELM              := $(TOOLS_BIN_DIR)/helm
-KIND              := $(TOOLS_BIN_DIR)/kind
-KUBECTL           := $(TOOLS_BIN_DIR)/kubectl
-GOIMPORTS         := $(TOOLS_BIN_DIR)/goimports
-GOIMPORTS_REVISER := $(TOOLS_BIN_DIR)/goimports-reviser
-GO_ADD_LICENSE    := $(TOOLS_BIN_DIR)/addlicense
-GOLANGCI_LINT     := $(TOOLS_BIN_DIR)/golangci-lint
+GOSEC              := $(TOOLS_BIN_DIR)/gosec
+GINKGO             := $(TOOLS_BIN_DIR)/ginkgo
+HELM               := $(TOOLS_BIN_DIR)/helm
+KIND               := $(TOOLS_BIN_DIR)/kind
+KUBECTL            := $(TOOLS_BIN_DIR)/kubectl
+GOIMPORTS          := $(TOOLS_BIN_DIR)/goimports
+GOIMPORTS_REVISER  := $(TOOLS_BIN_DIR)/goimports-reviser
+GO_ADD_LICENSE     := $(TOOLS_BIN_DIR)/addlicense
+GOLANGCI_LINT      := $(TOOLS_BIN_DIR)/golangci-lint
+PROTOC             := $(TOOLS_BIN_DIR)/protoc
+PROTOC_GEN_GO      := $(TOOLS_BIN_DIR)/protoc-gen-go
+PROTOC_GEN_GO_GRPC := $(TOOLS_BIN_DIR)/protoc-gen-go-grpc
 
 # Tool Versions
-GOSEC_VERSION             ?= v2.22.2
-HELM_VERSION              ?= v3.17.2
-KIND_VERSION              ?= v0.27.0
-KUBECTL_VERSION           ?= v1.32.3
-GOIMPORTS_REVISER_VERSION ?= v3.9.1
-GO_ADD_LICENSE_VERSION    ?= v1.1.1
-GOLANGCI_LINT_VERSION     ?= v2.8.0
+GOSEC_VERSION              ?= v2.22.2
+HELM_VERSION               ?= v3.17.2
+KIND_VERSION               ?= v0.27.0
+KUBECTL_VERSION            ?= v1.32.3
+GOIMPORTS_REVISER_VERSION  ?= v3.9.1
+GO_ADD_LICENSE_VERSION     ?= v1.1.1
+GOLANGCI_LINT_VERSION      ?= v2.8.0
+PROTOC_VERSION             ?= v29.3
+PROTOC_GEN_GO_VERSION      ?= v1.36.11
+PROTOC_GEN_GO_GRPC_VERSION ?= v1.5.1
+
+# protoc releases are named after the system and architecture names of protobuf.
+PROTOC_SYSTEM_NAME := $(subst darwin,osx,$(SYSTEM_NAME))
+PROTOC_SYSTEM_ARCH := $(subst amd64,x86_64,$(subst arm64,aarch_64,$(SYSTEM_ARCH)))
 
 # Use this "function" to add the version file as a prerequisite for the tool target: e.g.
 #   $(HELM): $(call tool_version_file,$(HELM),$(HELM_VERSION))
@@ -79,3 +89,15 @@ $(GOLANGCI_LINT):
 	# CGO_ENABLED has to be set to 1 in order for golangci-lint to be able to load plugins
 	# see https://github.com/golangci/golangci-lint/issues/1276
 	GOBIN=$(abspath $(TOOLS_BIN_DIR)) CGO_ENABLED=1 go install github.com/golangci/golangci-lint/v2/cmd/golangci-lint@$(GOLANGCI_LINT_VERSION)
+
+# protoc finds the well-known types in the include directory next to its bin directory.
+$(PROTOC): $(call tool_version_file,$(PROTOC),$(PROTOC_VERSION))
+	curl -fLo $(TOOLS_DIR)/protoc.zip --retry 5 --retry-delay 5 https://github.com/protocolbuffers/protobuf/releases/download/$(PROTOC_VERSION)/protoc-$(PROTOC_VERSION:v%=%)-$(PROTOC_SYSTEM_NAME)-$(PROTOC_SYSTEM_ARCH).zip
+	unzip -o $(TOOLS_DIR)/protoc.zip bin/protoc 'include/*' -d $(TOOLS_DIR)
+	rm $(TOOLS_DIR)/protoc.zip
+
+$(PROTOC_GEN_GO):
+	GOBIN=$(abspath $(TOOLS_BIN_DIR)) go install google.golang.org/protobuf/cmd/protoc-gen-go@$(PROTOC_GEN_GO_VERSION)
+
+$(PROTOC_GEN_GO_GRPC):
+	GOBIN=$(abspath $(TOOLS_BIN_DIR)) go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@$(PROTOC_GEN_GO_GRPC_VERSION)
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backuprestore
+
+import (
+	"context"
+	"fmt"
+
+	backuprestorev1 "github.com/gardener/etcd-backup-restore/pkg/api/backuprestore/v1"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"google.golang.org/grpc"
+)
+
+// Client is the typed client of the gRPC service of the backup-restore server. It wraps the client generated from
+// backuprestore.proto, and converts its messages from and to the types of the backup-restore server.
+type Client struct {
+	client backuprestorev1.BackupRestoreClient
+}
+
+// NewClient returns a client of the gRPC service of the backup-restore server, which sends its requests over the given
+// connection, e.g. created with grpc.NewClient. Pass BearerToken as per-RPC credentials to authenticate with a token.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: backuprestorev1.NewBackupRestoreClient(conn)}
+}
+
+// TriggerSnapshot takes an out-of-schedule snapshot.
+func (c *Client) TriggerSnapshot(ctx context.Context, req *TriggerSnapshotRequest, opts ...grpc.CallOption) (*TriggerSnapshotResponse, error) {
+	resp, err := c.client.TriggerSnapshot(ctx, &backuprestorev1.TriggerSnapshotRequest{Kind: req.Kind, Final: req.Final, Labels: req.Labels}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TriggerSnapshotResponse{Snapshot: SnapshotFromProto(resp.Snapshot)}, nil
+}
+
+// ListSnapshots lists a page of the snapshots in the store.
+func (c *Client) ListSnapshots(ctx context.Context, req *ListSnapshotsRequest, opts ...grpc.CallOption) (*ListSnapshotsResponse, error) {
+	resp, err := c.client.ListSnapshots(ctx, ListSnapshotsRequestToProto(req), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ListSnapshotsResponse{Snapshots: SnapListFromProto(resp.Snapshots), Continue: resp.Continue}, nil
+}
+
+// GetLatestSnapshots returns the latest full snapshot and the delta snapshots on top of it.
+func (c *Client) GetLatestSnapshots(ctx context.Context, opts ...grpc.CallOption) (*GetLatestSnapshotsResponse, error) {
+	resp, err := c.client.GetLatestSnapshots(ctx, &backuprestorev1.GetLatestSnapshotsRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GetLatestSnapshotsResponse{FullSnapshot: SnapshotFromProto(resp.FullSnapshot), DeltaSnapshots: SnapListFromProto(resp.DeltaSnapshots)}, nil
+}
+
+// StartInitialization starts the initialization of the etcd data directory.
+func (c *Client) StartInitialization(ctx context.Context, req *StartInitializationRequest, opts ...grpc.CallOption) (*StartInitializationResponse, error) {
+	resp, err := c.client.StartInitialization(ctx, &backuprestorev1.StartInitializationRequest{Mode: req.Mode}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &StartInitializationResponse{Status: resp.Status}, nil
+}
+
+// GetInitializationStatus returns the status of the initialization of the etcd data directory.
+func (c *Client) GetInitializationStatus(ctx context.Context, opts ...grpc.CallOption) (*GetInitializationStatusResponse, error) {
+	resp, err := c.client.GetInitializationStatus(ctx, &backuprestorev1.GetInitializationStatusRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GetInitializationStatusResponse{Status: resp.Status}, nil
+}
+
+// Health returns the health of the backup-restore server.
+func (c *Client) Health(ctx context.Context, opts ...grpc.CallOption) (*HealthResponse, error) {
+	resp, err := c.client.Health(ctx, &backuprestorev1.HealthRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &HealthResponse{Healthy: resp.Healthy, Pause: PauseStatusFromProto(resp.Pause)}, nil
+}
+
+// WatchEvents streams the backup events published from now on, until the given context is cancelled.
+// If the client is too slow to receive the events, the stream ends with a ResourceExhausted error.
+func (c *Client) WatchEvents(ctx context.Context, opts ...grpc.CallOption) (*EventStream, error) {
+	stream, err := c.client.WatchEvents(ctx, &backuprestorev1.WatchEventsRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{stream: stream}, nil
+}
+
+// EventStream is a stream of backup events, see Client.WatchEvents.
+type EventStream struct {
+	stream grpc.ServerStreamingClient[backuprestorev1.Event]
+}
+
+// Recv returns the next backup event of the stream.
+func (s *EventStream) Recv() (*events.Event, error) {
+	event, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return EventFromProto(event), nil
+}
+
+// SubmitRestoreJob submits a job restoring the backups into a directory, and returns its status.
+func (c *Client) SubmitRestoreJob(ctx context.Context, req *SubmitRestoreJobRequest, opts ...grpc.CallOption) (*jobs.Job, error) {
+	return jobFromProto(c.client.SubmitRestoreJob(ctx, SubmitRestoreJobRequestToProto(req), opts...))
+}
+
+// SubmitCompactionJob submits a job compacting the latest backups into a new full snapshot, and returns its status.
+func (c *Client) SubmitCompactionJob(ctx context.Context, req *SubmitCompactionJobRequest, opts ...grpc.CallOption) (*jobs.Job, error) {
+	return jobFromProto(c.client.SubmitCompactionJob(ctx, &backuprestorev1.SubmitCompactionJobRequest{Defragment: req.Defragment}, opts...))
+}
+
+// GetJob returns the status of the job with the given ID.
+func (c *Client) GetJob(ctx context.Context, id string, opts ...grpc.CallOption) (*jobs.Job, error) {
+	return jobFromProto(c.client.GetJob(ctx, &backuprestorev1.GetJobRequest{Id: id}, opts...))
+}
+
+// ListJobs returns the status of all jobs.
+func (c *Client) ListJobs(ctx context.Context, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	resp, err := c.client.ListJobs(ctx, &backuprestorev1.ListJobsRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	jobList := make([]*jobs.Job, 0, len(resp.Jobs))
+	for _, job := range resp.Jobs {
+		jobList = append(jobList, JobFromProto(job))
+	}
+	return &ListJobsResponse{Jobs: jobList}, nil
+}
+
+// CancelJob cancels the pending or running job with the given ID, and returns its status.
+func (c *Client) CancelJob(ctx context.Context, id string, opts ...grpc.CallOption) (*jobs.Job, error) {
+	return jobFromProto(c.client.CancelJob(ctx, &backuprestorev1.CancelJobRequest{Id: id}, opts...))
+}
+
+// PauseSnapshotting pauses the scheduled snapshots and the garbage collection, and returns the pause status.
+func (c *Client) PauseSnapshotting(ctx context.Context, req *PauseSnapshottingRequest, opts ...grpc.CallOption) (*brtypes.PauseStatus, error) {
+	return pauseStatusFromProto(c.client.PauseSnapshotting(ctx, &backuprestorev1.PauseSnapshottingRequest{Until: timePtrToProto(req.Until)}, opts...))
+}
+
+// ResumeSnapshotting resumes the scheduled snapshots and the garbage collection, and returns the pause status.
+func (c *Client) ResumeSnapshotting(ctx context.Context, opts ...grpc.CallOption) (*brtypes.PauseStatus, error) {
+	return pauseStatusFromProto(c.client.ResumeSnapshotting(ctx, &backuprestorev1.ResumeSnapshottingRequest{}, opts...))
+}
+
+// jobFromProto returns the job status of the given response, or the given error of the call.
+func jobFromProto(job *backuprestorev1.Job, err error) (*jobs.Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	return JobFromProto(job), nil
+}
+
+// pauseStatusFromProto returns the pause status of the given response, or the given error of the call.
+func pauseStatusFromProto(pauseStatus *backuprestorev1.PauseStatus, err error) (*brtypes.PauseStatus, error) {
+	if err != nil {
+		return nil, err
+	}
+	return PauseStatusFromProto(pauseStatus), nil
+}
+
+// BearerToken authenticates the requests of a client with a bearer token, see grpc.WithPerRPCCredentials.
+type BearerToken struct {
+	// Token is the bearer token.
+	Token string
+	// AllowInsecure allows to send the token over connections without transport security.
+	AllowInsecure bool
+}
+
+// GetRequestMetadata returns the authorization header with the bearer token.
+func (t BearerToken) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{"authorization": fmt.Sprintf("Bearer %s", t.Token)}, nil
+}
+
+// RequireTransportSecurity returns true unless the token may be sent over connections without transport security.
+func (t BearerToken) RequireTransportSecurity() bool {
+	return !t.AllowInsecure
+}
@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backuprestore
+
+import (
+	"math"
+	"time"
+
+	backuprestorev1 "github.com/gardener/etcd-backup-restore/pkg/api/backuprestore/v1"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SnapshotToProto returns the message of the given snapshot, or nil if the snapshot is nil.
+func SnapshotToProto(snap *brtypes.Snapshot) *backuprestorev1.Snapshot {
+	if snap == nil {
+		return nil
+	}
+	return &backuprestorev1.Snapshot{
+		Kind:                   snap.Kind,
+		SnapDir:                snap.SnapDir,
+		SnapName:               snap.SnapName,
+		Prefix:                 snap.Prefix,
+		CompressionSuffix:      snap.CompressionSuffix,
+		StartRevision:          snap.StartRevision,
+		LastRevision:           snap.LastRevision,
+		CreatedOn:              timestampToProto(snap.CreatedOn),
+		IsChunk:                snap.IsChunk,
+		IsFinal:                snap.IsFinal,
+		Size:                   snap.Size,
+		VersionId:              snap.VersionID,
+		ImmutabilityExpiryTime: timestampToProto(snap.ImmutabilityExpiryTime),
+		Pinned:                 snap.Pinned,
+		PinExpiryTime:          timestampToProto(snap.PinExpiryTime),
+		Labels:                 snap.Labels,
+	}
+}
+
+// SnapshotFromProto returns the snapshot of the given message, or nil if the message is nil.
+func SnapshotFromProto(snap *backuprestorev1.Snapshot) *brtypes.Snapshot {
+	if snap == nil {
+		return nil
+	}
+	return &brtypes.Snapshot{
+		Kind:                   snap.Kind,
+		SnapDir:                snap.SnapDir,
+		SnapName:               snap.SnapName,
+		Prefix:                 snap.Prefix,
+		CompressionSuffix:      snap.CompressionSuffix,
+		StartRevision:          snap.StartRevision,
+		LastRevision:           snap.LastRevision,
+		CreatedOn:              timeFromProto(snap.CreatedOn),
+		IsChunk:                snap.IsChunk,
+		IsFinal:                snap.IsFinal,
+		Size:                   snap.Size,
+		VersionID:              snap.VersionId,
+		ImmutabilityExpiryTime: timeFromProto(snap.ImmutabilityExpiryTime),
+		Pinned:                 snap.Pinned,
+		PinExpiryTime:          timeFromProto(snap.PinExpiryTime),
+		Labels:                 snap.Labels,
+	}
+}
+
+// SnapListToProto returns the messages of the given snapshots.
+func SnapListToProto(snapList brtypes.SnapList) []*backuprestorev1.Snapshot {
+	snaps := make([]*backuprestorev1.Snapshot, 0, len(snapList))
+	for _, snap := range snapList {
+		snaps = append(snaps, SnapshotToProto(snap))
+	}
+	return snaps
+}
+
+// SnapListFromProto returns the snapshots of the given messages.
+func SnapListFromProto(snaps []*backuprestorev1.Snapshot) brtypes.SnapList {
+	snapList := make(brtypes.SnapList, 0, len(snaps))
+	for _, snap := range snaps {
+		snapList = append(snapList, SnapshotFromProto(snap))
+	}
+	return snapList
+}
+
+// EventToProto returns the message of the given backup event.
+func EventToProto(event *events.Event) *backuprestorev1.Event {
+	return &backuprestorev1.Event{
+		Type:     string(event.Type),
+		Time:     timestampToProto(event.Time),
+		Snapshot: SnapshotToProto(event.Snapshot),
+		Message:  event.Message,
+	}
+}
+
+// EventFromProto returns the backup event of the given message.
+func EventFromProto(event *backuprestorev1.Event) *events.Event {
+	return &events.Event{
+		Type:     events.Type(event.Type),
+		Time:     timeFromProto(event.Time),
+		Snapshot: SnapshotFromProto(event.Snapshot),
+		Message:  event.Message,
+	}
+}
+
+// JobToProto returns the message of the given job status.
+func JobToProto(job *jobs.Job) *backuprestorev1.Job {
+	msg := &backuprestorev1.Job{
+		Id:         job.ID,
+		Type:       string(job.Type),
+		State:      string(job.State),
+		CreatedOn:  timestampToProto(job.CreatedOn),
+		StartedOn:  timePtrToProto(job.StartedOn),
+		FinishedOn: timePtrToProto(job.FinishedOn),
+		Progress: &backuprestorev1.RestoreProgress{
+			SnapshotsTotal:   int32(job.Progress.SnapshotsTotal),   // #nosec G115 -- the number of snapshots of a restoration fits into int32.
+			SnapshotsFetched: int32(job.Progress.SnapshotsFetched), // #nosec G115 -- the number of snapshots of a restoration fits into int32.
+			SnapshotsApplied: int32(job.Progress.SnapshotsApplied), // #nosec G115 -- the number of snapshots of a restoration fits into int32.
+			Revision:         job.Progress.Revision,
+		},
+		Error: job.Error,
+	}
+	if job.Result != nil {
+		msg.Result = &backuprestorev1.JobResult{
+			DataDir:  job.Result.DataDir,
+			Snapshot: SnapshotToProto(job.Result.Snapshot),
+			Revision: job.Result.Revision,
+		}
+	}
+	return msg
+}
+
+// JobFromProto returns the job status of the given message.
+func JobFromProto(msg *backuprestorev1.Job) *jobs.Job {
+	job := &jobs.Job{
+		ID:         msg.Id,
+		Type:       jobs.Type(msg.Type),
+		State:      jobs.State(msg.State),
+		CreatedOn:  timeFromProto(msg.CreatedOn),
+		StartedOn:  timePtrFromProto(msg.StartedOn),
+		FinishedOn: timePtrFromProto(msg.FinishedOn),
+		Progress: brtypes.RestoreProgress{
+			SnapshotsTotal:   int(msg.GetProgress().GetSnapshotsTotal()),
+			SnapshotsFetched: int(msg.GetProgress().GetSnapshotsFetched()),
+			SnapshotsApplied: int(msg.GetProgress().GetSnapshotsApplied()),
+			Revision:         msg.GetProgress().GetRevision(),
+		},
+		Error: msg.Error,
+	}
+	if msg.Result != nil {
+		job.Result = &jobs.Result{
+			DataDir:  msg.Result.DataDir,
+			Snapshot: SnapshotFromProto(msg.Result.Snapshot),
+			Revision: msg.Result.Revision,
+		}
+	}
+	return job
+}
+
+// PauseStatusToProto returns the message of the given pause status, or nil if the pause status is nil.
+func PauseStatusToProto(pauseStatus *brtypes.PauseStatus) *backuprestorev1.PauseStatus {
+	if pauseStatus == nil {
+		return nil
+	}
+	return &backuprestorev1.PauseStatus{
+		Paused: pauseStatus.Paused,
+		Since:  timePtrToProto(pauseStatus.Since),
+		Until:  timePtrToProto(pauseStatus.Until),
+	}
+}
+
+// PauseStatusFromProto returns the pause status of the given message, or nil if the message is nil.
+func PauseStatusFromProto(pauseStatus *backuprestorev1.PauseStatus) *brtypes.PauseStatus {
+	if pauseStatus == nil {
+		return nil
+	}
+	return &brtypes.PauseStatus{
+		Paused: pauseStatus.Paused,
+		Since:  timePtrFromProto(pauseStatus.Since),
+		Until:  timePtrFromProto(pauseStatus.Until),
+	}
+}
+
+// ListSnapshotsRequestToProto returns the message of the given request.
+func ListSnapshotsRequestToProto(req *ListSnapshotsRequest) *backuprestorev1.ListSnapshotsRequest {
+	// limits beyond the range of the message are rejected by the server anyway.
+	limit := min(max(req.Limit, math.MinInt32), math.MaxInt32)
+	return &backuprestorev1.ListSnapshotsRequest{
+		Kind:          req.Kind,
+		MinRevision:   req.MinRevision,
+		MaxRevision:   req.MaxRevision,
+		CreatedAfter:  timestampToProto(req.CreatedAfter),
+		CreatedBefore: timestampToProto(req.CreatedBefore),
+		Final:         req.Final,
+		Labels:        req.Labels,
+		IncludeAll:    req.IncludeAll,
+		Limit:         int32(limit), // #nosec G115 -- the limit is capped to the range of int32.
+		Continue:      req.Continue,
+	}
+}
+
+// ListSnapshotsRequestFromProto returns the request of the given message.
+func ListSnapshotsRequestFromProto(req *backuprestorev1.ListSnapshotsRequest) *ListSnapshotsRequest {
+	return &ListSnapshotsRequest{
+		Kind:          req.Kind,
+		MinRevision:   req.MinRevision,
+		MaxRevision:   req.MaxRevision,
+		CreatedAfter:  timeFromProto(req.CreatedAfter),
+		CreatedBefore: timeFromProto(req.CreatedBefore),
+		Final:         req.Final,
+		Labels:        req.Labels,
+		IncludeAll:    req.IncludeAll,
+		Limit:         int(req.Limit),
+		Continue:      req.Continue,
+	}
+}
+
+// SubmitRestoreJobRequestToProto returns the message of the given request.
+func SubmitRestoreJobRequestToProto(req *SubmitRestoreJobRequest) *backuprestorev1.SubmitRestoreJobRequest {
+	return &backuprestorev1.SubmitRestoreJobRequest{
+		DataDir:              req.DataDir,
+		TargetRevision:       req.TargetRevision,
+		TargetTime:           req.TargetTime,
+		TargetSnapshotLabels: req.TargetSnapshotLabels,
+	}
+}
+
+// SubmitRestoreJobRequestFromProto returns the request of the given message.
+func SubmitRestoreJobRequestFromProto(req *backuprestorev1.SubmitRestoreJobRequest) *SubmitRestoreJobRequest {
+	return &SubmitRestoreJobRequest{
+		DataDir:              req.DataDir,
+		TargetRevision:       req.TargetRevision,
+		TargetTime:           req.TargetTime,
+		TargetSnapshotLabels: req.TargetSnapshotLabels,
+	}
+}
+
+// timestampToProto returns the timestamp of the given time, or nil if the time is zero.
+func timestampToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// timeFromProto returns the time of the given timestamp, or the zero time if the timestamp is nil.
+func timeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// timePtrToProto returns the timestamp of the given time, or nil if the time is nil.
+func timePtrToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timePtrFromProto returns the time of the given timestamp, or nil if the timestamp is nil.
+func timePtrFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
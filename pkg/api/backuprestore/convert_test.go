@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backuprestore
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+	versionID := "version"
+	snap := &brtypes.Snapshot{
+		Kind:                   brtypes.SnapshotKindFull,
+		SnapDir:                "Backup-1",
+		SnapName:               "Full-00000000-00000005-1",
+		Prefix:                 "v2",
+		CompressionSuffix:      ".gz",
+		StartRevision:          1,
+		LastRevision:           5,
+		CreatedOn:              now,
+		IsChunk:                true,
+		IsFinal:                true,
+		Size:                   42,
+		VersionID:              &versionID,
+		ImmutabilityExpiryTime: later,
+		Pinned:                 true,
+		PinExpiryTime:          later,
+		Labels:                 map[string]string{"reason": "test"},
+	}
+	if got := SnapshotFromProto(SnapshotToProto(snap)); !reflect.DeepEqual(got, snap) {
+		t.Errorf("snapshot changed by conversion: got %+v want %+v", got, snap)
+	}
+
+	event := &events.Event{Type: events.TypeSnapshotTaken, Time: now, Snapshot: snap, Message: "message"}
+	if got := EventFromProto(EventToProto(event)); !reflect.DeepEqual(got, event) {
+		t.Errorf("event changed by conversion: got %+v want %+v", got, event)
+	}
+
+	job := &jobs.Job{
+		ID:         "job",
+		Type:       jobs.TypeCompaction,
+		State:      jobs.StateSucceeded,
+		CreatedOn:  now,
+		StartedOn:  &now,
+		FinishedOn: &later,
+		Progress:   brtypes.RestoreProgress{SnapshotsTotal: 3, SnapshotsFetched: 2, SnapshotsApplied: 1, Revision: 5},
+		Result:     &jobs.Result{DataDir: "/data", Snapshot: snap, Revision: 5},
+		Error:      "error",
+	}
+	if got := JobFromProto(JobToProto(job)); !reflect.DeepEqual(got, job) {
+		t.Errorf("job changed by conversion: got %+v want %+v", got, job)
+	}
+
+	pauseStatus := &brtypes.PauseStatus{Paused: true, Since: &now, Until: &later}
+	if got := PauseStatusFromProto(PauseStatusToProto(pauseStatus)); !reflect.DeepEqual(got, pauseStatus) {
+		t.Errorf("pause status changed by conversion: got %+v want %+v", got, pauseStatus)
+	}
+
+	final := true
+	listReq := &ListSnapshotsRequest{
+		Kind:          brtypes.SnapshotKindDelta,
+		MinRevision:   1,
+		MaxRevision:   5,
+		CreatedAfter:  now,
+		CreatedBefore: later,
+		Final:         &final,
+		Labels:        map[string]string{"reason": "test"},
+		IncludeAll:    true,
+		Limit:         10,
+		Continue:      "token",
+	}
+	if got := ListSnapshotsRequestFromProto(ListSnapshotsRequestToProto(listReq)); !reflect.DeepEqual(got, listReq) {
+		t.Errorf("list snapshots request changed by conversion: got %+v want %+v", got, listReq)
+	}
+
+	restoreReq := &SubmitRestoreJobRequest{DataDir: "/data", TargetRevision: 5, TargetTime: now.Format(time.RFC3339), TargetSnapshotLabels: "reason=test"}
+	if got := SubmitRestoreJobRequestFromProto(SubmitRestoreJobRequestToProto(restoreReq)); !reflect.DeepEqual(got, restoreReq) {
+		t.Errorf("submit restore job request changed by conversion: got %+v want %+v", got, restoreReq)
+	}
+}
+
+func TestConvertUnsetTimes(t *testing.T) {
+	snap := &brtypes.Snapshot{Kind: brtypes.SnapshotKindDelta, CreatedOn: time.Now().UTC()}
+	msg := SnapshotToProto(snap)
+	if msg.ImmutabilityExpiryTime != nil || msg.PinExpiryTime != nil {
+		t.Fatalf("zero times are expected to be unset: %v", msg)
+	}
+	if got := SnapshotFromProto(msg); !got.ImmutabilityExpiryTime.IsZero() || !got.PinExpiryTime.IsZero() || got.VersionID != nil {
+		t.Fatalf("unset fields are expected to be zero: %+v", got)
+	}
+	if SnapshotToProto(nil) != nil || SnapshotFromProto(nil) != nil || PauseStatusToProto(nil) != nil || PauseStatusFromProto(nil) != nil {
+		t.Fatal("nil is expected to be converted to nil")
+	}
+}
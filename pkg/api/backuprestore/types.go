@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backuprestore
+
+import (
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// TriggerSnapshotRequest is the request to take an out-of-schedule snapshot.
+type TriggerSnapshotRequest struct {
+	// Kind is the kind of the snapshot to take, i.e. Full or Incr.
+	Kind string `json:"kind"`
+	// Final marks the full snapshot as the final one before the etcd cluster is hibernated or deleted.
+	Final bool `json:"final,omitempty"`
+	// Labels are the labels of the snapshot, see Snapshot.Labels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TriggerSnapshotResponse is the response to a TriggerSnapshotRequest.
+type TriggerSnapshotResponse struct {
+	// Snapshot is the snapshot taken. It is nil if a delta snapshot was requested, but there were no new events to save.
+	Snapshot *brtypes.Snapshot `json:"snapshot,omitempty"`
+}
+
+// ListSnapshotsRequest is the request to list a page of the snapshots in the store. Unset filters do not restrict the listing.
+type ListSnapshotsRequest struct {
+	// Kind lists only the snapshots of the given kind, i.e. Full or Incr.
+	Kind string `json:"kind,omitempty"`
+	// MinRevision lists only the snapshots which contain revisions at or after the given revision.
+	MinRevision int64 `json:"minRevision,omitempty"`
+	// MaxRevision lists only the snapshots which contain revisions at or before the given revision.
+	MaxRevision int64 `json:"maxRevision,omitempty"`
+	// CreatedAfter lists only the snapshots taken at or after the given time.
+	CreatedAfter time.Time `json:"createdAfter,omitempty"`
+	// CreatedBefore lists only the snapshots taken at or before the given time.
+	CreatedBefore time.Time `json:"createdBefore,omitempty"`
+	// Final lists only the final or non-final snapshots.
+	Final *bool `json:"final,omitempty"`
+	// Labels lists only the snapshots which have all of the given labels.
+	Labels map[string]string `json:"labels,omitempty"`
+	// IncludeAll also lists the snapshots which are tagged to be excluded.
+	IncludeAll bool `json:"includeAll,omitempty"`
+	// Limit is the maximum number of snapshots in the page, the default page size is used if unset.
+	Limit int `json:"limit,omitempty"`
+	// Continue is the continue token of the previous page.
+	Continue string `json:"continue,omitempty"`
+}
+
+// ListSnapshotsResponse is a page of the snapshots in the store.
+type ListSnapshotsResponse struct {
+	Snapshots brtypes.SnapList `json:"snapshots"`
+	// Continue is the continue token for the next page. It is empty if there are no more snapshots.
+	Continue string `json:"continue,omitempty"`
+}
+
+// GetLatestSnapshotsResponse is the latest full snapshot and the delta snapshots on top of it.
+type GetLatestSnapshotsResponse struct {
+	FullSnapshot   *brtypes.Snapshot `json:"fullSnapshot"`
+	DeltaSnapshots brtypes.SnapList  `json:"deltaSnapshots"`
+}
+
+// StartInitializationRequest is the request to start the initialization of the etcd data directory.
+type StartInitializationRequest struct {
+	// Mode is the validation mode of the data directory, i.e. full or sanity. It defaults to full.
+	Mode string `json:"mode,omitempty"`
+}
+
+// StartInitializationResponse is the response to a StartInitializationRequest.
+type StartInitializationResponse struct {
+	// Status is the initialization status after the request, see GetInitializationStatusResponse.
+	Status string `json:"status"`
+}
+
+// GetInitializationStatusResponse is the status of the initialization of the etcd data directory.
+type GetInitializationStatusResponse struct {
+	// Status is New, Progress, Successful or Failed. Once a Successful or Failed status has been returned, the status is reset to New.
+	Status string `json:"status"`
+}
+
+// HealthResponse is the health of the backup-restore server.
+type HealthResponse struct {
+	Healthy bool `json:"healthy"`
+	// Pause is the pause status of the snapshotter, which is only set on the backup-restore leader while the
+	// snapshotting is paused.
+	Pause *brtypes.PauseStatus `json:"pause,omitempty"`
+}
+
+// SubmitRestoreJobRequest is the request to restore the backups into a directory in the background.
+type SubmitRestoreJobRequest struct {
+	// DataDir is the absolute path of the directory to restore the backups into. It must not exist yet.
+	DataDir string `json:"dataDir"`
+	// TargetRevision restores the backups up to the given revision, see RestorationConfig.TargetRevision.
+	TargetRevision int64 `json:"targetRevision,omitempty"`
+	// TargetTime restores the backups up to the given time in RFC3339 format, see RestorationConfig.TargetTime.
+	TargetTime string `json:"targetTime,omitempty"`
+	// TargetSnapshotLabels restores the backups up to the latest snapshot with the given labels, see RestorationConfig.TargetSnapshotLabels.
+	TargetSnapshotLabels string `json:"targetSnapshotLabels,omitempty"`
+}
+
+// SubmitCompactionJobRequest is the request to compact the latest backups into a new full snapshot in the background.
+type SubmitCompactionJobRequest struct {
+	// Defragment defragments the compacted data before taking the full snapshot. It defaults to true.
+	Defragment *bool `json:"defragment,omitempty"`
+}
+
+// ListJobsResponse is the status of all jobs.
+type ListJobsResponse struct {
+	// Jobs are the pending, running and recently finished jobs in the order of their submission.
+	Jobs []*jobs.Job `json:"jobs"`
+}
+
+// PauseSnapshottingRequest is the request to pause the scheduled snapshots and the garbage collection.
+type PauseSnapshottingRequest struct {
+	// Until is the time to resume the snapshotting at automatically. Without it, the snapshotting stays paused until
+	// it is resumed explicitly.
+	Until *time.Time `json:"until,omitempty"`
+}
@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//go:generate protoc --proto_path=../../../.. --go_out=../../../.. --go_opt=paths=source_relative --go-grpc_out=../../../.. --go-grpc_opt=paths=source_relative pkg/api/backuprestore/v1/backuprestore.proto
+
+// Package backuprestorev1 contains the messages and stubs generated from the definition of the gRPC API of the
+// backup-restore server in backuprestore.proto.
+package backuprestorev1
@@ -71,7 +71,7 @@ func (cp *Compactor) Compact(ctx context.Context, opts *brtypes.CompactOptions)
 	if err != nil {
 		return nil, err
 	}
-	embeddedEtcd, err := r.Restore(*compactorRestoreOptions, nil)
+	embeddedEtcd, err := r.Restore(ctx, *compactorRestoreOptions, nil)
 	if err != nil {
 		return nil, fmt.Errorf("unable to restore snapshots during compaction: %v", err)
 	}
@@ -187,7 +187,7 @@ var _ = Describe("Running Compactor", func() {
 				restorer, err := restorer.NewRestorer(store, logger)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				err = restorer.RestoreAndStopEtcd(*restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, *restoreOpts, nil)
 
 				Expect(err).ShouldNot(HaveOccurred())
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
@@ -266,7 +266,7 @@ var _ = Describe("Running Compactor", func() {
 				restorer, err := restorer.NewRestorer(store, logger)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				err = restorer.RestoreAndStopEtcd(*restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, *restoreOpts, nil)
 
 				Expect(err).ShouldNot(HaveOccurred())
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
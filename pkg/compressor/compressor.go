@@ -11,27 +11,39 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/sirupsen/logrus"
 )
 
 // CompressSnapshot takes uncompressed data as input and compress the data according to Compression Policy
-// and write the compressed data into one end of pipe.
-func CompressSnapshot(data io.ReadCloser, compressionPolicy string) (io.ReadCloser, error) {
+// and Compression Level, and write the compressed data into one end of pipe.
+// A compressionLevel of DefaultCompressionLevel uses the default level of the compression algorithm.
+func CompressSnapshot(data io.ReadCloser, compressionPolicy string, compressionLevel int) (io.ReadCloser, error) {
 	pReader, pWriter := io.Pipe()
 
-	var gWriter io.WriteCloser
+	var (
+		gWriter io.WriteCloser
+		err     error
+	)
 	logger := logrus.New().WithField("actor", "compressor")
 	logger.Infof("start compressing the snapshot using %v Compression Policy", compressionPolicy)
 
 	switch compressionPolicy {
 	case GzipCompressionPolicy:
-		gWriter = gzip.NewWriter(pWriter)
+		gWriter, err = gzip.NewWriterLevel(pWriter, flateCompressionLevel(compressionLevel))
 
 	case LzwCompressionPolicy:
 		gWriter = lzw.NewWriter(pWriter, lzw.LSB, LzwLiteralWidth)
 
 	case ZlibCompressionPolicy:
-		gWriter = zlib.NewWriter(pWriter)
+		gWriter, err = zlib.NewWriterLevel(pWriter, flateCompressionLevel(compressionLevel))
+
+	case ZstdCompressionPolicy:
+		var opts []zstd.EOption
+		if compressionLevel != DefaultCompressionLevel {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(compressionLevel)))
+		}
+		gWriter, err = zstd.NewWriter(pWriter, opts...)
 
 	// It is actually unreachable but just to be on safe side:
 	// for unsupported CompressionPolicy return the error
@@ -39,6 +51,10 @@ func CompressSnapshot(data io.ReadCloser, compressionPolicy string) (io.ReadClos
 		return nil, fmt.Errorf("unsupported Compression Policy")
 
 	}
+	if err != nil {
+		_ = pWriter.Close()
+		return nil, fmt.Errorf("unable to create writer for %v Compression Policy: %v", compressionPolicy, err)
+	}
 
 	go func() {
 		var err error
@@ -84,6 +100,14 @@ func DecompressSnapshot(data io.ReadCloser, compressionPolicy string) (io.ReadCl
 	case LzwCompressionPolicy:
 		deCompressedData = lzw.NewReader(data, lzw.LSB, LzwLiteralWidth)
 
+	case ZstdCompressionPolicy:
+		decoder, err := zstd.NewReader(data)
+		if err != nil {
+			logger.Errorf("unable to decompress: %v", err)
+			return data, err
+		}
+		deCompressedData = &zstdReadCloser{ReadCloser: decoder.IOReadCloser(), data: data}
+
 	// It is actually unreachable but just to be on safe side:
 	// for unsupported CompressionPolicy return the same data with error
 	default:
@@ -111,6 +135,9 @@ func GetCompressionSuffix(compressionEnabled bool, compressionPolicy string) (st
 	case GzipCompressionPolicy:
 		return GzipCompressionExtension, nil
 
+	case ZstdCompressionPolicy:
+		return ZstdCompressionExtension, nil
+
 	// unreachable but just to be on safe side:
 	// for unsupported CompressionPolicy return the error
 	default:
@@ -133,6 +160,9 @@ func IsSnapshotCompressed(compressionSuffix string) (bool, string, error) {
 	case LzwCompressionExtension:
 		return true, LzwCompressionPolicy, nil
 
+	case ZstdCompressionExtension:
+		return true, ZstdCompressionPolicy, nil
+
 	case UnCompressSnapshotExtension:
 		return false, "", nil
 
@@ -142,3 +172,24 @@ func IsSnapshotCompressed(compressionSuffix string) (bool, string, error) {
 		return false, "", fmt.Errorf("unsupported Compression Policy")
 	}
 }
+
+// flateCompressionLevel maps the compression level to the level of flate based compression algorithms,
+// as their level 0 means no compression at all.
+func flateCompressionLevel(compressionLevel int) int {
+	if compressionLevel == DefaultCompressionLevel {
+		return gzip.DefaultCompression
+	}
+	return compressionLevel
+}
+
+// zstdReadCloser closes both the zstd decoder and the underlying compressed data.
+type zstdReadCloser struct {
+	io.ReadCloser
+	data io.ReadCloser
+}
+
+// Close closes the zstd decoder and the underlying compressed data.
+func (z *zstdReadCloser) Close() error {
+	_ = z.ReadCloser.Close()
+	return z.data.Close()
+}
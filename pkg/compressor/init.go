@@ -5,6 +5,7 @@
 package compressor
 
 import (
+	"compress/flate"
 	"fmt"
 
 	flag "github.com/spf13/pflag"
@@ -15,6 +16,7 @@ func NewCompressorConfig() *CompressionConfig {
 	return &CompressionConfig{
 		Enabled:           DefaultCompression,
 		CompressionPolicy: DefaultCompressionPolicy,
+		CompressionLevel:  DefaultCompressionLevel,
 	}
 }
 
@@ -23,6 +25,7 @@ func (c *CompressionConfig) AddFlags(fs *flag.FlagSet) {
 
 	fs.BoolVar(&c.Enabled, "compress-snapshots", c.Enabled, "whether to compress the snapshots or not")
 	fs.StringVar(&c.CompressionPolicy, "compression-policy", c.CompressionPolicy, "Policy for compressing the snapshots")
+	fs.IntVar(&c.CompressionLevel, "compression-level", c.CompressionLevel, "Level for compressing the snapshots: [1-9] for gzip and zlib, [1-22] for zstd, 0 uses the default level of the compression policy")
 }
 
 // Validate validates the compression Config.
@@ -33,11 +36,26 @@ func (c *CompressionConfig) Validate() error {
 		return nil
 	}
 
-	for _, policy := range []string{GzipCompressionPolicy, ZlibCompressionPolicy, LzwCompressionPolicy} {
-		if c.CompressionPolicy == policy {
-			return nil
+	if c.CompressionLevel < 0 {
+		return fmt.Errorf("compression level must not be negative")
+	}
+
+	switch c.CompressionPolicy {
+	case GzipCompressionPolicy, ZlibCompressionPolicy:
+		if c.CompressionLevel > flate.BestCompression {
+			return fmt.Errorf("compression level %d is not supported by %s Compression Policy", c.CompressionLevel, c.CompressionPolicy)
+		}
+	case ZstdCompressionPolicy:
+		if c.CompressionLevel > MaxZstdCompressionLevel {
+			return fmt.Errorf("compression level %d is not supported by %s Compression Policy", c.CompressionLevel, c.CompressionPolicy)
+		}
+	case LzwCompressionPolicy:
+		if c.CompressionLevel != DefaultCompressionLevel {
+			return fmt.Errorf("compression level is not supported by %s Compression Policy", c.CompressionPolicy)
 		}
+	default:
+		return fmt.Errorf("%s: Compression Policy is not supported", c.CompressionPolicy)
 	}
-	return fmt.Errorf("%s: Compression Policy is not supported", c.CompressionPolicy)
+	return nil
 
 }
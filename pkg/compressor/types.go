@@ -12,11 +12,15 @@ const (
 	LzwCompressionPolicy = "lzw"
 	// ZlibCompressionPolicy is constant for zlib compression algorithm.
 	ZlibCompressionPolicy = "zlib"
+	// ZstdCompressionPolicy is constant for zstd compression algorithm.
+	ZstdCompressionPolicy = "zstd"
 
 	// DefaultCompression is constant used for whether to compress the snapshots or not.
 	DefaultCompression = true
 	// DefaultCompressionPolicy is constant for default compression algorithm(only if compression is enabled).
 	DefaultCompressionPolicy = "gzip"
+	// DefaultCompressionLevel is constant for using the default level of the compression algorithm.
+	DefaultCompressionLevel = 0
 
 	// UnCompressSnapshotExtension is used for snapshot suffix when compression is not enabled.
 	UnCompressSnapshotExtension = ""
@@ -26,14 +30,22 @@ const (
 	LzwCompressionExtension = ".Z"
 	// ZlibCompressionExtension is used for snapshot suffix when compressionPolicy is zlib.
 	ZlibCompressionExtension = ".zlib"
+	// ZstdCompressionExtension is used for snapshot suffix when compressionPolicy is zstd.
+	ZstdCompressionExtension = ".zst"
 	// Reference: https://en.wikipedia.org/wiki/List_of_archive_formats
 
 	// LzwLiteralWidth is constant used as literal Width in lzw compressionPolicy.
 	LzwLiteralWidth = 8 //[2,8]
+
+	// MaxZstdCompressionLevel is the highest compression level of zstd compressionPolicy.
+	MaxZstdCompressionLevel = 22
 )
 
 // CompressionConfig holds the compression configuration.
 type CompressionConfig struct {
 	CompressionPolicy string `json:"policy,omitempty"`
 	Enabled           bool   `json:"enabled"`
+	// CompressionLevel is the level used by the compression algorithm, 0 uses its default level.
+	// It is supported by gzip, zlib and zstd compressionPolicy.
+	CompressionLevel int `json:"level,omitempty"`
 }
@@ -94,15 +94,29 @@ waitLoop:
 
 // DefragDataPeriodically defragments the data directory of each etcd member.
 func DefragDataPeriodically(ctx context.Context, etcdConnectionConfig *brtypes.EtcdConnectionConfig, defragmentationSchedule cron.Schedule, callback CallbackFunc, logger *logrus.Entry) {
-	defragmentorJob := NewDefragmentorJob(ctx, etcdConnectionConfig, logger, callback)
-	// TODO: Sync logrus logger to cron logger
-	jobRunner := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
-	jobRunner.Schedule(defragmentationSchedule, defragmentorJob)
+	DefragDataPeriodicallyWithScheduleUpdates(ctx, etcdConnectionConfig, defragmentationSchedule, nil, callback, logger)
+}
 
+// DefragDataPeriodicallyWithScheduleUpdates defragments the data directory of each etcd member with the given schedule,
+// which is replaced by the schedules received from the given channel.
+func DefragDataPeriodicallyWithScheduleUpdates(ctx context.Context, etcdConnectionConfig *brtypes.EtcdConnectionConfig, defragmentationSchedule cron.Schedule, scheduleCh <-chan cron.Schedule, callback CallbackFunc, logger *logrus.Entry) {
+	// TODO: Sync logrus logger to cron logger
+	// the job is wrapped once, so that it is skipped while still running even if the schedule has been replaced
+	defragmentorJob := cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger)).Then(NewDefragmentorJob(ctx, etcdConnectionConfig, logger, callback))
+	jobRunner := cron.New()
+	entryID := jobRunner.Schedule(defragmentationSchedule, defragmentorJob)
 	jobRunner.Start()
-
-	<-ctx.Done()
-	logger.Info("Closing defragmentor.")
-	jobRunnerCtx := jobRunner.Stop()
-	<-jobRunnerCtx.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Closing defragmentor.")
+			jobRunnerCtx := jobRunner.Stop()
+			<-jobRunnerCtx.Done()
+			return
+		case schedule := <-scheduleCh:
+			jobRunner.Remove(entryID)
+			entryID = jobRunner.Schedule(schedule, defragmentorJob)
+			logger.Infof("Updated defragmentation schedule, next defragmentation at %s.", schedule.Next(time.Now()))
+		}
+	}
 }
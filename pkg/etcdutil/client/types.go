@@ -7,6 +7,7 @@ package client
 import (
 	"io"
 
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -28,10 +29,18 @@ type MaintenanceCloser interface {
 	io.Closer
 }
 
+// LeaseCloser adds io.Closer to the etcd lease API. Unlike the clientv3.Lease interface, it allows to grant leases with
+// a given ID.
+type LeaseCloser interface {
+	etcdserverpb.LeaseClient
+	io.Closer
+}
+
 // Factory interface defines a way to construct and close the client objects for different ETCD API.
 type Factory interface {
 	NewCluster() (ClusterCloser, error)
 	NewKV() (KVCloser, error)
 	NewMaintenance() (MaintenanceCloser, error)
+	NewLease() (LeaseCloser, error)
 	NewWatcher() (clientv3.Watcher, error) // clientv3.Watcher already supports io.Closer
 }
@@ -24,6 +24,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -73,6 +74,20 @@ func (f *factoryImpl) NewWatcher() (clientv3.Watcher, error) {
 	return f.NewClient()
 }
 
+func (f *factoryImpl) NewLease() (client.LeaseCloser, error) {
+	c, err := f.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return &leaseCloser{LeaseClient: clientv3.RetryLeaseClient(c), Closer: c}, nil
+}
+
+// leaseCloser implements the client.LeaseCloser interface with the lease API of an etcd client.
+type leaseCloser struct {
+	etcdserverpb.LeaseClient
+	io.Closer
+}
+
 // NewClientFactory returns the Factory using the supplied EtcdConnectionConfig.
 func NewClientFactory(fn brtypes.NewClientFactoryFunc, cfg brtypes.EtcdConnectionConfig) client.Factory {
 	if fn == nil {
@@ -298,14 +313,14 @@ func TakeAndSaveFullSnapshot(ctx context.Context, client client.MaintenanceClose
 
 	// check the integrity of full snapshot before compression and upload to object store.
 	// for more info: https://github.com/gardener/etcd-backup-restore/issues/778
-	if snapshotData, err = checkFullSnapshotIntegrity(rc, snapshotTempDBPath, logger); err != nil {
+	if snapshotData, err = CheckFullSnapshotIntegrity(rc, snapshotTempDBPath, logger); err != nil {
 		logger.Errorf("verification of full snapshot SHA256 hash has failed: %v", err)
 		return nil, err
 	}
 	logger.Info("full snapshot SHA256 hash has been successfully verified.")
 
 	if cc.Enabled {
-		snapshotData, err = compressor.CompressSnapshot(snapshotData, cc.CompressionPolicy)
+		snapshotData, err = compressor.CompressSnapshot(snapshotData, cc.CompressionPolicy, cc.CompressionLevel)
 		if err != nil {
 			return nil, fmt.Errorf("unable to obtain reader for compressed file: %v", err)
 		}
@@ -322,9 +337,9 @@ func TakeAndSaveFullSnapshot(ctx context.Context, client client.MaintenanceClose
 	return snapshot, nil
 }
 
-// checkFullSnapshotIntegrity verifies the integrity of the full snapshot by comparing
+// CheckFullSnapshotIntegrity verifies the integrity of the full snapshot by comparing
 // the appended SHA256 hash of the full snapshot with the calculated SHA256 hash of the full snapshot data.
-func checkFullSnapshotIntegrity(snapshotData io.ReadCloser, snapTempDBFilePath string, logger *logrus.Entry) (io.ReadCloser, error) {
+func CheckFullSnapshotIntegrity(snapshotData io.ReadCloser, snapTempDBFilePath string, logger *logrus.Entry) (io.ReadCloser, error) {
 	logger.Info("checking the full snapshot integrity with the help of SHA256")
 
 	// If previous temp db file already exist then remove it.
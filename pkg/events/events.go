@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"sync"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// Type is the type of a backup event.
+type Type string
+
+const (
+	// TypeSnapshotTaken is the type of the event published when a snapshot has been saved to the store.
+	TypeSnapshotTaken Type = "SnapshotTaken"
+	// TypeSnapshotFailed is the type of the event published when taking a snapshot has failed.
+	TypeSnapshotFailed Type = "SnapshotFailed"
+	// TypeSnapshotDeleted is the type of the event published when a snapshot has been garbage collected.
+	TypeSnapshotDeleted Type = "SnapshotDeleted"
+	// TypeInitializationStatusChanged is the type of the event published when the status of the initialization
+	// of the etcd data directory has changed.
+	TypeInitializationStatusChanged Type = "InitializationStatusChanged"
+	// TypeLeadershipChanged is the type of the event published when the member has started or stopped leading the backups.
+	TypeLeadershipChanged Type = "LeadershipChanged"
+	// TypeSnapshottingPaused is the type of the event published when the scheduled snapshots and the garbage
+	// collection have been paused, or when the deadline of the pause has been changed.
+	TypeSnapshottingPaused Type = "SnapshottingPaused"
+	// TypeSnapshottingResumed is the type of the event published when the scheduled snapshots and the garbage
+	// collection have been resumed.
+	TypeSnapshottingResumed Type = "SnapshottingResumed"
+)
+
+// Event is an event of the backup-restore server.
+type Event struct {
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+	// Snapshot is the snapshot the event is about, if any. For a failed snapshot, only its kind is set.
+	Snapshot *brtypes.Snapshot `json:"snapshot,omitempty"`
+	// Message describes the event, e.g. the reason of a failure or the new initialization status.
+	Message string `json:"message,omitempty"`
+}
+
+// Broadcaster publishes events to all of its subscribers. A nil Broadcaster discards all events.
+type Broadcaster struct {
+	mutex       sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// NewBroadcaster returns a new Broadcaster without subscribers.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: make(map[chan Event]struct{})}
+}
+
+// Publish publishes the given event to all subscribers, setting its time to the current time if unset.
+// Publish never blocks. A subscriber which is too slow to receive the event is unsubscribed, i.e. its
+// channel is closed, so that it notices that it has missed events.
+func (b *Broadcaster) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events published from now on, which buffers up to bufferSize events,
+// along with the function to cancel the subscription. The channel is closed once the subscription has ended.
+func (b *Broadcaster) Subscribe(bufferSize int) (<-chan Event, func()) {
+	ch := make(chan Event, bufferSize)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.subscribers[ch] = struct{}{}
+	return ch, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
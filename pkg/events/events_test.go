@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Broadcaster", func() {
+	var broadcaster *events.Broadcaster
+
+	BeforeEach(func() {
+		broadcaster = events.NewBroadcaster()
+	})
+
+	It("should publish events to all subscribers", func() {
+		first, cancelFirst := broadcaster.Subscribe(1)
+		defer cancelFirst()
+		second, cancelSecond := broadcaster.Subscribe(1)
+		defer cancelSecond()
+
+		broadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken})
+
+		for _, ch := range []<-chan events.Event{first, second} {
+			var event events.Event
+			Eventually(ch).Should(Receive(&event))
+			Expect(event.Type).To(Equal(events.TypeSnapshotTaken))
+			Expect(event.Time).NotTo(BeZero())
+		}
+	})
+
+	It("should not publish events to cancelled subscriptions", func() {
+		ch, cancel := broadcaster.Subscribe(1)
+		cancel()
+		cancel()
+
+		broadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken})
+		Expect(ch).To(BeClosed())
+	})
+
+	It("should end the subscription of a subscriber which is too slow to receive events", func() {
+		slow, cancelSlow := broadcaster.Subscribe(1)
+		defer cancelSlow()
+		fast, cancelFast := broadcaster.Subscribe(2)
+		defer cancelFast()
+
+		broadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken})
+		broadcaster.Publish(events.Event{Type: events.TypeSnapshotDeleted})
+
+		Expect(slow).To(Receive())
+		Expect(slow).To(BeClosed())
+		Expect(fast).To(Receive())
+		Expect(fast).To(Receive())
+		Expect(fast).NotTo(BeClosed())
+	})
+
+	It("should discard events if it is nil", func() {
+		var nilBroadcaster *events.Broadcaster
+		Expect(func() { nilBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken}) }).NotTo(Panic())
+	})
+})
@@ -160,6 +160,30 @@ var _ = Describe("Heartbeat", func() {
 				err = k8sClientset.Delete(context.TODO(), lease)
 				Expect(err).ShouldNot(HaveOccurred())
 			})
+			It("Should record the outcome of the full snapshot lease renewals", func() {
+				fullSnap := &brtypes.Snapshot{
+					Kind:          brtypes.SnapshotKindFull,
+					CreatedOn:     time.Now(),
+					StartRevision: 0,
+					LastRevision:  980,
+				}
+				fullSnap.GenerateSnapshotName()
+				Expect(k8sClientset.Create(context.TODO(), lease)).To(Succeed())
+
+				Expect(heartbeat.FullSnapshotCaseLeaseUpdate(context.TODO(), logger, fullSnap, k8sClientset, brtypes.DefaultFullSnapshotLeaseName, time.Now())).To(Succeed())
+				renewal := heartbeat.LeaseRenewals()[heartbeat.LeaseKindFullSnapshot]
+				Expect(renewal.LastError).To(BeEmpty())
+				Expect(renewal.LastSuccess).To(Equal(renewal.LastAttempt))
+				lastSuccess := renewal.LastSuccess
+
+				Expect(heartbeat.FullSnapshotCaseLeaseUpdate(context.TODO(), logger, nil, k8sClientset, brtypes.DefaultFullSnapshotLeaseName, time.Now())).NotTo(Succeed())
+				renewal = heartbeat.LeaseRenewals()[heartbeat.LeaseKindFullSnapshot]
+				Expect(renewal.LastError).NotTo(BeEmpty())
+				Expect(renewal.LastSuccess).To(Equal(lastSuccess))
+				Expect(renewal.LastAttempt.Before(lastSuccess)).To(BeFalse())
+
+				Expect(k8sClientset.Delete(context.TODO(), lease)).To(Succeed())
+			})
 		})
 		Context("With valid delta snapshot lease present", func() {
 			BeforeEach(func() {
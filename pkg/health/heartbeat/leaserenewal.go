@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package heartbeat
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	// LeaseKindMember is the kind of the member lease renewed periodically by each member.
+	LeaseKindMember = "member"
+	// LeaseKindFullSnapshot is the kind of the full snapshot lease renewed after each full snapshot.
+	LeaseKindFullSnapshot = "fullSnapshot"
+	// LeaseKindDeltaSnapshot is the kind of the delta snapshot lease renewed after each delta snapshot.
+	LeaseKindDeltaSnapshot = "deltaSnapshot"
+)
+
+// LeaseRenewalStatus is the outcome of the renewals of a lease.
+type LeaseRenewalStatus struct {
+	// LastAttempt is the time of the latest renewal.
+	LastAttempt time.Time
+	// LastSuccess is the time of the latest successful renewal.
+	LastSuccess time.Time
+	// LastError is the error of the latest renewal, if it has failed.
+	LastError string
+}
+
+var (
+	leaseRenewalsMutex sync.Mutex
+	leaseRenewals      = map[string]LeaseRenewalStatus{}
+)
+
+// recordLeaseRenewal records the outcome of renewing the lease of the given kind.
+func recordLeaseRenewal(kind string, err error) {
+	leaseRenewalsMutex.Lock()
+	defer leaseRenewalsMutex.Unlock()
+	status := leaseRenewals[kind]
+	status.LastAttempt = time.Now().UTC()
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	} else {
+		status.LastSuccess = status.LastAttempt
+	}
+	leaseRenewals[kind] = status
+}
+
+// LeaseRenewals returns the outcome of the renewals of the leases by this process, keyed by the kind of the lease.
+// Leases which have not been renewed yet are left out.
+func LeaseRenewals() map[string]LeaseRenewalStatus {
+	leaseRenewalsMutex.Lock()
+	defer leaseRenewalsMutex.Unlock()
+	return maps.Clone(leaseRenewals)
+}
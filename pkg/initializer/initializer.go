@@ -6,6 +6,7 @@ package initializer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -186,8 +187,29 @@ func (e *EtcdInitializer) restoreCorruptData() (bool, error) {
 		err = fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
 		return false, err
 	}
-	logger.Info("Finding latest set of snapshot to recover from...")
-	baseSnap, deltaSnapList, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+	restoreTarget, err := getRestoreTarget(tempRestoreOptions.Config)
+	if err != nil {
+		return false, err
+	}
+	if len(restoreTarget) != 0 {
+		restoredTarget, err := os.ReadFile(restoreTargetMarkerPath(dataDir)) // #nosec G304 -- this is a trusted marker file written to by etcdbr.
+		if err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("failed to read the restore target marker: %v", err)
+		}
+		if string(restoredTarget) == restoreTarget {
+			// the target has already been restored, restoring it again would discard all writes made since.
+			logger.Infof("Data directory has already been restored up to the target, will restore the latest backups instead.")
+			tempRestoreOptions.Config.TargetRevision = 0
+			tempRestoreOptions.Config.TargetTime = ""
+			tempRestoreOptions.Config.TargetSnapshotLabels = ""
+		}
+	}
+	if tempRestoreOptions.Config.IsPointInTimeRestore() {
+		logger.Infof("Finding set of snapshot to recover up to target revision %d, target time %q and target snapshot labels %q...", tempRestoreOptions.Config.TargetRevision, tempRestoreOptions.Config.TargetTime, tempRestoreOptions.Config.TargetSnapshotLabels)
+	} else {
+		logger.Info("Finding latest set of snapshot to recover from...")
+	}
+	baseSnap, deltaSnapList, err := miscellaneous.GetFullSnapshotAndDeltaSnapListForRestore(store, tempRestoreOptions.Config)
 	if err != nil {
 		logger.Errorf("failed to get latest set of snapshot: %v", err)
 		return false, err
@@ -199,6 +221,18 @@ func (e *EtcdInitializer) restoreCorruptData() (bool, error) {
 		return e.restoreWithEmptySnapstore()
 	}
 
+	if tempRestoreOptions.Config.IsPointInTimeRestore() && tempRestoreOptions.Config.RevisionBump > 0 {
+		// the revision is bumped past the latest backed up revision, so that the data directory is not considered
+		// inconsistent with the backups taken before the restoration and the snapshots taken after it succeed them.
+		latestRevision, err := getLatestSnapshotRevision(store)
+		if err != nil {
+			return false, err
+		}
+		if latestRevision > baseSnap.LastRevision {
+			tempRestoreOptions.Config.RevisionBump += uint64(latestRevision - baseSnap.LastRevision) // #nosec G115 -- the difference is positive.
+		}
+	}
+
 	tempRestoreOptions.BaseSnapshot = baseSnap
 	tempRestoreOptions.DeltaSnapList = deltaSnapList
 	tempRestoreOptions.Config.DataDir = fmt.Sprintf("%s.%s", tempRestoreOptions.Config.DataDir, "part")
@@ -212,7 +246,7 @@ func (e *EtcdInitializer) restoreCorruptData() (bool, error) {
 		return false, err
 	}
 	m := member.NewMemberControl(e.Config.EtcdConnectionConfig)
-	if err := rs.RestoreAndStopEtcd(tempRestoreOptions, m); err != nil {
+	if err := rs.RestoreAndStopEtcd(context.TODO(), tempRestoreOptions, m); err != nil {
 		err = fmt.Errorf("failed to restore snapshot: %v", err)
 		return false, err
 	}
@@ -220,10 +254,56 @@ func (e *EtcdInitializer) restoreCorruptData() (bool, error) {
 	if err := e.removeContents(dataDir); err != nil {
 		return false, fmt.Errorf("failed to remove corrupt contents with restored snapshot: %v", err)
 	}
+	if len(restoreTarget) != 0 {
+		if err := os.WriteFile(restoreTargetMarkerPath(dataDir), []byte(restoreTarget), 0600); err != nil {
+			return false, fmt.Errorf("failed to write the restore target marker: %v", err)
+		}
+	} else if err := os.Remove(restoreTargetMarkerPath(dataDir)); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove the restore target marker: %v", err)
+	}
 	logger.Infoln("Successfully restored the etcd data directory.")
 	return true, nil
 }
 
+// restoreTargetMarkerPath returns the path of the marker next to the given data directory, which records the target up
+// to which the data directory has been restored. The initializer restores up to a target only once, as the target
+// remains configured for the following initializations.
+func restoreTargetMarkerPath(dataDir string) string {
+	return fmt.Sprintf("%s.%s", dataDir, "restore-target")
+}
+
+// getRestoreTarget returns the target of the restoration as recorded in the restore target marker.
+// It returns an empty string if the restoration is not bounded by a target.
+func getRestoreTarget(config *brtypes.RestorationConfig) (string, error) {
+	if !config.IsPointInTimeRestore() {
+		return "", nil
+	}
+	target, err := json.Marshal(struct {
+		TargetRevision       int64  `json:"targetRevision,omitempty"`
+		TargetTime           string `json:"targetTime,omitempty"`
+		TargetSnapshotLabels string `json:"targetSnapshotLabels,omitempty"`
+	}{config.TargetRevision, config.TargetTime, config.TargetSnapshotLabels})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the restore target: %v", err)
+	}
+	return string(target), nil
+}
+
+// getLatestSnapshotRevision returns the last revision of the latest snapshot in the given store.
+func getLatestSnapshotRevision(store brtypes.SnapStore) (int64, error) {
+	fullSnap, deltaSnapList, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the latest snapshot: %v", err)
+	}
+	if len(deltaSnapList) != 0 {
+		return deltaSnapList[len(deltaSnapList)-1].LastRevision, nil
+	}
+	if fullSnap != nil {
+		return fullSnap.LastRevision, nil
+	}
+	return 0, nil
+}
+
 // restoreWithEmptySnapstore removes the data directory as
 // part of restoration process for empty snapstore case.
 // It returns true if data directory removal is successful,
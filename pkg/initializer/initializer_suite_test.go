@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package initializer_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+	"github.com/gardener/etcd-backup-restore/test/utils"
+
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	outputDir = "../../test/output"
+	etcdDir   = outputDir + "/default.etcd"
+	// snapstoreDir is the container of the local snapstore, which is resolved within the home directory of the suite.
+	snapstoreDir = "snapshotter.bkp"
+)
+
+var (
+	testCtx = context.Background()
+	logger  = logrus.New().WithField("suite", "initializer")
+	err     error
+	keyTo   int
+	endRev  int64
+)
+
+func TestInitializer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Initializer Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	err = os.RemoveAll(outputDir)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	etcd, err := utils.StartEmbeddedEtcd(testCtx, etcdDir, logger, utils.DefaultEtcdName, "")
+	Expect(err).ShouldNot(HaveOccurred())
+	endpoints := []string{etcd.Clients[0].Addr().String()}
+	defer func() {
+		etcd.Server.Stop()
+		etcd.Close()
+	}()
+
+	populatorCtx, cancelPopulator := context.WithTimeout(testCtx, 8*time.Second)
+	defer cancelPopulator()
+	resp := &utils.EtcdDataPopulationResponse{}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go utils.PopulateEtcdWithWaitGroup(populatorCtx, wg, logger, endpoints, "", "", resp)
+
+	deltaSnapshotPeriod := time.Second
+	ctx := utils.ContextWithWaitGroupFollwedByGracePeriod(populatorCtx, wg, deltaSnapshotPeriod+2*time.Second)
+
+	compressionConfig := compressor.NewCompressorConfig()
+	snapstoreConfig := brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+	err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, endpoints, "", "", ctx.Done(), true, compressionConfig)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	keyTo = resp.KeyTo
+	endRev = resp.EndRevision
+	return nil
+}, func(_ []byte) {})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	err = os.RemoveAll(outputDir)
+	Expect(err).ShouldNot(HaveOccurred())
+})
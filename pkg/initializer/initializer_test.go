@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package initializer_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/gardener/etcd-backup-restore/pkg/initializer"
+	"github.com/gardener/etcd-backup-restore/pkg/initializer/validator"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+	"github.com/gardener/etcd-backup-restore/test/utils"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	podName      = "etcd-test-0"
+	podNamespace = "test"
+	revisionBump = 1000
+)
+
+var _ = Describe("Initializer", func() {
+	var (
+		restoredDir     string
+		restoreOptions  *brtypes.RestoreOptions
+		snapstoreConfig *brtypes.SnapstoreConfig
+	)
+
+	BeforeEach(func() {
+		restoredDir = outputDir + "/restored.etcd"
+		Expect(os.RemoveAll(restoredDir)).To(Succeed())
+
+		// the member control of the initializer updates the peer URLs of the restored member from the etcd config.
+		etcdConfigFile := outputDir + "/etcd.conf.yaml"
+		Expect(os.WriteFile(etcdConfigFile, []byte(`name: `+podName+`
+initial-advertise-peer-urls:
+  `+podName+`:
+    - http://localhost:2380
+initial-cluster: `+podName+`=http://localhost:2380
+`), 0600)).To(Succeed())
+		Expect(os.Setenv("ETCD_CONF", etcdConfigFile)).To(Succeed())
+		Expect(os.Setenv("POD_NAME", podName)).To(Succeed())
+		Expect(os.Setenv("POD_NAMESPACE", podNamespace)).To(Succeed())
+
+		config := brtypes.NewRestorationConfig()
+		config.DataDir = restoredDir
+		config.TempSnapshotsDir = outputDir + "/restoration.temp"
+		clusterURLs, err := types.NewURLsMap(config.InitialCluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		peerURLs, err := types.NewURLs(config.InitialAdvertisePeerURLs)
+		Expect(err).ShouldNot(HaveOccurred())
+		restoreOptions = &brtypes.RestoreOptions{
+			Config:      config,
+			ClusterURLs: clusterURLs,
+			PeerURLs:    peerURLs,
+		}
+		snapstoreConfig = &brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local", TempDir: outputDir + "/snapstore.temp"}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ETCD_CONF")).To(Succeed())
+		Expect(os.Unsetenv("POD_NAME")).To(Succeed())
+		Expect(os.Unsetenv("POD_NAMESPACE")).To(Succeed())
+	})
+
+	initialize := func() {
+		etcdInitializer, err := initializer.NewInitializer(restoreOptions, snapstoreConfig, brtypes.NewEtcdConnectionConfig(), logger.Logger)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(etcdInitializer.Initialize(validator.Full)).To(Succeed())
+	}
+
+	// withRestoredEtcd starts an etcd on the restored data directory and calls the given function with a client of it.
+	withRestoredEtcd := func(f func(cli *clientv3.Client)) {
+		etcd, err := utils.StartEmbeddedEtcd(testCtx, restoredDir, logger, restoreOptions.Config.Name, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		defer func() {
+			etcd.Server.Stop()
+			etcd.Close()
+		}()
+		cli, err := clientv3.New(clientv3.Config{Endpoints: []string{etcd.Clients[0].Addr().String()}})
+		Expect(err).ShouldNot(HaveOccurred())
+		defer cli.Close()
+		f(cli)
+	}
+
+	Context("with a target revision", func() {
+		It("should restore up to the target only once", func() {
+			targetRevision := endRev / 2
+			lastKey := keyTo
+			if lastKey%10 == 0 {
+				// every 10th key is deleted by the populator.
+				lastKey--
+			}
+			restoreOptions.Config.TargetRevision = targetRevision
+			restoreOptions.Config.RevisionBump = revisionBump
+
+			By("restoring the data directory up to the target")
+			initialize()
+			withRestoredEtcd(func(cli *clientv3.Client) {
+				resp, err := cli.Get(context.TODO(), utils.KeyPrefix+strconv.Itoa(lastKey))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Kvs).To(BeEmpty())
+				// the revision is bumped past the latest backed up revision.
+				Expect(resp.Header.Revision).To(BeNumerically(">=", endRev+revisionBump))
+
+				_, err = cli.Put(context.TODO(), "after-restoration", "value")
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+
+			By("restarting the initializer")
+			initialize()
+			withRestoredEtcd(func(cli *clientv3.Client) {
+				resp, err := cli.Get(context.TODO(), "after-restoration")
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Kvs).To(HaveLen(1))
+			})
+
+			By("restarting the initializer without a data directory")
+			Expect(os.RemoveAll(restoredDir)).To(Succeed())
+			initialize()
+			withRestoredEtcd(func(cli *clientv3.Client) {
+				resp, err := cli.Get(context.TODO(), utils.KeyPrefix+strconv.Itoa(lastKey))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Kvs).To(HaveLen(1))
+			})
+		})
+	})
+})
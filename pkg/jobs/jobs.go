@@ -0,0 +1,308 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// MaxQueuedJobs is the maximum number of jobs waiting to be run.
+	MaxQueuedJobs = 10
+	// MaxFinishedJobs is the maximum number of finished jobs kept for their callers to fetch the result.
+	MaxFinishedJobs = 50
+)
+
+var (
+	// ErrJobNotFound is the error returned when there is no job with the requested ID.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobFinished is the error returned when cancelling a job which has already finished.
+	ErrJobFinished = errors.New("job has already finished")
+	// ErrQueueFull is the error returned when submitting a job while MaxQueuedJobs jobs are waiting to be run.
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// Type is the type of a job.
+type Type string
+
+const (
+	// TypeRestore is the type of jobs which restore the backups into a directory.
+	TypeRestore Type = "restore"
+	// TypeCompaction is the type of jobs which compact the backups into a new full snapshot.
+	TypeCompaction Type = "compaction"
+)
+
+// State is the state of a job.
+type State string
+
+const (
+	// StatePending is the state of a job waiting to be run.
+	StatePending State = "Pending"
+	// StateRunning is the state of a running job.
+	StateRunning State = "Running"
+	// StateSucceeded is the state of a job which has finished successfully.
+	StateSucceeded State = "Succeeded"
+	// StateFailed is the state of a job which has failed.
+	StateFailed State = "Failed"
+	// StateCancelled is the state of a job which has been cancelled.
+	StateCancelled State = "Cancelled"
+)
+
+// IsFinished returns true if a job in the state will not change its state anymore.
+func (s State) IsFinished() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCancelled
+}
+
+// Job is the status of a job.
+type Job struct {
+	ID         string                  `json:"id"`
+	Type       Type                    `json:"type"`
+	State      State                   `json:"state"`
+	CreatedOn  time.Time               `json:"createdOn"`
+	StartedOn  *time.Time              `json:"startedOn,omitempty"`
+	FinishedOn *time.Time              `json:"finishedOn,omitempty"`
+	Progress   brtypes.RestoreProgress `json:"progress"`
+	// Result is the result of a job which has succeeded.
+	Result *Result `json:"result,omitempty"`
+	// Error is the error of a job which has failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Result is the result of a job.
+type Result struct {
+	// DataDir is the directory the backups were restored into by a restore job.
+	DataDir string `json:"dataDir,omitempty"`
+	// Snapshot is the full snapshot taken by a compaction job.
+	Snapshot *brtypes.Snapshot `json:"snapshot,omitempty"`
+	// Revision is the revision of the restored or compacted data.
+	Revision int64 `json:"revision"`
+}
+
+// RunFunc runs a job, reporting its progress to the given function. The job is to be aborted once the context is done.
+type RunFunc func(ctx context.Context, onProgress brtypes.RestoreProgressFunc) (*Result, error)
+
+// job is a submitted job along with the function running it.
+type job struct {
+	Job
+	run             RunFunc
+	cancel          context.CancelFunc
+	cancelRequested bool
+}
+
+// Manager runs submitted jobs one after the other, and keeps their status for their callers to track them.
+type Manager struct {
+	logger *logrus.Entry
+	mutex  sync.Mutex
+	// jobs are the pending, running and finished jobs in the order of their submission.
+	jobs  []*job
+	queue chan *job
+}
+
+// NewManager returns a job manager. Submitted jobs are only run once Run has been called.
+func NewManager(logger *logrus.Entry) *Manager {
+	return &Manager{
+		logger: logger.WithField("actor", "job-manager"),
+		queue:  make(chan *job, MaxQueuedJobs),
+	}
+}
+
+// Run runs the submitted jobs one after the other until the context is done. Once the context is done, the running
+// job is aborted and the pending jobs are cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			m.cancelPendingJobs()
+			return
+		case j := <-m.queue:
+			m.runJob(ctx, j)
+		}
+	}
+}
+
+// Submit submits a job of the given type, which is run by the given function. It returns the status of the job.
+func (m *Manager) Submit(jobType Type, run RunFunc) (*Job, error) {
+	id, err := newJobID(jobType)
+	if err != nil {
+		return nil, err
+	}
+	j := &job{
+		Job: Job{
+			ID:        id,
+			Type:      jobType,
+			State:     StatePending,
+			CreatedOn: time.Now().UTC(),
+		},
+		run: run,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	select {
+	case m.queue <- j:
+	default:
+		return nil, ErrQueueFull
+	}
+	m.jobs = append(m.jobs, j)
+	m.pruneFinishedJobs()
+	m.logger.Infof("Submitted %s job %s", jobType, id)
+	status := j.Job
+	return &status, nil
+}
+
+// Get returns the status of the job with the given ID.
+func (m *Manager) Get(id string) (*Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	j := m.find(id)
+	if j == nil {
+		return nil, ErrJobNotFound
+	}
+	status := j.Job
+	return &status, nil
+}
+
+// List returns the status of all jobs in the order of their submission.
+func (m *Manager) List() []*Job {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	jobs := make([]*Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		status := j.Job
+		jobs = append(jobs, &status)
+	}
+	return jobs
+}
+
+// Cancel cancels the job with the given ID. A pending job is cancelled right away, a running job once it has been
+// aborted. It returns the status of the job.
+func (m *Manager) Cancel(id string) (*Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	j := m.find(id)
+	if j == nil {
+		return nil, ErrJobNotFound
+	}
+	switch j.State {
+	case StatePending:
+		m.finish(j, StateCancelled)
+	case StateRunning:
+		j.cancelRequested = true
+		j.cancel()
+	default:
+		return nil, ErrJobFinished
+	}
+	m.logger.Infof("Cancelling %s job %s", j.Type, id)
+	status := j.Job
+	return &status, nil
+}
+
+// runJob runs the given job unless it has been cancelled while it was pending.
+func (m *Manager) runJob(ctx context.Context, j *job) {
+	m.mutex.Lock()
+	if j.State != StatePending {
+		m.mutex.Unlock()
+		return
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	startedOn := time.Now().UTC()
+	j.State = StateRunning
+	j.StartedOn = &startedOn
+	j.cancel = cancel
+	m.mutex.Unlock()
+
+	m.logger.Infof("Running %s job %s", j.Type, j.ID)
+	result, err := j.run(jobCtx, func(progress brtypes.RestoreProgress) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		j.Progress = progress
+	})
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	switch {
+	case j.cancelRequested:
+		m.finish(j, StateCancelled)
+		m.logger.Infof("Cancelled %s job %s", j.Type, j.ID)
+	case err != nil:
+		j.Error = err.Error()
+		m.finish(j, StateFailed)
+		m.logger.Errorf("Failed %s job %s: %v", j.Type, j.ID, err)
+	default:
+		j.Result = result
+		m.finish(j, StateSucceeded)
+		m.logger.Infof("Finished %s job %s", j.Type, j.ID)
+	}
+}
+
+// cancelPendingJobs cancels all pending jobs.
+func (m *Manager) cancelPendingJobs() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, j := range m.jobs {
+		if j.State == StatePending {
+			m.finish(j, StateCancelled)
+		}
+	}
+}
+
+// finish sets the final state of the given job. The caller must hold the mutex.
+func (m *Manager) finish(j *job, state State) {
+	finishedOn := time.Now().UTC()
+	j.State = state
+	j.FinishedOn = &finishedOn
+	j.cancel = nil
+	m.pruneFinishedJobs()
+}
+
+// pruneFinishedJobs removes the oldest finished jobs beyond MaxFinishedJobs. The caller must hold the mutex.
+func (m *Manager) pruneFinishedJobs() {
+	finished := 0
+	for _, j := range m.jobs {
+		if j.State.IsFinished() {
+			finished++
+		}
+	}
+	jobs := m.jobs[:0]
+	for _, j := range m.jobs {
+		if j.State.IsFinished() && finished > MaxFinishedJobs {
+			finished--
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	clear(m.jobs[len(jobs):])
+	m.jobs = jobs
+}
+
+// find returns the job with the given ID, or nil if there is none. The caller must hold the mutex.
+func (m *Manager) find(id string) *job {
+	for _, j := range m.jobs {
+		if j.ID == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// newJobID returns a random ID for a job of the given type.
+func newJobID(jobType Type) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate job ID: %v", err)
+	}
+	return fmt.Sprintf("%s-%s", jobType, hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		manager *jobs.Manager
+		ctx     context.Context
+		cancel  context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		manager = jobs.NewManager(logrus.NewEntry(logrus.New()))
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	// jobState returns a function polling the state of the job with the given ID.
+	jobState := func(id string) func() jobs.State {
+		return func() jobs.State {
+			job, err := manager.Get(id)
+			Expect(err).NotTo(HaveOccurred())
+			return job.State
+		}
+	}
+
+	// blockingJob returns a job which reports progress and blocks until it is released or aborted.
+	blockingJob := func(release <-chan struct{}) jobs.RunFunc {
+		return func(ctx context.Context, onProgress brtypes.RestoreProgressFunc) (*jobs.Result, error) {
+			onProgress(brtypes.RestoreProgress{SnapshotsTotal: 2, SnapshotsFetched: 1, Revision: 5})
+			select {
+			case <-release:
+				return &jobs.Result{Revision: 10}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	It("should run jobs and report their progress and result", func() {
+		release := make(chan struct{})
+		job, err := manager.Submit(jobs.TypeRestore, blockingJob(release))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.ID).To(HavePrefix("restore-"))
+		Expect(job.State).To(Equal(jobs.StatePending))
+
+		go manager.Run(ctx)
+		Eventually(jobState(job.ID)).Should(Equal(jobs.StateRunning))
+		job, err = manager.Get(job.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.StartedOn).NotTo(BeNil())
+		Expect(job.Progress).To(Equal(brtypes.RestoreProgress{SnapshotsTotal: 2, SnapshotsFetched: 1, Revision: 5}))
+
+		close(release)
+		Eventually(jobState(job.ID)).Should(Equal(jobs.StateSucceeded))
+		job, err = manager.Get(job.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.FinishedOn).NotTo(BeNil())
+		Expect(job.Result).To(Equal(&jobs.Result{Revision: 10}))
+	})
+
+	It("should report the error of failed jobs", func() {
+		job, err := manager.Submit(jobs.TypeCompaction, func(context.Context, brtypes.RestoreProgressFunc) (*jobs.Result, error) {
+			return nil, errors.New("no base snapshot found")
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		go manager.Run(ctx)
+		Eventually(jobState(job.ID)).Should(Equal(jobs.StateFailed))
+		job, err = manager.Get(job.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Error).To(Equal("no base snapshot found"))
+		Expect(job.Result).To(BeNil())
+	})
+
+	It("should run jobs one after the other and cancel pending and running jobs", func() {
+		first, err := manager.Submit(jobs.TypeRestore, blockingJob(make(chan struct{})))
+		Expect(err).NotTo(HaveOccurred())
+		second, err := manager.Submit(jobs.TypeRestore, blockingJob(make(chan struct{})))
+		Expect(err).NotTo(HaveOccurred())
+
+		go manager.Run(ctx)
+		Eventually(jobState(first.ID)).Should(Equal(jobs.StateRunning))
+		Consistently(jobState(second.ID), "100ms").Should(Equal(jobs.StatePending))
+
+		job, err := manager.Cancel(second.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.State).To(Equal(jobs.StateCancelled))
+
+		_, err = manager.Cancel(first.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(jobState(first.ID)).Should(Equal(jobs.StateCancelled))
+
+		_, err = manager.Cancel(first.ID)
+		Expect(err).To(MatchError(jobs.ErrJobFinished))
+		Expect(manager.List()).To(HaveLen(2))
+	})
+
+	It("should reject jobs once the queue is full", func() {
+		for range jobs.MaxQueuedJobs {
+			_, err := manager.Submit(jobs.TypeRestore, blockingJob(nil))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err := manager.Submit(jobs.TypeRestore, blockingJob(nil))
+		Expect(err).To(MatchError(jobs.ErrQueueFull))
+	})
+
+	It("should return an error for unknown jobs", func() {
+		_, err := manager.Get("restore-unknown")
+		Expect(err).To(MatchError(jobs.ErrJobNotFound))
+		_, err = manager.Cancel("restore-unknown")
+		Expect(err).To(MatchError(jobs.ErrJobNotFound))
+	})
+})
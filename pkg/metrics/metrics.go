@@ -33,11 +33,12 @@ const (
 	// LabelEndPoint is metric label for metric of etcd cluster endpoint.
 	LabelEndPoint = "endpoint"
 
-	namespaceEtcdBR      = "etcdbr"
-	subsystemSnapshot    = "snapshot"
-	subsystemRestore     = "restoration"
-	subsystemSnapstore   = "snapstore"
-	subsystemSnapshotter = "snapshotter"
+	namespaceEtcdBR       = "etcdbr"
+	subsystemSnapshot     = "snapshot"
+	subsystemRestore      = "restoration"
+	subsystemSnapstore    = "snapstore"
+	subsystemSnapshotter  = "snapshotter"
+	subsystemRestoreDrill = "restore_drill"
 )
 
 var (
@@ -165,6 +166,27 @@ var (
 		[]string{},
 	)
 
+	// SnapshotSpoolPendingTotal is metric to expose the number of delta snapshots in the local spool waiting to be uploaded to the snapstore.
+	SnapshotSpoolPendingTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemSnapshot,
+			Name:      "spool_pending_total",
+			Help:      "Total number of delta snapshots in the local spool waiting to be uploaded to the snapstore.",
+		},
+		[]string{},
+	)
+	// SnapshotSpoolPendingBytes is metric to expose the size of the delta snapshots in the local spool waiting to be uploaded to the snapstore.
+	SnapshotSpoolPendingBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemSnapshot,
+			Name:      "spool_pending_bytes",
+			Help:      "Total size in bytes of the delta snapshots in the local spool waiting to be uploaded to the snapstore.",
+		},
+		[]string{},
+	)
+
 	//SnapshotterOperationFailure is metric to count the number of snapshotter operations that have errored out
 	SnapshotterOperationFailure = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -176,6 +198,28 @@ var (
 		[]string{LabelError},
 	)
 
+	// SnapshotterPaused is metric to expose whether or not the scheduled snapshots and the garbage collection are paused.
+	SnapshotterPaused = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemSnapshotter,
+			Name:      "paused",
+			Help:      "Whether or not the scheduled snapshots and the garbage collection are paused. 1 if paused, 0 otherwise.",
+		},
+		[]string{},
+	)
+
+	// SnapshotterPausedUntilTimestamp is metric to expose the time at which the paused snapshotting is resumed automatically.
+	SnapshotterPausedUntilTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemSnapshotter,
+			Name:      "paused_until_timestamp",
+			Help:      "Timestamp at which the paused snapshotting is resumed automatically. 0 if not paused or paused without deadline.",
+		},
+		[]string{},
+	)
+
 	// CurrentClusterSize is metric to expose the current Etcd cluster size.
 	CurrentClusterSize = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -235,6 +279,72 @@ var (
 		},
 		[]string{LabelSucceeded},
 	)
+
+	// RestoreDrillTotal is metric to count the restore drills.
+	RestoreDrillTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "total",
+			Help:      "Total number of restore drills.",
+		},
+		[]string{LabelSucceeded},
+	)
+
+	// RestoreDrillDurationSeconds is metric to expose the duration required to restore the latest backups during a restore drill.
+	RestoreDrillDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "duration_seconds",
+			Help:      "Total latency distribution of restore drills.",
+		},
+		[]string{LabelSucceeded},
+	)
+
+	// RestoreDrillLatestSuccessTimestamp is metric to expose the timestamp of the latest successful restore drill.
+	RestoreDrillLatestSuccessTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "latest_success_timestamp",
+			Help:      "Timestamp of the latest successful restore drill.",
+		},
+		[]string{},
+	)
+
+	// RestoreDrillRevision is metric to expose the revision of the data restored by the latest successful restore drill.
+	RestoreDrillRevision = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "revision",
+			Help:      "Revision of the data restored by the latest successful restore drill.",
+		},
+		[]string{},
+	)
+
+	// RestoreDrillKeysTotal is metric to expose the number of keys restored by the latest successful restore drill.
+	RestoreDrillKeysTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "keys_total",
+			Help:      "Total number of keys restored by the latest successful restore drill.",
+		},
+		[]string{},
+	)
+
+	// RestoreDrillHashKV is metric to expose the hash of the data restored by the latest successful restore drill.
+	RestoreDrillHashKV = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceEtcdBR,
+			Subsystem: subsystemRestoreDrill,
+			Name:      "hash_kv",
+			Help:      "Hash of the key value store restored by the latest successful restore drill, as returned by the HashKV API of etcd.",
+		},
+		[]string{},
+	)
 )
 
 // generateLabelCombinations generates combinations of label values for metrics
@@ -437,15 +547,57 @@ func init() {
 	// SnapstoreLatestDeltasSize
 	SnapstoreLatestDeltasRevisionsTotal.With(prometheus.Labels(map[string]string{}))
 
+	// SnapshotSpoolPendingTotal
+	SnapshotSpoolPendingTotal.With(prometheus.Labels(map[string]string{}))
+
+	// SnapshotSpoolPendingBytes
+	SnapshotSpoolPendingBytes.With(prometheus.Labels(map[string]string{}))
+
 	//SnapshotterOperationFailure
 	SnapshotterOperationFailure.With(prometheus.Labels(map[string]string{LabelError: ""}))
 
+	// SnapshotterPaused
+	SnapshotterPaused.With(prometheus.Labels(map[string]string{}))
+
+	// SnapshotterPausedUntilTimestamp
+	SnapshotterPausedUntilTimestamp.With(prometheus.Labels(map[string]string{}))
+
 	//CurrentClusterSize
 	CurrentClusterSize.With(prometheus.Labels(map[string]string{}))
 
 	// IsLearner
 	IsLearner.With(prometheus.Labels(map[string]string{}))
 
+	// RestoreDrillTotal
+	restoreDrillTotalLabelValues := map[string][]string{
+		LabelSucceeded: labels[LabelSucceeded],
+	}
+	restoreDrillTotalCombinations := generateLabelCombinations(restoreDrillTotalLabelValues)
+	for _, combination := range restoreDrillTotalCombinations {
+		RestoreDrillTotal.With(prometheus.Labels(combination))
+	}
+
+	// RestoreDrillDurationSeconds
+	restoreDrillDurationSecondsLabelValues := map[string][]string{
+		LabelSucceeded: labels[LabelSucceeded],
+	}
+	restoreDrillDurationSecondsCombinations := generateLabelCombinations(restoreDrillDurationSecondsLabelValues)
+	for _, combination := range restoreDrillDurationSecondsCombinations {
+		RestoreDrillDurationSeconds.With(prometheus.Labels(combination))
+	}
+
+	// RestoreDrillLatestSuccessTimestamp
+	RestoreDrillLatestSuccessTimestamp.With(prometheus.Labels(map[string]string{}))
+
+	// RestoreDrillRevision
+	RestoreDrillRevision.With(prometheus.Labels(map[string]string{}))
+
+	// RestoreDrillKeysTotal
+	RestoreDrillKeysTotal.With(prometheus.Labels(map[string]string{}))
+
+	// RestoreDrillHashKV
+	RestoreDrillHashKV.With(prometheus.Labels(map[string]string{}))
+
 	// Metrics have to be registered to be exposed:
 	prometheus.MustRegister(GCSnapshotCounter)
 
@@ -461,7 +613,12 @@ func init() {
 	prometheus.MustRegister(SnapstoreLatestDeltasTotal)
 	prometheus.MustRegister(SnapstoreLatestDeltasRevisionsTotal)
 
+	prometheus.MustRegister(SnapshotSpoolPendingTotal)
+	prometheus.MustRegister(SnapshotSpoolPendingBytes)
+
 	prometheus.MustRegister(SnapshotterOperationFailure)
+	prometheus.MustRegister(SnapshotterPaused)
+	prometheus.MustRegister(SnapshotterPausedUntilTimestamp)
 
 	prometheus.MustRegister(CurrentClusterSize)
 	prometheus.MustRegister(IsLearner)
@@ -469,4 +626,11 @@ func init() {
 	prometheus.MustRegister(MemberRemoveDurationSeconds)
 	prometheus.MustRegister(AddLearnerDurationSeconds)
 	prometheus.MustRegister(MemberPromoteDurationSeconds)
+
+	prometheus.MustRegister(RestoreDrillTotal)
+	prometheus.MustRegister(RestoreDrillDurationSeconds)
+	prometheus.MustRegister(RestoreDrillLatestSuccessTimestamp)
+	prometheus.MustRegister(RestoreDrillRevision)
+	prometheus.MustRegister(RestoreDrillKeysTotal)
+	prometheus.MustRegister(RestoreDrillHashKV)
 }
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// hashTrailerReaderBufferSize is the size of the buffer used to read a snapshot ahead of its hash trailer.
+const hashTrailerReaderBufferSize = 32 * 1024
+
+// ErrCannotStripHash is the error returned when the hash trailer of a compressed snapshot is to be stripped without decompressing it.
+var ErrCannotStripHash = errors.New("hash trailer can only be stripped from decompressed snapshots")
+
+// OpenSnapshot opens the snapshot with the given name from the store for reading.
+// If decompress is true, the snapshot is decompressed with the compression policy of the snapshot.
+// If stripHash is true, the SHA256 hash appended to the snapshot is verified and stripped, so that the
+// content is a plain etcd database file for full snapshots or the JSON array of the events for delta snapshots.
+// Delta snapshots in the binary format are re-encoded in the JSON format for this.
+// If the hash does not match, reading the snapshot fails once its end is reached.
+func OpenSnapshot(store brtypes.SnapStore, name string, decompress, stripHash bool) (io.ReadCloser, *brtypes.Snapshot, error) {
+	snap, err := GetSnapshot(store, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	compressed, compressionPolicy, err := compressor.IsSnapshotCompressed(snap.CompressionSuffix)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stripHash && compressed && !decompress {
+		return nil, nil, fmt.Errorf("%w: snapshot %s is compressed with %s", ErrCannotStripHash, name, compressionPolicy)
+	}
+
+	rc, err := store.Fetch(*snap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to fetch snapshot %s from store: %v", name, err)
+	}
+	if compressed && decompress {
+		decompressed, err := compressor.DecompressSnapshot(rc, compressionPolicy)
+		if err != nil {
+			rc.Close()
+			return nil, nil, fmt.Errorf("unable to decompress snapshot %s: %v", name, err)
+		}
+		rc = &multiCloser{ReadCloser: decompressed, closers: []io.Closer{rc}}
+	}
+	if stripHash {
+		if snap.Kind == brtypes.SnapshotKindDelta {
+			buffered := bufio.NewReader(rc)
+			if delta.IsBinaryFormat(buffered) {
+				rc = newJSONDeltaSnapshotReader(buffered, rc)
+			} else {
+				rc = &multiCloser{ReadCloser: io.NopCloser(buffered), closers: []io.Closer{rc}}
+			}
+		}
+		rc = &hashTrailerReader{ReadCloser: rc, hash: sha256.New()}
+	}
+	return rc, snap, nil
+}
+
+// SnapshotFileName returns the name of the file the snapshot is downloaded to, given whether it is decompressed
+// and whether its hash is stripped.
+func SnapshotFileName(snap *brtypes.Snapshot, decompressed, hashStripped bool) string {
+	name := snap.SnapName
+	if decompressed {
+		name = strings.TrimSuffix(name, snap.CompressionSuffix)
+	}
+	if !hashStripped {
+		return name
+	}
+	if snap.Kind == brtypes.SnapshotKindFull {
+		return name + ".db"
+	}
+	return name + ".json"
+}
+
+// multiCloser is an io.ReadCloser which closes the given closers along with the wrapped io.ReadCloser.
+type multiCloser struct {
+	io.ReadCloser
+	closers []io.Closer
+}
+
+// Close closes the wrapped io.ReadCloser and the closers.
+func (m *multiCloser) Close() error {
+	errs := []error{m.ReadCloser.Close()}
+	for _, c := range m.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// jsonDeltaSnapshotReader reads a delta snapshot in the binary format re-encoded in the JSON format, along with its hash.
+type jsonDeltaSnapshotReader struct {
+	*io.PipeReader
+	snapshot io.Closer
+	done     chan struct{}
+}
+
+// newJSONDeltaSnapshotReader returns a reader of the delta snapshot in the binary format read from the given reader,
+// re-encoded in the JSON format. The given closer is closed along with the returned reader.
+func newJSONDeltaSnapshotReader(r io.Reader, snapshot io.Closer) *jsonDeltaSnapshotReader {
+	pr, pw := io.Pipe()
+	jr := &jsonDeltaSnapshotReader{PipeReader: pr, snapshot: snapshot, done: make(chan struct{})}
+	go func() {
+		defer close(jr.done)
+		pw.CloseWithError(reencodeDeltaSnapshot(r, pw, brtypes.DeltaSnapshotFormatJSON))
+	}()
+	return jr
+}
+
+// Close stops re-encoding the delta snapshot and closes it.
+func (r *jsonDeltaSnapshotReader) Close() error {
+	r.PipeReader.Close()
+	<-r.done
+	return r.snapshot.Close()
+}
+
+// reencodeDeltaSnapshot decodes the delta snapshot read from the given reader, verifying its hash, and writes it
+// encoded in the given format to the given writer.
+func reencodeDeltaSnapshot(r io.Reader, w io.Writer, format string) error {
+	decoder, err := delta.NewDecoder(r)
+	if err != nil {
+		return err
+	}
+	encoder, err := delta.NewEncoder(w, format)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return encoder.Close()
+		}
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+}
+
+// hashTrailerReader reads a snapshot without the SHA256 hash appended to it, and verifies the hash once the end
+// of the snapshot is reached.
+type hashTrailerReader struct {
+	io.ReadCloser
+	hash    hash.Hash
+	buf     []byte
+	scratch [hashTrailerReaderBufferSize]byte
+	err     error
+}
+
+// Read reads the snapshot up to, but excluding, its hash trailer.
+func (r *hashTrailerReader) Read(p []byte) (int, error) {
+	// Always hold back the last sha256.Size bytes read, as they may turn out to be the hash.
+	for len(r.buf) <= sha256.Size && r.err == nil {
+		n, err := r.ReadCloser.Read(r.scratch[:])
+		r.buf = append(r.buf, r.scratch[:n]...)
+		r.err = err
+	}
+	if r.err != nil && r.err != io.EOF {
+		return 0, r.err
+	}
+
+	available := len(r.buf) - sha256.Size
+	if available < 0 {
+		return 0, fmt.Errorf("snapshot is missing hash")
+	}
+	if available == 0 {
+		if computedHash := r.hash.Sum(nil); !bytes.Equal(computedHash, r.buf) {
+			return 0, fmt.Errorf("expected sha256 %x, got %x", r.buf, computedHash)
+		}
+		return 0, io.EOF
+	}
+	n := copy(p, r.buf[:available])
+	r.hash.Write(p[:n])
+	r.buf = append(r.buf[:0], r.buf[n:]...)
+	return n, nil
+}
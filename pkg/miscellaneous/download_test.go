@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var _ = Describe("Downloading snapshots", func() {
+	var (
+		store   brtypes.SnapStore
+		content []byte
+	)
+
+	// saveSnapshot saves a snapshot with the given content followed by the given hash to the store.
+	saveSnapshot := func(kind, compressionPolicy string, hash []byte) *brtypes.Snapshot {
+		snap := &brtypes.Snapshot{
+			Kind:          kind,
+			StartRevision: 1,
+			LastRevision:  10,
+			CreatedOn:     time.Now().UTC(),
+		}
+		data := io.NopCloser(bytes.NewReader(append(bytes.Clone(content), hash...)))
+		if compressionPolicy != "" {
+			var err error
+			snap.CompressionSuffix, err = compressor.GetCompressionSuffix(true, compressionPolicy)
+			Expect(err).NotTo(HaveOccurred())
+			data, err = compressor.CompressSnapshot(data, compressionPolicy, compressor.DefaultCompressionLevel)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		snap.GenerateSnapshotName()
+		Expect(store.Save(*snap, data)).To(Succeed())
+		return snap
+	}
+
+	readSnapshot := func(name string, decompress, stripHash bool) ([]byte, error) {
+		rc, _, err := OpenSnapshot(store, name, decompress, stripHash)
+		Expect(err).NotTo(HaveOccurred())
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	BeforeEach(func() {
+		var err error
+		store, err = snapstore.NewLocalSnapStore(filepath.Join(GinkgoT().TempDir(), "v2"))
+		Expect(err).NotTo(HaveOccurred())
+		// Larger than the read-ahead buffer, so that the hash trailer spans several reads.
+		content = bytes.Repeat([]byte("etcd-snapshot-data"), 4096)
+	})
+
+	Describe("#OpenSnapshot", func() {
+		It("should return the snapshot as stored", func() {
+			hash := sha256.Sum256(content)
+			snap := saveSnapshot(brtypes.SnapshotKindFull, compressor.GzipCompressionPolicy, hash[:])
+
+			data, err := readSnapshot(snap.SnapName, false, false)
+			Expect(err).NotTo(HaveOccurred())
+			decompressed, err := compressor.DecompressSnapshot(io.NopCloser(bytes.NewReader(data)), compressor.GzipCompressionPolicy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(io.ReadAll(decompressed)).To(Equal(append(bytes.Clone(content), hash[:]...)))
+		})
+
+		It("should return the decompressed snapshot without its hash", func() {
+			hash := sha256.Sum256(content)
+			snap := saveSnapshot(brtypes.SnapshotKindDelta, compressor.ZstdCompressionPolicy, hash[:])
+
+			data, err := readSnapshot(snap.SnapName, true, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(content))
+		})
+
+		It("should return the uncompressed snapshot without its hash", func() {
+			hash := sha256.Sum256(content)
+			snap := saveSnapshot(brtypes.SnapshotKindFull, "", hash[:])
+
+			data, err := readSnapshot(snap.SnapName, false, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(content))
+		})
+
+		Context("with a delta snapshot in the binary format", func() {
+			var events []brtypes.Event
+
+			BeforeEach(func() {
+				createdOn := time.Unix(1700000000, 0)
+				events = []brtypes.Event{
+					{
+						EtcdEvent: &clientv3.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("/registry/key"), Value: []byte("value"), CreateRevision: 11, ModRevision: 11, Version: 1, Lease: 42}},
+						Time:      createdOn,
+						Lease:     &brtypes.Lease{ID: 42, GrantedTTL: 3600, TTL: 3599},
+					},
+					{
+						EtcdEvent: &clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("/registry/key"), ModRevision: 12}},
+						Time:      createdOn.Add(time.Second),
+					},
+				}
+				buf := &bytes.Buffer{}
+				encoder, err := delta.NewEncoder(buf, brtypes.DeltaSnapshotFormatBinary)
+				Expect(err).NotTo(HaveOccurred())
+				for i := range events {
+					Expect(encoder.Encode(&events[i])).To(Succeed())
+				}
+				Expect(encoder.Close()).To(Succeed())
+				content = buf.Bytes()
+			})
+
+			It("should return the events as a JSON array without its hash", func() {
+				snap := saveSnapshot(brtypes.SnapshotKindDelta, compressor.ZstdCompressionPolicy, nil)
+
+				data, err := readSnapshot(snap.SnapName, true, true)
+				Expect(err).NotTo(HaveOccurred())
+				var decoded []brtypes.Event
+				Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+				Expect(decoded).To(HaveLen(len(events)))
+				for i := range events {
+					Expect(decoded[i].EtcdEvent).To(Equal(events[i].EtcdEvent))
+					Expect(decoded[i].Time).To(BeTemporally("==", events[i].Time))
+					Expect(decoded[i].Lease).To(Equal(events[i].Lease))
+				}
+			})
+
+			It("should fail reading the snapshot if its hash does not match", func() {
+				content[len(content)-1] ^= 0xff
+				snap := saveSnapshot(brtypes.SnapshotKindDelta, "", nil)
+
+				_, err := readSnapshot(snap.SnapName, false, true)
+				Expect(err).To(MatchError(ContainSubstring("sha256")))
+			})
+		})
+
+		It("should fail reading the snapshot if its hash does not match", func() {
+			hash := sha256.Sum256([]byte("other-data"))
+			snap := saveSnapshot(brtypes.SnapshotKindDelta, "", hash[:])
+
+			_, err := readSnapshot(snap.SnapName, false, true)
+			Expect(err).To(MatchError(ContainSubstring("expected sha256")))
+		})
+
+		It("should fail reading the snapshot if it is missing its hash", func() {
+			content = []byte("short")
+			snap := saveSnapshot(brtypes.SnapshotKindDelta, "", nil)
+
+			_, err := readSnapshot(snap.SnapName, false, true)
+			Expect(err).To(MatchError(ContainSubstring("missing hash")))
+		})
+
+		It("should return error if the hash of a compressed snapshot is to be stripped without decompressing it", func() {
+			snap := saveSnapshot(brtypes.SnapshotKindFull, compressor.GzipCompressionPolicy, nil)
+
+			_, _, err := OpenSnapshot(store, snap.SnapName, false, true)
+			Expect(errors.Is(err, ErrCannotStripHash)).To(BeTrue())
+		})
+
+		It("should return error if the snapshot does not exist", func() {
+			_, _, err := OpenSnapshot(store, "Full-00000000-00000001-1700000000", false, false)
+			Expect(errors.Is(err, brtypes.ErrSnapshotNotFound)).To(BeTrue())
+		})
+	})
+
+	Describe("#SnapshotFileName", func() {
+		It("should name the file after the content of the snapshot", func() {
+			snap := &brtypes.Snapshot{Kind: brtypes.SnapshotKindFull, SnapName: "Full-00000000-00000010-1700000000.gz", CompressionSuffix: compressor.GzipCompressionExtension}
+			Expect(SnapshotFileName(snap, false, false)).To(Equal("Full-00000000-00000010-1700000000.gz"))
+			Expect(SnapshotFileName(snap, true, false)).To(Equal("Full-00000000-00000010-1700000000"))
+			Expect(SnapshotFileName(snap, true, true)).To(Equal("Full-00000000-00000010-1700000000.db"))
+
+			snap = &brtypes.Snapshot{Kind: brtypes.SnapshotKindDelta, SnapName: "Incr-00000011-00000020-1700000000"}
+			Expect(SnapshotFileName(snap, false, true)).To(Equal("Incr-00000011-00000020-1700000000.json"))
+		})
+	})
+})
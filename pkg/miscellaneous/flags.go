@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// FlagEnvName returns the name of the environment variable for the flag with the given name, e.g.
+// ETCDBR_STORAGE_PROVIDER for the flag storage-provider and the prefix ETCDBR_.
+func FlagEnvName(envPrefix, flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// OverrideFlags overrides the values of the flags of the given flag set, first by the environment variables named after
+// the flags with the given prefix, and then by the flags which have been explicitly set in the given command-line flag
+// set. The flags of the command line thereby take precedence over the environment variables, which take precedence over
+// the values the flags have been bound to.
+func OverrideFlags(fs, cmdFlags *flag.FlagSet, envPrefix string) error {
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		envName := FlagEnvName(envPrefix, f.Name)
+		if value, ok := os.LookupEnv(envName); ok {
+			if err := fs.Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q of environment variable %s: %v", value, envName, err))
+			}
+		}
+	})
+	cmdFlags.Visit(func(cmdFlag *flag.Flag) {
+		f := fs.Lookup(cmdFlag.Name)
+		if f == nil {
+			return
+		}
+		if err := overrideFlag(fs, f, cmdFlag); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value %q of flag --%s: %v", cmdFlag.Value.String(), cmdFlag.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// overrideFlag sets the given flag of the given flag set to the value of the given command-line flag.
+func overrideFlag(fs *flag.FlagSet, f, cmdFlag *flag.Flag) error {
+	// the string of a slice flag cannot be parsed back, and setting a slice flag twice appends to it
+	if cmdSlice, ok := cmdFlag.Value.(flag.SliceValue); ok {
+		if slice, ok := f.Value.(flag.SliceValue); ok {
+			return slice.Replace(cmdSlice.GetSlice())
+		}
+	}
+	return fs.Set(f.Name, cmdFlag.Value.String())
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	flag "github.com/spf13/pflag"
+)
+
+var _ = Describe("Overriding flags", func() {
+	type config struct {
+		Provider  string
+		Period    time.Duration
+		Endpoints []string
+		Enabled   bool
+	}
+	const envPrefix = "ETCDBR_TEST_"
+
+	var (
+		cfg      *config
+		fs       *flag.FlagSet
+		cmdFlags *flag.FlagSet
+	)
+
+	addFlags := func(fs *flag.FlagSet, c *config) {
+		fs.StringVar(&c.Provider, "storage-provider", c.Provider, "")
+		fs.DurationVar(&c.Period, "delta-snapshot-period", c.Period, "")
+		fs.StringSliceVar(&c.Endpoints, "endpoints", c.Endpoints, "")
+		fs.BoolVar(&c.Enabled, "enabled", c.Enabled, "")
+	}
+	setEnv := func(name, value string) {
+		Expect(os.Setenv(name, value)).To(Succeed())
+		DeferCleanup(os.Unsetenv, name)
+	}
+
+	BeforeEach(func() {
+		// the config as loaded from the defaults and the config file
+		cfg = &config{Provider: "S3", Period: time.Minute, Endpoints: []string{"http://file:2379"}}
+		fs = flag.NewFlagSet("layered", flag.ContinueOnError)
+		addFlags(fs, cfg)
+		cmdFlags = flag.NewFlagSet("command-line", flag.ContinueOnError)
+		addFlags(cmdFlags, &config{})
+	})
+
+	It("should return the environment variable name of a flag", func() {
+		Expect(FlagEnvName("ETCDBR_", "delta-snapshot-period")).To(Equal("ETCDBR_DELTA_SNAPSHOT_PERIOD"))
+	})
+
+	It("should keep the values if neither environment variables nor flags are set", func() {
+		Expect(OverrideFlags(fs, cmdFlags, envPrefix)).To(Succeed())
+		Expect(*cfg).To(Equal(config{Provider: "S3", Period: time.Minute, Endpoints: []string{"http://file:2379"}}))
+	})
+
+	It("should override the values by the environment variables", func() {
+		setEnv(envPrefix+"STORAGE_PROVIDER", "GCS")
+		setEnv(envPrefix+"ENDPOINTS", "http://env-0:2379,http://env-1:2379")
+		setEnv(envPrefix+"ENABLED", "true")
+		Expect(OverrideFlags(fs, cmdFlags, envPrefix)).To(Succeed())
+		Expect(*cfg).To(Equal(config{Provider: "GCS", Period: time.Minute, Endpoints: []string{"http://env-0:2379", "http://env-1:2379"}, Enabled: true}))
+	})
+
+	It("should override the environment variables by the flags set on the command line", func() {
+		setEnv(envPrefix+"STORAGE_PROVIDER", "GCS")
+		setEnv(envPrefix+"ENDPOINTS", "http://env:2379")
+		setEnv(envPrefix+"DELTA_SNAPSHOT_PERIOD", "30s")
+		Expect(cmdFlags.Parse([]string{"--storage-provider=ABS", "--endpoints=http://flag-0:2379", "--endpoints=http://flag-1:2379"})).To(Succeed())
+		Expect(OverrideFlags(fs, cmdFlags, envPrefix)).To(Succeed())
+		Expect(*cfg).To(Equal(config{Provider: "ABS", Period: 30 * time.Second, Endpoints: []string{"http://flag-0:2379", "http://flag-1:2379"}}))
+	})
+
+	It("should override the values by flags explicitly set to their default", func() {
+		cfg.Enabled = true
+		Expect(cmdFlags.Parse([]string{"--enabled=false"})).To(Succeed())
+		Expect(OverrideFlags(fs, cmdFlags, envPrefix)).To(Succeed())
+		Expect(cfg.Enabled).To(BeFalse())
+	})
+
+	It("should fail for invalid environment variables", func() {
+		setEnv(envPrefix+"DELTA_SNAPSHOT_PERIOD", "often")
+		Expect(OverrideFlags(fs, cmdFlags, envPrefix)).To(MatchError(ContainSubstring(envPrefix + "DELTA_SNAPSHOT_PERIOD")))
+	})
+})
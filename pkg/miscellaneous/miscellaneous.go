@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
@@ -89,6 +90,27 @@ func GetNLatestFullSnapshots(store brtypes.SnapStore, n int) (brtypes.SnapList,
 	return fullSnapshotList, nil
 }
 
+// GetSnapshot returns the snapshot with the given name from the store.
+// The name may optionally be prefixed with the snapshot directory of the snapshot.
+func GetSnapshot(store brtypes.SnapStore, name string) (*brtypes.Snapshot, error) {
+	snapList, err := store.List(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range snapList {
+		if snap.IsChunk {
+			continue
+		}
+		if snap.SnapName == name || path.Join(snap.SnapDir, snap.SnapName) == name {
+			if err := snapstore.LoadSnapshotLabels(store, brtypes.SnapList{snap}); err != nil {
+				return nil, err
+			}
+			return snap, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", brtypes.ErrSnapshotNotFound, name)
+}
+
 // GetLatestFullSnapshotAndDeltaSnapList returns the latest snapshot.
 func GetLatestFullSnapshotAndDeltaSnapList(store brtypes.SnapStore) (*brtypes.Snapshot, brtypes.SnapList, error) {
 	var (
@@ -122,6 +144,187 @@ func GetLatestFullSnapshotAndDeltaSnapList(store brtypes.SnapStore) (*brtypes.Sn
 	return fullSnapshot, deltaSnapList, nil
 }
 
+// GetFullSnapshotAndDeltaSnapListForRestore returns the base full snapshot and the delta snapshots to restore from,
+// as per the point-in-time target of the restoration config. If no target is configured, the latest set of snapshots is returned.
+func GetFullSnapshotAndDeltaSnapListForRestore(store brtypes.SnapStore, config *brtypes.RestorationConfig) (*brtypes.Snapshot, brtypes.SnapList, error) {
+	if config == nil || !config.IsPointInTimeRestore() {
+		return GetLatestFullSnapshotAndDeltaSnapList(store)
+	}
+
+	targetLabels, err := config.GetTargetSnapshotLabels()
+	if err != nil {
+		return nil, nil, err
+	}
+	if targetLabels != nil {
+		snap, err := GetLatestSnapshotWithLabels(store, targetLabels)
+		if err != nil {
+			return nil, nil, err
+		}
+		return GetFullSnapshotAndDeltaSnapListUptoTarget(store, snap.LastRevision, time.Time{})
+	}
+
+	targetTime, err := config.GetTargetTime()
+	if err != nil {
+		return nil, nil, err
+	}
+	return GetFullSnapshotAndDeltaSnapListUptoTarget(store, config.TargetRevision, targetTime)
+}
+
+// GetLatestSnapshotWithLabels returns the latest full or delta snapshot from the store which has all of the given labels.
+func GetLatestSnapshotWithLabels(store brtypes.SnapStore, labels map[string]string) (*brtypes.Snapshot, error) {
+	snapList, err := store.List(false)
+	if err != nil {
+		return nil, err
+	}
+	var labelledSnapList brtypes.SnapList
+	for _, snap := range snapList {
+		if snap.HasLabels && !snap.IsChunk {
+			labelledSnapList = append(labelledSnapList, snap)
+		}
+	}
+	if err := snapstore.LoadSnapshotLabels(store, labelledSnapList); err != nil {
+		return nil, err
+	}
+	for index := len(labelledSnapList) - 1; index >= 0; index-- {
+		if labelledSnapList[index].MatchesLabels(labels) {
+			return labelledSnapList[index], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no snapshot with labels %v", brtypes.ErrSnapshotNotFound, labels)
+}
+
+// GetFullSnapshotAndDeltaSnapListUptoTarget returns the latest full snapshot taken at or before the target, along with
+// the delta snapshots on top of it which are required to reach the target. A targetRevision of 0 and a zero targetTime
+// mean no bound on revision and time respectively. The last delta snapshot in the returned list may contain events
+// beyond the target, which are expected to be dropped by the restorer.
+func GetFullSnapshotAndDeltaSnapListUptoTarget(store brtypes.SnapStore, targetRevision int64, targetTime time.Time) (*brtypes.Snapshot, brtypes.SnapList, error) {
+	snapList, err := store.List(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isFullSnapshotWithinTarget := func(snap *brtypes.Snapshot) bool {
+		if targetRevision > 0 && snap.LastRevision > targetRevision {
+			return false
+		}
+		if !targetTime.IsZero() && snap.CreatedOn.After(targetTime) {
+			return false
+		}
+		return true
+	}
+
+	fullSnapshotIndex := -1
+	for index, snap := range snapList {
+		if snap.IsChunk || snap.Kind != brtypes.SnapshotKindFull {
+			continue
+		}
+		if isFullSnapshotWithinTarget(snap) {
+			fullSnapshotIndex = index
+		}
+	}
+	if fullSnapshotIndex == -1 {
+		return nil, nil, fmt.Errorf("no full snapshot found at or before the target revision %d and target time %v", targetRevision, targetTime)
+	}
+
+	var (
+		fullSnapshot      = snapList[fullSnapshotIndex]
+		deltaSnapList     brtypes.SnapList
+		lastRevision      = fullSnapshot.LastRevision
+		nextFullSnapshot  *brtypes.Snapshot
+		targetTimeReached bool
+	)
+	for _, snap := range snapList[fullSnapshotIndex+1:] {
+		if snap.IsChunk {
+			continue
+		}
+		if snap.Kind == brtypes.SnapshotKindFull {
+			nextFullSnapshot = snap
+			break
+		}
+		if targetRevision > 0 && snap.StartRevision > targetRevision {
+			break
+		}
+		deltaSnapList = append(deltaSnapList, snap)
+		lastRevision = snap.LastRevision
+		if !targetTime.IsZero() && snap.CreatedOn.After(targetTime) {
+			// this delta snapshot contains the events around the target time, none of the later ones are required
+			targetTimeReached = true
+			break
+		}
+	}
+
+	if targetRevision > lastRevision && !targetTimeReached {
+		return nil, nil, targetRevisionNotCoveredError(targetRevision, fullSnapshot, lastRevision, nextFullSnapshot)
+	}
+
+	return fullSnapshot, deltaSnapList, nil
+}
+
+// GetFullSnapshotAndDeltaSnapListByName returns the full snapshot with the given name, along with the delta snapshots
+// on top of it which are required to reach the target revision. The name may optionally be prefixed with the snapshot
+// directory of the snapshot. A targetRevision of 0 means all delta snapshots up to the next full snapshot. The last delta
+// snapshot in the returned list may contain events beyond the target, which are expected to be dropped by the restorer.
+func GetFullSnapshotAndDeltaSnapListByName(store brtypes.SnapStore, name string, targetRevision int64) (*brtypes.Snapshot, brtypes.SnapList, error) {
+	snapList, err := store.List(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fullSnapshotIndex := -1
+	for index, snap := range snapList {
+		if snap.IsChunk || snap.Kind != brtypes.SnapshotKindFull {
+			continue
+		}
+		if snap.SnapName == name || path.Join(snap.SnapDir, snap.SnapName) == name {
+			fullSnapshotIndex = index
+			break
+		}
+	}
+	if fullSnapshotIndex == -1 {
+		return nil, nil, fmt.Errorf("%w: no full snapshot %s", brtypes.ErrSnapshotNotFound, name)
+	}
+	fullSnapshot := snapList[fullSnapshotIndex]
+	if targetRevision > 0 && fullSnapshot.LastRevision > targetRevision {
+		return nil, nil, fmt.Errorf("full snapshot %s with revision %d is beyond the target revision %d", fullSnapshot.SnapName, fullSnapshot.LastRevision, targetRevision)
+	}
+
+	var (
+		deltaSnapList    brtypes.SnapList
+		lastRevision     = fullSnapshot.LastRevision
+		nextFullSnapshot *brtypes.Snapshot
+	)
+	for _, snap := range snapList[fullSnapshotIndex+1:] {
+		if snap.IsChunk {
+			continue
+		}
+		if snap.Kind == brtypes.SnapshotKindFull {
+			nextFullSnapshot = snap
+			break
+		}
+		if targetRevision > 0 && snap.StartRevision > targetRevision {
+			break
+		}
+		deltaSnapList = append(deltaSnapList, snap)
+		lastRevision = snap.LastRevision
+	}
+
+	if targetRevision > lastRevision {
+		return nil, nil, targetRevisionNotCoveredError(targetRevision, fullSnapshot, lastRevision, nextFullSnapshot)
+	}
+
+	return fullSnapshot, deltaSnapList, nil
+}
+
+// targetRevisionNotCoveredError returns the error for a target revision beyond the given last revision reached by the
+// delta snapshots on top of the given full snapshot. The target revision is either within a gap of the delta snapshots
+// before the next full snapshot, or beyond the last backed up revision if there is no next full snapshot.
+func targetRevisionNotCoveredError(targetRevision int64, fullSnapshot *brtypes.Snapshot, lastRevision int64, nextFullSnapshot *brtypes.Snapshot) error {
+	if nextFullSnapshot == nil {
+		return fmt.Errorf("target revision %d is beyond the last backed up revision %d", targetRevision, lastRevision)
+	}
+	return fmt.Errorf("target revision %d is not covered by the delta snapshots after full snapshot %s, which end at revision %d", targetRevision, fullSnapshot.SnapName, lastRevision)
+}
+
 type backup struct {
 	FullSnapshot      *brtypes.Snapshot
 	DeltaSnapshotList brtypes.SnapList
@@ -6,11 +6,13 @@ package miscellaneous
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	mockfactory "github.com/gardener/etcd-backup-restore/pkg/mock/etcdutil/client"
@@ -169,6 +171,173 @@ var _ = Describe("Miscellaneous Tests", func() {
 		})
 	})
 
+	Describe("Getting snapshots up to a point-in-time target", func() {
+		var (
+			baseTime                           time.Time
+			fullSnap0, fullSnap1               *brtypes.Snapshot
+			deltaSnap0, deltaSnap1, deltaSnap2 *brtypes.Snapshot
+		)
+
+		newSnapshot := func(kind string, startRevision, lastRevision int64, createdOn time.Time) *brtypes.Snapshot {
+			return &brtypes.Snapshot{
+				SnapName:      fmt.Sprintf("%s-%08d-%08d", kind, startRevision, lastRevision),
+				Kind:          kind,
+				StartRevision: startRevision,
+				LastRevision:  lastRevision,
+				CreatedOn:     createdOn,
+			}
+		}
+
+		BeforeEach(func() {
+			baseTime = time.Now().UTC().Truncate(time.Second)
+			fullSnap0 = newSnapshot(brtypes.SnapshotKindFull, 0, 10, baseTime)
+			deltaSnap0 = newSnapshot(brtypes.SnapshotKindDelta, 11, 20, baseTime.Add(time.Minute))
+			deltaSnap1 = newSnapshot(brtypes.SnapshotKindDelta, 21, 30, baseTime.Add(2*time.Minute))
+			fullSnap1 = newSnapshot(brtypes.SnapshotKindFull, 0, 30, baseTime.Add(3*time.Minute))
+			deltaSnap2 = newSnapshot(brtypes.SnapshotKindDelta, 31, 40, baseTime.Add(4*time.Minute))
+
+			snapList = brtypes.SnapList{fullSnap0, deltaSnap0, deltaSnap1, fullSnap1, deltaSnap2}
+			ds = NewDummyStore(snapList)
+		})
+
+		Describe("#GetFullSnapshotAndDeltaSnapListUptoTarget", func() {
+			It("should return the full snapshot and the delta snapshots covering the target revision", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 25, time.Time{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap0))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap0, deltaSnap1}))
+			})
+
+			It("should return the latest full snapshot at or before the target revision", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 35, time.Time{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap1))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap2}))
+			})
+
+			It("should return the delta snapshots up to and including the first one taken after the target time", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 0, baseTime.Add(90*time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap0))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap0, deltaSnap1}))
+			})
+
+			It("should return the latest full snapshot taken at or before the target time", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 0, baseTime.Add(3*time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap1))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap2}))
+			})
+
+			It("should return error if there is no full snapshot before the target", func() {
+				_, _, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 5, time.Time{})
+				Expect(err).To(MatchError(ContainSubstring("no full snapshot found")))
+
+				_, _, err = GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 0, baseTime.Add(-time.Minute))
+				Expect(err).To(MatchError(ContainSubstring("no full snapshot found")))
+			})
+
+			It("should return error if the delta snapshots covering the target revision are missing", func() {
+				ds = NewDummyStore(brtypes.SnapList{fullSnap0, deltaSnap0, fullSnap1, deltaSnap2})
+
+				_, _, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 25, time.Time{})
+				Expect(err).To(MatchError(ContainSubstring("target revision 25 is not covered")))
+			})
+
+			It("should return error if the target revision is beyond the last backed up revision", func() {
+				_, _, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 45, time.Time{})
+				Expect(err).To(MatchError(ContainSubstring("target revision 45 is beyond the last backed up revision 40")))
+			})
+
+			It("should return the delta snapshots up to the target time if it is reached before the target revision", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListUptoTarget(ds, 45, baseTime.Add(210*time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap1))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap2}))
+			})
+		})
+
+		Describe("#GetFullSnapshotAndDeltaSnapListByName", func() {
+			It("should return the full snapshot with the given name and all delta snapshots on top of it", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListByName(ds, fullSnap0.SnapName, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap0))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap0, deltaSnap1}))
+			})
+
+			It("should return the delta snapshots covering the target revision", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListByName(ds, fullSnap0.SnapName, 15)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap0))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap0}))
+			})
+
+			It("should return error if the target revision is not covered by the full snapshot and its delta snapshots", func() {
+				_, _, err := GetFullSnapshotAndDeltaSnapListByName(ds, fullSnap1.SnapName, 25)
+				Expect(err).To(MatchError(ContainSubstring("is beyond the target revision 25")))
+
+				_, _, err = GetFullSnapshotAndDeltaSnapListByName(ds, fullSnap0.SnapName, 35)
+				Expect(err).To(MatchError(ContainSubstring("target revision 35 is not covered")))
+
+				_, _, err = GetFullSnapshotAndDeltaSnapListByName(ds, fullSnap1.SnapName, 45)
+				Expect(err).To(MatchError(ContainSubstring("target revision 45 is beyond the last backed up revision 40")))
+			})
+
+			It("should return error if there is no full snapshot with the given name", func() {
+				_, _, err := GetFullSnapshotAndDeltaSnapListByName(ds, deltaSnap0.SnapName, 0)
+				Expect(errors.Is(err, brtypes.ErrSnapshotNotFound)).To(BeTrue())
+			})
+		})
+
+		Describe("#GetFullSnapshotAndDeltaSnapListForRestore", func() {
+			var restorationConfig *brtypes.RestorationConfig
+
+			BeforeEach(func() {
+				restorationConfig = brtypes.NewRestorationConfig()
+			})
+
+			It("should return the latest set of snapshots if no target is configured", func() {
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListForRestore(ds, restorationConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap1))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap2}))
+			})
+
+			It("should return the set of snapshots up to the configured target time", func() {
+				restorationConfig.TargetTime = baseTime.Add(30 * time.Second).Format(time.RFC3339)
+
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListForRestore(ds, restorationConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap).To(Equal(fullSnap0))
+				Expect(deltaSnapList).To(Equal(brtypes.SnapList{deltaSnap0}))
+			})
+
+			It("should return the set of snapshots up to the latest snapshot with the configured target snapshot labels", func() {
+				store, err := snapstore.NewLocalSnapStore(filepath.Join(GinkgoT().TempDir(), "v2"))
+				Expect(err).NotTo(HaveOccurred())
+				for _, snap := range snapList {
+					snap.GenerateSnapshotName()
+					Expect(store.Save(*snap, io.NopCloser(strings.NewReader("dummy-snapshot-content")))).To(Succeed())
+				}
+				Expect(snapstore.SaveSnapshotLabels(store, fullSnap0, map[string]string{"reason": "pre-upgrade"})).To(Succeed())
+				Expect(snapstore.SaveSnapshotLabels(store, deltaSnap0, map[string]string{"reason": "pre-upgrade", "ticket": "OPS-1"})).To(Succeed())
+				Expect(snapstore.SaveSnapshotLabels(store, deltaSnap2, map[string]string{"reason": "post-upgrade"})).To(Succeed())
+				restorationConfig.TargetSnapshotLabels = "reason=pre-upgrade"
+				Expect(restorationConfig.Validate()).To(Succeed())
+
+				fullSnap, deltaSnapList, err := GetFullSnapshotAndDeltaSnapListForRestore(store, restorationConfig)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fullSnap.SnapName).To(Equal(fullSnap0.SnapName))
+				Expect(deltaSnapList).To(HaveLen(1))
+				Expect(deltaSnapList[0].SnapName).To(Equal(deltaSnap0.SnapName))
+
+				restorationConfig.TargetSnapshotLabels = "reason=rollback"
+				_, _, err = GetFullSnapshotAndDeltaSnapListForRestore(store, restorationConfig)
+				Expect(errors.Is(err, brtypes.ErrSnapshotNotFound)).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("Etcd Cluster", func() {
 		var (
 			dummyID              = uint64(1111)
@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+const (
+	// DefaultSnapshotListLimit is the default number of snapshots in a page of a snapshot listing.
+	DefaultSnapshotListLimit = 100
+	// MaxSnapshotListLimit is the maximum number of snapshots in a page of a snapshot listing.
+	MaxSnapshotListLimit = 1000
+)
+
+// ErrInvalidContinueToken is the error returned when the continue token of a snapshot listing cannot be parsed.
+var ErrInvalidContinueToken = errors.New("invalid continue token")
+
+// SnapshotFilter selects snapshots from a snapshot listing. Unset fields do not restrict the selection.
+type SnapshotFilter struct {
+	// Kind selects the snapshots of the given kind, i.e. Full or Incr.
+	Kind string
+	// MinRevision selects the snapshots which contain revisions at or after the given revision.
+	MinRevision int64
+	// MaxRevision selects the snapshots which contain revisions at or before the given revision.
+	MaxRevision int64
+	// CreatedAfter selects the snapshots taken at or after the given time.
+	CreatedAfter time.Time
+	// CreatedBefore selects the snapshots taken at or before the given time.
+	CreatedBefore time.Time
+	// Final selects the final or non-final snapshots.
+	Final *bool
+	// Labels selects the snapshots which have all of the given labels.
+	Labels map[string]string
+}
+
+// Matches returns true if the given snapshot is selected by the filter.
+// The labels of the snapshot are expected to be loaded if the filter selects by labels.
+func (f *SnapshotFilter) Matches(snap *brtypes.Snapshot) bool {
+	switch {
+	case len(f.Kind) != 0 && snap.Kind != f.Kind:
+		return false
+	case f.MinRevision > 0 && snap.LastRevision < f.MinRevision:
+		return false
+	case f.MaxRevision > 0 && snap.StartRevision > f.MaxRevision:
+		return false
+	case !f.CreatedAfter.IsZero() && snap.CreatedOn.Before(f.CreatedAfter):
+		return false
+	case !f.CreatedBefore.IsZero() && snap.CreatedOn.After(f.CreatedBefore):
+		return false
+	case f.Final != nil && snap.IsFinal != *f.Final:
+		return false
+	}
+	return snap.MatchesLabels(f.Labels)
+}
+
+// ListSnapshots returns a page of at most limit snapshots from the store which are selected by the given filter,
+// ordered by their last revision and creation time, along with the continue token for the next page.
+// The continue token is empty if there are no more snapshots. Chunks of snapshots are not listed.
+// includeAll specifies whether to include the snapshots with exclude tags, see SnapStore.List.
+func ListSnapshots(store brtypes.SnapStore, includeAll bool, filter *SnapshotFilter, limit int, continueToken string) (brtypes.SnapList, string, error) {
+	if limit <= 0 || limit > MaxSnapshotListLimit {
+		return nil, "", fmt.Errorf("limit must be between 1 and %d, got %d", MaxSnapshotListLimit, limit)
+	}
+	var after *snapshotListPosition
+	if len(continueToken) != 0 {
+		position, err := parseContinueToken(continueToken)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &position
+	}
+
+	snapList, err := store.List(includeAll)
+	if err != nil {
+		return nil, "", err
+	}
+	var candidates brtypes.SnapList
+	for _, snap := range snapList {
+		if !snap.IsChunk {
+			candidates = append(candidates, snap)
+		}
+	}
+	if len(filter.Labels) != 0 {
+		if err := snapstore.LoadSnapshotLabels(store, candidates); err != nil {
+			return nil, "", err
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return positionOf(candidates[i]).before(positionOf(candidates[j]))
+	})
+
+	var page brtypes.SnapList
+	for _, snap := range candidates {
+		if after != nil && !after.before(positionOf(snap)) {
+			continue
+		}
+		if !filter.Matches(snap) {
+			continue
+		}
+		if len(page) == limit {
+			return page, positionOf(page[len(page)-1]).token(), snapstore.LoadSnapshotLabels(store, page)
+		}
+		page = append(page, snap)
+	}
+	return page, "", snapstore.LoadSnapshotLabels(store, page)
+}
+
+// snapshotListPosition is the position of a snapshot in a snapshot listing.
+type snapshotListPosition struct {
+	lastRevision int64
+	createdOn    int64
+	snapPath     string
+}
+
+// positionOf returns the position of the given snapshot in a snapshot listing.
+func positionOf(snap *brtypes.Snapshot) snapshotListPosition {
+	return snapshotListPosition{
+		lastRevision: snap.LastRevision,
+		createdOn:    snap.CreatedOn.Unix(),
+		snapPath:     path.Join(snap.SnapDir, snap.SnapName),
+	}
+}
+
+// before returns true if the position is before the given position.
+func (p snapshotListPosition) before(other snapshotListPosition) bool {
+	if p.lastRevision != other.lastRevision {
+		return p.lastRevision < other.lastRevision
+	}
+	if p.createdOn != other.createdOn {
+		return p.createdOn < other.createdOn
+	}
+	return p.snapPath < other.snapPath
+}
+
+// token returns the continue token of a listing which continues after the position.
+// The listing continues at the right position even if snapshots were deleted in between.
+func (p snapshotListPosition) token() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%d/%s", p.lastRevision, p.createdOn, p.snapPath)))
+}
+
+// parseContinueToken parses the position encoded in the given continue token.
+func parseContinueToken(token string) (snapshotListPosition, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return snapshotListPosition{}, fmt.Errorf("%w: %v", ErrInvalidContinueToken, err)
+	}
+	tokens := strings.SplitN(string(data), "/", 3)
+	if len(tokens) != 3 {
+		return snapshotListPosition{}, ErrInvalidContinueToken
+	}
+	lastRevision, err := strconv.ParseInt(tokens[0], 10, 64)
+	if err != nil {
+		return snapshotListPosition{}, fmt.Errorf("%w: %v", ErrInvalidContinueToken, err)
+	}
+	createdOn, err := strconv.ParseInt(tokens[1], 10, 64)
+	if err != nil {
+		return snapshotListPosition{}, fmt.Errorf("%w: %v", ErrInvalidContinueToken, err)
+	}
+	return snapshotListPosition{lastRevision: lastRevision, createdOn: createdOn, snapPath: tokens[2]}, nil
+}
+
+// SnapshotDetails is the full metadata of a snapshot.
+type SnapshotDetails struct {
+	*brtypes.Snapshot
+	// Compressed is true if the snapshot is compressed.
+	Compressed bool `json:"compressed"`
+	// CompressionPolicy is the policy the snapshot is compressed with.
+	CompressionPolicy string `json:"compressionPolicy,omitempty"`
+	// Chain is the chain of snapshots the snapshot belongs to.
+	Chain SnapshotChain `json:"chain"`
+}
+
+// SnapshotChain is a full snapshot followed by the delta snapshots on top of it, which are restored together.
+type SnapshotChain struct {
+	// FullSnapshot is the full snapshot the chain starts with. It is empty if no full snapshot precedes the delta snapshots.
+	FullSnapshot string `json:"fullSnapshot,omitempty"`
+	// Snapshots are the snapshots of the chain, starting with the full snapshot.
+	Snapshots []string `json:"snapshots"`
+	// Index is the position of the snapshot in the chain.
+	Index int `json:"index"`
+	// Latest is true if the chain is the latest one, which is restored by default.
+	Latest bool `json:"latest"`
+}
+
+// GetSnapshotDetails returns the full metadata of the snapshot with the given name from the store.
+// The name may optionally be prefixed with the snapshot directory of the snapshot.
+func GetSnapshotDetails(store brtypes.SnapStore, name string) (*SnapshotDetails, error) {
+	snapList, err := store.List(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		chains       []SnapshotChain
+		details      *SnapshotDetails
+		chainOfSnap  int
+		currentChain = -1
+	)
+	for _, snap := range snapList {
+		if snap.IsChunk {
+			continue
+		}
+		snapPath := path.Join(snap.SnapDir, snap.SnapName)
+		if snap.Kind == brtypes.SnapshotKindFull || currentChain == -1 {
+			chains = append(chains, SnapshotChain{})
+			currentChain++
+			if snap.Kind == brtypes.SnapshotKindFull {
+				chains[currentChain].FullSnapshot = snapPath
+			}
+		}
+		if snap.SnapName == name || snapPath == name {
+			compressed, policy, err := compressor.IsSnapshotCompressed(snap.CompressionSuffix)
+			if err != nil {
+				return nil, err
+			}
+			details = &SnapshotDetails{Snapshot: snap, Compressed: compressed, CompressionPolicy: policy}
+			details.Chain.Index = len(chains[currentChain].Snapshots)
+			chainOfSnap = currentChain
+		}
+		chains[currentChain].Snapshots = append(chains[currentChain].Snapshots, snapPath)
+	}
+	if details == nil {
+		return nil, fmt.Errorf("%w: %s", brtypes.ErrSnapshotNotFound, name)
+	}
+
+	index := details.Chain.Index
+	details.Chain = chains[chainOfSnap]
+	details.Chain.Index = index
+	details.Chain.Latest = chainOfSnap == len(chains)-1
+	if err := snapstore.LoadSnapshotLabels(store, brtypes.SnapList{details.Snapshot}); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package miscellaneous
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listing snapshots", func() {
+	var (
+		store    brtypes.SnapStore
+		baseTime time.Time
+		snaps    brtypes.SnapList
+	)
+
+	names := func(snapList brtypes.SnapList) []string {
+		var snapNames []string
+		for _, snap := range snapList {
+			snapNames = append(snapNames, snap.SnapName)
+		}
+		return snapNames
+	}
+
+	BeforeEach(func() {
+		var err error
+		store, err = snapstore.NewLocalSnapStore(filepath.Join(GinkgoT().TempDir(), "v2"))
+		Expect(err).NotTo(HaveOccurred())
+
+		baseTime = time.Now().UTC().Truncate(time.Second).Add(-time.Hour)
+		snaps = brtypes.SnapList{
+			{Kind: brtypes.SnapshotKindFull, StartRevision: 0, LastRevision: 10, CreatedOn: baseTime, CompressionSuffix: compressor.GzipCompressionExtension},
+			{Kind: brtypes.SnapshotKindDelta, StartRevision: 11, LastRevision: 20, CreatedOn: baseTime.Add(time.Minute)},
+			{Kind: brtypes.SnapshotKindDelta, StartRevision: 21, LastRevision: 30, CreatedOn: baseTime.Add(2 * time.Minute)},
+			{Kind: brtypes.SnapshotKindFull, StartRevision: 0, LastRevision: 30, CreatedOn: baseTime.Add(3 * time.Minute)},
+			{Kind: brtypes.SnapshotKindDelta, StartRevision: 31, LastRevision: 40, CreatedOn: baseTime.Add(4 * time.Minute), IsFinal: true},
+		}
+		for _, snap := range snaps {
+			snap.GenerateSnapshotName()
+			Expect(store.Save(*snap, io.NopCloser(strings.NewReader("dummy-snapshot-content")))).To(Succeed())
+		}
+	})
+
+	Describe("#ListSnapshots", func() {
+		It("should list the snapshots page by page", func() {
+			page, continueToken, err := ListSnapshots(store, false, &SnapshotFilter{}, 2, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal(names(snaps[:2])))
+			Expect(page[0].Size).To(Equal(int64(len("dummy-snapshot-content"))))
+			Expect(continueToken).NotTo(BeEmpty())
+
+			page, continueToken, err = ListSnapshots(store, false, &SnapshotFilter{}, 2, continueToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal(names(snaps[2:4])))
+			Expect(continueToken).NotTo(BeEmpty())
+
+			page, continueToken, err = ListSnapshots(store, false, &SnapshotFilter{}, 2, continueToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal(names(snaps[4:])))
+			Expect(continueToken).To(BeEmpty())
+		})
+
+		It("should continue the listing after the last listed snapshot if snapshots were deleted in between", func() {
+			page, continueToken, err := ListSnapshots(store, false, &SnapshotFilter{}, 2, "")
+			Expect(err).NotTo(HaveOccurred())
+			for _, snap := range page {
+				Expect(store.Delete(*snap)).To(Succeed())
+			}
+
+			page, _, err = ListSnapshots(store, false, &SnapshotFilter{}, 2, continueToken)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal(names(snaps[2:4])))
+		})
+
+		It("should list the snapshots selected by the filter", func() {
+			final := true
+			for _, test := range []struct {
+				filter   SnapshotFilter
+				expected brtypes.SnapList
+			}{
+				{SnapshotFilter{Kind: brtypes.SnapshotKindFull}, brtypes.SnapList{snaps[0], snaps[3]}},
+				{SnapshotFilter{MinRevision: 25, MaxRevision: 35}, brtypes.SnapList{snaps[2], snaps[3], snaps[4]}},
+				{SnapshotFilter{CreatedAfter: baseTime.Add(time.Minute), CreatedBefore: baseTime.Add(2 * time.Minute)}, brtypes.SnapList{snaps[1], snaps[2]}},
+				{SnapshotFilter{Final: &final}, brtypes.SnapList{snaps[4]}},
+			} {
+				page, continueToken, err := ListSnapshots(store, false, &test.filter, MaxSnapshotListLimit, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(names(page)).To(Equal(names(test.expected)))
+				Expect(continueToken).To(BeEmpty())
+			}
+		})
+
+		It("should list the snapshots selected by labels along with their labels", func() {
+			Expect(snapstore.SaveSnapshotLabels(store, snaps[3], map[string]string{"reason": "pre-upgrade"})).To(Succeed())
+
+			page, _, err := ListSnapshots(store, false, &SnapshotFilter{Labels: map[string]string{"reason": "pre-upgrade"}}, 10, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(page)).To(Equal([]string{snaps[3].SnapName}))
+			Expect(page[0].Labels).To(Equal(map[string]string{"reason": "pre-upgrade"}))
+		})
+
+		It("should return error for an invalid continue token", func() {
+			_, _, err := ListSnapshots(store, false, &SnapshotFilter{}, 10, "invalid")
+			Expect(errors.Is(err, ErrInvalidContinueToken)).To(BeTrue())
+		})
+	})
+
+	Describe("#GetSnapshotDetails", func() {
+		It("should return the details of a full snapshot", func() {
+			details, err := GetSnapshotDetails(store, snaps[0].SnapName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(details.SnapName).To(Equal(snaps[0].SnapName))
+			Expect(details.Compressed).To(BeTrue())
+			Expect(details.CompressionPolicy).To(Equal(compressor.GzipCompressionPolicy))
+			Expect(details.Chain).To(Equal(SnapshotChain{
+				FullSnapshot: snaps[0].SnapName,
+				Snapshots:    names(snaps[:3]),
+				Index:        0,
+				Latest:       false,
+			}))
+		})
+
+		It("should return the details of a delta snapshot of the latest chain", func() {
+			details, err := GetSnapshotDetails(store, snaps[4].SnapName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(details.Compressed).To(BeFalse())
+			Expect(details.IsFinal).To(BeTrue())
+			Expect(details.Chain).To(Equal(SnapshotChain{
+				FullSnapshot: snaps[3].SnapName,
+				Snapshots:    names(snaps[3:]),
+				Index:        1,
+				Latest:       true,
+			}))
+		})
+
+		It("should return error if the snapshot does not exist", func() {
+			_, err := GetSnapshotDetails(store, "Full-00000000-00000001-1700000000")
+			Expect(errors.Is(err, brtypes.ErrSnapshotNotFound)).To(BeTrue())
+		})
+	})
+})
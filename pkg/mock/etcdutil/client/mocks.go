@@ -73,6 +73,21 @@ func (mr *MockFactoryMockRecorder) NewKV() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewKV", reflect.TypeOf((*MockFactory)(nil).NewKV))
 }
 
+// NewLease mocks base method.
+func (m *MockFactory) NewLease() (client.LeaseCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewLease")
+	ret0, _ := ret[0].(client.LeaseCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewLease indicates an expected call of NewLease.
+func (mr *MockFactoryMockRecorder) NewLease() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewLease", reflect.TypeOf((*MockFactory)(nil).NewLease))
+}
+
 // NewMaintenance mocks base method.
 func (m *MockFactory) NewMaintenance() (client.MaintenanceCloser, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Role grants access to a group of endpoints of the backup-restore server.
+type Role string
+
+const (
+	// RoleRead grants read-only access to the snapshots, the initialization status, the jobs, the configuration and the
+	// health report.
+	RoleRead Role = "read"
+	// RoleTrigger grants access to trigger out-of-schedule snapshots, to pin and unpin snapshots, and to pause and
+	// resume the snapshotting.
+	RoleTrigger Role = "trigger"
+	// RoleInitialize grants access to start the initialization of the etcd data directory.
+	RoleInitialize Role = "initialize"
+	// RoleDownload grants access to download snapshots.
+	RoleDownload Role = "download"
+	// RoleJobs grants access to submit and cancel restore and compaction jobs.
+	RoleJobs Role = "jobs"
+	// RoleAdmin grants access to all endpoints, including the profiling endpoints.
+	RoleAdmin Role = "admin"
+)
+
+// roles are the roles known to the backup-restore server.
+var roles = []Role{RoleRead, RoleTrigger, RoleInitialize, RoleDownload, RoleJobs, RoleAdmin}
+
+var (
+	// errUnauthenticated is the error returned when a request carries neither a bearer token nor a verified client certificate.
+	errUnauthenticated = errors.New("request carries neither a bearer token nor a verified client certificate")
+	// errInvalidToken is the error returned when the bearer token of a request is not listed in the token file.
+	errInvalidToken = errors.New("invalid bearer token")
+)
+
+// principal is the authenticated client of a request.
+type principal struct {
+	name  string
+	roles []Role
+}
+
+// hasRole returns true if the principal has been granted the given role, or the admin role.
+func (p *principal) hasRole(role Role) bool {
+	return slices.Contains(p.roles, role) || slices.Contains(p.roles, RoleAdmin)
+}
+
+// tokenFile is the content of the file with the bearer tokens of the clients of the backup-restore server.
+type tokenFile struct {
+	Tokens []tokenEntry `json:"tokens"`
+}
+
+// tokenEntry is a bearer token along with the name of the client it belongs to and the roles granted to it.
+type tokenEntry struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	Roles []Role `json:"roles"`
+}
+
+// readTokenFile reads and validates the token file at the given path.
+func readTokenFile(path string) (*tokenFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- this is a trusted token file.
+	if err != nil {
+		return nil, fmt.Errorf("unable to read token file: %v", err)
+	}
+	tokens := &tokenFile{}
+	if err := yaml.Unmarshal(data, tokens); err != nil {
+		return nil, fmt.Errorf("unable to parse token file: %v", err)
+	}
+	for i, entry := range tokens.Tokens {
+		if entry.Name == "" || entry.Token == "" {
+			return nil, fmt.Errorf("token %d of token file is missing its name or token", i)
+		}
+		if err := validateRoles(entry.Roles); err != nil {
+			return nil, fmt.Errorf("token %s of token file is invalid: %v", entry.Name, err)
+		}
+	}
+	return tokens, nil
+}
+
+// validateRoles checks that the given roles are known to the backup-restore server.
+func validateRoles(grantedRoles []Role) error {
+	for _, role := range grantedRoles {
+		if !slices.Contains(roles, role) {
+			return fmt.Errorf("unknown role %q", role)
+		}
+	}
+	return nil
+}
+
+// authenticator authenticates the clients of the backup-restore server by bearer tokens listed in a token file,
+// and by client certificates verified against the client CA bundle of the server.
+type authenticator struct {
+	// tokenFile is the path of the token file, it is read on every request so that changes take effect without a restart.
+	tokenFile string
+}
+
+// authenticateToken returns the principal the given bearer token belongs to.
+func (a *authenticator) authenticateToken(token string) (*principal, error) {
+	if a.tokenFile == "" {
+		return nil, errInvalidToken
+	}
+	tokens, err := readTokenFile(a.tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range tokens.Tokens {
+		if subtle.ConstantTimeCompare([]byte(entry.Token), []byte(token)) == 1 {
+			return &principal{name: entry.Name, roles: entry.Roles}, nil
+		}
+	}
+	return nil, errInvalidToken
+}
+
+// authenticateCertificate returns the principal of the given verified client certificate. The principal is named
+// after the common name of the certificate, and is granted the roles listed as organizations of the certificate.
+func (a *authenticator) authenticateCertificate(cert *x509.Certificate) *principal {
+	p := &principal{name: cert.Subject.CommonName}
+	for _, organization := range cert.Subject.Organization {
+		if role := Role(organization); slices.Contains(roles, role) {
+			p.roles = append(p.roles, role)
+		}
+	}
+	return p
+}
+
+// authenticate returns the principal of a request with the given authorization header and TLS connection state,
+// identified by its bearer token or else by its verified client certificate.
+func (a *authenticator) authenticate(authorization string, state *tls.ConnectionState) (*principal, error) {
+	if authorization != "" {
+		token, found := strings.CutPrefix(authorization, "Bearer ")
+		if !found {
+			return nil, errInvalidToken
+		}
+		return a.authenticateToken(token)
+	}
+	if state != nil && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		return a.authenticateCertificate(state.VerifiedChains[0][0]), nil
+	}
+	return nil, errUnauthenticated
+}
+
+// principalContextKey is the key of the authenticated principal in the context of a request.
+type principalContextKey struct{}
+
+// principalFromContext returns the authenticated principal of a request, or nil if authentication is disabled.
+func principalFromContext(ctx context.Context) *principal {
+	p, _ := ctx.Value(principalContextKey{}).(*principal)
+	return p
+}
+
+// isAuthenticationEnabled returns true if clients of the server are to be authenticated.
+func (h *HTTPHandler) isAuthenticationEnabled() bool {
+	return h.ClientCAFile != "" || h.TokenFile != ""
+}
+
+// withRole wraps the given handler, so that it only serves requests of principals which have been granted the
+// given role. If authentication is disabled, all requests are served.
+func (h *HTTPHandler) withRole(role Role, handler http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if !h.isAuthenticationEnabled() {
+			handler(rw, req)
+			return
+		}
+		h.checkAndSetSecurityHeaders(rw)
+		a := &authenticator{tokenFile: h.TokenFile}
+		p, err := a.authenticate(req.Header.Get("Authorization"), req.TLS)
+		if err != nil {
+			h.Logger.Warnf("Rejecting unauthenticated request to %s from %s: %v", req.URL.Path, req.RemoteAddr, err)
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !p.hasRole(role) {
+			h.Logger.Warnf("Rejecting request to %s from %s as %s has not been granted the %s role", req.URL.Path, req.RemoteAddr, p.name, role)
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+		handler(rw, req.WithContext(context.WithValue(req.Context(), principalContextKey{}, p)))
+	}
+}
+
+// loadCertPool loads the PEM encoded certificates of the given CA bundle file into a certificate pool.
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	caCerts, err := os.ReadFile(caFile) // #nosec G304 -- this is a trusted CA bundle file.
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCerts) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const testTokenFile = `tokens:
+- name: dashboard
+  token: read-token
+  roles: ["read"]
+- name: operator
+  token: operator-token
+  roles: ["trigger", "initialize"]
+- name: admin
+  token: admin-token
+  roles: ["admin"]
+`
+
+func TestWithRole(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte(testTokenFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	handler := &HTTPHandler{Logger: logrus.NewEntry(logrus.New()), TokenFile: tokenFile, ClientCAFile: "ca.crt"}
+
+	for _, test := range []struct {
+		description    string
+		role           Role
+		authorization  string
+		organizations  []string
+		expectedStatus int
+	}{
+		{"no credentials", RoleRead, "", nil, http.StatusUnauthorized},
+		{"unknown token", RoleRead, "Bearer other-token", nil, http.StatusUnauthorized},
+		{"malformed authorization", RoleRead, "Basic read-token", nil, http.StatusUnauthorized},
+		{"token with role", RoleRead, "Bearer read-token", nil, http.StatusOK},
+		{"token without role", RoleTrigger, "Bearer read-token", nil, http.StatusForbidden},
+		{"token with one of several roles", RoleInitialize, "Bearer operator-token", nil, http.StatusOK},
+		{"admin token", RoleDownload, "Bearer admin-token", nil, http.StatusOK},
+		{"certificate with role", RoleTrigger, "", []string{"trigger"}, http.StatusOK},
+		{"certificate without role", RoleInitialize, "", []string{"read", "system:masters"}, http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/snapshot/full", nil)
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+		if test.organizations != nil {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: "client", Organization: test.organizations}}
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		var p *principal
+		rr := httptest.NewRecorder()
+		handler.withRole(test.role, func(rw http.ResponseWriter, req *http.Request) {
+			p = principalFromContext(req.Context())
+			rw.WriteHeader(http.StatusOK)
+		})(rr, req)
+
+		if rr.Code != test.expectedStatus {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", test.description, rr.Code, test.expectedStatus)
+		}
+		if rr.Code == http.StatusOK && p == nil {
+			t.Fatalf("%s: principal of the request is missing", test.description)
+		}
+	}
+}
+
+func TestWithRoleWithoutAuthentication(t *testing.T) {
+	handler := &HTTPHandler{Logger: logrus.NewEntry(logrus.New())}
+	rr := httptest.NewRecorder()
+	handler.withRole(RoleInitialize, func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})(rr, httptest.NewRequest(http.MethodGet, "/initialization/start", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestReadTokenFile(t *testing.T) {
+	for _, test := range []struct {
+		content   string
+		expectErr bool
+	}{
+		{testTokenFile, false},
+		{"tokens:\n- name: dashboard\n  token: read-token\n  roles: [\"write\"]\n", true},
+		{"tokens:\n- name: dashboard\n  roles: [\"read\"]\n", true},
+		{"tokens: {", true},
+	} {
+		tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+		if err := os.WriteFile(tokenFile, []byte(test.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readTokenFile(tokenFile); (err != nil) != test.expectErr {
+			t.Fatalf("unexpected result reading token file %q: %v", test.content, err)
+		}
+	}
+}
+
+func TestValidateTokenFileRequiresTLS(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte(testTokenFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	for _, file := range []string{certFile, keyFile} {
+		if err := os.WriteFile(file, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewHTTPServerConfig()
+	c.TokenFile = tokenFile
+	if err := c.Validate(); err == nil {
+		t.Fatal("Validate() accepted a token file without TLS")
+	}
+	c.TLSCertFile, c.TLSKeyFile = certFile, keyFile
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() rejected a token file with TLS: %v", err)
+	}
+}
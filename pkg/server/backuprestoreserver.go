@@ -16,16 +16,20 @@ import (
 	"github.com/gardener/etcd-backup-restore/pkg/errors"
 	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
 	"github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
 	"github.com/gardener/etcd-backup-restore/pkg/health/heartbeat"
 	"github.com/gardener/etcd-backup-restore/pkg/initializer"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
 	"github.com/gardener/etcd-backup-restore/pkg/leaderelection"
 	"github.com/gardener/etcd-backup-restore/pkg/member"
 	"github.com/gardener/etcd-backup-restore/pkg/metrics"
 	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
 	"github.com/gardener/etcd-backup-restore/pkg/snapshot/copier"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/restoredrill"
 	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
 	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+	"github.com/gardener/etcd-backup-restore/pkg/wrappers"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
@@ -40,7 +44,20 @@ type BackupRestoreServer struct {
 	logger                  *logrus.Entry
 	config                  *BackupRestoreComponentConfig
 	defragmentationSchedule cron.Schedule
+	restoreDrillSchedule    cron.Schedule
 	backoffConfig           *backoff.ExponentialBackoff
+	events                  *events.Broadcaster
+	jobs                    *jobs.Manager
+	// configMutex guards the parts of the config which can be reloaded, along with the running snapshotters.
+	configMutex      sync.Mutex
+	snapshotters     []*snapshotter.Snapshotter
+	defragScheduleCh chan cron.Schedule
+	// pauseMutex guards the pause status of the snapshotting, which is kept across snapshotters, along with the
+	// snapshotter whose pause status is tracked.
+	pauseMutex          sync.Mutex
+	pauseStatus         brtypes.PauseStatus
+	pauseStatusStore    pauseStatusStore
+	pausableSnapshotter *snapshotter.Snapshotter
 }
 
 var (
@@ -56,13 +73,24 @@ func NewBackupRestoreServer(logger *logrus.Logger, config *BackupRestoreComponen
 		// Ideally this case should not occur, since this check is done at the config validaitions.
 		return nil, err
 	}
+	var restoreDrillSchedule cron.Schedule
+	if config.RestoreDrillConfig.IsEnabled() {
+		if restoreDrillSchedule, err = cron.ParseStandard(config.RestoreDrillConfig.Schedule); err != nil {
+			// Ideally this case should not occur, since this check is done at the config validaitions.
+			return nil, err
+		}
+	}
 	exponentialBackoffConfig := backoff.NewExponentialBackOffConfig(config.ExponentialBackoffConfig.AttemptLimit, config.ExponentialBackoffConfig.Multiplier, config.ExponentialBackoffConfig.ThresholdTime.Duration)
 
 	return &BackupRestoreServer{
 		logger:                  serverLogger,
 		config:                  config,
 		defragmentationSchedule: defragmentationSchedule,
+		restoreDrillSchedule:    restoreDrillSchedule,
 		backoffConfig:           exponentialBackoffConfig,
+		events:                  events.NewBroadcaster(),
+		jobs:                    jobs.NewManager(serverLogger),
+		defragScheduleCh:        make(chan cron.Schedule, 1),
 	}, nil
 }
 
@@ -128,10 +156,17 @@ func (b *BackupRestoreServer) startHTTPServer(initializer initializer.Initialize
 		EnableTLS:            b.config.ServerConfig.TLSCertFile != "" && b.config.ServerConfig.TLSKeyFile != "",
 		ServerTLSCertFile:    b.config.ServerConfig.TLSCertFile,
 		ServerTLSKeyFile:     b.config.ServerConfig.TLSKeyFile,
+		ClientCAFile:         b.config.ServerConfig.ClientCAFile,
+		TokenFile:            b.config.ServerConfig.TokenFile,
 		HTTPHandlerMutex:     &sync.Mutex{},
 		EtcdConnectionConfig: etcdConfig,
 		StorageProvider:      storageProvider,
 		SnapstoreConfig:      snapstoreConfig,
+		EventBroadcaster:     b.events,
+		JobManager:           b.jobs,
+		RestorationConfig:    b.config.RestorationConfig,
+		CompactorConfig:      b.newJobCompactorConfig(),
+		HealthConfig:         b.config.HealthConfig,
 	}
 	handler.SetStatus(http.StatusServiceUnavailable)
 	b.logger.Info("Registering the http request handlers...")
@@ -142,6 +177,18 @@ func (b *BackupRestoreServer) startHTTPServer(initializer initializer.Initialize
 	return handler
 }
 
+// newJobCompactorConfig returns the compactor config for the compaction jobs, which renew the snapshot leases like the
+// snapshotter does.
+func (b *BackupRestoreServer) newJobCompactorConfig() *brtypes.CompactorConfig {
+	config := brtypes.NewCompactorConfig()
+	config.EnabledLeaseRenewal = b.config.HealthConfig.SnapshotLeaseRenewalEnabled
+	config.FullSnapshotLeaseName = b.config.HealthConfig.FullSnapshotLeaseName
+	config.DeltaSnapshotLeaseName = b.config.HealthConfig.DeltaSnapshotLeaseName
+	// the metrics of the server are scraped anyway, so there is no need to wait after compaction
+	config.MetricsScrapeWaitDuration = wrappers.Duration{}
+	return config
+}
+
 func waitUntilEtcdRunning(ctx context.Context, etcdConnectionConfig *brtypes.EtcdConnectionConfig, logger *logrus.Logger) error {
 	ticker := time.NewTicker(4 * time.Second)
 	defer ticker.Stop()
@@ -210,6 +257,17 @@ func (b *BackupRestoreServer) runServer(ctx context.Context, restoreOpts *brtype
 		}
 	}()
 
+	go b.jobs.Run(ctx)
+
+	if b.config.ServerConfig.GRPCPort != 0 {
+		grpcServer, err := NewGRPCServer(handler, b.config.ServerConfig.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("failed to create gRPC server: %v", err)
+		}
+		go grpcServer.Start()
+		defer grpcServer.Stop()
+	}
+
 	metrics.CurrentClusterSize.With(prometheus.Labels{}).Set(float64(restoreOpts.OriginalClusterSize))
 
 	if err := waitUntilEtcdRunning(ctx, b.config.EtcdConnectionConfig, b.logger.Logger); err != nil {
@@ -219,8 +277,17 @@ func (b *BackupRestoreServer) runServer(ctx context.Context, restoreOpts *brtype
 	if err := b.updatePeerURLIfChanged(ctx, handler.EnableTLS, b.logger.Logger); err != nil {
 		b.logger.Errorf("failed to update member peer url: %v", err)
 	}
+
+	if runServerWithSnapshotter && b.config.HealthConfig.SnapshotLeaseRenewalEnabled {
+		if store, err := newLeasePauseStatusStore(b.config.HealthConfig); err != nil {
+			b.logger.Warnf("Failed to create the pause status store, the pause of the snapshotting will not survive restarts: %v", err)
+		} else {
+			b.pauseStatusStore = store
+		}
+	}
 	leaderCallbacks := &brtypes.LeaderCallbacks{
 		OnStartedLeading: func(leCtx context.Context) {
+			b.events.Publish(events.Event{Type: events.TypeLeadershipChanged, Message: "started leading"})
 			ssrStopCh = make(chan struct{})
 			var err error
 			var defragCallBack defragmentor.CallbackFunc
@@ -238,7 +305,10 @@ func (b *BackupRestoreServer) runServer(ctx context.Context, restoreOpts *brtype
 					if err != nil {
 						b.logger.Fatalf("failed to create secondary snapstore from configured storage provider: %v", err)
 					}
-					backupssr, err := snapshotter.NewSnapshotter(b.logger, b.config.SnapshotterConfig, ss, b.config.EtcdConnectionConfig, b.config.CompressionConfig, b.config.HealthConfig, b.config.SecondarySnapstoreConfig.StoreConfig)
+					// the delta snapshots are only spooled by the snapshotter of the primary snapstore
+					backupSnapshotterConfig := *b.snapshotterConfig()
+					backupSnapshotterConfig.DeltaSnapshotSpoolDir = ""
+					backupssr, err := snapshotter.NewSnapshotter(b.logger, &backupSnapshotterConfig, ss, b.config.EtcdConnectionConfig, b.config.CompressionConfig, b.config.HealthConfig, b.config.SecondarySnapstoreConfig.StoreConfig)
 					if err != nil {
 						b.logger.Fatalf("failed to create snapshot backup copier: %v", err)
 					}
@@ -247,24 +317,36 @@ func (b *BackupRestoreServer) runServer(ctx context.Context, restoreOpts *brtype
 					if err := cp.SyncBackups(leCtx, b.config.SecondarySnapstoreConfig.SyncPeriod.Duration); err != nil {
 						b.logger.Fatalf("failed to sync backups: %v", err)
 					}
+					b.registerSnapshotter(backupssr, false)
 					go backupssr.RunGarbageCollector(backupGcStop)
 				}
 
 				// Get the new snapshotter object
 				b.logger.Infof("Creating snapshotter...")
-				ssr, err = snapshotter.NewSnapshotter(b.logger, b.config.SnapshotterConfig, ss, b.config.EtcdConnectionConfig, b.config.CompressionConfig, b.config.HealthConfig, b.config.SnapstoreConfig)
+				ssr, err = snapshotter.NewSnapshotter(b.logger, b.snapshotterConfig(), ss, b.config.EtcdConnectionConfig, b.config.CompressionConfig, b.config.HealthConfig, b.config.SnapstoreConfig)
 				if err != nil {
 					b.logger.Fatalf("failed to create new Snapshotter object: %v", err)
 				}
+				ssr.EventBroadcaster = b.events
+				b.registerSnapshotter(ssr, true)
 
 				// set "http handler" with the latest snapshotter object
 				handler.SetSnapshotter(ssr)
 				go handleSsrStopRequest(leCtx, b.logger, ssrStopCh)
+				go ssr.RunDeltaSnapshotSpoolUploader(ssrStopCh)
+
+				if b.restoreDrillSchedule != nil {
+					b.logger.Infof("Starting periodic restore drills...")
+					drill := restoredrill.NewDrill(ss, b.config.RestoreDrillConfig, b.config.RestorationConfig, b.logger)
+					go restoredrill.RunDrillsPeriodically(leCtx, drill, b.restoreDrillSchedule)
+				}
 			}
 			go b.runEtcdProbeLoopWithSnapshotter(leCtx, handler, ssr, ss, ssrStopCh)
-			go defragmentor.DefragDataPeriodically(leCtx, b.config.EtcdConnectionConfig, b.defragmentationSchedule, defragCallBack, b.logger)
+			go defragmentor.DefragDataPeriodicallyWithScheduleUpdates(leCtx, b.config.EtcdConnectionConfig, b.currentDefragmentationSchedule(), b.defragScheduleCh, defragCallBack, b.logger)
 		},
 		OnStoppedLeading: func() {
+			b.events.Publish(events.Event{Type: events.TypeLeadershipChanged, Message: "stopped leading"})
+			b.unregisterSnapshotters()
 			if runServerWithSnapshotter {
 				b.logger.Info("backup-restore stops leading...")
 				if b.config.SecondarySnapstoreConfig.BackupSyncEnabled {
@@ -316,6 +398,7 @@ func (b *BackupRestoreServer) runServer(ctx context.Context, restoreOpts *brtype
 	if err != nil {
 		return err
 	}
+	handler.SetLeaderElector(le)
 
 	if b.config.HealthConfig.MemberLeaseRenewalEnabled {
 		go func() {
@@ -422,7 +505,13 @@ func (b *BackupRestoreServer) runEtcdProbeLoopWithSnapshotter(ctx context.Contex
 			// the delta snapshot memory limit), after which a full snapshot
 			// is taken and the regular snapshot schedule comes into effect.
 
-			fullSnapshotMaxTimeWindowInHours := ssr.GetFullSnapshotMaxTimeWindow(b.config.SnapshotterConfig.FullSnapshotSchedule)
+			// the snapshots taken at startup are written to the store as well, so they wait for a pause to end
+			if !ssr.WaitUntilResumed(ssrStopCh) {
+				b.logger.Info("Snapshotter stopped.")
+				b.logger.Info("Shutting down...")
+				return
+			}
+			fullSnapshotMaxTimeWindowInHours := ssr.GetFullSnapshotMaxTimeWindow(b.snapshotterConfig().FullSnapshotSchedule)
 			initialDeltaSnapshotTaken = false
 			if !ssr.IsFullSnapshotRequiredAtStartup(fullSnapshotMaxTimeWindowInHours) {
 				ssrStopped, err := ssr.CollectEventsSincePrevSnapshot(ssrStopCh)
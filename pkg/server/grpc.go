@@ -0,0 +1,423 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/api/backuprestore"
+	backuprestorev1 "github.com/gardener/etcd-backup-restore/pkg/api/backuprestore/v1"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// eventStreamBufferSize is the number of events buffered for each stream of backup events.
+const eventStreamBufferSize = 100
+
+// grpcUnauthenticatedMethods are the methods of the gRPC API which are served without authentication.
+var grpcUnauthenticatedMethods = map[string]bool{
+	backuprestorev1.BackupRestore_Health_FullMethodName: true,
+}
+
+// grpcMethodRoles are the roles required to call the methods of the gRPC API. Calls of authenticated methods without a
+// role are rejected.
+var grpcMethodRoles = map[string]Role{
+	backuprestorev1.BackupRestore_TriggerSnapshot_FullMethodName:         RoleTrigger,
+	backuprestorev1.BackupRestore_ListSnapshots_FullMethodName:           RoleRead,
+	backuprestorev1.BackupRestore_GetLatestSnapshots_FullMethodName:      RoleRead,
+	backuprestorev1.BackupRestore_StartInitialization_FullMethodName:     RoleInitialize,
+	backuprestorev1.BackupRestore_GetInitializationStatus_FullMethodName: RoleRead,
+	backuprestorev1.BackupRestore_WatchEvents_FullMethodName:             RoleRead,
+	backuprestorev1.BackupRestore_SubmitRestoreJob_FullMethodName:        RoleJobs,
+	backuprestorev1.BackupRestore_SubmitCompactionJob_FullMethodName:     RoleJobs,
+	backuprestorev1.BackupRestore_GetJob_FullMethodName:                  RoleRead,
+	backuprestorev1.BackupRestore_ListJobs_FullMethodName:                RoleRead,
+	backuprestorev1.BackupRestore_CancelJob_FullMethodName:               RoleJobs,
+	backuprestorev1.BackupRestore_PauseSnapshotting_FullMethodName:       RoleTrigger,
+	backuprestorev1.BackupRestore_ResumeSnapshotting_FullMethodName:      RoleTrigger,
+}
+
+// GRPCServer serves the gRPC API of the backup-restore server, which mirrors the HTTP API served by the HTTPHandler.
+type GRPCServer struct {
+	backuprestorev1.UnimplementedBackupRestoreServer
+
+	handler *HTTPHandler
+	server  *grpc.Server
+	logger  *logrus.Entry
+	port    uint
+}
+
+// NewGRPCServer returns a gRPC server on the given port, which serves the state of the given HTTPHandler. It uses the
+// TLS and authentication configuration of the handler.
+func NewGRPCServer(handler *HTTPHandler, port uint) (*GRPCServer, error) {
+	s := &GRPCServer{
+		handler: handler,
+		logger:  handler.Logger.WithField("actor", "grpc-server"),
+		port:    port,
+	}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.authorizeUnary),
+		grpc.StreamInterceptor(s.authorizeStream),
+	}
+	if handler.EnableTLS {
+		cert, err := tls.LoadX509KeyPair(handler.ServerTLSCertFile, handler.ServerTLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load server TLS cert and key: %v", err)
+		}
+		tlsConfig := &tls.Config{ // #nosec G402 -- TLSConfig.MinVersion=1.2 by default.
+			Certificates: []tls.Certificate{cert},
+		}
+		if handler.ClientCAFile != "" {
+			if tlsConfig.ClientCAs, err = loadCertPool(handler.ClientCAFile); err != nil {
+				return nil, fmt.Errorf("unable to load client CA bundle: %v", err)
+			}
+			// Client certificates are optional, as clients may authenticate with bearer tokens instead.
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
+	backuprestorev1.RegisterBackupRestoreServer(s.server, s)
+	return s, nil
+}
+
+// Start starts the gRPC server to listen for requests.
+func (s *GRPCServer) Start() {
+	s.logger.Infof("Starting gRPC server at port: %d", s.port)
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		s.logger.Fatalf("Failed to listen on gRPC server port: %v", err)
+	}
+	if err := s.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		s.logger.Fatalf("Failed to start gRPC server: %v", err)
+	}
+	s.logger.Infof("gRPC server closed gracefully.")
+}
+
+// Stop stops the gRPC server, cancelling the open streams.
+func (s *GRPCServer) Stop() {
+	s.server.Stop()
+}
+
+// authorizeUnary only lets requests of principals which have been granted the role of the method through.
+func (s *GRPCServer) authorizeUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authorizeStream only lets streams of principals which have been granted the role of the method through.
+func (s *GRPCServer) authorizeStream(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorize checks that the caller of the given method has been granted the role of the method, if authentication is enabled.
+func (s *GRPCServer) authorize(ctx context.Context, fullMethod string) error {
+	if grpcUnauthenticatedMethods[fullMethod] || !s.handler.isAuthenticationEnabled() {
+		return nil
+	}
+	role, ok := grpcMethodRoles[fullMethod]
+	if !ok {
+		s.logger.Warnf("Rejecting call of %s as no role is granting it", fullMethod)
+		return status.Errorf(codes.PermissionDenied, "method %s is not allowed", fullMethod)
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &tlsInfo.State
+		}
+	}
+
+	a := &authenticator{tokenFile: s.handler.TokenFile}
+	p, err := a.authenticate(authorization, state)
+	if err != nil {
+		s.logger.Warnf("Rejecting unauthenticated call of %s: %v", fullMethod, err)
+		return status.Error(codes.Unauthenticated, "invalid or missing credentials")
+	}
+	if !p.hasRole(role) {
+		s.logger.Warnf("Rejecting call of %s as %s has not been granted the %s role", fullMethod, p.name, role)
+		return status.Errorf(codes.PermissionDenied, "the %s role is required", role)
+	}
+	return nil
+}
+
+// snapshotter returns the snapshotter of the handler, or an error if the member is not running the snapshotter.
+func (s *GRPCServer) snapshotter() (*snapshotter.Snapshotter, error) {
+	s.handler.HTTPHandlerMutex.Lock()
+	defer s.handler.HTTPHandlerMutex.Unlock()
+	if s.handler.Snapshotter == nil {
+		if len(s.handler.StorageProvider) > 0 {
+			return nil, status.Error(codes.FailedPrecondition, "snapshotter is not running on this member, send the request to the backup-restore leader")
+		}
+		return nil, status.Error(codes.FailedPrecondition, "snapshotter is not configured")
+	}
+	return s.handler.Snapshotter, nil
+}
+
+// store returns the configured snapstore, or an error if no storage provider is configured.
+func (s *GRPCServer) store() (brtypes.SnapStore, error) {
+	if len(s.handler.StorageProvider) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "storage provider is not configured")
+	}
+	store, err := snapstore.GetSnapstore(s.handler.SnapstoreConfig)
+	if err != nil {
+		s.logger.Warnf("Unable to create snapstore from configured storage provider: %v", err)
+		return nil, status.Errorf(codes.Internal, "unable to create snapstore: %v", err)
+	}
+	return store, nil
+}
+
+// TriggerSnapshot takes an out-of-schedule snapshot.
+func (s *GRPCServer) TriggerSnapshot(ctx context.Context, req *backuprestorev1.TriggerSnapshotRequest) (*backuprestorev1.TriggerSnapshotResponse, error) {
+	if err := brtypes.ValidateSnapshotLabels(req.Labels); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot labels: %v", err)
+	}
+	ssr, err := s.snapshotter()
+	if err != nil {
+		return nil, err
+	}
+	var snap *brtypes.Snapshot
+	switch req.Kind {
+	case brtypes.SnapshotKindFull:
+		snap, err = ssr.TriggerFullSnapshot(ctx, req.Final)
+	case brtypes.SnapshotKindDelta:
+		snap, err = ssr.TriggerDeltaSnapshot()
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot kind %q, expected %s or %s", req.Kind, brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta)
+	}
+	if errors.Is(err, snapshotter.ErrSnapshottingPaused) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		s.logger.Warnf("Skipped triggering out-of-schedule %s snapshot: %v", req.Kind, err)
+		return nil, status.Errorf(codes.Internal, "unable to take snapshot: %v", err)
+	}
+	if err := s.handler.saveSnapshotLabels(snap, req.Labels); err != nil {
+		s.logger.Warnf("Took out-of-schedule %s snapshot, but unable to save its labels: %v", req.Kind, err)
+		if errors.Is(err, snapshotter.ErrSnapshottingPaused) {
+			return nil, status.Errorf(codes.FailedPrecondition, "took snapshot, but unable to save its labels: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "took snapshot, but unable to save its labels: %v", err)
+	}
+	return &backuprestorev1.TriggerSnapshotResponse{Snapshot: backuprestore.SnapshotToProto(snap)}, nil
+}
+
+// ListSnapshots lists a page of the snapshots in the store.
+func (s *GRPCServer) ListSnapshots(_ context.Context, msg *backuprestorev1.ListSnapshotsRequest) (*backuprestorev1.ListSnapshotsResponse, error) {
+	req := backuprestore.ListSnapshotsRequestFromProto(msg)
+	switch req.Kind {
+	case "", brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid kind %q, expected %s or %s", req.Kind, brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta)
+	}
+	if err := brtypes.ValidateSnapshotLabels(req.Labels); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid labels: %v", err)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = miscellaneous.DefaultSnapshotListLimit
+	}
+	if limit < 0 || limit > miscellaneous.MaxSnapshotListLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d, got %d", miscellaneous.MaxSnapshotListLimit, limit)
+	}
+	store, err := s.store()
+	if err != nil {
+		return nil, err
+	}
+	filter := &miscellaneous.SnapshotFilter{
+		Kind:          req.Kind,
+		MinRevision:   req.MinRevision,
+		MaxRevision:   req.MaxRevision,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Final:         req.Final,
+		Labels:        req.Labels,
+	}
+	snapList, continueToken, err := miscellaneous.ListSnapshots(store, req.IncludeAll, filter, limit, req.Continue)
+	if errors.Is(err, miscellaneous.ErrInvalidContinueToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		s.logger.Warnf("Unable to list snapshots from snapstore: %v", err)
+		return nil, status.Errorf(codes.Internal, "unable to list snapshots: %v", err)
+	}
+	return &backuprestorev1.ListSnapshotsResponse{Snapshots: backuprestore.SnapListToProto(snapList), Continue: continueToken}, nil
+}
+
+// GetLatestSnapshots returns the latest full snapshot and the delta snapshots on top of it.
+func (s *GRPCServer) GetLatestSnapshots(_ context.Context, _ *backuprestorev1.GetLatestSnapshotsRequest) (*backuprestorev1.GetLatestSnapshotsResponse, error) {
+	store, err := s.store()
+	if err != nil {
+		return nil, err
+	}
+	fullSnap, deltaSnaps, err := getLatestSnapshots(store)
+	if err != nil {
+		s.logger.Warnf("Unable to fetch latest snapshots from snapstore: %v", err)
+		return nil, status.Errorf(codes.Internal, "unable to fetch latest snapshots: %v", err)
+	}
+	return &backuprestorev1.GetLatestSnapshotsResponse{
+		FullSnapshot:   backuprestore.SnapshotToProto(fullSnap),
+		DeltaSnapshots: backuprestore.SnapListToProto(deltaSnaps),
+	}, nil
+}
+
+// StartInitialization starts the initialization of the etcd data directory.
+func (s *GRPCServer) StartInitialization(_ context.Context, req *backuprestorev1.StartInitializationRequest) (*backuprestorev1.StartInitializationResponse, error) {
+	s.logger.Info("Received start initialization request.")
+	return &backuprestorev1.StartInitializationResponse{Status: s.handler.startInitialization(req.Mode)}, nil
+}
+
+// GetInitializationStatus returns the status of the initialization of the etcd data directory.
+func (s *GRPCServer) GetInitializationStatus(_ context.Context, _ *backuprestorev1.GetInitializationStatusRequest) (*backuprestorev1.GetInitializationStatusResponse, error) {
+	return &backuprestorev1.GetInitializationStatusResponse{Status: s.handler.readInitializationStatus()}, nil
+}
+
+// Health returns the health of the backup-restore server.
+func (s *GRPCServer) Health(_ context.Context, _ *backuprestorev1.HealthRequest) (*backuprestorev1.HealthResponse, error) {
+	return &backuprestorev1.HealthResponse{
+		Healthy: s.handler.GetStatus() == http.StatusOK,
+		Pause:   backuprestore.PauseStatusToProto(s.handler.snapshottingPauseStatus()),
+	}, nil
+}
+
+// WatchEvents streams the backup events published from now on, until the client cancels the stream.
+func (s *GRPCServer) WatchEvents(_ *backuprestorev1.WatchEventsRequest, stream grpc.ServerStreamingServer[backuprestorev1.Event]) error {
+	if s.handler.EventBroadcaster == nil {
+		return status.Error(codes.Unimplemented, "backup events are not published")
+	}
+	eventCh, cancel := s.handler.EventBroadcaster.Subscribe(eventStreamBufferSize)
+	defer cancel()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-eventCh:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "client is too slow to receive the backup events, watch the events again")
+			}
+			if err := stream.Send(backuprestore.EventToProto(&event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SubmitRestoreJob submits a job restoring the backups into the directory of the request.
+func (s *GRPCServer) SubmitRestoreJob(_ context.Context, req *backuprestorev1.SubmitRestoreJobRequest) (*backuprestorev1.Job, error) {
+	return jobResponse(s.handler.submitRestoreJob(backuprestore.SubmitRestoreJobRequestFromProto(req)))
+}
+
+// SubmitCompactionJob submits a job compacting the latest backups into a new full snapshot.
+func (s *GRPCServer) SubmitCompactionJob(_ context.Context, req *backuprestorev1.SubmitCompactionJobRequest) (*backuprestorev1.Job, error) {
+	return jobResponse(s.handler.submitCompactionJob(&backuprestore.SubmitCompactionJobRequest{Defragment: req.Defragment}))
+}
+
+// GetJob returns the status of a job.
+func (s *GRPCServer) GetJob(_ context.Context, req *backuprestorev1.GetJobRequest) (*backuprestorev1.Job, error) {
+	if s.handler.JobManager == nil {
+		return nil, jobStatusError(errJobsUnavailable)
+	}
+	return jobResponse(s.handler.JobManager.Get(req.Id))
+}
+
+// ListJobs returns the status of all jobs.
+func (s *GRPCServer) ListJobs(_ context.Context, _ *backuprestorev1.ListJobsRequest) (*backuprestorev1.ListJobsResponse, error) {
+	if s.handler.JobManager == nil {
+		return nil, jobStatusError(errJobsUnavailable)
+	}
+	resp := &backuprestorev1.ListJobsResponse{}
+	for _, job := range s.handler.JobManager.List() {
+		resp.Jobs = append(resp.Jobs, backuprestore.JobToProto(job))
+	}
+	return resp, nil
+}
+
+// CancelJob cancels a pending or running job.
+func (s *GRPCServer) CancelJob(_ context.Context, req *backuprestorev1.CancelJobRequest) (*backuprestorev1.Job, error) {
+	if s.handler.JobManager == nil {
+		return nil, jobStatusError(errJobsUnavailable)
+	}
+	return jobResponse(s.handler.JobManager.Cancel(req.Id))
+}
+
+// PauseSnapshotting pauses the scheduled snapshots and the garbage collection.
+func (s *GRPCServer) PauseSnapshotting(_ context.Context, req *backuprestorev1.PauseSnapshottingRequest) (*backuprestorev1.PauseStatus, error) {
+	ssr, err := s.snapshotter()
+	if err != nil {
+		return nil, err
+	}
+	var until time.Time
+	if req.Until != nil {
+		until = req.Until.AsTime()
+	}
+	pauseStatus, err := ssr.Pause(until)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return backuprestore.PauseStatusToProto(pauseStatus), nil
+}
+
+// ResumeSnapshotting resumes the scheduled snapshots and the garbage collection.
+func (s *GRPCServer) ResumeSnapshotting(_ context.Context, _ *backuprestorev1.ResumeSnapshottingRequest) (*backuprestorev1.PauseStatus, error) {
+	ssr, err := s.snapshotter()
+	if err != nil {
+		return nil, err
+	}
+	return backuprestore.PauseStatusToProto(ssr.Resume()), nil
+}
+
+// jobResponse returns the message of the given job status, or the gRPC status error of the given error.
+func jobResponse(job *jobs.Job, err error) (*backuprestorev1.Job, error) {
+	if err != nil {
+		return nil, jobStatusError(err)
+	}
+	return backuprestore.JobToProto(job), nil
+}
+
+// jobStatusError returns the gRPC status error for an error returned when submitting, fetching or cancelling a job.
+func jobStatusError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, errJobsUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errInvalidJob):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, jobs.ErrJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, jobs.ErrJobFinished), errors.Is(err, snapshotter.ErrSnapshottingPaused):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, jobs.ErrQueueFull):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/api/backuprestore"
+	backuprestorev1 "github.com/gardener/etcd-backup-restore/pkg/api/backuprestore/v1"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startTestGRPCServer serves the gRPC API of the given handler on a local port and returns a client connected to it.
+func startTestGRPCServer(t *testing.T, handler *HTTPHandler) *backuprestore.Client {
+	t.Helper()
+	s, err := NewGRPCServer(handler, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return backuprestore.NewClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup", Prefix: "v2"}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := brtypes.Snapshot{Kind: brtypes.SnapshotKindFull, StartRevision: 0, LastRevision: 5, CreatedOn: time.Now().UTC()}
+	snap.GenerateSnapshotName()
+	if err := store.Save(snap, io.NopCloser(bytes.NewReader([]byte("snapshot")))); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &HTTPHandler{
+		Logger:               logrus.NewEntry(logrus.New()),
+		HTTPHandlerMutex:     &sync.Mutex{},
+		StorageProvider:      brtypes.SnapstoreProviderLocal,
+		SnapstoreConfig:      snapstoreConfig,
+		EventBroadcaster:     events.NewBroadcaster(),
+		initializationStatus: initializationStatusNew,
+	}
+	handler.SetStatus(http.StatusOK)
+	client := startTestGRPCServer(t, handler)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	health, err := client.Health(ctx)
+	if err != nil || !health.Healthy {
+		t.Fatalf("unexpected health: %v, %v", health, err)
+	}
+	initStatus, err := client.GetInitializationStatus(ctx)
+	if err != nil || initStatus.Status != initializationStatusNew {
+		t.Fatalf("unexpected initialization status: %v, %v", initStatus, err)
+	}
+
+	list, err := client.ListSnapshots(ctx, &backuprestore.ListSnapshotsRequest{Kind: brtypes.SnapshotKindFull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Snapshots) != 1 || list.Snapshots[0].SnapName != snap.SnapName || list.Snapshots[0].CreatedOn.Unix() != snap.CreatedOn.Unix() {
+		t.Fatalf("unexpected snapshots listed: %v", list.Snapshots)
+	}
+	if _, err := client.ListSnapshots(ctx, &backuprestore.ListSnapshotsRequest{Continue: "invalid"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("unexpected error listing snapshots with invalid continue token: %v", err)
+	}
+	latest, err := client.GetLatestSnapshots(ctx)
+	if err != nil || latest.FullSnapshot == nil || latest.FullSnapshot.SnapName != snap.SnapName {
+		t.Fatalf("unexpected latest snapshots: %v, %v", latest, err)
+	}
+	if _, err := client.TriggerSnapshot(ctx, &backuprestore.TriggerSnapshotRequest{Kind: brtypes.SnapshotKindFull}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("unexpected error triggering snapshot without snapshotter: %v", err)
+	}
+	if _, err := client.PauseSnapshotting(ctx, &backuprestore.PauseSnapshottingRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("unexpected error pausing snapshotting without snapshotter: %v", err)
+	}
+	if _, err := client.SubmitRestoreJob(ctx, &backuprestore.SubmitRestoreJobRequest{DataDir: t.TempDir()}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("unexpected error submitting restore job without job manager: %v", err)
+	}
+
+	stream, err := client.WatchEvents(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The subscription is only registered once the server has started to handle the stream, so keep publishing
+	// until the event is received.
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				handler.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken, Snapshot: &snap})
+			}
+		}
+	}()
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != events.TypeSnapshotTaken || event.Snapshot == nil || event.Snapshot.SnapName != snap.SnapName {
+		t.Fatalf("unexpected event received: %v", event)
+	}
+}
+
+func TestGRPCServerAuthorization(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte(testTokenFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	handler := &HTTPHandler{
+		Logger:               logrus.NewEntry(logrus.New()),
+		HTTPHandlerMutex:     &sync.Mutex{},
+		TokenFile:            tokenFile,
+		initializationStatus: initializationStatusNew,
+	}
+	client := startTestGRPCServer(t, handler)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, test := range []struct {
+		description  string
+		token        string
+		expectedCode codes.Code
+	}{
+		{"no credentials", "", codes.Unauthenticated},
+		{"unknown token", "other-token", codes.Unauthenticated},
+		{"token without role", "operator-token", codes.PermissionDenied},
+		{"token with role", "read-token", codes.OK},
+	} {
+		var opts []grpc.CallOption
+		if test.token != "" {
+			opts = append(opts, grpc.PerRPCCredentials(backuprestore.BearerToken{Token: test.token, AllowInsecure: true}))
+		}
+		if _, err := client.GetInitializationStatus(ctx, opts...); status.Code(err) != test.expectedCode {
+			t.Fatalf("%s: unexpected error: got %v want code %v", test.description, err, test.expectedCode)
+		}
+	}
+	if _, err := client.Health(ctx); err != nil {
+		t.Fatalf("health is expected to be served without credentials: %v", err)
+	}
+
+	s := &GRPCServer{handler: handler, logger: handler.Logger}
+	md := metadata.Pairs("authorization", "Bearer read-token")
+	if err := s.authorize(metadata.NewIncomingContext(ctx, md), "/"+backuprestorev1.BackupRestore_ServiceDesc.ServiceName+"/Unknown"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("method without role: unexpected error: got %v want code %v", err, codes.PermissionDenied)
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	"github.com/gardener/etcd-backup-restore/pkg/health/heartbeat"
+	"github.com/gardener/etcd-backup-restore/pkg/leaderelection"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+const (
+	// snapstoreProbeInterval is the minimum interval between two probes of the snapstore for the health report.
+	snapstoreProbeInterval = 30 * time.Second
+	// snapstoreProbeTimeout is the time after which a probe of the snapstore is reported as failed.
+	snapstoreProbeTimeout = 10 * time.Second
+)
+
+// snapstoreProbe caches the health of the snapstore, so that frequent health reports do not list the store each time.
+type snapstoreProbe struct {
+	mutex       sync.Mutex
+	checkedOn   time.Time
+	lastSuccess *time.Time
+	health      brtypes.ComponentHealth
+}
+
+// serveHealthReport responds with the health of each component of backup-restore on this member
+func (h *HTTPHandler) serveHealthReport(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	report := h.healthReport(req.Context(), time.Now().UTC())
+	out, err := json.Marshal(report)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal health report to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if report.Healthy {
+		rw.WriteHeader(http.StatusOK)
+	} else {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write health report response: %v", err)
+	}
+}
+
+// healthReport checks the health of each component of backup-restore on this member.
+func (h *HTTPHandler) healthReport(ctx context.Context, now time.Time) *brtypes.HealthReport {
+	le := h.currentLeaderElector()
+	report := &brtypes.HealthReport{
+		CheckedOn:      now,
+		Etcd:           h.etcdHealth(ctx, now),
+		LeaderElection: leaderElectionHealth(le),
+		Snapstore:      h.snapstoreHealth(now),
+	}
+
+	ssr := h.currentSnapshotter()
+	switch {
+	case len(h.StorageProvider) == 0:
+		notConfigured := brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "no storage provider is configured"}
+		report.Snapshotter, report.FullSnapshot, report.DeltaSnapshot, report.GarbageCollection = notConfigured, notConfigured, notConfigured, notConfigured
+	case ssr == nil:
+		notRunning := brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "the snapshotter only runs on the backup-restore leader"}
+		if le != nil && le.CurrentState == leaderelection.StateLeader {
+			notRunning = brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "the snapshotter is being created"}
+		}
+		report.Snapshotter, report.FullSnapshot, report.DeltaSnapshot, report.GarbageCollection = notRunning, notRunning, notRunning, notRunning
+	default:
+		report.Snapshotter = brtypes.ComponentHealth{Status: brtypes.ComponentStatusHealthy, Message: "the snapshotter is active"}
+		if pauseStatus := ssr.GetPauseStatus(); pauseStatus.Paused {
+			report.Snapshotter.Message = "the snapshotting is paused"
+			if pauseStatus.Until != nil {
+				report.Snapshotter.Message += " until " + pauseStatus.Until.Format(time.RFC3339)
+			}
+		} else if !ssr.IsSnapshotterStateActive() {
+			report.Snapshotter = brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: "the snapshotter is not active, it is either starting or restarting after a failure"}
+		}
+		report.FullSnapshot = ssr.FullSnapshotHealth(now)
+		report.DeltaSnapshot = ssr.DeltaSnapshotHealth(now)
+		report.GarbageCollection = ssr.GarbageCollectionHealth()
+	}
+	report.LeaseRenewal = h.leaseRenewalHealth(ssr != nil)
+
+	report.Healthy = !slices.ContainsFunc([]brtypes.ComponentHealth{
+		report.Etcd,
+		report.LeaderElection,
+		report.Snapshotter,
+		report.FullSnapshot,
+		report.DeltaSnapshot,
+		report.Snapstore,
+		report.LeaseRenewal,
+		report.GarbageCollection,
+	}, func(component brtypes.ComponentHealth) bool {
+		return component.Status == brtypes.ComponentStatusUnhealthy
+	})
+	return report
+}
+
+// etcdHealth checks whether the etcd member is reachable and has a leader.
+func (h *HTTPHandler) etcdHealth(ctx context.Context, now time.Time) brtypes.ComponentHealth {
+	if h.EtcdConnectionConfig == nil || len(h.EtcdConnectionConfig.Endpoints) == 0 {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "no etcd endpoint is configured"}
+	}
+	endpoint := h.EtcdConnectionConfig.Endpoints[0]
+	client, err := etcdutil.NewFactory(*h.EtcdConnectionConfig).NewMaintenance()
+	if err != nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("failed to create etcd maintenance client: %v", err)}
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, brtypes.DefaultEtcdStatusConnecTimeout)
+	defer cancel()
+	response, err := client.Status(ctx, endpoint)
+	if err != nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("failed to get status of etcd endpoint %s: %v", endpoint, err)}
+	}
+	switch {
+	case len(response.Errors) != 0:
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("etcd endpoint %s reports errors: %s", endpoint, strings.Join(response.Errors, "; "))}
+	case response.Leader == leaderelection.NoLeaderState:
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("etcd endpoint %s has no leader", endpoint)}
+	}
+	return brtypes.ComponentHealth{
+		Status:      brtypes.ComponentStatusHealthy,
+		Message:     fmt.Sprintf("etcd %s at %s is reachable at revision %d", response.Version, endpoint, response.Header.GetRevision()),
+		LastSuccess: &now,
+	}
+}
+
+// leaderElectionHealth returns the health of the leader election from the state of the given leader elector.
+func leaderElectionHealth(le *leaderelection.LeaderElector) brtypes.ComponentHealth {
+	if le == nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "the leader election has not started yet"}
+	}
+	switch state := le.CurrentState; state {
+	case leaderelection.StateLeader:
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusHealthy, Message: "this member is the backup-restore leader"}
+	case leaderelection.StateUnknown:
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: "the status of the etcd member cannot be determined"}
+	default:
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusHealthy, Message: fmt.Sprintf("this member is a backup-restore %s", strings.ToLower(state))}
+	}
+}
+
+// snapstoreHealth checks whether the snapshots in the snapstore can be listed. The result is cached for the snapstore
+// probe interval.
+func (h *HTTPHandler) snapstoreHealth(now time.Time) brtypes.ComponentHealth {
+	if len(h.StorageProvider) == 0 || h.SnapstoreConfig == nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "no storage provider is configured"}
+	}
+	probe := &h.snapstoreProbe
+	probe.mutex.Lock()
+	defer probe.mutex.Unlock()
+	if !probe.checkedOn.IsZero() && now.Sub(probe.checkedOn) < snapstoreProbeInterval {
+		return probe.health
+	}
+
+	probe.health = probeSnapstore(h.SnapstoreConfig)
+	probe.checkedOn = now
+	if probe.health.Status == brtypes.ComponentStatusHealthy {
+		probe.lastSuccess = &now
+	}
+	probe.health.LastSuccess = probe.lastSuccess
+	return probe.health
+}
+
+// probeSnapstore lists the snapshots in the configured snapstore.
+func probeSnapstore(config *brtypes.SnapstoreConfig) brtypes.ComponentHealth {
+	store, err := snapstore.GetSnapstore(config)
+	if err != nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("failed to create snapstore from configured storage provider: %v", err)}
+	}
+	// the snapstore cannot be cancelled, so the listing is left running if it takes too long
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := store.List(false)
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("failed to list snapshots in the %s snapstore: %v", config.Provider, err)}
+		}
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusHealthy, Message: fmt.Sprintf("snapshots in the %s snapstore can be listed", config.Provider)}
+	case <-time.After(snapstoreProbeTimeout):
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: fmt.Sprintf("listing snapshots in the %s snapstore did not finish within %s", config.Provider, snapstoreProbeTimeout)}
+	}
+}
+
+// leaseRenewalHealth returns the health of the lease renewal, based on the outcome of the latest renewal of each
+// enabled lease. The snapshot leases are only renewed while the snapshotter runs.
+func (h *HTTPHandler) leaseRenewalHealth(snapshotterRunning bool) brtypes.ComponentHealth {
+	var kinds []string
+	if h.HealthConfig != nil && h.HealthConfig.MemberLeaseRenewalEnabled {
+		kinds = append(kinds, heartbeat.LeaseKindMember)
+	}
+	if h.HealthConfig != nil && h.HealthConfig.SnapshotLeaseRenewalEnabled && snapshotterRunning {
+		kinds = append(kinds, heartbeat.LeaseKindFullSnapshot, heartbeat.LeaseKindDeltaSnapshot)
+	}
+	if len(kinds) == 0 {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "no lease is renewed on this member"}
+	}
+
+	renewals := heartbeat.LeaseRenewals()
+	health := brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown}
+	var messages []string
+	for _, kind := range kinds {
+		renewal, ok := renewals[kind]
+		switch {
+		case !ok:
+			messages = append(messages, fmt.Sprintf("%s lease has not been renewed yet", kind))
+		case renewal.LastError != "":
+			health.Status = brtypes.ComponentStatusUnhealthy
+			messages = append(messages, fmt.Sprintf("renewing %s lease at %s failed: %s", kind, renewal.LastAttempt.Format(time.RFC3339), renewal.LastError))
+		default:
+			if health.Status == brtypes.ComponentStatusUnknown {
+				health.Status = brtypes.ComponentStatusHealthy
+			}
+			messages = append(messages, fmt.Sprintf("%s lease was renewed at %s", kind, renewal.LastSuccess.Format(time.RFC3339)))
+		}
+	}
+	health.Message = strings.Join(messages, "; ")
+	return health
+}
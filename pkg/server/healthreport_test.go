@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	"github.com/gardener/etcd-backup-restore/pkg/leaderelection"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestServeHealthReport(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup"}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.NewEntry(logrus.New())
+	ssr, err := snapshotter.NewSnapshotter(logger, snapshotter.NewSnapshotterConfig(), store, brtypes.NewEtcdConnectionConfig(), compressor.NewCompressorConfig(), brtypes.NewHealthConfig(), snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &HTTPHandler{
+		Logger:            logger,
+		StorageProvider:   brtypes.SnapstoreProviderLocal,
+		SnapstoreConfig:   snapstoreConfig,
+		HTTPHandlerMutex:  &sync.Mutex{},
+		HealthConfig:      brtypes.NewHealthConfig(),
+		JobManager:        jobs.NewManager(logger),
+		RestorationConfig: brtypes.NewRestorationConfig(),
+		CompactorConfig:   brtypes.NewCompactorConfig(),
+	}
+	handler.RegisterHandler()
+
+	serve := func() (int, *brtypes.HealthReport) {
+		rr := httptest.NewRecorder()
+		handler.server.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz/report", nil))
+		report := &brtypes.HealthReport{}
+		if err := json.Unmarshal(rr.Body.Bytes(), report); err != nil {
+			t.Fatal(err)
+		}
+		return rr.Code, report
+	}
+
+	code, report := serve()
+	if code != http.StatusOK || !report.Healthy {
+		t.Fatalf("member without snapshotter is expected to be healthy: got %v %+v", code, report)
+	}
+	for _, test := range []struct {
+		name      string
+		component brtypes.ComponentHealth
+		expected  brtypes.ComponentStatus
+	}{
+		{"etcd", report.Etcd, brtypes.ComponentStatusUnknown},
+		{"leader election", report.LeaderElection, brtypes.ComponentStatusUnknown},
+		{"snapshotter", report.Snapshotter, brtypes.ComponentStatusDisabled},
+		{"snapstore", report.Snapstore, brtypes.ComponentStatusHealthy},
+		{"lease renewal", report.LeaseRenewal, brtypes.ComponentStatusDisabled},
+	} {
+		if test.component.Status != test.expected {
+			t.Fatalf("unexpected status of %s: got %v want %v", test.name, test.component.Status, test.expected)
+		}
+	}
+	if report.Snapstore.LastSuccess == nil {
+		t.Fatal("successful snapstore probe is expected to be reported")
+	}
+
+	handler.SetLeaderElector(&leaderelection.LeaderElector{CurrentState: leaderelection.StateLeader})
+	handler.SetSnapshotter(ssr)
+	code, report = serve()
+	if code != http.StatusServiceUnavailable || report.Healthy {
+		t.Fatalf("inactive snapshotter is expected to be unhealthy: got %v %+v", code, report)
+	}
+	if report.LeaderElection.Status != brtypes.ComponentStatusHealthy || report.Snapshotter.Status != brtypes.ComponentStatusUnhealthy {
+		t.Fatalf("unexpected status of leader election or snapshotter: %+v", report)
+	}
+
+	ssr.SetSnapshotterActive()
+	code, report = serve()
+	if code != http.StatusOK || !report.Healthy || report.Snapshotter.Status != brtypes.ComponentStatusHealthy {
+		t.Fatalf("active snapshotter is expected to be healthy: got %v %+v", code, report)
+	}
+	if report.FullSnapshot.Status != brtypes.ComponentStatusUnknown || report.DeltaSnapshot.Status != brtypes.ComponentStatusUnknown {
+		t.Fatalf("snapshots are expected to be unknown before the first snapshot: %+v", report)
+	}
+
+	handler.SetLeaderElector(&leaderelection.LeaderElector{CurrentState: leaderelection.StateUnknown})
+	if _, report = serve(); report.Healthy || report.LeaderElection.Status != brtypes.ComponentStatusUnhealthy {
+		t.Fatalf("unknown leader election state is expected to be unhealthy: %+v", report)
+	}
+
+	// the snapshotter is replaced while leadership changes, which must not race with the health report
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			handler.SetSnapshotterToNil()
+			handler.SetSnapshotter(ssr)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		serve()
+	}
+	wg.Wait()
+}
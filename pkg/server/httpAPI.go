@@ -23,10 +23,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
 	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
 	etcdclient "github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
 	"github.com/gardener/etcd-backup-restore/pkg/initializer"
 	"github.com/gardener/etcd-backup-restore/pkg/initializer/validator"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	"github.com/gardener/etcd-backup-restore/pkg/leaderelection"
 	"github.com/gardener/etcd-backup-restore/pkg/member"
 	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
 	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
@@ -85,6 +89,15 @@ type HTTPHandler struct {
 	initializationStatus      string
 	ServerTLSCertFile         string
 	ServerTLSKeyFile          string
+	ClientCAFile              string
+	TokenFile                 string
+	EventBroadcaster          *events.Broadcaster
+	JobManager                *jobs.Manager
+	RestorationConfig         *brtypes.RestorationConfig
+	CompactorConfig           *brtypes.CompactorConfig
+	HealthConfig              *brtypes.HealthConfig
+	leaderElector             *leaderelection.LeaderElector
+	snapstoreProbe            snapstoreProbe
 	status                    int
 	Port                      uint
 	initializationStatusMutex sync.Mutex
@@ -96,6 +109,9 @@ type HTTPHandler struct {
 // healthCheck contains the HealthStatus of backup restore.
 type healthCheck struct {
 	HealthStatus bool `json:"health"`
+	// Pause is the pause status of the snapshotter, which is only set on the backup-restore leader while the
+	// snapshotting is paused.
+	Pause *brtypes.PauseStatus `json:"pause,omitempty"`
 }
 
 // GetStatus returns the current status in the HTTPHandler
@@ -125,20 +141,63 @@ func (h *HTTPHandler) SetSnapshotter(ssr *snapshotter.Snapshotter) {
 	h.Snapshotter = ssr
 }
 
+// SetLeaderElector sets the leader elector, whose state is reported by the health report, in the HTTPHandler.
+func (h *HTTPHandler) SetLeaderElector(le *leaderelection.LeaderElector) {
+	h.HTTPHandlerMutex.Lock()
+	defer h.HTTPHandlerMutex.Unlock()
+	h.leaderElector = le
+}
+
+// currentLeaderElector returns the leader elector set in the HTTPHandler, if any.
+func (h *HTTPHandler) currentLeaderElector() *leaderelection.LeaderElector {
+	h.HTTPHandlerMutex.Lock()
+	defer h.HTTPHandlerMutex.Unlock()
+	return h.leaderElector
+}
+
+// currentSnapshotter returns the Snapshotter set in the HTTPHandler, if any.
+func (h *HTTPHandler) currentSnapshotter() *snapshotter.Snapshotter {
+	h.HTTPHandlerMutex.Lock()
+	defer h.HTTPHandlerMutex.Unlock()
+	return h.Snapshotter
+}
+
+// isSnapshottingPaused returns true if the Snapshotter set in the HTTPHandler is paused.
+func (h *HTTPHandler) isSnapshottingPaused() bool {
+	h.HTTPHandlerMutex.Lock()
+	defer h.HTTPHandlerMutex.Unlock()
+	return h.Snapshotter != nil && h.Snapshotter.IsPaused()
+}
+
 // RegisterHandler registers the handler for different requests
 func (h *HTTPHandler) RegisterHandler() {
 	mux := http.NewServeMux()
 	if h.EnableProfiling {
-		registerPProfHandler(mux)
+		h.registerPProfHandler(mux)
 	}
 
 	h.initializationStatus = "New"
-	mux.HandleFunc("/initialization/start", h.serveInitialize)
-	mux.HandleFunc("/initialization/status", h.serveInitializationStatus)
-	mux.HandleFunc("/snapshot/full", h.serveFullSnapshotTrigger)
-	mux.HandleFunc("/snapshot/delta", h.serveDeltaSnapshotTrigger)
-	mux.HandleFunc("/snapshot/latest", h.serveLatestSnapshotMetadata)
-	mux.HandleFunc("/config", h.serveConfig)
+	mux.HandleFunc("/initialization/start", h.withRole(RoleInitialize, h.serveInitialize))
+	mux.HandleFunc("/initialization/status", h.withRole(RoleRead, h.serveInitializationStatus))
+	mux.HandleFunc("/snapshot/full", h.withRole(RoleTrigger, h.serveFullSnapshotTrigger))
+	mux.HandleFunc("/snapshot/delta", h.withRole(RoleTrigger, h.serveDeltaSnapshotTrigger))
+	mux.HandleFunc("/snapshot/latest", h.withRole(RoleRead, h.serveLatestSnapshotMetadata))
+	mux.HandleFunc("/snapshots", h.withRole(RoleRead, h.serveSnapshotList))
+	mux.HandleFunc("/snapshots/{name...}", h.withRole(RoleRead, h.serveSnapshotDetails))
+	mux.HandleFunc("/snapshot/gc/dry-run", h.withRole(RoleRead, h.serveGarbageCollectionDryRun))
+	mux.HandleFunc("/snapshot/download", h.withRole(RoleDownload, h.serveSnapshotDownload))
+	mux.HandleFunc("/snapshot/pin", h.withRole(RoleTrigger, h.serveSnapshotPin))
+	mux.HandleFunc("/snapshot/unpin", h.withRole(RoleTrigger, h.serveSnapshotUnpin))
+	mux.HandleFunc("/snapshot/pause", h.withRole(RoleTrigger, h.serveSnapshottingPause))
+	mux.HandleFunc("/snapshot/resume", h.withRole(RoleTrigger, h.serveSnapshottingResume))
+	mux.HandleFunc("/healthz/report", h.withRole(RoleRead, h.serveHealthReport))
+	mux.HandleFunc("/config", h.withRole(RoleRead, h.serveConfig))
+	mux.HandleFunc("/jobs", h.withRole(RoleRead, h.serveJobList))
+	mux.HandleFunc("/jobs/restore", h.withRole(RoleJobs, h.serveRestoreJobSubmission))
+	mux.HandleFunc("/jobs/compaction", h.withRole(RoleJobs, h.serveCompactionJobSubmission))
+	mux.HandleFunc("/jobs/{id}", h.withRole(RoleRead, h.serveJob))
+	mux.HandleFunc("/jobs/{id}/cancel", h.withRole(RoleJobs, h.serveJobCancellation))
+	// The health and metrics endpoints are not authenticated, so that they can be used by probes and scrapers.
 	mux.HandleFunc("/healthz", h.serveHealthz)
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -150,17 +209,17 @@ func (h *HTTPHandler) RegisterHandler() {
 }
 
 // registerPProfHandler registers the PProf handler for profiling.
-func registerPProfHandler(mux *http.ServeMux) {
-	mux.HandleFunc("/debug/pprof/", pprof.Index)
-	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
-	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-	mux.HandleFunc("/debug/pprof/heap", pprof.Handler("heap").ServeHTTP)
-	mux.HandleFunc("/debug/pprof/goroutine", pprof.Handler("goroutine").ServeHTTP)
-	mux.HandleFunc("/debug/pprof/threadcreate", pprof.Handler("threadcreate").ServeHTTP)
-	mux.HandleFunc("/debug/pprof/block", pprof.Handler("block").ServeHTTP)
-	mux.HandleFunc("/debug/pprof/mutex", pprof.Handler("mutex").ServeHTTP)
+func (h *HTTPHandler) registerPProfHandler(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", h.withRole(RoleAdmin, pprof.Index))
+	mux.HandleFunc("/debug/pprof/profile", h.withRole(RoleAdmin, pprof.Profile))
+	mux.HandleFunc("/debug/pprof/symbol", h.withRole(RoleAdmin, pprof.Symbol))
+	mux.HandleFunc("/debug/pprof/trace", h.withRole(RoleAdmin, pprof.Trace))
+	mux.HandleFunc("/debug/pprof/cmdline", h.withRole(RoleAdmin, pprof.Cmdline))
+	mux.HandleFunc("/debug/pprof/heap", h.withRole(RoleAdmin, pprof.Handler("heap").ServeHTTP))
+	mux.HandleFunc("/debug/pprof/goroutine", h.withRole(RoleAdmin, pprof.Handler("goroutine").ServeHTTP))
+	mux.HandleFunc("/debug/pprof/threadcreate", h.withRole(RoleAdmin, pprof.Handler("threadcreate").ServeHTTP))
+	mux.HandleFunc("/debug/pprof/block", h.withRole(RoleAdmin, pprof.Handler("block").ServeHTTP))
+	mux.HandleFunc("/debug/pprof/mutex", h.withRole(RoleAdmin, pprof.Handler("mutex").ServeHTTP))
 }
 
 // checkAndSetSecurityHeaders serves the health status of the server
@@ -185,6 +244,18 @@ func (h *HTTPHandler) Start() {
 	}
 
 	h.Logger.Infof("TLS enabled. Starting HTTPS server.")
+	if h.ClientCAFile != "" {
+		clientCAs, err := loadCertPool(h.ClientCAFile)
+		if err != nil {
+			h.Logger.Fatalf("Failed to load client CA bundle: %v", err)
+		}
+		// Client certificates are optional, as clients may authenticate with bearer tokens instead.
+		h.server.TLSConfig = &tls.Config{ // #nosec G402 -- TLSConfig.MinVersion=1.2 by default.
+			ClientCAs:  clientCAs,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}
+		h.Logger.Infof("Client certificate authentication enabled.")
+	}
 
 	err := h.server.ListenAndServeTLS(h.ServerTLSCertFile, h.ServerTLSKeyFile)
 	if err != nil && err != http.ErrServerClosed {
@@ -206,6 +277,7 @@ func (h *HTTPHandler) serveHealthz(rw http.ResponseWriter, _ *http.Request) {
 		HealthStatus: func() bool {
 			return h.GetStatus() == http.StatusOK
 		}(),
+		Pause: h.snapshottingPauseStatus(),
 	}
 	out, err := json.Marshal(healthCheck)
 	if err != nil {
@@ -217,21 +289,40 @@ func (h *HTTPHandler) serveHealthz(rw http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+// snapshottingPauseStatus returns the pause status of the configured Snapshotter, or nil if there is no Snapshotter
+// or the snapshotting is not paused.
+func (h *HTTPHandler) snapshottingPauseStatus() *brtypes.PauseStatus {
+	ssr := h.currentSnapshotter()
+	if ssr == nil {
+		return nil
+	}
+	if status := ssr.GetPauseStatus(); status.Paused {
+		return status
+	}
+	return nil
+}
+
 // serveInitialize starts initialization for the configured Initializer
 func (h *HTTPHandler) serveInitialize(rw http.ResponseWriter, req *http.Request) {
 	h.checkAndSetSecurityHeaders(rw)
 	h.Logger.Info("Received start initialization request.")
+	h.startInitialization(req.URL.Query().Get("mode"))
+	rw.WriteHeader(http.StatusOK)
+}
+
+// startInitialization starts the initialization with the given validation mode in the background, unless an
+// initialization is in progress or its result has not been read yet. It returns the resulting initialization status.
+func (h *HTTPHandler) startInitialization(modeVal string) string {
 	h.initializationStatusMutex.Lock()
 	defer h.initializationStatusMutex.Unlock()
 	if h.initializationStatus == initializationStatusNew {
-		h.Logger.Infof("Updating status from %s to %s", h.initializationStatus, initializationStatusProgress)
-		h.initializationStatus = initializationStatusProgress
+		h.setInitializationStatus(initializationStatusProgress)
 		go func() {
 			var mode validator.Mode
 
 			h.SetStatus(http.StatusServiceUnavailable)
 
-			switch modeVal := req.URL.Query().Get("mode"); modeVal {
+			switch modeVal {
 			case string(validator.Full):
 				mode = validator.Full
 			case string(validator.Sanity):
@@ -245,34 +336,48 @@ func (h *HTTPHandler) serveInitialize(rw http.ResponseWriter, req *http.Request)
 			defer h.initializationStatusMutex.Unlock()
 			if err != nil {
 				h.Logger.Errorf("Failed initialization: %v", err)
-				h.initializationStatus = initializationStatusFailed
+				h.setInitializationStatus(initializationStatusFailed)
 				return
 			}
 			h.Logger.Info("Successfully initialized data directory for etcd.")
-			h.initializationStatus = initializationStatusSuccessful
+			h.setInitializationStatus(initializationStatusSuccessful)
 		}()
 	}
-	rw.WriteHeader(http.StatusOK)
+	return h.initializationStatus
 }
 
 // serveInitializationStatus serves the etcd initialization progress status
 func (h *HTTPHandler) serveInitializationStatus(rw http.ResponseWriter, _ *http.Request) {
 	h.checkAndSetSecurityHeaders(rw)
-	h.initializationStatusMutex.Lock()
-	defer h.initializationStatusMutex.Unlock()
-	h.Logger.Infof("Responding to status request with: %s", h.initializationStatus)
+	status := h.readInitializationStatus()
 
 	rw.WriteHeader(http.StatusOK)
 
-	if _, err := rw.Write([]byte(h.initializationStatus)); err != nil {
+	if _, err := rw.Write([]byte(status)); err != nil {
 		h.Logger.Errorf("Unable to write latest snapshot metadata response: %v", err)
-		return
 	}
+}
 
-	if h.initializationStatus == initializationStatusSuccessful || h.initializationStatus == initializationStatusFailed {
-		h.Logger.Infof("Updating status from %s to %s", h.initializationStatus, initializationStatusNew)
-		h.initializationStatus = initializationStatusNew
+// readInitializationStatus returns the initialization status. Once the result of an initialization has been read,
+// the status is reset so that the next initialization can be started.
+func (h *HTTPHandler) readInitializationStatus() string {
+	h.initializationStatusMutex.Lock()
+	defer h.initializationStatusMutex.Unlock()
+	h.Logger.Infof("Responding to status request with: %s", h.initializationStatus)
+
+	status := h.initializationStatus
+	if status == initializationStatusSuccessful || status == initializationStatusFailed {
+		h.setInitializationStatus(initializationStatusNew)
 	}
+	return status
+}
+
+// setInitializationStatus updates the initialization status and publishes the change. The caller must hold the
+// initialization status mutex.
+func (h *HTTPHandler) setInitializationStatus(status string) {
+	h.Logger.Infof("Updating status from %s to %s", h.initializationStatus, status)
+	h.initializationStatus = status
+	h.EventBroadcaster.Publish(events.Event{Type: events.TypeInitializationStatusChanged, Message: status})
 }
 
 // serveFullSnapshotTrigger triggers an out-of-schedule full snapshot
@@ -299,10 +404,21 @@ func (h *HTTPHandler) serveFullSnapshotTrigger(rw http.ResponseWriter, req *http
 			return
 		}
 	}
+	labels, err := parseSnapshotLabels(req.URL.Query())
+	if err != nil {
+		h.Logger.Warnf("Could not parse snapshot labels of request: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	s, err := h.Snapshotter.TriggerFullSnapshot(req.Context(), isFinal)
 	if err != nil {
 		h.Logger.Warnf("Skipped triggering out-of-schedule full snapshot: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
+		rw.WriteHeader(snapshotTriggerErrorStatus(err))
+		return
+	}
+	if err := h.saveSnapshotLabels(s, labels); err != nil {
+		h.Logger.Warnf("Took out-of-schedule full snapshot, but unable to save its labels: %v", err)
+		rw.WriteHeader(snapshotTriggerErrorStatus(err))
 		return
 	}
 	out, err := json.Marshal(s)
@@ -331,10 +447,21 @@ func (h *HTTPHandler) serveDeltaSnapshotTrigger(rw http.ResponseWriter, req *htt
 		rw.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
+	labels, err := parseSnapshotLabels(req.URL.Query())
+	if err != nil {
+		h.Logger.Warnf("Could not parse snapshot labels of request: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	s, err := h.Snapshotter.TriggerDeltaSnapshot()
 	if err != nil {
 		h.Logger.Warnf("Skipped triggering out-of-schedule delta snapshot: %v", err)
-		rw.WriteHeader(http.StatusInternalServerError)
+		rw.WriteHeader(snapshotTriggerErrorStatus(err))
+		return
+	}
+	if err := h.saveSnapshotLabels(s, labels); err != nil {
+		h.Logger.Warnf("Took out-of-schedule delta snapshot, but unable to save its labels: %v", err)
+		rw.WriteHeader(snapshotTriggerErrorStatus(err))
 		return
 	}
 
@@ -350,6 +477,14 @@ func (h *HTTPHandler) serveDeltaSnapshotTrigger(rw http.ResponseWriter, req *htt
 	}
 }
 
+// snapshotTriggerErrorStatus returns the HTTP status code for an error returned when triggering an out-of-schedule snapshot.
+func snapshotTriggerErrorStatus(err error) int {
+	if errors.Is(err, snapshotter.ErrSnapshottingPaused) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
 func (h *HTTPHandler) serveLatestSnapshotMetadata(rw http.ResponseWriter, req *http.Request) {
 	h.checkAndSetSecurityHeaders(rw)
 	if h.Snapshotter == nil {
@@ -370,7 +505,7 @@ func (h *HTTPHandler) serveLatestSnapshotMetadata(rw http.ResponseWriter, req *h
 		return
 	}
 
-	fullSnap, deltaSnaps, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+	fullSnap, deltaSnaps, err := getLatestSnapshots(store)
 	if err != nil {
 		h.Logger.Warnf("Unable to fetch latest snapshots from snapstore: %v", err)
 		rw.WriteHeader(http.StatusInternalServerError)
@@ -394,6 +529,493 @@ func (h *HTTPHandler) serveLatestSnapshotMetadata(rw http.ResponseWriter, req *h
 	}
 }
 
+// getLatestSnapshots returns the latest full snapshot and the delta snapshots on top of it, along with their labels.
+func getLatestSnapshots(store brtypes.SnapStore) (*brtypes.Snapshot, brtypes.SnapList, error) {
+	fullSnap, deltaSnaps, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapList := deltaSnaps
+	if fullSnap != nil {
+		snapList = append(brtypes.SnapList{fullSnap}, deltaSnaps...)
+	}
+	if err := snapstore.LoadSnapshotLabels(store, snapList); err != nil {
+		return nil, nil, fmt.Errorf("unable to fetch labels of latest snapshots: %v", err)
+	}
+	return fullSnap, deltaSnaps, nil
+}
+
+// parseSnapshotLabels returns the labels of an out-of-schedule snapshot from the request parameters.
+// Labels are passed as `label=key=value` parameters, and the reason for taking the snapshot as the `reason` parameter.
+func parseSnapshotLabels(query url.Values) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, label := range query["label"] {
+		key, value, found := strings.Cut(label, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", label)
+		}
+		labels[key] = value
+	}
+	if reason := query.Get(brtypes.SnapshotLabelReason); reason != "" {
+		labels[brtypes.SnapshotLabelReason] = reason
+	}
+	if err := brtypes.ValidateSnapshotLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// saveSnapshotLabels stores the given labels of the given out-of-schedule snapshot. It fails with
+// snapshotter.ErrSnapshottingPaused if the snapshotting has been paused in the meantime.
+func (h *HTTPHandler) saveSnapshotLabels(snap *brtypes.Snapshot, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	if snap == nil {
+		h.Logger.Info("Ignoring snapshot labels since no snapshot was taken")
+		return nil
+	}
+	if h.isSnapshottingPaused() {
+		return snapshotter.ErrSnapshottingPaused
+	}
+	store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+	}
+	return snapstore.SaveSnapshotLabels(store, snap, labels)
+}
+
+// serveSnapshotList lists a page of the snapshots in the configured snapstore,
+// which are selected by the filters of the request
+func (h *HTTPHandler) serveSnapshotList(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.Snapshotter == nil {
+		if len(h.StorageProvider) > 0 {
+			h.Logger.Info("Fowarding the snapshot list request to backup-restore leader")
+			h.delegateReqToLeader(rw, req)
+			return
+		}
+		h.Logger.Warnf("Ignoring snapshot list request as snapshotter is not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	query := req.URL.Query()
+	filter, err := parseSnapshotFilter(query)
+	if err != nil {
+		h.Logger.Warnf("Could not parse snapshot filter of request: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	includeAll := false
+	if includeAllValue := query.Get("includeAll"); includeAllValue != "" {
+		if includeAll, err = strconv.ParseBool(includeAllValue); err != nil {
+			h.Logger.Warnf("Could not parse request parameter 'includeAll' to bool: %v", err)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	limit := miscellaneous.DefaultSnapshotListLimit
+	if limitValue := query.Get("limit"); limitValue != "" {
+		if limit, err = strconv.Atoi(limitValue); err != nil || limit <= 0 || limit > miscellaneous.MaxSnapshotListLimit {
+			h.Logger.Warnf("Request parameter 'limit' must be a number between 1 and %d, got %q", miscellaneous.MaxSnapshotListLimit, limitValue)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+	if err != nil {
+		h.Logger.Warnf("Unable to create snapstore from configured storage provider: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	snapList, continueToken, err := miscellaneous.ListSnapshots(store, includeAll, filter, limit, query.Get("continue"))
+	if errors.Is(err, miscellaneous.ErrInvalidContinueToken) {
+		h.Logger.Warnf("Unable to list snapshots: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		h.Logger.Warnf("Unable to list snapshots from snapstore: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if snapList == nil {
+		snapList = brtypes.SnapList{}
+	}
+
+	out, err := json.Marshal(snapshotListResponse{Snapshots: snapList, Continue: continueToken})
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal snapshot list response to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write snapshot list response: %v", err)
+	}
+}
+
+// parseSnapshotFilter returns the filter of a snapshot listing from the request parameters.
+func parseSnapshotFilter(query url.Values) (*miscellaneous.SnapshotFilter, error) {
+	filter := &miscellaneous.SnapshotFilter{}
+	switch kind := query.Get("kind"); kind {
+	case "", brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta:
+		filter.Kind = kind
+	default:
+		return nil, fmt.Errorf("invalid kind %q, expected %s or %s", kind, brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta)
+	}
+	for param, revision := range map[string]*int64{"minRevision": &filter.MinRevision, "maxRevision": &filter.MaxRevision} {
+		if value := query.Get(param); value != "" {
+			var err error
+			if *revision, err = strconv.ParseInt(value, 10, 64); err != nil || *revision < 0 {
+				return nil, fmt.Errorf("invalid %s %q, expected a non-negative number", param, value)
+			}
+		}
+	}
+	for param, t := range map[string]*time.Time{"createdAfter": &filter.CreatedAfter, "createdBefore": &filter.CreatedBefore} {
+		if value := query.Get(param); value != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid %s %q, expected RFC3339 format: %v", param, value, err)
+			}
+		}
+	}
+	if value := query.Get("final"); value != "" {
+		final, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid final %q: %v", value, err)
+		}
+		filter.Final = &final
+	}
+	if value := query.Get("labels"); value != "" {
+		labels, err := brtypes.ParseSnapshotLabels(value)
+		if err != nil {
+			return nil, err
+		}
+		filter.Labels = labels
+	}
+	return filter, nil
+}
+
+// serveSnapshotDetails returns the full metadata of the snapshot with the name in the request path
+func (h *HTTPHandler) serveSnapshotDetails(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.Snapshotter == nil {
+		if len(h.StorageProvider) > 0 {
+			h.Logger.Info("Fowarding the snapshot details request to backup-restore leader")
+			h.delegateReqToLeader(rw, req)
+			return
+		}
+		h.Logger.Warnf("Ignoring snapshot details request as snapshotter is not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	name := req.PathValue("name")
+
+	store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+	if err != nil {
+		h.Logger.Warnf("Unable to create snapstore from configured storage provider: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	details, err := miscellaneous.GetSnapshotDetails(store, name)
+	if errors.Is(err, brtypes.ErrSnapshotNotFound) {
+		h.Logger.Warnf("Unable to get snapshot details: %v", err)
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.Logger.Warnf("Unable to get details of snapshot %s from snapstore: %v", name, err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	out, err := json.Marshal(details)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal snapshot details to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write snapshot details response: %v", err)
+	}
+}
+
+// serveGarbageCollectionDryRun reports which snapshots the garbage collector
+// of the configured Snapshotter would delete, keep or skip
+func (h *HTTPHandler) serveGarbageCollectionDryRun(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.Snapshotter == nil {
+		if len(h.StorageProvider) > 0 {
+			h.Logger.Info("Fowarding the garbage collection dry-run request to backup-restore leader")
+			h.delegateReqToLeader(rw, req)
+			return
+		}
+		h.Logger.Warnf("Ignoring garbage collection dry-run request as snapshotter is not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	report, err := h.Snapshotter.GarbageCollectionDryRun()
+	if err != nil {
+		h.Logger.Warnf("Unable to evaluate garbage collection policy: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	out, err := json.Marshal(report)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal garbage collection report to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write garbage collection report response: %v", err)
+	}
+}
+
+// serveSnapshotPin pins the full snapshot with the given name, protecting it from
+// garbage collection indefinitely or until the optional expiry time
+func (h *HTTPHandler) serveSnapshotPin(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	var expiry time.Time
+	if until := req.URL.Query().Get("until"); until != "" {
+		var err error
+		expiry, err = time.Parse(time.RFC3339, until)
+		if err != nil {
+			h.Logger.Warnf("Could not parse request parameter 'until' as RFC3339 time: %v", err)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	h.serveSnapshotPinUpdate(rw, req, "pin", func(store brtypes.SnapStore, snap *brtypes.Snapshot) error {
+		return snapstore.PinSnapshot(store, snap, expiry)
+	})
+}
+
+// serveSnapshotUnpin removes the pin of the full snapshot with the given name
+func (h *HTTPHandler) serveSnapshotUnpin(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	h.serveSnapshotPinUpdate(rw, req, "unpin", snapstore.UnpinSnapshot)
+}
+
+// serveSnapshotPinUpdate applies the given pin update to the snapshot named in the request, and responds with the updated snapshot
+func (h *HTTPHandler) serveSnapshotPinUpdate(rw http.ResponseWriter, req *http.Request, action string, update func(brtypes.SnapStore, *brtypes.Snapshot) error) {
+	if h.Snapshotter == nil {
+		if len(h.StorageProvider) > 0 {
+			h.Logger.Infof("Fowarding the snapshot %s request to backup-restore leader", action)
+			h.delegateReqToLeader(rw, req)
+			return
+		}
+		h.Logger.Warnf("Ignoring snapshot %s request as snapshotter is not configured", action)
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		h.Logger.Warnf("Ignoring snapshot %s request without request parameter 'name'", action)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if h.isSnapshottingPaused() {
+		h.Logger.Warnf("Ignoring snapshot %s request: %v", action, snapshotter.ErrSnapshottingPaused)
+		rw.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+	if err != nil {
+		h.Logger.Warnf("Unable to create snapstore from configured storage provider: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	snap, err := miscellaneous.GetSnapshot(store, name)
+	if errors.Is(err, brtypes.ErrSnapshotNotFound) {
+		h.Logger.Warnf("Unable to %s snapshot: %v", action, err)
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.Logger.Warnf("Unable to fetch snapshot %s from snapstore: %v", name, err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err := update(store, snap); err != nil {
+		h.Logger.Warnf("Unable to %s snapshot: %v", action, err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	out, err := json.Marshal(snap)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal snapshot to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write snapshot %s response: %v", action, err)
+	}
+}
+
+// serveSnapshottingPause pauses the scheduled snapshots and the garbage collection of the configured Snapshotter,
+// until the optional deadline given by the request parameter 'until' or 'duration'
+func (h *HTTPHandler) serveSnapshottingPause(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	until, err := parsePauseDeadline(req.URL.Query(), time.Now())
+	if err != nil {
+		h.Logger.Warnf("Could not parse pause deadline of request: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	h.serveSnapshottingPauseUpdate(rw, req, "pause", func(ssr *snapshotter.Snapshotter) (*brtypes.PauseStatus, error) {
+		return ssr.Pause(until)
+	})
+}
+
+// serveSnapshottingResume resumes the scheduled snapshots and the garbage collection of the configured Snapshotter
+func (h *HTTPHandler) serveSnapshottingResume(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	h.serveSnapshottingPauseUpdate(rw, req, "resume", func(ssr *snapshotter.Snapshotter) (*brtypes.PauseStatus, error) {
+		return ssr.Resume(), nil
+	})
+}
+
+// serveSnapshottingPauseUpdate applies the given pause update to the configured Snapshotter, and responds with the
+// resulting pause status
+func (h *HTTPHandler) serveSnapshottingPauseUpdate(rw http.ResponseWriter, req *http.Request, action string, update func(*snapshotter.Snapshotter) (*brtypes.PauseStatus, error)) {
+	if req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Snapshotter == nil {
+		if len(h.StorageProvider) > 0 {
+			h.Logger.Infof("Fowarding the snapshotting %s request to backup-restore leader", action)
+			h.delegateReqToLeader(rw, req)
+			return
+		}
+		h.Logger.Warnf("Ignoring snapshotting %s request as snapshotter is not configured", action)
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	status, err := update(h.Snapshotter)
+	if err != nil {
+		h.Logger.Warnf("Unable to %s snapshotting: %v", action, err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	out, err := json.Marshal(status)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal pause status to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write pause status response: %v", err)
+	}
+}
+
+// parsePauseDeadline returns the deadline of a pause given by the request parameter 'until' as RFC3339 time, or by the
+// request parameter 'duration' relative to the given time. It returns the zero time if neither is set.
+func parsePauseDeadline(query url.Values, now time.Time) (time.Time, error) {
+	until, duration := query.Get("until"), query.Get("duration")
+	switch {
+	case until != "" && duration != "":
+		return time.Time{}, fmt.Errorf("only one of the request parameters 'until' and 'duration' can be set")
+	case until != "":
+		deadline, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid request parameter 'until': %v", err)
+		}
+		return deadline, nil
+	case duration != "":
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid request parameter 'duration': %v", err)
+		}
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("request parameter 'duration' must be positive")
+		}
+		return now.Add(d), nil
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// serveSnapshotDownload streams the snapshot with the name given in the request parameter 'name' from the snapstore,
+// optionally decompressed and with its hash trailer stripped. Snapshots contain the whole etcd data, so they are only
+// served to clients which have been granted the download role, and not at all if authentication is disabled.
+func (h *HTTPHandler) serveSnapshotDownload(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if !h.isAuthenticationEnabled() {
+		h.Logger.Warnf("Ignoring snapshot download request as authentication is not enabled")
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if len(h.StorageProvider) == 0 {
+		h.Logger.Warnf("Ignoring snapshot download request as storage provider is not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	query := req.URL.Query()
+	name := query.Get("name")
+	if name == "" {
+		h.Logger.Warnf("Ignoring snapshot download request without request parameter 'name'")
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var decompress, stripHash bool
+	for param, value := range map[string]*bool{"decompress": &decompress, "stripHash": &stripHash} {
+		if rawValue := query.Get(param); rawValue != "" {
+			var err error
+			if *value, err = strconv.ParseBool(rawValue); err != nil {
+				h.Logger.Warnf("Could not parse request parameter '%s' to bool: %v", param, err)
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+	if err != nil {
+		h.Logger.Warnf("Unable to create snapstore from configured storage provider: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rc, snap, err := miscellaneous.OpenSnapshot(store, name, decompress, stripHash)
+	if errors.Is(err, brtypes.ErrSnapshotNotFound) {
+		h.Logger.Warnf("Unable to download snapshot: %v", err)
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	} else if errors.Is(err, miscellaneous.ErrCannotStripHash) {
+		h.Logger.Warnf("Unable to download snapshot: %v", err)
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	} else if err != nil {
+		h.Logger.Warnf("Unable to open snapshot %s from snapstore: %v", name, err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+
+	compressed := snap.CompressionSuffix != compressor.UnCompressSnapshotExtension
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", miscellaneous.SnapshotFileName(snap, compressed && decompress, stripHash)))
+	// the size of an encrypted snapshot is the size of the stored ciphertext, not of the decrypted snapshot served.
+	_, encrypted := store.(*snapstore.EncryptedSnapStore)
+	if snap.Size > 0 && !encrypted && (!compressed || !decompress) && !stripHash {
+		rw.Header().Set("Content-Length", strconv.FormatInt(snap.Size, 10))
+	}
+	rw.WriteHeader(http.StatusOK)
+	h.Logger.Infof("Streaming snapshot %s to %s", name, req.RemoteAddr)
+	if _, err := io.Copy(rw, rc); err != nil {
+		h.Logger.Errorf("Unable to stream snapshot %s: %v", name, err)
+		// Abort the response, so that the client does not mistake the partially streamed snapshot for a complete one.
+		panic(http.ErrAbortHandler)
+	}
+}
+
 func (h *HTTPHandler) serveConfig(rw http.ResponseWriter, req *http.Request) {
 	inputFileName := miscellaneous.EtcdConfigFilePath
 	dir, err := os.UserHomeDir()
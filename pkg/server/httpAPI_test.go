@@ -5,17 +5,37 @@
 package server
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
 )
 
 func TestHealthCheckHandler(t *testing.T) {
 	// HTTPHandler is implementation to handle HTTP API exposed by server
-	healthyHandler := HTTPHandler{}
+	healthyHandler := HTTPHandler{HTTPHandlerMutex: &sync.Mutex{}}
 	healthyHandler.SetStatus(http.StatusOK)
-	unhealthyHandler := HTTPHandler{}
+	unhealthyHandler := HTTPHandler{HTTPHandlerMutex: &sync.Mutex{}}
 	unhealthyHandler.SetStatus(http.StatusInternalServerError)
 	if err := healthCheckTest(healthyHandler.serveHealthz, http.StatusOK, true); err != nil {
 		t.Fatal(err)
@@ -54,3 +74,291 @@ func healthCheckTest(handlerFunc http.HandlerFunc, expectedStatus int, expectedH
 	}
 	return nil
 }
+
+func TestParseSnapshotLabels(t *testing.T) {
+	query, err := url.ParseQuery("final=true&label=ticket=OPS-1&label=system=ci&reason=before+the+upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels, err := parseSnapshotLabels(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"ticket": "OPS-1", "system": "ci", "reason": "before the upgrade"}
+	if !reflect.DeepEqual(labels, expected) {
+		t.Fatalf("parsed unexpected labels: got %v want %v", labels, expected)
+	}
+
+	for _, rawQuery := range []string{"label=ticket", "label==OPS-1"} {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parseSnapshotLabels(query); err == nil {
+			t.Fatalf("expected error parsing labels of %q", rawQuery)
+		}
+	}
+}
+
+func TestParseSnapshotFilter(t *testing.T) {
+	query, err := url.ParseQuery("kind=Full&minRevision=10&maxRevision=20&createdAfter=2024-06-01T00:00:00Z&final=false&labels=reason=pre-upgrade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := parseSnapshotFilter(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	final := false
+	expected := &miscellaneous.SnapshotFilter{
+		Kind:         brtypes.SnapshotKindFull,
+		MinRevision:  10,
+		MaxRevision:  20,
+		CreatedAfter: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Final:        &final,
+		Labels:       map[string]string{"reason": "pre-upgrade"},
+	}
+	if !reflect.DeepEqual(filter, expected) {
+		t.Fatalf("parsed unexpected filter: got %+v want %+v", filter, expected)
+	}
+
+	for _, rawQuery := range []string{"kind=Chunk", "minRevision=-1", "maxRevision=latest", "createdBefore=yesterday", "final=maybe", "labels=reason"} {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parseSnapshotFilter(query); err == nil {
+			t.Fatalf("expected error parsing filter of %q", rawQuery)
+		}
+	}
+}
+
+func TestServeSnapshotDownload(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup", Prefix: "v2"}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte(`[{"type":"put"}]`)
+	hash := sha256.Sum256(content)
+	snap := brtypes.Snapshot{Kind: brtypes.SnapshotKindDelta, StartRevision: 1, LastRevision: 2, CreatedOn: time.Now().UTC()}
+	snap.GenerateSnapshotName()
+	if err := store.Save(snap, io.NopCloser(bytes.NewReader(append(bytes.Clone(content), hash[:]...)))); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte(testTokenFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		description    string
+		tokenFile      string
+		authorization  string
+		query          string
+		expectedStatus int
+		expectedBody   []byte
+	}{
+		{"authentication disabled", "", "Bearer admin-token", "name=" + snap.SnapName, http.StatusForbidden, nil},
+		{"missing token", tokenFile, "", "name=" + snap.SnapName, http.StatusUnauthorized, nil},
+		{"wrong token", tokenFile, "Bearer other-token", "name=" + snap.SnapName, http.StatusUnauthorized, nil},
+		{"token without download role", tokenFile, "Bearer read-token", "name=" + snap.SnapName, http.StatusForbidden, nil},
+		{"missing name", tokenFile, "Bearer admin-token", "", http.StatusBadRequest, nil},
+		{"unknown snapshot", tokenFile, "Bearer admin-token", "name=Incr-00000003-00000004-1700000000", http.StatusNotFound, nil},
+		{"raw snapshot", tokenFile, "Bearer admin-token", "name=" + snap.SnapName, http.StatusOK, append(bytes.Clone(content), hash[:]...)},
+		{"stripped snapshot", tokenFile, "Bearer admin-token", "name=" + snap.SnapName + "&decompress=true&stripHash=true", http.StatusOK, content},
+	} {
+		handler := &HTTPHandler{
+			Logger:          logrus.NewEntry(logrus.New()),
+			StorageProvider: brtypes.SnapstoreProviderLocal,
+			SnapstoreConfig: snapstoreConfig,
+			TokenFile:       test.tokenFile,
+		}
+		req := httptest.NewRequest(http.MethodGet, "/snapshot/download?"+test.query, nil)
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+		rr := httptest.NewRecorder()
+		handler.withRole(RoleDownload, handler.serveSnapshotDownload)(rr, req)
+
+		if rr.Code != test.expectedStatus {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", test.description, rr.Code, test.expectedStatus)
+		}
+		if test.expectedBody != nil && !bytes.Equal(rr.Body.Bytes(), test.expectedBody) {
+			t.Fatalf("%s: handler returned unexpected body: got %q want %q", test.description, rr.Body.Bytes(), test.expectedBody)
+		}
+		if length := rr.Header().Get("Content-Length"); length != "" && length != strconv.Itoa(rr.Body.Len()) {
+			t.Fatalf("%s: handler returned wrong content length: got %s want %d", test.description, length, rr.Body.Len())
+		}
+	}
+}
+
+func TestServeEncryptedSnapshotDownload(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	keyFile := filepath.Join(t.TempDir(), "kek")
+	if err := os.WriteFile(keyFile, bytes.Repeat([]byte{7}, 32), 0600); err != nil {
+		t.Fatal(err)
+	}
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup", Prefix: "v2", EncryptionKeyFile: keyFile}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := bytes.Repeat([]byte("snapshot"), 20*1024)
+	snap := brtypes.Snapshot{Kind: brtypes.SnapshotKindFull, StartRevision: 0, LastRevision: 2, CreatedOn: time.Now().UTC()}
+	snap.GenerateSnapshotName()
+	if err := store.Save(snap, io.NopCloser(bytes.NewReader(content))); err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "tokens.yaml")
+	if err := os.WriteFile(tokenFile, []byte(testTokenFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	handler := &HTTPHandler{
+		Logger:          logrus.NewEntry(logrus.New()),
+		StorageProvider: brtypes.SnapstoreProviderLocal,
+		SnapstoreConfig: snapstoreConfig,
+		TokenFile:       tokenFile,
+	}
+	server := httptest.NewServer(handler.withRole(RoleDownload, handler.serveSnapshotDownload))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/snapshot/download?name="+snap.SnapName, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer admin-token")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", resp.StatusCode, http.StatusOK)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read the downloaded snapshot: %v", err)
+	}
+	if !bytes.Equal(body, content) {
+		t.Fatalf("handler returned unexpected body of %d bytes, want the %d bytes of the decrypted snapshot", len(body), len(content))
+	}
+}
+
+func TestParsePauseDeadline(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		query       url.Values
+		expected    time.Time
+		expectError bool
+	}{
+		{url.Values{}, time.Time{}, false},
+		{url.Values{"duration": {"2h"}}, now.Add(2 * time.Hour), false},
+		{url.Values{"until": {"2024-01-01T06:00:00Z"}}, now.Add(6 * time.Hour), false},
+		{url.Values{"duration": {"0s"}}, time.Time{}, true},
+		{url.Values{"duration": {"-1h"}}, time.Time{}, true},
+		{url.Values{"duration": {"soon"}}, time.Time{}, true},
+		{url.Values{"until": {"tomorrow"}}, time.Time{}, true},
+		{url.Values{"until": {"2024-01-01T06:00:00Z"}, "duration": {"2h"}}, time.Time{}, true},
+	} {
+		actual, err := parsePauseDeadline(test.query, now)
+		if (err != nil) != test.expectError {
+			t.Fatalf("parsePauseDeadline(%v): unexpected error: %v", test.query, err)
+		}
+		if !actual.Equal(test.expected) {
+			t.Fatalf("parsePauseDeadline(%v): got %v want %v", test.query, actual, test.expected)
+		}
+	}
+}
+
+func TestServeSnapshottingPause(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup"}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.NewEntry(logrus.New())
+	ssr, err := snapshotter.NewSnapshotter(logger, snapshotter.NewSnapshotterConfig(), store, brtypes.NewEtcdConnectionConfig(), compressor.NewCompressorConfig(), brtypes.NewHealthConfig(), snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &HTTPHandler{
+		Logger:            logger,
+		HTTPHandlerMutex:  &sync.Mutex{},
+		Snapshotter:       ssr,
+		StorageProvider:   brtypes.SnapstoreProviderLocal,
+		SnapstoreConfig:   snapstoreConfig,
+		JobManager:        jobs.NewManager(logger),
+		RestorationConfig: brtypes.NewRestorationConfig(),
+		CompactorConfig:   brtypes.NewCompactorConfig(),
+	}
+	handler.RegisterHandler()
+	handler.SetStatus(http.StatusOK)
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.server.Handler.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+
+	for _, test := range []struct {
+		description    string
+		method         string
+		target         string
+		expectedStatus int
+	}{
+		{"pause with GET", http.MethodGet, "/snapshot/pause", http.StatusMethodNotAllowed},
+		{"resume with GET", http.MethodGet, "/snapshot/resume", http.StatusMethodNotAllowed},
+		{"invalid duration", http.MethodPost, "/snapshot/pause?duration=soon", http.StatusBadRequest},
+		{"past deadline", http.MethodPost, "/snapshot/pause?until=2020-01-01T00:00:00Z", http.StatusBadRequest},
+	} {
+		if rr := serve(test.method, test.target); rr.Code != test.expectedStatus {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", test.description, rr.Code, test.expectedStatus)
+		}
+	}
+	if ssr.IsPaused() {
+		t.Fatal("rejected pause requests are not expected to pause the snapshotting")
+	}
+
+	rr := serve(http.MethodPost, "/snapshot/pause?duration=1h")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code for pause: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var status brtypes.PauseStatus
+	if err := json.Unmarshal(rr.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Paused || status.Since == nil || status.Until == nil || !ssr.IsPaused() {
+		t.Fatalf("unexpected pause status: %+v", status)
+	}
+
+	var health struct {
+		Health bool                 `json:"health"`
+		Pause  *brtypes.PauseStatus `json:"pause"`
+	}
+	if err := json.Unmarshal(serve(http.MethodGet, "/healthz").Body.Bytes(), &health); err != nil {
+		t.Fatal(err)
+	}
+	if !health.Health || health.Pause == nil || !health.Pause.Paused {
+		t.Fatalf("health check is expected to report the pause: %+v", health)
+	}
+
+	for _, target := range []string{"/snapshot/pin?name=Full-00000000-00000010-1717409400.gz", "/snapshot/unpin?name=Full-00000000-00000010-1717409400.gz", "/jobs/compaction"} {
+		if rr := serve(http.MethodPost, target); rr.Code != http.StatusConflict {
+			t.Fatalf("%s: handler returned wrong status code while paused: got %v want %v", target, rr.Code, http.StatusConflict)
+		}
+	}
+
+	rr = serve(http.MethodPost, "/snapshot/resume")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code for resume: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Paused || ssr.IsPaused() {
+		t.Fatalf("unexpected pause status after resume: %+v", status)
+	}
+}
@@ -7,6 +7,7 @@ package server
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/gardener/etcd-backup-restore/pkg/compressor"
 	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
@@ -27,6 +28,7 @@ func NewBackupRestoreComponentConfig() *BackupRestoreComponentConfig {
 		SecondarySnapstoreConfig: snapstore.NewSecondarySnapstoreConfig(),
 		CompressionConfig:        compressor.NewCompressorConfig(),
 		RestorationConfig:        brtypes.NewRestorationConfig(),
+		RestoreDrillConfig:       brtypes.NewRestoreDrillConfig(),
 		DefragmentationSchedule:  defaultDefragmentationSchedule,
 		HealthConfig:             brtypes.NewHealthConfig(),
 		LeaderElectionConfig:     brtypes.NewLeaderElectionConfig(),
@@ -42,6 +44,7 @@ func (c *BackupRestoreComponentConfig) AddFlags(fs *flag.FlagSet) {
 	c.SnapshotterConfig.AddFlags(fs)
 	c.SnapstoreConfig.AddFlags(fs)
 	c.RestorationConfig.AddFlags(fs)
+	c.RestoreDrillConfig.AddFlags(fs)
 	c.CompressionConfig.AddFlags(fs)
 	c.HealthConfig.AddFlags(fs)
 	c.LeaderElectionConfig.AddFlags(fs)
@@ -69,6 +72,25 @@ func (c *BackupRestoreComponentConfig) Validate() error {
 	if err := c.RestorationConfig.Validate(); err != nil {
 		return err
 	}
+	if err := c.RestorationConfig.ValidateInitialization(); err != nil {
+		return err
+	}
+	if err := c.RestoreDrillConfig.Validate(); err != nil {
+		return err
+	}
+	if c.RestoreDrillConfig.IsEnabled() {
+		drillDir, err := filepath.Abs(c.RestoreDrillConfig.DataDir)
+		if err != nil {
+			return fmt.Errorf("invalid restore drill data dir %s: %v", c.RestoreDrillConfig.DataDir, err)
+		}
+		dataDir, err := filepath.Abs(c.RestorationConfig.DataDir)
+		if err != nil {
+			return fmt.Errorf("invalid etcd data dir %s: %v", c.RestorationConfig.DataDir, err)
+		}
+		if isWithinDir(drillDir, dataDir) || isWithinDir(dataDir, drillDir) {
+			return fmt.Errorf("restore drill data dir %s must not overlap with the etcd data dir %s", drillDir, dataDir)
+		}
+	}
 	if err := c.CompressionConfig.Validate(); err != nil {
 		return err
 	}
@@ -101,7 +123,10 @@ func (c *BackupRestoreComponentConfig) Complete() {
 type HTTPServerConfig struct {
 	TLSCertFile     string `json:"server-cert,omitempty"`
 	TLSKeyFile      string `json:"server-key,omitempty"`
+	ClientCAFile    string `json:"clientCAFile,omitempty"`
+	TokenFile       string `json:"tokenFile,omitempty"`
 	Port            uint   `json:"port,omitempty"`
+	GRPCPort        uint   `json:"grpcPort,omitempty"`
 	EnableProfiling bool   `json:"enableProfiling,omitempty"`
 }
 
@@ -116,9 +141,12 @@ func NewHTTPServerConfig() *HTTPServerConfig {
 // AddFlags adds the flags to flagset.
 func (c *HTTPServerConfig) AddFlags(fs *flag.FlagSet) {
 	fs.UintVarP(&c.Port, "server-port", "p", c.Port, "port on which server should listen")
+	fs.UintVar(&c.GRPCPort, "grpc-server-port", c.GRPCPort, "port on which the gRPC server should listen, the gRPC server is disabled if not set")
 	fs.BoolVar(&c.EnableProfiling, "enable-profiling", c.EnableProfiling, "enable profiling")
 	fs.StringVar(&c.TLSCertFile, "server-cert", "", "TLS certificate file for backup-restore server")
 	fs.StringVar(&c.TLSKeyFile, "server-key", "", "TLS key file for backup-restore server")
+	fs.StringVar(&c.ClientCAFile, "server-client-ca-file", "", "CA bundle file to verify client certificates against, clients are granted the roles listed as organizations of their certificates")
+	fs.StringVar(&c.TokenFile, "server-token-file", "", "file containing the bearer tokens of the clients of the backup-restore server along with the roles granted to them")
 }
 
 // Validate validates the config.E
@@ -133,5 +161,24 @@ func (c *HTTPServerConfig) Validate() error {
 			return fmt.Errorf("TLS enabled but server TLS key file is invalid. Will not start HTTPS server: %v", err)
 		}
 	}
+	if c.GRPCPort != 0 && c.GRPCPort == c.Port {
+		return fmt.Errorf("gRPC server port %d must differ from the HTTP server port", c.GRPCPort)
+	}
+	if c.ClientCAFile != "" {
+		if !enableTLS {
+			return fmt.Errorf("client certificate authentication requires the server TLS cert and key files")
+		}
+		if _, err := loadCertPool(c.ClientCAFile); err != nil {
+			return fmt.Errorf("client CA file is invalid: %v", err)
+		}
+	}
+	if c.TokenFile != "" {
+		if !enableTLS {
+			return fmt.Errorf("bearer token authentication requires the server TLS cert and key files")
+		}
+		if _, err := readTokenFile(c.TokenFile); err != nil {
+			return err
+		}
+	}
 	return nil
 }
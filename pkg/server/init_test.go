@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import "testing"
+
+func TestValidateRestoreDrillDataDir(t *testing.T) {
+	for _, test := range []struct {
+		drillDir  string
+		dataDir   string
+		expectErr bool
+	}{
+		{"/var/etcd/restore-drill", "/var/etcd/data", false},
+		{"/var/etcd/data", "/var/etcd/data", true},
+		{"/var/etcd", "/var/etcd/data", true},
+		{"/var/etcd/data/restore-drill", "/var/etcd/data", true},
+		{"/var/etcd/data-restore-drill", "/var/etcd/data", false},
+	} {
+		c := NewBackupRestoreComponentConfig()
+		c.RestoreDrillConfig.Schedule = "0 3 * * *"
+		c.RestoreDrillConfig.DataDir = test.drillDir
+		c.RestorationConfig.DataDir = test.dataDir
+		if err := c.Validate(); (err != nil) != test.expectErr {
+			t.Fatalf("Validate() with restore drill data dir %q and etcd data dir %q: got error %v, expected error: %v", test.drillDir, test.dataDir, err, test.expectErr)
+		}
+	}
+}
+
+func TestValidateRestoreTargetRevisionBump(t *testing.T) {
+	for _, test := range []struct {
+		targetRevision int64
+		revisionBump   uint64
+		expectErr      bool
+	}{
+		{0, 0, false},
+		{0, 1000, false},
+		{10, 0, true},
+		{10, 1000, false},
+	} {
+		c := NewBackupRestoreComponentConfig()
+		c.RestorationConfig.TargetRevision = test.targetRevision
+		c.RestorationConfig.RevisionBump = test.revisionBump
+		if err := c.Validate(); (err != nil) != test.expectErr {
+			t.Fatalf("Validate() with target revision %d and revision bump %d: got error %v, expected error: %v", test.targetRevision, test.revisionBump, err, test.expectErr)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/etcd-backup-restore/pkg/api/backuprestore"
+	"github.com/gardener/etcd-backup-restore/pkg/compactor"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/restorer"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// errJobsUnavailable is the error returned when submitting a job to a server without job manager or storage provider.
+	errJobsUnavailable = errors.New("jobs require a storage provider to be configured")
+	// errInvalidJob is the error returned when submitting a job with invalid options.
+	errInvalidJob = errors.New("invalid job")
+)
+
+// jobsAvailable returns true if the handler is configured to run restore and compaction jobs.
+func (h *HTTPHandler) jobsAvailable() bool {
+	return h.JobManager != nil && len(h.StorageProvider) != 0 && h.RestorationConfig != nil && h.CompactorConfig != nil
+}
+
+// submitRestoreJob submits a job restoring the backups into the directory of the given request.
+func (h *HTTPHandler) submitRestoreJob(req *backuprestore.SubmitRestoreJobRequest) (*jobs.Job, error) {
+	if !h.jobsAvailable() {
+		return nil, errJobsUnavailable
+	}
+	if !filepath.IsAbs(req.DataDir) {
+		return nil, fmt.Errorf("%w: data directory must be an absolute path, got %q", errInvalidJob, req.DataDir)
+	}
+	dataDir := filepath.Clean(req.DataDir)
+	if isWithinDir(dataDir, h.RestorationConfig.DataDir) {
+		return nil, fmt.Errorf("%w: data directory must not be within the etcd data directory %s", errInvalidJob, h.RestorationConfig.DataDir)
+	}
+	if _, err := os.Stat(dataDir); err == nil {
+		return nil, fmt.Errorf("%w: data directory %s already exists", errInvalidJob, dataDir)
+	}
+
+	// the snapshots to restore are selected once the job runs.
+	ro, err := restorer.NewScratchRestoreOptions(h.RestorationConfig, dataDir, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	config := ro.Config
+	config.TargetRevision = req.TargetRevision
+	config.TargetTime = req.TargetTime
+	config.TargetSnapshotLabels = req.TargetSnapshotLabels
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidJob, err)
+	}
+
+	return h.JobManager.Submit(jobs.TypeRestore, func(ctx context.Context, onProgress brtypes.RestoreProgressFunc) (*jobs.Result, error) {
+		// the data directory might have been created since the job has been submitted
+		if _, err := os.Stat(config.DataDir); err == nil {
+			return nil, fmt.Errorf("data directory %s already exists", config.DataDir)
+		}
+		store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+		}
+		if ro.BaseSnapshot, ro.DeltaSnapList, err = miscellaneous.GetFullSnapshotAndDeltaSnapListForRestore(store, config); err != nil {
+			return nil, fmt.Errorf("failed to get snapshots to restore: %v", err)
+		}
+		if ro.BaseSnapshot == nil {
+			return nil, fmt.Errorf("no base snapshot found")
+		}
+		if config.TempSnapshotsDir, err = os.MkdirTemp("", "restore-job-"); err != nil {
+			return nil, fmt.Errorf("failed to create temporary snapshots directory: %v", err)
+		}
+		var revision int64
+		ro.OnProgress = func(progress brtypes.RestoreProgress) {
+			revision = progress.Revision
+			onProgress(progress)
+		}
+
+		rs, err := restorer.NewRestorer(store, h.Logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := rs.RestoreAndStopEtcd(ctx, *ro, nil); err != nil {
+			if err := os.RemoveAll(config.DataDir); err != nil {
+				h.Logger.Errorf("Failed to remove data directory %s of failed restore job: %v", config.DataDir, err)
+			}
+			return nil, fmt.Errorf("failed to restore snapshots: %v", err)
+		}
+		return &jobs.Result{DataDir: config.DataDir, Revision: revision}, nil
+	})
+}
+
+// submitCompactionJob submits a job compacting the latest backups into a new full snapshot.
+func (h *HTTPHandler) submitCompactionJob(req *backuprestore.SubmitCompactionJobRequest) (*jobs.Job, error) {
+	if !h.jobsAvailable() {
+		return nil, errJobsUnavailable
+	}
+	// a compaction job uploads a full snapshot, which must not be written while the snapshotting is paused.
+	if h.isSnapshottingPaused() {
+		return nil, snapshotter.ErrSnapshottingPaused
+	}
+	compactorConfig := *h.CompactorConfig
+	if req.Defragment != nil {
+		compactorConfig.NeedDefragmentation = *req.Defragment
+	}
+
+	return h.JobManager.Submit(jobs.TypeCompaction, func(ctx context.Context, onProgress brtypes.RestoreProgressFunc) (*jobs.Result, error) {
+		if h.isSnapshottingPaused() {
+			return nil, snapshotter.ErrSnapshottingPaused
+		}
+		store, err := snapstore.GetSnapstore(h.SnapstoreConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+		}
+		baseSnap, deltaSnapList, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest snapshots: %v", err)
+		}
+		if baseSnap == nil {
+			return nil, fmt.Errorf("no base snapshot found")
+		}
+
+		workDir, err := os.MkdirTemp("", "compaction-job-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary compaction directory: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(workDir); err != nil {
+				h.Logger.Errorf("Failed to remove temporary compaction directory %s: %v", workDir, err)
+			}
+		}()
+		ro, err := restorer.NewScratchRestoreOptions(h.RestorationConfig, filepath.Join(workDir, "data"), filepath.Join(workDir, "snapshots"), baseSnap, deltaSnapList)
+		if err != nil {
+			return nil, err
+		}
+		ro.OnProgress = onProgress
+
+		var clientSet client.Client
+		if compactorConfig.EnabledLeaseRenewal {
+			if clientSet, err = miscellaneous.GetKubernetesClientSetOrError(); err != nil {
+				return nil, fmt.Errorf("failed to create clientset: %v", err)
+			}
+		}
+		cp := compactor.NewCompactor(store, h.Logger, clientSet)
+		snapshot, err := cp.Compact(ctx, &brtypes.CompactOptions{
+			RestoreOptions:  ro,
+			CompactorConfig: &compactorConfig,
+			TempDir:         h.SnapstoreConfig.TempDir,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compact snapshots: %v", err)
+		}
+		h.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken, Snapshot: snapshot, Message: "compaction"})
+		return &jobs.Result{Snapshot: snapshot, Revision: snapshot.LastRevision}, nil
+	})
+}
+
+// isWithinDir returns true if the given path is the given directory or lies within it.
+func isWithinDir(path, dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// serveRestoreJobSubmission submits a job restoring the backups into the directory of the request
+func (h *HTTPHandler) serveRestoreJobSubmission(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	query := req.URL.Query()
+	jobReq := &backuprestore.SubmitRestoreJobRequest{
+		DataDir:              query.Get("dataDir"),
+		TargetTime:           query.Get("targetTime"),
+		TargetSnapshotLabels: query.Get("targetSnapshotLabels"),
+	}
+	if targetRevision := query.Get("targetRevision"); targetRevision != "" {
+		var err error
+		if jobReq.TargetRevision, err = strconv.ParseInt(targetRevision, 10, 64); err != nil {
+			h.Logger.Warnf("Could not parse request parameter 'targetRevision' to int: %v", err)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	job, err := h.submitRestoreJob(jobReq)
+	h.writeJobResponse(rw, http.StatusAccepted, job, err)
+}
+
+// serveCompactionJobSubmission submits a job compacting the latest backups into a new full snapshot
+func (h *HTTPHandler) serveCompactionJobSubmission(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	jobReq := &backuprestore.SubmitCompactionJobRequest{}
+	if defragment := req.URL.Query().Get("defragment"); defragment != "" {
+		needDefragmentation, err := strconv.ParseBool(defragment)
+		if err != nil {
+			h.Logger.Warnf("Could not parse request parameter 'defragment' to bool: %v", err)
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		jobReq.Defragment = &needDefragmentation
+	}
+	job, err := h.submitCompactionJob(jobReq)
+	h.writeJobResponse(rw, http.StatusAccepted, job, err)
+}
+
+// serveJobList serves the status of all jobs
+func (h *HTTPHandler) serveJobList(rw http.ResponseWriter, _ *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.JobManager == nil {
+		h.Logger.Warnf("Ignoring job list request as jobs are not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	out, err := json.Marshal(backuprestore.ListJobsResponse{Jobs: h.JobManager.List()})
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal job list to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write job list response: %v", err)
+	}
+}
+
+// serveJob serves the status of the job with the ID of the request path
+func (h *HTTPHandler) serveJob(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.JobManager == nil {
+		h.Logger.Warnf("Ignoring job request as jobs are not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	job, err := h.JobManager.Get(req.PathValue("id"))
+	h.writeJobResponse(rw, http.StatusOK, job, err)
+}
+
+// serveJobCancellation cancels the job with the ID of the request path
+func (h *HTTPHandler) serveJobCancellation(rw http.ResponseWriter, req *http.Request) {
+	h.checkAndSetSecurityHeaders(rw)
+	if h.JobManager == nil {
+		h.Logger.Warnf("Ignoring job cancellation request as jobs are not configured")
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	job, err := h.JobManager.Cancel(req.PathValue("id"))
+	h.writeJobResponse(rw, http.StatusOK, job, err)
+}
+
+// writeJobResponse writes the status of the given job with the given status code, or the status code of the given error.
+func (h *HTTPHandler) writeJobResponse(rw http.ResponseWriter, statusCode int, job *jobs.Job, err error) {
+	if err != nil {
+		h.Logger.Warnf("Unable to serve job request: %v", err)
+		switch {
+		case errors.Is(err, errJobsUnavailable):
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		case errors.Is(err, errInvalidJob):
+			rw.WriteHeader(http.StatusBadRequest)
+		case errors.Is(err, jobs.ErrJobNotFound):
+			rw.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, jobs.ErrJobFinished), errors.Is(err, snapshotter.ErrSnapshottingPaused):
+			rw.WriteHeader(http.StatusConflict)
+		case errors.Is(err, jobs.ErrQueueFull):
+			rw.WriteHeader(http.StatusTooManyRequests)
+		default:
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	out, err := json.Marshal(job)
+	if err != nil {
+		h.Logger.Warnf("Unable to marshal job to json: %v", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(statusCode)
+	if _, err = rw.Write(out); err != nil {
+		h.Logger.Errorf("Unable to write job response: %v", err)
+	}
+}
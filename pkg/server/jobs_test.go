@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gardener/etcd-backup-restore/pkg/jobs"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestServeJobs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	tempDir := t.TempDir()
+	existingDir := filepath.Join(tempDir, "existing")
+	if err := os.Mkdir(existingDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	restorationConfig := brtypes.NewRestorationConfig()
+	restorationConfig.DataDir = filepath.Join(tempDir, "etcd")
+	logger := logrus.NewEntry(logrus.New())
+	handler := &HTTPHandler{
+		Logger:           logger,
+		HTTPHandlerMutex: &sync.Mutex{},
+		StorageProvider:  brtypes.SnapstoreProviderLocal,
+		SnapstoreConfig:  &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup"},
+		// the job manager is not run, so that submitted jobs stay pending
+		JobManager:        jobs.NewManager(logger),
+		RestorationConfig: restorationConfig,
+		CompactorConfig:   brtypes.NewCompactorConfig(),
+	}
+	handler.RegisterHandler()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.server.Handler.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+	restoreTarget := func(query url.Values) string {
+		return "/jobs/restore?" + query.Encode()
+	}
+
+	for _, test := range []struct {
+		description    string
+		target         string
+		expectedStatus int
+	}{
+		{"relative data directory", restoreTarget(url.Values{"dataDir": {"restored"}}), http.StatusBadRequest},
+		{"etcd data directory", restoreTarget(url.Values{"dataDir": {restorationConfig.DataDir}}), http.StatusBadRequest},
+		{"data directory within etcd data directory", restoreTarget(url.Values{"dataDir": {filepath.Join(restorationConfig.DataDir, "member")}}), http.StatusBadRequest},
+		{"existing data directory", restoreTarget(url.Values{"dataDir": {existingDir}}), http.StatusBadRequest},
+		{"invalid target revision", restoreTarget(url.Values{"dataDir": {filepath.Join(tempDir, "restored")}, "targetRevision": {"latest"}}), http.StatusBadRequest},
+		{"invalid target time", restoreTarget(url.Values{"dataDir": {filepath.Join(tempDir, "restored")}, "targetTime": {"yesterday"}}), http.StatusBadRequest},
+		{"invalid defragment", "/jobs/compaction?defragment=maybe", http.StatusBadRequest},
+		{"unknown job", "/jobs/restore-0000000000000000", http.StatusNotFound},
+		{"cancel unknown job", "/jobs/restore-0000000000000000/cancel", http.StatusNotFound},
+	} {
+		if rr := serve(http.MethodPost, test.target); rr.Code != test.expectedStatus {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", test.description, rr.Code, test.expectedStatus)
+		}
+	}
+
+	rr := serve(http.MethodPost, restoreTarget(url.Values{"dataDir": {filepath.Join(tempDir, "restored")}, "targetRevision": {"10"}}))
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code for restore job: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+	var job jobs.Job
+	if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Type != jobs.TypeRestore || job.State != jobs.StatePending {
+		t.Fatalf("unexpected restore job: %+v", job)
+	}
+	if rr := serve(http.MethodPost, "/jobs/compaction?defragment=false"); rr.Code != http.StatusAccepted {
+		t.Fatalf("handler returned wrong status code for compaction job: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+
+	rr = serve(http.MethodGet, "/jobs")
+	var list struct {
+		Jobs []jobs.Job `json:"jobs"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Jobs) != 2 || list.Jobs[0].ID != job.ID || list.Jobs[1].Type != jobs.TypeCompaction {
+		t.Fatalf("unexpected jobs listed: %+v", list.Jobs)
+	}
+
+	if rr := serve(http.MethodPost, "/jobs/"+job.ID+"/cancel"); rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code for cancellation: got %v want %v", rr.Code, http.StatusOK)
+	}
+	rr = serve(http.MethodGet, "/jobs/"+job.ID)
+	if err := json.Unmarshal(rr.Body.Bytes(), &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.State != jobs.StateCancelled {
+		t.Fatalf("unexpected state of cancelled job: %v", job.State)
+	}
+	if rr := serve(http.MethodPost, "/jobs/"+job.ID+"/cancel"); rr.Code != http.StatusConflict {
+		t.Fatalf("handler returned wrong status code for cancellation of finished job: got %v want %v", rr.Code, http.StatusConflict)
+	}
+}
+
+func TestServeJobsWithoutStorageProvider(t *testing.T) {
+	logger := logrus.NewEntry(logrus.New())
+	handler := &HTTPHandler{
+		Logger:            logger,
+		JobManager:        jobs.NewManager(logger),
+		RestorationConfig: brtypes.NewRestorationConfig(),
+		CompactorConfig:   brtypes.NewCompactorConfig(),
+	}
+	handler.RegisterHandler()
+	for _, target := range []string{"/jobs/restore?dataDir=/tmp/restored", "/jobs/compaction"} {
+		rr := httptest.NewRecorder()
+		handler.server.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, target, nil))
+		if rr.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("%s: handler returned wrong status code: got %v want %v", target, rr.Code, http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func TestIsWithinDir(t *testing.T) {
+	for _, test := range []struct {
+		path     string
+		dir      string
+		expected bool
+	}{
+		{"/var/etcd/data", "/var/etcd/data", true},
+		{"/var/etcd/data/member", "/var/etcd/data", true},
+		{"/var/etcd/data/../restored", "/var/etcd/data", false},
+		{"/var/etcd/data-restored", "/var/etcd/data", false},
+		{"/var/etcd", "/var/etcd/data", false},
+	} {
+		if actual := isWithinDir(filepath.Clean(test.path), test.dir); actual != test.expected {
+			t.Fatalf("isWithinDir(%q, %q): got %v want %v", test.path, test.dir, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	v1 "k8s.io/api/coordination/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PauseStatusAnnotation is the annotation of the full snapshot lease which holds the pause status of the snapshotting,
+// so that the pause survives restarts of the sidecar and leader changes.
+const PauseStatusAnnotation = "backup-restore.etcd.gardener.cloud/snapshotting-pause"
+
+// pauseStatusStore persists the pause status of the snapshotting.
+type pauseStatusStore interface {
+	// load returns the persisted pause status, which is not paused if none has been persisted.
+	load(ctx context.Context) (*brtypes.PauseStatus, error)
+	// save persists the given pause status.
+	save(ctx context.Context, status brtypes.PauseStatus) error
+}
+
+// leasePauseStatusStore persists the pause status of the snapshotting in an annotation of a lease.
+type leasePauseStatusStore struct {
+	client    client.Client
+	namespace string
+	leaseName string
+}
+
+// newLeasePauseStatusStore returns a pause status store which persists the pause status in the full snapshot lease.
+func newLeasePauseStatusStore(healthConfig *brtypes.HealthConfig) (*leasePauseStatusStore, error) {
+	namespace, err := miscellaneous.GetEnvVarOrError("POD_NAMESPACE")
+	if err != nil {
+		return nil, err
+	}
+	clientSet, err := miscellaneous.GetKubernetesClientSetOrError()
+	if err != nil {
+		return nil, err
+	}
+	return &leasePauseStatusStore{
+		client:    clientSet,
+		namespace: namespace,
+		leaseName: healthConfig.FullSnapshotLeaseName,
+	}, nil
+}
+
+func (s *leasePauseStatusStore) load(ctx context.Context) (*brtypes.PauseStatus, error) {
+	lease := &v1.Lease{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.leaseName}, lease); err != nil {
+		return nil, fmt.Errorf("failed to fetch lease %s: %v", s.leaseName, err)
+	}
+	status := &brtypes.PauseStatus{}
+	value, ok := lease.Annotations[PauseStatusAnnotation]
+	if !ok {
+		return status, nil
+	}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of lease %s: %v", PauseStatusAnnotation, s.leaseName, err)
+	}
+	return status, nil
+}
+
+func (s *leasePauseStatusStore) save(ctx context.Context, status brtypes.PauseStatus) error {
+	var value string
+	if status.Paused {
+		data, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	// Retry on conflict is necessary because multiple actors update the full snapshot lease.
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		lease := &v1.Lease{}
+		if err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.leaseName}, lease); err != nil {
+			return fmt.Errorf("failed to fetch lease %s: %v", s.leaseName, err)
+		}
+		current, ok := lease.Annotations[PauseStatusAnnotation]
+		if current == value && ok == status.Paused {
+			return nil
+		}
+		updatedLease := lease.DeepCopy()
+		if status.Paused {
+			if updatedLease.Annotations == nil {
+				updatedLease.Annotations = map[string]string{}
+			}
+			updatedLease.Annotations[PauseStatusAnnotation] = value
+		} else {
+			delete(updatedLease.Annotations, PauseStatusAnnotation)
+		}
+		return s.client.Patch(ctx, updatedLease, client.MergeFromWithOptions(lease, &client.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// takeOverPauseStatus applies the pause status of the previous snapshotter to the given snapshotter, and keeps track
+// of the pause status of the given snapshotter from now on. The persisted pause status takes precedence over the one
+// known to the server, since the snapshotting might have been paused by another member or before a restart.
+func (b *BackupRestoreServer) takeOverPauseStatus(ssr *snapshotter.Snapshotter) {
+	ssr.OnPauseStatusChange = func(status brtypes.PauseStatus) {
+		b.onPauseStatusChange(ssr, status)
+	}
+
+	b.pauseMutex.Lock()
+	if b.pauseStatusStore != nil {
+		ctx, cancel := context.WithTimeout(context.TODO(), brtypes.LeaseUpdateTimeoutDuration)
+		status, err := b.pauseStatusStore.load(ctx)
+		cancel()
+		if err != nil {
+			b.logger.Warnf("Failed to load the persisted pause status, using the last known one: %v", err)
+		} else {
+			b.pauseStatus = *status
+		}
+	}
+	b.pausableSnapshotter = ssr
+	status := b.pauseStatus
+	b.pauseMutex.Unlock()
+
+	// the pause status must not be locked while calling the snapshotter, which calls back into the server
+	ssr.RestorePauseStatus(status)
+}
+
+// onPauseStatusChange records and persists the changed pause status of the given snapshotter. Changes of snapshotters
+// which have been replaced in the meantime are ignored.
+func (b *BackupRestoreServer) onPauseStatusChange(ssr *snapshotter.Snapshotter, status brtypes.PauseStatus) {
+	b.pauseMutex.Lock()
+	defer b.pauseMutex.Unlock()
+	if ssr != b.pausableSnapshotter || pauseStatusEqual(b.pauseStatus, status) {
+		return
+	}
+	b.pauseStatus = status
+	if b.pauseStatusStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), brtypes.LeaseUpdateTimeoutDuration)
+	defer cancel()
+	if err := b.pauseStatusStore.save(ctx, status); err != nil {
+		b.logger.Errorf("Failed to persist the pause status: %v", err)
+	}
+}
+
+// releasePauseStatus stops keeping track of the pause status of the current snapshotter, e.g. once the server has
+// stopped leading. The last known pause status is kept for the next snapshotter.
+func (b *BackupRestoreServer) releasePauseStatus() {
+	b.pauseMutex.Lock()
+	defer b.pauseMutex.Unlock()
+	b.pausableSnapshotter = nil
+}
+
+// pauseStatusEqual returns whether the given pause statuses are equal.
+func pauseStatusEqual(a, b brtypes.PauseStatus) bool {
+	if a.Paused != b.Paused {
+		return false
+	}
+	if (a.Since == nil) != (b.Since == nil) || (a.Since != nil && !a.Since.Equal(*b.Since)) {
+		return false
+	}
+	return (a.Until == nil) == (b.Until == nil) && (a.Until == nil || a.Until.Equal(*b.Until))
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPauseTestSnapshotter(t *testing.T) *snapshotter.Snapshotter {
+	t.Helper()
+	snapstoreConfig := &brtypes.SnapstoreConfig{Provider: brtypes.SnapstoreProviderLocal, Container: "backup"}
+	store, err := snapstore.GetSnapstore(snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ssr, err := snapshotter.NewSnapshotter(logrus.NewEntry(logrus.New()), snapshotter.NewSnapshotterConfig(), store, brtypes.NewEtcdConnectionConfig(), compressor.NewCompressorConfig(), brtypes.NewHealthConfig(), snapstoreConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ssr
+}
+
+func TestPauseSurvivesNewSnapshotter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	b, err := NewBackupRestoreServer(logrus.New(), NewBackupRestoreComponentConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := newPauseTestSnapshotter(t)
+	b.registerSnapshotter(first, true)
+	paused, err := first.Pause(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.unregisterSnapshotters()
+
+	second := newPauseTestSnapshotter(t)
+	b.registerSnapshotter(second, true)
+	status := second.GetPauseStatus()
+	if !pauseStatusEqual(*status, *paused) {
+		t.Fatalf("expected new snapshotter to take over the pause %+v, got %+v", paused, status)
+	}
+
+	// the replaced snapshotter must not resume the snapshotting of its successors
+	first.Resume()
+	b.unregisterSnapshotters()
+	third := newPauseTestSnapshotter(t)
+	b.registerSnapshotter(third, true)
+	if !third.IsPaused() {
+		t.Fatal("expected snapshotting to stay paused once a replaced snapshotter has been resumed")
+	}
+
+	third.Resume()
+	b.unregisterSnapshotters()
+	fourth := newPauseTestSnapshotter(t)
+	b.registerSnapshotter(fourth, true)
+	if fourth.IsPaused() {
+		t.Fatal("expected snapshotting to stay resumed")
+	}
+
+	// the backup copier cannot be paused
+	copier := newPauseTestSnapshotter(t)
+	if _, err := fourth.Pause(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	b.registerSnapshotter(copier, false)
+	if copier.IsPaused() {
+		t.Fatal("expected snapshotter which is not pausable not to take over the pause")
+	}
+}
+
+func TestPauseSurvivesRestart(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	lease := &v1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: brtypes.DefaultFullSnapshotLeaseName}}
+	store := &leasePauseStatusStore{
+		client:    fake.NewClientBuilder().WithObjects(lease).Build(),
+		namespace: lease.Namespace,
+		leaseName: lease.Name,
+	}
+	annotation := func() (string, bool) {
+		current := &v1.Lease{}
+		if err := store.client.Get(context.TODO(), client.ObjectKeyFromObject(lease), current); err != nil {
+			t.Fatal(err)
+		}
+		value, ok := current.Annotations[PauseStatusAnnotation]
+		return value, ok
+	}
+	newServer := func() *BackupRestoreServer {
+		b, err := NewBackupRestoreServer(logrus.New(), NewBackupRestoreComponentConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.pauseStatusStore = store
+		return b
+	}
+
+	b := newServer()
+	first := newPauseTestSnapshotter(t)
+	b.registerSnapshotter(first, true)
+	paused, err := first.Pause(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := annotation(); !ok {
+		t.Fatal("expected pause status to be persisted in the full snapshot lease")
+	}
+
+	restarted := newServer()
+	second := newPauseTestSnapshotter(t)
+	restarted.registerSnapshotter(second, true)
+	status := second.GetPauseStatus()
+	if !pauseStatusEqual(*status, *paused) {
+		t.Fatalf("expected restarted server to take over the pause %+v, got %+v", paused, status)
+	}
+
+	second.Resume()
+	if value, ok := annotation(); ok {
+		t.Fatalf("expected pause status to be removed from the full snapshot lease once resumed, got %s", value)
+	}
+
+	// an expired pause is not taken over
+	expired := time.Now().Add(-time.Minute)
+	if err := store.save(context.TODO(), brtypes.PauseStatus{Paused: true, Since: &expired, Until: &expired}); err != nil {
+		t.Fatal(err)
+	}
+	third := newPauseTestSnapshotter(t)
+	newServer().registerSnapshotter(third, true)
+	if third.IsPaused() {
+		t.Fatal("expected pause with passed deadline not to be taken over")
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ReloadConfig applies the parts of the given config which can be changed while the server runs to the server and its
+// running snapshotters: the full snapshot schedule, the delta snapshot period and memory limit, the garbage collection
+// policy, the maximum number of backups, the GFS retention, the delta snapshot retention period and the defragmentation
+// schedule. Changes to the other parts of the config require a restart and are ignored. The given config is expected
+// to be validated.
+func (b *BackupRestoreServer) ReloadConfig(config *BackupRestoreComponentConfig) error {
+	defragmentationSchedule, err := cron.ParseStandard(config.DefragmentationSchedule)
+	if err != nil {
+		return fmt.Errorf("invalid defragmentation schedule %s: %v", config.DefragmentationSchedule, err)
+	}
+
+	b.configMutex.Lock()
+	defer b.configMutex.Unlock()
+	snapshotterConfig := reloadSnapshotterConfig(b.config.SnapshotterConfig, config.SnapshotterConfig)
+	for _, ssr := range b.snapshotters {
+		if err := ssr.UpdateConfig(snapshotterConfig); err != nil {
+			return fmt.Errorf("failed to update snapshotter config: %v", err)
+		}
+	}
+	b.config.SnapshotterConfig = snapshotterConfig
+
+	if b.config.DefragmentationSchedule != config.DefragmentationSchedule {
+		b.config.DefragmentationSchedule = config.DefragmentationSchedule
+		b.defragmentationSchedule = defragmentationSchedule
+		// the defragmentor only runs while leading, and only needs the latest schedule
+		select {
+		case <-b.defragScheduleCh:
+		default:
+		}
+		b.defragScheduleCh <- defragmentationSchedule
+	}
+	b.logger.Info("Reloaded the server config.")
+	return nil
+}
+
+// reloadSnapshotterConfig returns a copy of the given current snapshotter config with the parts which can be reloaded
+// taken from the given reloaded config.
+func reloadSnapshotterConfig(current, reloaded *brtypes.SnapshotterConfig) *brtypes.SnapshotterConfig {
+	config := *current
+	config.FullSnapshotSchedule = reloaded.FullSnapshotSchedule
+	config.DeltaSnapshotPeriod = reloaded.DeltaSnapshotPeriod
+	config.DeltaSnapshotMemoryLimit = reloaded.DeltaSnapshotMemoryLimit
+	config.DeltaSnapshotSpillSizeLimit = reloaded.DeltaSnapshotSpillSizeLimit
+	config.GarbageCollectionPolicy = reloaded.GarbageCollectionPolicy
+	config.MaxBackups = reloaded.MaxBackups
+	config.GFSRetention = reloaded.GFSRetention
+	config.DeltaSnapshotRetentionPeriod = reloaded.DeltaSnapshotRetentionPeriod
+	return &config
+}
+
+// registerSnapshotter registers the given running snapshotter, so that reloaded configs are applied to it. The pause
+// status of the previous snapshotter is applied to a pausable snapshotter, which can be paused via the HTTP and gRPC APIs.
+func (b *BackupRestoreServer) registerSnapshotter(ssr *snapshotter.Snapshotter, pausable bool) {
+	if pausable {
+		b.takeOverPauseStatus(ssr)
+	}
+	b.configMutex.Lock()
+	defer b.configMutex.Unlock()
+	// the config might have been reloaded since the snapshotter has been created
+	if err := ssr.UpdateConfig(b.config.SnapshotterConfig); err != nil {
+		b.logger.Warnf("Failed to update snapshotter config: %v", err)
+	}
+	b.snapshotters = append(b.snapshotters, ssr)
+}
+
+// unregisterSnapshotters unregisters the snapshotters once the server has stopped leading.
+func (b *BackupRestoreServer) unregisterSnapshotters() {
+	b.configMutex.Lock()
+	defer b.configMutex.Unlock()
+	b.snapshotters = nil
+	b.releasePauseStatus()
+}
+
+// snapshotterConfig returns the current snapshotter config, which must not be modified.
+func (b *BackupRestoreServer) snapshotterConfig() *brtypes.SnapshotterConfig {
+	b.configMutex.Lock()
+	defer b.configMutex.Unlock()
+	return b.config.SnapshotterConfig
+}
+
+// currentDefragmentationSchedule returns the current defragmentation schedule.
+func (b *BackupRestoreServer) currentDefragmentationSchedule() cron.Schedule {
+	b.configMutex.Lock()
+	defer b.configMutex.Unlock()
+	return b.defragmentationSchedule
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestReloadConfig(t *testing.T) {
+	config := NewBackupRestoreComponentConfig()
+	b, err := NewBackupRestoreServer(logrus.New(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialSnapshotterConfig := b.snapshotterConfig()
+
+	reloaded := NewBackupRestoreComponentConfig()
+	reloaded.SnapshotterConfig.FullSnapshotSchedule = "0 */2 * * *"
+	reloaded.SnapshotterConfig.GarbageCollectionPolicy = brtypes.GarbageCollectionPolicyLimitBased
+	reloaded.SnapshotterConfig.MaxBackups = 3
+	reloaded.SnapshotterConfig.GarbageCollectionPeriod.Duration = time.Hour
+	reloaded.DefragmentationSchedule = "0 1 * * *"
+	if err := b.ReloadConfig(reloaded); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshotterConfig := b.snapshotterConfig()
+	if snapshotterConfig.FullSnapshotSchedule != "0 */2 * * *" || snapshotterConfig.GarbageCollectionPolicy != brtypes.GarbageCollectionPolicyLimitBased || snapshotterConfig.MaxBackups != 3 {
+		t.Fatalf("reloadable snapshotter config not applied: %+v", snapshotterConfig)
+	}
+	if snapshotterConfig.GarbageCollectionPeriod != initialSnapshotterConfig.GarbageCollectionPeriod {
+		t.Fatalf("garbage collection period is not expected to be reloaded: got %s", snapshotterConfig.GarbageCollectionPeriod)
+	}
+	if initialSnapshotterConfig.MaxBackups == 3 {
+		t.Fatal("the previous snapshotter config is expected to be left unchanged")
+	}
+	select {
+	case schedule := <-b.defragScheduleCh:
+		if schedule != b.currentDefragmentationSchedule() {
+			t.Fatal("unexpected defragmentation schedule sent")
+		}
+	default:
+		t.Fatal("updated defragmentation schedule is expected to be sent")
+	}
+
+	reloaded.DefragmentationSchedule = "invalid"
+	if err := b.ReloadConfig(reloaded); err == nil {
+		t.Fatal("invalid defragmentation schedule is expected to be rejected")
+	}
+}
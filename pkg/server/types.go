@@ -25,6 +25,7 @@ type BackupRestoreComponentConfig struct {
 	SecondarySnapstoreConfig *brtypes.SecondarySnapstoreConfig `json:"secondarySnapstoreConfig,omitempty"`
 	CompressionConfig        *compressor.CompressionConfig     `json:"compressionConfig,omitempty"`
 	RestorationConfig        *brtypes.RestorationConfig        `json:"restorationConfig,omitempty"`
+	RestoreDrillConfig       *brtypes.RestoreDrillConfig       `json:"restoreDrillConfig,omitempty"`
 	HealthConfig             *brtypes.HealthConfig             `json:"healthConfig,omitempty"`
 	LeaderElectionConfig     *brtypes.Config                   `json:"leaderElectionConfig,omitempty"`
 	ExponentialBackoffConfig *brtypes.ExponentialBackoffConfig `json:"exponentialBackoffConfig,omitempty"`
@@ -37,3 +38,8 @@ type latestSnapshotMetadataResponse struct {
 	FullSnapshot   *brtypes.Snapshot `json:"fullSnapshot"`
 	DeltaSnapshots brtypes.SnapList  `json:"deltaSnapshots"`
 }
+
+type snapshotListResponse struct {
+	Snapshots brtypes.SnapList `json:"snapshots"`
+	Continue  string           `json:"continue,omitempty"`
+}
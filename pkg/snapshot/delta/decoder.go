@@ -0,0 +1,387 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Decoder decodes the events of a delta snapshot in either format.
+type Decoder struct {
+	r      *hashingReader
+	format string
+	json   *json.Decoder
+	jr     *trailingHashReader
+	block  []byte
+	events []brtypes.Event
+	done   bool
+	err    error
+}
+
+// hashingReader reads from a buffered reader and adds the bytes read to a hash.
+type hashingReader struct {
+	*bufio.Reader
+	hash hash.Hash
+}
+
+// Read reads into p and adds the bytes read to the hash.
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+// ReadByte reads a single byte and adds it to the hash.
+func (r *hashingReader) ReadByte() (byte, error) {
+	b, err := r.Reader.ReadByte()
+	if err == nil {
+		r.hash.Write([]byte{b})
+	}
+	return b, err
+}
+
+// trailingHashReader reads from a reader up to the hash trailing the data, and adds the bytes read to a hash. The
+// trailing hash is held back, and is available once the reader is at its end.
+type trailingHashReader struct {
+	r       io.Reader
+	hash    hash.Hash
+	buf     []byte
+	pending []byte
+	err     error
+}
+
+// Read reads into p and adds the bytes read to the hash. The last sha256.Size bytes of the data are never read.
+func (r *trailingHashReader) Read(p []byte) (int, error) {
+	for len(r.pending) <= sha256.Size {
+		if r.err != nil {
+			return 0, r.err
+		}
+		var n int
+		n, r.err = r.r.Read(r.buf)
+		r.pending = append(r.pending, r.buf[:n]...)
+	}
+	n := copy(p, r.pending[:len(r.pending)-sha256.Size])
+	r.hash.Write(p[:n])
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// verify verifies the data read against the trailing hash. It must be called once the reader is at its end.
+func (r *trailingHashReader) verify() error {
+	if r.err != io.EOF {
+		return fmt.Errorf("failed to read delta snapshot: %v", r.err)
+	}
+	if len(r.pending) != sha256.Size {
+		return fmt.Errorf("delta snapshot is missing hash")
+	}
+	if computedHash := r.hash.Sum(nil); !bytes.Equal(r.pending, computedHash) {
+		return fmt.Errorf("expected sha256 %v, got %v", r.pending, computedHash)
+	}
+	return nil
+}
+
+// NewDecoder returns a decoder of the delta snapshot read from the given reader, which must be decompressed already.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	d := &Decoder{r: &hashingReader{Reader: bufio.NewReader(r), hash: sha256.New()}}
+	header, err := d.r.Peek(len(magic) + 1)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read delta snapshot header: %v", err)
+	}
+	if !bytes.HasPrefix(header, magic) {
+		d.format = brtypes.DeltaSnapshotFormatJSON
+		if err := d.readJSON(); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if len(header) <= len(magic) {
+		return nil, fmt.Errorf("delta snapshot is missing the format version")
+	}
+	if version := header[len(magic)]; version != FormatVersion {
+		return nil, fmt.Errorf("unsupported delta snapshot format version %d", version)
+	}
+	d.format = brtypes.DeltaSnapshotFormatBinary
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return nil, fmt.Errorf("failed to read delta snapshot header: %v", err)
+	}
+	return d, nil
+}
+
+// IsBinaryFormat returns true if the delta snapshot read from the given reader, which must be decompressed already, is
+// in the binary format. It does not consume the delta snapshot.
+func IsBinaryFormat(r *bufio.Reader) bool {
+	header, _ := r.Peek(len(magic))
+	return bytes.HasPrefix(header, magic)
+}
+
+// Format returns the format of the delta snapshot.
+func (d *Decoder) Format() string {
+	return d.format
+}
+
+// Next returns the next event of the delta snapshot. It returns io.EOF once all events have been returned and the
+// whole delta snapshot has been verified against its hash.
+func (d *Decoder) Next() (*brtypes.Event, error) {
+	for len(d.events) == 0 && d.err == nil {
+		if d.done {
+			return nil, io.EOF
+		}
+		if d.json != nil {
+			d.err = d.readJSONEvent()
+		} else {
+			d.err = d.readBlock()
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	event := &d.events[0]
+	d.events = d.events[1:]
+	return event, nil
+}
+
+// readJSON starts reading the delta snapshot in the JSON format, up to its first event. The events are decoded one by
+// one from the JSON array, so that the delta snapshot is never held in memory as a whole.
+func (d *Decoder) readJSON() error {
+	d.jr = &trailingHashReader{r: d.r.Reader, hash: sha256.New(), buf: make([]byte, 32*1024)}
+	d.json = json.NewDecoder(d.jr)
+	token, err := d.json.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read events: %w", unexpectedEOF(err))
+	}
+	if token == nil {
+		// earlier versions marshalled empty events as null
+		return d.readJSONHash()
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("failed to read events: expected an array, got %v", token)
+	}
+	return nil
+}
+
+// readJSONEvent decodes the next event of the delta snapshot in the JSON format. Once the end of the array is reached,
+// the delta snapshot is verified against its hash.
+func (d *Decoder) readJSONEvent() error {
+	if !d.json.More() {
+		if _, err := d.json.Token(); err != nil {
+			return fmt.Errorf("failed to read end of events: %w", unexpectedEOF(err))
+		}
+		return d.readJSONHash()
+	}
+	var event brtypes.Event
+	if err := d.json.Decode(&event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", unexpectedEOF(err))
+	}
+	if err := checkEvent(&event); err != nil {
+		return err
+	}
+	d.events = []brtypes.Event{event}
+	return nil
+}
+
+// readJSONHash verifies the delta snapshot in the JSON format against the hash following its events.
+func (d *Decoder) readJSONHash() error {
+	if _, err := d.json.Token(); err != io.EOF {
+		if err != nil {
+			return fmt.Errorf("failed to read delta snapshot: %v", err)
+		}
+		return fmt.Errorf("unexpected data after the events of the delta snapshot")
+	}
+	if err := d.jr.verify(); err != nil {
+		return err
+	}
+	d.done = true
+	return nil
+}
+
+// readBlock reads the next block of the delta snapshot in the binary format, verifies it against its checksum and
+// decodes its events. Once the end of the blocks is reached, the delta snapshot is verified against its hash.
+func (d *Decoder) readBlock() error {
+	length, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return fmt.Errorf("failed to read length of block: %w", unexpectedEOF(err))
+	}
+	if length == 0 {
+		return d.readHash()
+	}
+	if length > maxBlockSize {
+		return fmt.Errorf("block of %d bytes exceeds the maximum block size of %d bytes", length, maxBlockSize)
+	}
+
+	if uint64(cap(d.block)) < length {
+		d.block = make([]byte, length)
+	}
+	block := d.block[:length]
+	if _, err := io.ReadFull(d.r, block); err != nil {
+		return fmt.Errorf("failed to read block: %w", unexpectedEOF(err))
+	}
+	checksum := make([]byte, checksumSize)
+	if _, err := io.ReadFull(d.r, checksum); err != nil {
+		return fmt.Errorf("failed to read checksum of block: %w", unexpectedEOF(err))
+	}
+	if expected, computed := binary.BigEndian.Uint32(checksum), crc32.Checksum(block, crcTable); expected != computed {
+		return fmt.Errorf("expected checksum %08x of block, got %08x", expected, computed)
+	}
+	d.events, err = decodeBlock(block)
+	return err
+}
+
+// readHash reads the hash at the end of the delta snapshot in the binary format and verifies the delta snapshot
+// against it.
+func (d *Decoder) readHash() error {
+	computedHash := d.r.hash.Sum(nil)
+	snapHash := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(d.r.Reader, snapHash); err != nil {
+		return fmt.Errorf("delta snapshot is missing hash: %w", unexpectedEOF(err))
+	}
+	if !bytes.Equal(snapHash, computedHash) {
+		return fmt.Errorf("expected sha256 %v, got %v", snapHash, computedHash)
+	}
+	if _, err := d.r.Reader.ReadByte(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the hash of the delta snapshot")
+	}
+	d.done = true
+	return nil
+}
+
+// decodeBlock decodes the events of the given block. Unknown fields are skipped.
+func decodeBlock(block []byte) ([]brtypes.Event, error) {
+	var events []brtypes.Event
+	for len(block) > 0 {
+		num, typ, n := protowire.ConsumeTag(block)
+		if n < 0 {
+			return nil, fmt.Errorf("failed to decode block: %v", protowire.ParseError(n))
+		}
+		block = block[n:]
+		if num != fieldBlockEvents || typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, block); n < 0 {
+				return nil, fmt.Errorf("failed to decode block: %v", protowire.ParseError(n))
+			}
+			block = block[n:]
+			continue
+		}
+		message, n := protowire.ConsumeBytes(block)
+		if n < 0 {
+			return nil, fmt.Errorf("failed to decode block: %v", protowire.ParseError(n))
+		}
+		block = block[n:]
+		event, err := decodeEvent(message)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// decodeEvent decodes the given event message. Unknown fields are skipped.
+func decodeEvent(message []byte) (brtypes.Event, error) {
+	var event brtypes.Event
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return event, fmt.Errorf("failed to decode event: %v", protowire.ParseError(n))
+		}
+		message = message[n:]
+		switch {
+		case num == fieldEventEvent && typ == protowire.BytesType:
+			var data []byte
+			if data, n = protowire.ConsumeBytes(message); n >= 0 {
+				etcdEvent := &mvccpb.Event{}
+				if err := etcdEvent.Unmarshal(data); err != nil {
+					return event, fmt.Errorf("failed to unmarshal etcd event: %v", err)
+				}
+				event.EtcdEvent = (*clientv3.Event)(etcdEvent)
+			}
+		case num == fieldEventTime && typ == protowire.VarintType:
+			var t uint64
+			if t, n = protowire.ConsumeVarint(message); n >= 0 {
+				event.Time = time.Unix(0, int64(t)) // #nosec G115 -- encoded from int64.
+			}
+		case num == fieldEventLease && typ == protowire.BytesType:
+			var data []byte
+			if data, n = protowire.ConsumeBytes(message); n >= 0 {
+				lease, err := decodeLease(data)
+				if err != nil {
+					return event, err
+				}
+				event.Lease = lease
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, message)
+		}
+		if n < 0 {
+			return event, fmt.Errorf("failed to decode event: %v", protowire.ParseError(n))
+		}
+		message = message[n:]
+	}
+	return event, checkEvent(&event)
+}
+
+// decodeLease decodes the given lease message. Unknown fields are skipped.
+func decodeLease(message []byte) (*brtypes.Lease, error) {
+	lease := &brtypes.Lease{}
+	for len(message) > 0 {
+		num, typ, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil, fmt.Errorf("failed to decode lease: %v", protowire.ParseError(n))
+		}
+		message = message[n:]
+		var field *int64
+		switch {
+		case num == fieldLeaseID && typ == protowire.VarintType:
+			field = &lease.ID
+		case num == fieldLeaseGrantedTTL && typ == protowire.VarintType:
+			field = &lease.GrantedTTL
+		case num == fieldLeaseTTL && typ == protowire.VarintType:
+			field = &lease.TTL
+		}
+		if field != nil {
+			var v uint64
+			if v, n = protowire.ConsumeVarint(message); n >= 0 {
+				*field = int64(v) // #nosec G115 -- encoded from int64.
+			}
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, message)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("failed to decode lease: %v", protowire.ParseError(n))
+		}
+		message = message[n:]
+	}
+	return lease, nil
+}
+
+// checkEvent checks that the given event has a key value, which records the revision of the event.
+func checkEvent(event *brtypes.Event) error {
+	if event.EtcdEvent == nil || event.EtcdEvent.Kv == nil {
+		return fmt.Errorf("delta snapshot contains an event without key value")
+	}
+	return nil
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF for io.EOF, as the delta snapshot must not end before its hash.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
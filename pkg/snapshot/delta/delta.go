@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package delta encodes and decodes the events of delta snapshots.
+//
+// Delta snapshots can be written in a versioned binary format, in which the events are framed in blocks, each of which
+// is a length-prefixed protobuf message followed by its checksum:
+//
+//	snapshot = magic version block* end hash
+//	block    = uvarint(len(payload)) payload crc32c(payload)
+//	end      = uvarint(0)
+//
+// The magic is the 8 bytes "ETCDBRDS", the version a single byte, the checksum of a block the big-endian CRC-32 of its
+// payload with the Castagnoli polynomial, and the hash the SHA256 hash of everything preceding it, like for the JSON
+// format, so that tools verifying or stripping the hash of snapshots work for both formats. The payload of a block is
+// the protobuf encoding of the following messages:
+//
+//	message Block {
+//	  repeated Event events = 1;
+//	}
+//
+//	message Event {
+//	  mvccpb.Event event = 1;
+//	  // time at which the event was received by the snapshotter, in nanoseconds since the Unix epoch
+//	  int64 time = 2;
+//	  // lease to which the key is attached, only set for the first event of each lease in a delta snapshot
+//	  Lease lease = 3;
+//	}
+//
+//	message Lease {
+//	  int64 id = 1;
+//	  int64 granted_ttl = 2;
+//	  int64 ttl = 3;
+//	}
+//
+// Each block is verified before its events are decoded, so that a delta snapshot can be decoded and applied as a
+// stream, with the memory bounded by the size of a block. Delta snapshots in the JSON format, the default and the only
+// format of earlier versions, are a JSON array of events followed by its SHA256 hash. The decoder streams them as well,
+// one event at a time. In both formats, the events are handed out before the hash at the end of the delta snapshot is
+// verified, so a mismatching hash is only reported once all events have been decoded.
+package delta
+
+import (
+	"hash/crc32"
+)
+
+const (
+	// FormatVersion is the version of the binary format written by the encoder.
+	FormatVersion = 1
+
+	// blockSize is the size of the encoded events after which the encoder ends a block.
+	blockSize = 1024 * 1024
+	// maxBlockSize is the maximum size of a block accepted by the decoder. Blocks exceed the block size by at most one
+	// event, which etcd limits to a fraction of this size.
+	maxBlockSize = 64 * 1024 * 1024
+	// checksumSize is the size of the checksum of a block.
+	checksumSize = 4
+
+	// fieldBlockEvents is the number of the events field of a block message.
+	fieldBlockEvents = 1
+	// fieldEventEvent is the number of the etcd event field of an event message.
+	fieldEventEvent = 1
+	// fieldEventTime is the number of the time field of an event message.
+	fieldEventTime = 2
+	// fieldEventLease is the number of the lease field of an event message.
+	fieldEventLease = 3
+	// fieldLeaseID is the number of the ID field of a lease message.
+	fieldLeaseID = 1
+	// fieldLeaseGrantedTTL is the number of the granted TTL field of a lease message.
+	fieldLeaseGrantedTTL = 2
+	// fieldLeaseTTL is the number of the TTL field of a lease message.
+	fieldLeaseTTL = 3
+)
+
+// magic is the beginning of delta snapshots in the binary format.
+var magic = []byte("ETCDBRDS")
+
+// crcTable is the table of the Castagnoli polynomial used for the checksums of blocks.
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package delta_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// newTestEvents returns events of the given number of revisions, with a put and a delete in each revision. The keys of
+// every third revision are attached to a lease.
+func newTestEvents(revisions int, valueSize int) []brtypes.Event {
+	var events []brtypes.Event
+	createdOn := time.Unix(1700000000, 0)
+	for rev := int64(1); rev <= int64(revisions); rev++ {
+		key := []byte(fmt.Sprintf("/registry/key-%d", rev))
+		var lease *brtypes.Lease
+		if rev%3 == 0 {
+			lease = &brtypes.Lease{ID: 0x694d7a1f5e3c0000 + rev, GrantedTTL: 3600, TTL: 3600 - rev}
+		}
+		put := brtypes.Event{
+			EtcdEvent: &clientv3.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: key, Value: []byte(strings.Repeat("v", valueSize)), CreateRevision: rev, ModRevision: rev, Version: 1}},
+			Time:      createdOn.Add(time.Duration(rev) * time.Millisecond),
+			Lease:     lease,
+		}
+		if lease != nil {
+			put.EtcdEvent.Kv.Lease = lease.ID
+		}
+		events = append(events,
+			put,
+			brtypes.Event{
+				EtcdEvent: &clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: key, ModRevision: rev}},
+				Time:      createdOn.Add(time.Duration(rev) * time.Millisecond),
+			},
+		)
+	}
+	return events
+}
+
+// encode encodes the given events in the given format, flushing them after every flushInterval events.
+func encode(events []brtypes.Event, format string, flushInterval int) []byte {
+	buf := &bytes.Buffer{}
+	encoder, err := delta.NewEncoder(buf, format)
+	Expect(err).ShouldNot(HaveOccurred())
+	for i := range events {
+		Expect(encoder.Encode(&events[i])).To(Succeed())
+		if (i+1)%flushInterval == 0 {
+			Expect(encoder.Flush()).To(Succeed())
+		}
+	}
+	Expect(encoder.Close()).To(Succeed())
+	return buf.Bytes()
+}
+
+// decode decodes the events from the given data until the decoder fails or reaches the end.
+func decode(data []byte) (*delta.Decoder, []brtypes.Event, error) {
+	decoder, err := delta.NewDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	var events []brtypes.Event
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return decoder, events, nil
+		}
+		if err != nil {
+			return decoder, events, err
+		}
+		events = append(events, *event)
+	}
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+var _ = Describe("Delta snapshot encoding", func() {
+	expectEqualEvents := func(actual, expected []brtypes.Event) {
+		Expect(actual).To(HaveLen(len(expected)))
+		for i := range expected {
+			Expect(*actual[i].EtcdEvent).To(Equal(*expected[i].EtcdEvent))
+			Expect(actual[i].Time.Equal(expected[i].Time)).To(BeTrue())
+			Expect(actual[i].Lease).To(Equal(expected[i].Lease))
+		}
+	}
+
+	for _, format := range []string{brtypes.DeltaSnapshotFormatBinary, brtypes.DeltaSnapshotFormatJSON} {
+		It(fmt.Sprintf("should decode the events encoded in the %s format", format), func() {
+			events := newTestEvents(50, 100*1024)
+			decoder, decoded, err := decode(encode(events, format, 7))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(decoder.Format()).To(Equal(format))
+			expectEqualEvents(decoded, events)
+		})
+	}
+
+	It("should use the JSON format by default", func() {
+		decoder, _, err := decode(encode(newTestEvents(1, 10), "", 1))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(decoder.Format()).To(Equal(brtypes.DeltaSnapshotFormatJSON))
+	})
+
+	It("should not return the events of a corrupted block", func() {
+		events := newTestEvents(4, 10)
+		data := encode(events, brtypes.DeltaSnapshotFormatBinary, 4)
+		// corrupt the last value of the second block
+		index := bytes.LastIndex(data, []byte("/registry/key-4"))
+		Expect(index).To(BeNumerically(">", 0))
+		data[index+len("/registry/key-4")+3] ^= 0xff
+
+		_, decoded, err := decode(data)
+		Expect(err).To(MatchError(ContainSubstring("checksum")))
+		expectEqualEvents(decoded, events[:4])
+	})
+
+	It("should fail to decode a truncated delta snapshot", func() {
+		data := encode(newTestEvents(4, 10), brtypes.DeltaSnapshotFormatBinary, 4)
+		for _, length := range []int{len(data) - 1, len(data) - 40} {
+			_, _, err := decode(data[:length])
+			Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+		}
+	})
+
+	It("should fail to decode a delta snapshot whose hash does not match", func() {
+		for _, format := range []string{brtypes.DeltaSnapshotFormatBinary, brtypes.DeltaSnapshotFormatJSON} {
+			data := encode(newTestEvents(4, 10), format, 4)
+			data[len(data)-1] ^= 0xff
+			_, _, err := decode(data)
+			Expect(err).To(MatchError(ContainSubstring("expected sha256")))
+		}
+	})
+
+	It("should decode the events of the JSON format without reading the whole delta snapshot", func() {
+		data := encode(newTestEvents(50, 100*1024), brtypes.DeltaSnapshotFormatJSON, 1)
+		r := &countingReader{r: bytes.NewReader(data)}
+		decoder, err := delta.NewDecoder(r)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = decoder.Next()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(r.n).To(BeNumerically("<", len(data)/10))
+	})
+
+	It("should decode a delta snapshot of earlier versions without events", func() {
+		data := []byte("null")
+		hash := sha256.Sum256(data)
+		_, decoded, err := decode(append(data, hash[:]...))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(decoded).To(BeEmpty())
+	})
+
+	It("should fail to decode a truncated delta snapshot in the JSON format", func() {
+		data := encode(newTestEvents(4, 10), brtypes.DeltaSnapshotFormatJSON, 4)
+		for _, length := range []int{len(data) - 1, len(data) - 40, 10} {
+			_, _, err := decode(data[:length])
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	It("should fail to decode an unsupported format version", func() {
+		data := encode(newTestEvents(1, 10), brtypes.DeltaSnapshotFormatBinary, 1)
+		data[len("ETCDBRDS")] = delta.FormatVersion + 1
+		_, _, err := decode(data)
+		Expect(err).To(MatchError(ContainSubstring("unsupported delta snapshot format version")))
+	})
+})
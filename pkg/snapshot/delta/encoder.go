@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package delta
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Encoder encodes the events of a delta snapshot.
+type Encoder interface {
+	// Encode encodes the given event.
+	Encode(event *brtypes.Event) error
+	// Flush writes the events encoded so far.
+	Flush() error
+	// Close writes the events encoded so far and ends the delta snapshot with its hash. It does not close the
+	// underlying writer.
+	Close() error
+}
+
+// NewEncoder returns an encoder which writes the events of a delta snapshot to the given writer in the given format.
+// The JSON format is used if no format is given.
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	hash := sha256.New()
+	hw := io.MultiWriter(w, hash)
+	switch format {
+	case brtypes.DeltaSnapshotFormatJSON, "":
+		return &jsonEncoder{w: w, hw: hw, hash: hash}, nil
+	case brtypes.DeltaSnapshotFormatBinary:
+		header := append(append([]byte{}, magic...), FormatVersion)
+		if _, err := hw.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to write delta snapshot header: %v", err)
+		}
+		return &binaryEncoder{w: w, hw: hw, hash: hash}, nil
+	default:
+		return nil, fmt.Errorf("unknown delta snapshot format: %s", format)
+	}
+}
+
+// binaryEncoder encodes events in the binary format.
+type binaryEncoder struct {
+	w     io.Writer
+	hw    io.Writer
+	hash  hash.Hash
+	block []byte
+}
+
+// Encode appends the given event to the current block, and writes the block once it has reached the block size.
+func (e *binaryEncoder) Encode(event *brtypes.Event) error {
+	etcdEvent, err := (*mvccpb.Event)(event.EtcdEvent).Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+	var message []byte
+	message = protowire.AppendTag(message, fieldEventEvent, protowire.BytesType)
+	message = protowire.AppendBytes(message, etcdEvent)
+	message = protowire.AppendTag(message, fieldEventTime, protowire.VarintType)
+	message = protowire.AppendVarint(message, uint64(event.Time.UnixNano())) // #nosec G115 -- decoded as int64 again.
+	if event.Lease != nil {
+		var lease []byte
+		lease = protowire.AppendTag(lease, fieldLeaseID, protowire.VarintType)
+		lease = protowire.AppendVarint(lease, uint64(event.Lease.ID)) // #nosec G115 -- decoded as int64 again.
+		lease = protowire.AppendTag(lease, fieldLeaseGrantedTTL, protowire.VarintType)
+		lease = protowire.AppendVarint(lease, uint64(event.Lease.GrantedTTL)) // #nosec G115 -- decoded as int64 again.
+		lease = protowire.AppendTag(lease, fieldLeaseTTL, protowire.VarintType)
+		lease = protowire.AppendVarint(lease, uint64(event.Lease.TTL)) // #nosec G115 -- decoded as int64 again.
+		message = protowire.AppendTag(message, fieldEventLease, protowire.BytesType)
+		message = protowire.AppendBytes(message, lease)
+	}
+
+	e.block = protowire.AppendTag(e.block, fieldBlockEvents, protowire.BytesType)
+	e.block = protowire.AppendBytes(e.block, message)
+	if len(e.block) >= blockSize {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes the current block, if it contains any events.
+func (e *binaryEncoder) Flush() error {
+	if len(e.block) == 0 {
+		return nil
+	}
+	frame := make([]byte, 0, binary.MaxVarintLen64+len(e.block)+checksumSize)
+	frame = binary.AppendUvarint(frame, uint64(len(e.block)))
+	frame = append(frame, e.block...)
+	frame = binary.BigEndian.AppendUint32(frame, crc32.Checksum(e.block, crcTable))
+	if _, err := e.hw.Write(frame); err != nil {
+		return fmt.Errorf("failed to write block of events: %v", err)
+	}
+	e.block = e.block[:0]
+	return nil
+}
+
+// Close writes the current block, the end of the blocks and the hash.
+func (e *binaryEncoder) Close() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	if _, err := e.hw.Write(binary.AppendUvarint(nil, 0)); err != nil {
+		return fmt.Errorf("failed to write end of blocks: %v", err)
+	}
+	if _, err := e.w.Write(e.hash.Sum(nil)); err != nil {
+		return fmt.Errorf("failed to write hash of events: %v", err)
+	}
+	return nil
+}
+
+// jsonEncoder encodes events in the JSON format of earlier versions, as a JSON array.
+type jsonEncoder struct {
+	w      io.Writer
+	hw     io.Writer
+	hash   hash.Hash
+	events int
+}
+
+// Encode writes the given event as the next element of the array.
+func (e *jsonEncoder) Encode(event *brtypes.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal events to json: %v", err)
+	}
+	separator := byte(',')
+	if e.events == 0 {
+		separator = '['
+	}
+	if _, err := e.hw.Write(append([]byte{separator}, data...)); err != nil {
+		return fmt.Errorf("failed to write event: %v", err)
+	}
+	e.events++
+	return nil
+}
+
+// Flush does nothing, as the events are written as they are encoded.
+func (e *jsonEncoder) Flush() error {
+	return nil
+}
+
+// Close ends the array and writes the hash.
+func (e *jsonEncoder) Close() error {
+	end := []byte("]")
+	if e.events == 0 {
+		end = []byte("[]")
+	}
+	if _, err := e.hw.Write(end); err != nil {
+		return fmt.Errorf("failed to write end of events: %v", err)
+	}
+	if _, err := e.w.Write(e.hash.Sum(nil)); err != nil {
+		return fmt.Errorf("failed to write hash of events: %v", err)
+	}
+	return nil
+}
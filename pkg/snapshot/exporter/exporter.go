@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/restorer"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Record is a key of the restored data as written in the NDJSON format.
+type Record struct {
+	Key            string `json:"key"`
+	Value          []byte `json:"value"`
+	CreateRevision int64  `json:"createRevision"`
+	ModRevision    int64  `json:"modRevision"`
+	Version        int64  `json:"version"`
+	Lease          int64  `json:"lease"`
+}
+
+// Result holds the outcome of a successful export.
+type Result struct {
+	// BaseSnapshot is the name of the full snapshot the backups were restored from.
+	BaseSnapshot string
+	// DeltaSnapshots is the number of delta snapshots applied on top of the base snapshot.
+	DeltaSnapshots int
+	// Revision is the revision of the restored data.
+	Revision int64
+	// KeyCount is the number of exported keys.
+	KeyCount int64
+}
+
+// Exporter restores backups into a scratch directory and writes the restored keys, to inspect or extract them
+// without restoring an etcd cluster.
+type Exporter struct {
+	logger            *logrus.Entry
+	store             brtypes.SnapStore
+	config            *brtypes.ExporterConfig
+	restorationConfig *brtypes.RestorationConfig
+}
+
+// NewExporter returns a new exporter for the backups in the given store. The restoration config is used to tune the
+// restoration, its data directory and cluster configuration are replaced by scratch values.
+func NewExporter(store brtypes.SnapStore, config *brtypes.ExporterConfig, restorationConfig *brtypes.RestorationConfig, logger *logrus.Entry) *Exporter {
+	return &Exporter{
+		logger:            logger.WithField("actor", "exporter"),
+		store:             store,
+		config:            config,
+		restorationConfig: restorationConfig,
+	}
+}
+
+// Export restores the full snapshot and the delta snapshots on top of it up to the target revision into the scratch
+// directory, and writes the keys selected by the key prefix filter to the given writer, ordered by key.
+func (x *Exporter) Export(ctx context.Context, w io.Writer) (*Result, error) {
+	baseSnap, deltaSnapList, err := x.snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	// the backups are restored into a scratch directory of the export, so that nothing else within the data dir is removed.
+	if err := os.MkdirAll(x.config.DataDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create export data dir %s: %v", x.config.DataDir, err)
+	}
+	scratchDir, err := os.MkdirTemp(x.config.DataDir, "export-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch dir in export data dir %s: %v", x.config.DataDir, err)
+	}
+	defer func() {
+		if err := os.RemoveAll(scratchDir); err != nil {
+			x.logger.Errorf("Failed to remove export scratch dir %s: %v", scratchDir, err)
+		}
+	}()
+	ro, err := restorer.NewScratchRestoreOptions(x.restorationConfig, filepath.Join(scratchDir, "data"), filepath.Join(scratchDir, "snapshots"), baseSnap, deltaSnapList)
+	if err != nil {
+		return nil, err
+	}
+	// the restoration stops at the target revision, the keys are filtered while they are exported.
+	ro.Config.TargetRevision = x.config.TargetRevision
+
+	x.logger.Infof("Restoring full snapshot %s and %d delta snapshots...", baseSnap.SnapName, len(deltaSnapList))
+	r, err := restorer.NewRestorer(x.store, x.logger)
+	if err != nil {
+		return nil, err
+	}
+	embeddedEtcd, err := r.Restore(ctx, *ro, nil)
+	if embeddedEtcd != nil {
+		defer embeddedEtcd.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore snapshots: %v", err)
+	}
+	// There is a possibility that restore operation may not start an embedded ETCD.
+	if embeddedEtcd == nil {
+		if embeddedEtcd, err = miscellaneous.StartEmbeddedEtcd(x.logger, ro); err != nil {
+			return nil, err
+		}
+		defer embeddedEtcd.Close()
+	}
+
+	clientFactory := etcdutil.NewClientFactory(ro.NewClientFactory, brtypes.EtcdConnectionConfig{
+		MaxCallSendMsgSize: ro.Config.MaxCallSendMsgSize,
+		Endpoints:          []string{embeddedEtcd.Clients[0].Addr().String()},
+		InsecureTransport:  true,
+	})
+	clientKV, err := clientFactory.NewKV()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build etcd KV client: %v", err)
+	}
+	defer clientKV.Close()
+
+	result := &Result{
+		BaseSnapshot:   baseSnap.SnapName,
+		DeltaSnapshots: len(deltaSnapList),
+	}
+	x.logger.Info("Exporting the restored keys...")
+	if err := x.export(ctx, clientKV, w, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// snapshots returns the full snapshot to export along with the delta snapshots on top of it up to the target revision.
+// The latest full snapshot at or before the target revision is exported if no full snapshot is configured.
+func (x *Exporter) snapshots() (*brtypes.Snapshot, brtypes.SnapList, error) {
+	if len(x.config.Snapshot) != 0 {
+		baseSnap, deltaSnapList, err := miscellaneous.GetFullSnapshotAndDeltaSnapListByName(x.store, x.config.Snapshot, x.config.TargetRevision)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get snapshots of full snapshot %s: %w", x.config.Snapshot, err)
+		}
+		return baseSnap, deltaSnapList, nil
+	}
+	baseSnap, deltaSnapList, err := miscellaneous.GetFullSnapshotAndDeltaSnapListUptoTarget(x.store, x.config.TargetRevision, time.Time{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshots to export: %w", err)
+	}
+	return baseSnap, deltaSnapList, nil
+}
+
+// export writes the selected keys of the restored data to the given writer in the configured format.
+func (x *Exporter) export(ctx context.Context, clientKV client.KVCloser, w io.Writer, result *Result) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	write := func(kv *mvccpb.KeyValue) error {
+		if x.config.Format == brtypes.ExportFormatKeys {
+			_, err := fmt.Fprintf(bw, "%s\n", kv.Key)
+			return err
+		}
+		return encoder.Encode(Record{
+			Key:            string(kv.Key),
+			Value:          kv.Value,
+			CreateRevision: kv.CreateRevision,
+			ModRevision:    kv.ModRevision,
+			Version:        kv.Version,
+			Lease:          kv.Lease,
+		})
+	}
+
+	keyFilter := x.config.KeyFilter()
+	for _, keyRange := range exportRanges(keyFilter.IncludePrefixes) {
+		key := keyRange.start
+		for {
+			opts := []clientv3.OpOption{clientv3.WithLimit(x.config.BatchSize), clientv3.WithRev(result.Revision)}
+			if len(keyRange.end) != 0 {
+				opts = append(opts, clientv3.WithRange(keyRange.end))
+			} else {
+				opts = append(opts, clientv3.WithFromKey())
+			}
+			resp, err := clientKV.Get(ctx, key, opts...)
+			if err != nil {
+				return fmt.Errorf("failed to get restored keys from %q: %v", key, err)
+			}
+			// all batches are read at the revision of the first one.
+			result.Revision = resp.Header.Revision
+			for _, kv := range resp.Kvs {
+				if !keyFilter.Includes(kv.Key) {
+					continue
+				}
+				if err := write(kv); err != nil {
+					return fmt.Errorf("failed to write key %q: %v", kv.Key, err)
+				}
+				result.KeyCount++
+			}
+			if !resp.More || len(resp.Kvs) == 0 {
+				break
+			}
+			key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write the export: %v", err)
+	}
+	return nil
+}
+
+// keyRange is a range of keys from start up to end, exclusive. An empty end means all keys from start.
+type keyRange struct {
+	start, end string
+}
+
+// exportRanges returns the sorted key ranges of the given include prefixes, or the whole key space if there are none.
+// Prefixes which have another one of the prefixes as prefix are left out, so that no key is exported twice.
+func exportRanges(includePrefixes []string) []keyRange {
+	if len(includePrefixes) == 0 {
+		return []keyRange{{start: "\x00"}}
+	}
+	prefixes := slices.Clone(includePrefixes)
+	slices.Sort(prefixes)
+	var ranges []keyRange
+	for _, prefix := range prefixes {
+		if len(ranges) != 0 && strings.HasPrefix(prefix, ranges[len(ranges)-1].start) {
+			continue
+		}
+		ranges = append(ranges, keyRange{start: prefix, end: clientv3.GetPrefixRangeEnd(prefix)})
+	}
+	return ranges
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+	"github.com/gardener/etcd-backup-restore/test/utils"
+
+	"github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	outputDir    = "../../../test/output"
+	etcdDir      = outputDir + "/default.etcd"
+	snapstoreDir = outputDir + "/snapshotter.bkp"
+	exportDir    = outputDir + "/export"
+	podCount     = 5
+)
+
+var (
+	testCtx = context.Background()
+	logger  = logrus.New().WithField("suite", "exporter")
+	err     error
+	// fullSnapshotRevision is the revision of the full snapshot, lastRevision is the last revision of the delta snapshots.
+	fullSnapshotRevision, lastRevision int64
+	eventLease                         clientv3.LeaseID
+)
+
+func TestExporter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Exporter Suite")
+}
+
+var _ = SynchronizedBeforeSuite(func() []byte {
+	err = os.RemoveAll(outputDir)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	etcd, err := utils.StartEmbeddedEtcd(testCtx, etcdDir, logger, utils.DefaultEtcdName, "")
+	Expect(err).ShouldNot(HaveOccurred())
+	endpoints := []string{etcd.Clients[0].Addr().String()}
+	defer func() {
+		etcd.Server.Stop()
+		etcd.Close()
+	}()
+	cli, err := clientv3.New(clientv3.Config{Endpoints: endpoints})
+	Expect(err).ShouldNot(HaveOccurred())
+	defer cli.Close()
+
+	// the full snapshot holds all pods with value v1, a secret and an event with a lease.
+	for i := 0; i < podCount; i++ {
+		_, err = cli.Put(testCtx, fmt.Sprintf("/registry/pods/default/pod-%d", i), "v1")
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	_, err = cli.Put(testCtx, "/registry/secrets/default/secret-0", "v1")
+	Expect(err).ShouldNot(HaveOccurred())
+	lease, err := cli.Grant(testCtx, 3600)
+	Expect(err).ShouldNot(HaveOccurred())
+	eventLease = lease.ID
+	resp, err := cli.Put(testCtx, "/registry/events/default/event-0", "v1", clientv3.WithLease(eventLease))
+	Expect(err).ShouldNot(HaveOccurred())
+	fullSnapshotRevision = resp.Header.Revision
+
+	deltaSnapshotPeriod := time.Second
+	compressionConfig := compressor.NewCompressorConfig()
+	snapstoreConfig := brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+	ctx, cancel := context.WithTimeout(testCtx, 2*time.Second)
+	defer cancel()
+	err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, endpoints, "", "", ctx.Done(), true, compressionConfig)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	// the delta snapshots update all pods but the last one to value v2, and delete the last one.
+	for i := 0; i < podCount-1; i++ {
+		_, err = cli.Put(testCtx, fmt.Sprintf("/registry/pods/default/pod-%d", i), "v2")
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	delResp, err := cli.Delete(testCtx, fmt.Sprintf("/registry/pods/default/pod-%d", podCount-1))
+	Expect(err).ShouldNot(HaveOccurred())
+	lastRevision = delResp.Header.Revision
+
+	ctx, cancel = context.WithTimeout(testCtx, 2*time.Second)
+	defer cancel()
+	err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, endpoints, "", "", ctx.Done(), false, compressionConfig)
+	Expect(err).ShouldNot(HaveOccurred())
+	return nil
+}, func(_ []byte) {})
+
+var _ = SynchronizedAfterSuite(func() {}, func() {
+	err = os.RemoveAll(outputDir)
+	Expect(err).ShouldNot(HaveOccurred())
+})
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/exporter"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		config *brtypes.ExporterConfig
+		store  brtypes.SnapStore
+	)
+
+	BeforeEach(func() {
+		config = brtypes.NewExporterConfig()
+		config.DataDir = exportDir
+		config.BatchSize = 2
+		store, err = snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"})
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	export := func() (*exporter.Result, []byte) {
+		Expect(config.Validate()).To(Succeed())
+		buf := &bytes.Buffer{}
+		result, err := exporter.NewExporter(store, config, brtypes.NewRestorationConfig(), logger).Export(context.TODO(), buf)
+		Expect(err).ShouldNot(HaveOccurred())
+		scratchDirs, err := filepath.Glob(filepath.Join(exportDir, "export-*"))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(scratchDirs).To(BeEmpty())
+		return result, buf.Bytes()
+	}
+
+	decodeRecords := func(data []byte) map[string]exporter.Record {
+		records := map[string]exporter.Record{}
+		var keys []string
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			var record exporter.Record
+			Expect(json.Unmarshal(scanner.Bytes(), &record)).To(Succeed())
+			records[record.Key] = record
+			keys = append(keys, record.Key)
+		}
+		Expect(scanner.Err()).ShouldNot(HaveOccurred())
+		Expect(keys).To(BeEquivalentTo(sortedKeys(records)))
+		return records
+	}
+
+	It("should export all keys of the latest backups as NDJSON", func() {
+		result, data := export()
+		Expect(result.DeltaSnapshots).To(BeNumerically(">", 0))
+		Expect(result.Revision).To(Equal(lastRevision))
+		Expect(result.KeyCount).To(Equal(int64(podCount + 1)))
+
+		records := decodeRecords(data)
+		Expect(records).To(HaveLen(podCount + 1))
+		for i := 0; i < podCount-1; i++ {
+			record := records[fmt.Sprintf("/registry/pods/default/pod-%d", i)]
+			Expect(string(record.Value)).To(Equal("v2"))
+			Expect(record.CreateRevision).To(BeNumerically("<=", fullSnapshotRevision))
+			Expect(record.ModRevision).To(BeNumerically(">", fullSnapshotRevision))
+			Expect(record.Version).To(Equal(int64(2)))
+			Expect(record.Lease).To(BeZero())
+		}
+		Expect(records).NotTo(HaveKey(fmt.Sprintf("/registry/pods/default/pod-%d", podCount-1)))
+		Expect(records["/registry/events/default/event-0"].Lease).To(Equal(int64(eventLease)))
+		Expect(records["/registry/events/default/event-0"].ModRevision).To(Equal(fullSnapshotRevision))
+	})
+
+	It("should export only the keys matching the key prefix filters", func() {
+		config.Format = brtypes.ExportFormatKeys
+		config.IncludeKeyPrefixes = []string{"/registry/pods/", "/registry/events/", "/registry/pods/default/"}
+		config.ExcludeKeyPrefixes = []string{"/registry/pods/default/pod-1"}
+		result, data := export()
+		Expect(result.KeyCount).To(Equal(int64(podCount - 1)))
+		Expect(strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")).To(Equal([]string{
+			"/registry/events/default/event-0",
+			"/registry/pods/default/pod-0",
+			"/registry/pods/default/pod-2",
+			"/registry/pods/default/pod-3",
+		}))
+	})
+
+	It("should export the given full snapshot up to the target revision", func() {
+		fullSnapshot, _, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+		Expect(err).ShouldNot(HaveOccurred())
+		config.Snapshot = fullSnapshot.SnapName
+		config.TargetRevision = fullSnapshotRevision + 2
+		result, data := export()
+		Expect(result.BaseSnapshot).To(Equal(fullSnapshot.SnapName))
+		Expect(result.Revision).To(Equal(fullSnapshotRevision + 2))
+
+		records := decodeRecords(data)
+		Expect(records).To(HaveLen(podCount + 2))
+		for i := 0; i < podCount; i++ {
+			expectedValue := "v1"
+			if i < 2 {
+				expectedValue = "v2"
+			}
+			Expect(string(records[fmt.Sprintf("/registry/pods/default/pod-%d", i)].Value)).To(Equal(expectedValue))
+		}
+	})
+
+	It("should keep the other contents of the export data dir", func() {
+		Expect(os.MkdirAll(exportDir, 0700)).To(Succeed())
+		otherFile := filepath.Join(exportDir, "other")
+		Expect(os.WriteFile(otherFile, []byte("other"), 0600)).To(Succeed())
+		defer os.Remove(otherFile)
+
+		result, _ := export()
+		Expect(result.KeyCount).To(Equal(int64(podCount + 1)))
+		Expect(otherFile).To(BeAnExistingFile())
+	})
+
+	It("should fail if the given full snapshot does not exist", func() {
+		config.Snapshot = "Full-00000000-00000001-1"
+		_, err := exporter.NewExporter(store, config, brtypes.NewRestorationConfig(), logger).Export(context.TODO(), &bytes.Buffer{})
+		Expect(errors.Is(err, brtypes.ErrSnapshotNotFound)).To(BeTrue())
+	})
+
+	It("should fail without backups", func() {
+		emptyStoreDir := outputDir + "/empty.bkp"
+		defer os.RemoveAll(emptyStoreDir)
+		store, err := snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: emptyStoreDir, Provider: "Local"})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = exporter.NewExporter(store, config, brtypes.NewRestorationConfig(), logger).Export(context.TODO(), &bytes.Buffer{})
+		Expect(err).Should(MatchError(ContainSubstring("no full snapshot found")))
+	})
+})
+
+func sortedKeys(records map[string]exporter.Record) []string {
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
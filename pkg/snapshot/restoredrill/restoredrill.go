@@ -0,0 +1,217 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package restoredrill
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	"github.com/gardener/etcd-backup-restore/pkg/metrics"
+	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/restorer"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cron "github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Result holds the outcome of a successful restore drill.
+type Result struct {
+	// BaseSnapshot is the name of the full snapshot the backups were restored from.
+	BaseSnapshot string
+	// DeltaSnapshots is the number of delta snapshots applied on top of the base snapshot.
+	DeltaSnapshots int
+	// ExpectedRevision is the last revision of the restored snapshots.
+	ExpectedRevision int64
+	// Revision is the revision of the restored data.
+	Revision int64
+	// KeyCount is the number of keys in the restored data.
+	KeyCount int64
+	// HashKV is the hash of the restored key value store at Revision.
+	HashKV   uint32
+	Duration time.Duration
+}
+
+// Drill restores the latest backups into a scratch directory to verify that they can be restored.
+type Drill struct {
+	logger            *logrus.Entry
+	store             brtypes.SnapStore
+	config            *brtypes.RestoreDrillConfig
+	restorationConfig *brtypes.RestorationConfig
+}
+
+// NewDrill returns a new restore drill for the backups in the given store. The restoration config is used
+// to tune the restoration, its data directory and cluster configuration are replaced by scratch values.
+func NewDrill(store brtypes.SnapStore, config *brtypes.RestoreDrillConfig, restorationConfig *brtypes.RestorationConfig, logger *logrus.Entry) *Drill {
+	return &Drill{
+		logger:            logger.WithField("actor", "restore-drill"),
+		store:             store,
+		config:            config,
+		restorationConfig: restorationConfig,
+	}
+}
+
+// Run restores the latest full snapshot and the delta snapshots based on it into the scratch directory,
+// and checks that the restored data has the last revision of the snapshots.
+func (d *Drill) Run(ctx context.Context) (*Result, error) {
+	startTime := time.Now()
+	result, err := d.run(ctx)
+	duration := time.Since(startTime)
+	if err != nil {
+		metrics.RestoreDrillTotal.With(prometheus.Labels{metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Inc()
+		metrics.RestoreDrillDurationSeconds.With(prometheus.Labels{metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Observe(duration.Seconds())
+		return nil, err
+	}
+	result.Duration = duration
+
+	metrics.RestoreDrillTotal.With(prometheus.Labels{metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Inc()
+	metrics.RestoreDrillDurationSeconds.With(prometheus.Labels{metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Observe(duration.Seconds())
+	metrics.RestoreDrillLatestSuccessTimestamp.With(prometheus.Labels{}).Set(float64(time.Now().Unix()))
+	metrics.RestoreDrillRevision.With(prometheus.Labels{}).Set(float64(result.Revision))
+	metrics.RestoreDrillKeysTotal.With(prometheus.Labels{}).Set(float64(result.KeyCount))
+	metrics.RestoreDrillHashKV.With(prometheus.Labels{}).Set(float64(result.HashKV))
+	return result, nil
+}
+
+func (d *Drill) run(ctx context.Context) (*Result, error) {
+	baseSnap, deltaSnapList, err := miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(d.store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest snapshots: %v", err)
+	}
+	if baseSnap == nil {
+		return nil, fmt.Errorf("no base snapshot found")
+	}
+
+	// The drill restores into a scratch directory of its own, so that it never removes anything else within the data dir.
+	if err := os.MkdirAll(d.config.DataDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create restore drill data dir %s: %v", d.config.DataDir, err)
+	}
+	scratchDir, err := os.MkdirTemp(d.config.DataDir, "restore-drill-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch dir in restore drill data dir %s: %v", d.config.DataDir, err)
+	}
+	defer func() {
+		if err := os.RemoveAll(scratchDir); err != nil {
+			d.logger.Errorf("Failed to remove restore drill scratch dir %s: %v", scratchDir, err)
+		}
+	}()
+	// the drill restores all the latest backups.
+	ro, err := restorer.NewScratchRestoreOptions(d.restorationConfig, filepath.Join(scratchDir, "data"), filepath.Join(scratchDir, "snapshots"), baseSnap, deltaSnapList)
+	if err != nil {
+		return nil, err
+	}
+
+	d.logger.Infof("Restoring full snapshot %s and %d delta snapshots...", baseSnap.SnapName, len(deltaSnapList))
+	r, err := restorer.NewRestorer(d.store, d.logger)
+	if err != nil {
+		return nil, err
+	}
+	embeddedEtcd, err := r.Restore(ctx, *ro, nil)
+	if embeddedEtcd != nil {
+		defer embeddedEtcd.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore snapshots: %v", err)
+	}
+	// There is a possibility that restore operation may not start an embedded ETCD.
+	if embeddedEtcd == nil {
+		if embeddedEtcd, err = miscellaneous.StartEmbeddedEtcd(d.logger, ro); err != nil {
+			return nil, err
+		}
+		defer embeddedEtcd.Close()
+	}
+
+	endpoint := embeddedEtcd.Clients[0].Addr().String()
+	clientFactory := etcdutil.NewClientFactory(ro.NewClientFactory, brtypes.EtcdConnectionConfig{
+		MaxCallSendMsgSize: ro.Config.MaxCallSendMsgSize,
+		Endpoints:          []string{endpoint},
+		InsecureTransport:  true,
+	})
+	clientKV, err := clientFactory.NewKV()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build etcd KV client: %v", err)
+	}
+	defer clientKV.Close()
+	clientMaintenance, err := clientFactory.NewMaintenance()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build etcd maintenance client: %v", err)
+	}
+	defer clientMaintenance.Close()
+
+	resp, err := clientKV.Get(ctx, "\x00", clientv3.WithFromKey(), clientv3.WithCountOnly())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count restored keys: %v", err)
+	}
+	result := &Result{
+		BaseSnapshot:     baseSnap.SnapName,
+		DeltaSnapshots:   len(deltaSnapList),
+		ExpectedRevision: baseSnap.LastRevision,
+		Revision:         resp.Header.Revision,
+		KeyCount:         resp.Count,
+	}
+	if len(deltaSnapList) != 0 {
+		result.ExpectedRevision = deltaSnapList[len(deltaSnapList)-1].LastRevision
+	}
+	// The revision in the name of a full snapshot might be lower than the revision of its data.
+	// Refer: https://github.com/coreos/etcd/issues/9037
+	// The removal of the revision padding of events filtered out by the snapshotter takes one more revision.
+	if result.Revision < result.ExpectedRevision || (len(deltaSnapList) != 0 && result.Revision > result.ExpectedRevision+1) {
+		return nil, fmt.Errorf("revision %d of the restored data does not match the last revision %d of the snapshots", result.Revision, result.ExpectedRevision)
+	}
+
+	hashResp, err := clientMaintenance.HashKV(ctx, endpoint, result.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash of restored data: %v", err)
+	}
+	result.HashKV = hashResp.Hash
+	return result, nil
+}
+
+// drillJob implements the cron.Job for restore drills.
+type drillJob struct {
+	ctx    context.Context
+	drill  *Drill
+	config *brtypes.RestoreDrillConfig
+	logger *logrus.Entry
+}
+
+func (j *drillJob) Run() {
+	ctx, cancel := context.WithTimeout(j.ctx, j.config.Timeout.Duration)
+	defer cancel()
+
+	j.logger.Info("Starting restore drill...")
+	result, err := j.drill.Run(ctx)
+	if err != nil {
+		j.logger.Errorf("Restore drill failed: %v", err)
+		return
+	}
+	j.logger.Infof("Restore drill succeeded in %v: restored full snapshot %s and %d delta snapshots up to revision %d with %d keys and hash %d.",
+		result.Duration, result.BaseSnapshot, result.DeltaSnapshots, result.Revision, result.KeyCount, result.HashKV)
+}
+
+// RunDrillsPeriodically runs the restore drill as per the given schedule until the context is cancelled.
+func RunDrillsPeriodically(ctx context.Context, drill *Drill, schedule cron.Schedule) {
+	job := &drillJob{
+		ctx:    ctx,
+		drill:  drill,
+		config: drill.config,
+		logger: drill.logger,
+	}
+	jobRunner := cron.New(cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
+	jobRunner.Schedule(schedule, job)
+
+	jobRunner.Start()
+
+	<-ctx.Done()
+	drill.logger.Info("Closing restore drills.")
+	jobRunnerCtx := jobRunner.Stop()
+	<-jobRunnerCtx.Done()
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package restoredrill_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/gardener/etcd-backup-restore/pkg/metrics"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/restoredrill"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restore drill", func() {
+	var config *brtypes.RestoreDrillConfig
+
+	BeforeEach(func() {
+		config = brtypes.NewRestoreDrillConfig()
+		config.DataDir = drillDir
+	})
+
+	drillCount := func(succeeded string) float64 {
+		return metricValue(metrics.RestoreDrillTotal.With(prometheus.Labels{metrics.LabelSucceeded: succeeded}))
+	}
+
+	Context("with backups", func() {
+		It("should restore the latest backups and report the restored data", func() {
+			store, err := snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"})
+			Expect(err).ShouldNot(HaveOccurred())
+			succeeded := drillCount(metrics.ValueSucceededTrue)
+
+			drill := restoredrill.NewDrill(store, config, brtypes.NewRestorationConfig(), logger)
+			result, err := drill.Run(context.TODO())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// every 10th key is deleted by the populator.
+			expectedKeys := int64(keyTo + 1 - (keyTo/10 + 1))
+			Expect(result.DeltaSnapshots).To(BeNumerically(">", 0))
+			Expect(result.Revision).To(Equal(endRev))
+			Expect(result.Revision).To(Equal(result.ExpectedRevision))
+			Expect(result.KeyCount).To(Equal(expectedKeys))
+			Expect(result.HashKV).NotTo(BeZero())
+
+			Expect(drillCount(metrics.ValueSucceededTrue)).To(Equal(succeeded + 1))
+			Expect(metricValue(metrics.RestoreDrillRevision.With(prometheus.Labels{}))).To(Equal(float64(endRev)))
+			Expect(metricValue(metrics.RestoreDrillKeysTotal.With(prometheus.Labels{}))).To(Equal(float64(expectedKeys)))
+			entries, err := os.ReadDir(drillDir)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("should restore the same data in every drill", func() {
+			store, err := snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"})
+			Expect(err).ShouldNot(HaveOccurred())
+
+			drill := restoredrill.NewDrill(store, config, brtypes.NewRestorationConfig(), logger)
+			first, err := drill.Run(context.TODO())
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := drill.Run(context.TODO())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(second.HashKV).To(Equal(first.HashKV))
+			Expect(second.KeyCount).To(Equal(first.KeyCount))
+		})
+	})
+
+	Context("without backups", func() {
+		It("should fail", func() {
+			emptyStoreDir := outputDir + "/empty.bkp"
+			defer os.RemoveAll(emptyStoreDir)
+			store, err := snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: emptyStoreDir, Provider: "Local"})
+			Expect(err).ShouldNot(HaveOccurred())
+			failed := drillCount(metrics.ValueSucceededFalse)
+
+			drill := restoredrill.NewDrill(store, config, brtypes.NewRestorationConfig(), logger)
+			_, err = drill.Run(context.TODO())
+			Expect(err).Should(MatchError(ContainSubstring("no base snapshot found")))
+			Expect(drillCount(metrics.ValueSucceededFalse)).To(Equal(failed + 1))
+		})
+	})
+})
+
+func metricValue(metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	Expect(metric.Write(m)).To(Succeed())
+	if m.Counter != nil {
+		return m.Counter.GetValue()
+	}
+	return m.Gauge.GetValue()
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package restorer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// leaseKeepAlivePeriod is the period in which the leases of the applied events are kept alive while the delta snapshots
+// are applied.
+const leaseKeepAlivePeriod = time.Second
+
+// leaseApplier recreates the leases of the keys of the applied events in the embedded etcd, so that the keys are
+// attached to their leases as in the original data.
+type leaseApplier struct {
+	logger      *logrus.Entry
+	clientLease client.LeaseCloser
+	// leases tells for each lease looked up so far whether it exists in the embedded etcd.
+	leases map[int64]bool
+	// keptAlive are the leases which exist in the embedded etcd and are attached to keys by the applied events, both
+	// the ones of the base snapshot and the ones granted by the lease applier. They are kept alive until the lease
+	// applier is closed, so that they do not expire and delete their keys while the delta snapshots are applied, and
+	// later events can still attach keys to them.
+	keptAliveMutex  sync.Mutex
+	keptAlive       []int64
+	stopKeepAlive   context.CancelFunc
+	keepAliveClosed chan struct{}
+}
+
+// newLeaseApplier returns a lease applier which recreates the leases with the given lease client.
+func newLeaseApplier(logger *logrus.Entry, clientLease client.LeaseCloser) *leaseApplier {
+	return &leaseApplier{
+		logger:      logger,
+		clientLease: clientLease,
+		leases:      map[int64]bool{},
+	}
+}
+
+// putOptions returns the options of the put operation of the given event, which attach its key to its lease. A lease
+// which does not exist in the embedded etcd yet is granted with its recorded ID and remaining TTL. The key is not
+// attached to any lease if its lease neither exists nor has been recorded, as for delta snapshots taken by earlier
+// versions.
+func (l *leaseApplier) putOptions(event *brtypes.Event) ([]clientv3.OpOption, error) {
+	id := event.EtcdEvent.Kv.Lease
+	if id == 0 {
+		return nil, nil
+	}
+	exists, lookedUp := l.leases[id]
+	if !exists && (!lookedUp || event.Lease != nil) {
+		var err error
+		if exists, err = l.recreate(id, event.Lease, !lookedUp); err != nil {
+			return nil, err
+		}
+		l.leases[id] = exists
+	}
+	if !exists {
+		return nil, nil
+	}
+	return []clientv3.OpOption{clientv3.WithLease(clientv3.LeaseID(id))}, nil
+}
+
+// recreate grants the lease with the given ID as recorded, unless it exists already, e.g. as part of the base snapshot.
+// It returns whether the lease exists in the embedded etcd, and keeps it alive if so.
+func (l *leaseApplier) recreate(id int64, lease *brtypes.Lease, warn bool) (bool, error) {
+	resp, err := l.clientLease.LeaseTimeToLive(context.TODO(), &etcdserverpb.LeaseTimeToLiveRequest{ID: id})
+	if err != nil {
+		return false, fmt.Errorf("failed to look up lease %x: %v", id, err)
+	}
+	if resp.TTL >= 0 {
+		l.keepAlive(id)
+		return true, nil
+	}
+	if lease == nil {
+		if warn {
+			l.logger.Warnf("Lease %x is neither part of the restored data nor recorded in the delta snapshots, its keys are restored without lease.", id)
+		}
+		return false, nil
+	}
+	if _, err := l.clientLease.LeaseGrant(context.TODO(), &etcdserverpb.LeaseGrantRequest{ID: id, TTL: lease.TTL}); err != nil {
+		return false, fmt.Errorf("failed to recreate lease %x: %v", id, err)
+	}
+	l.logger.Infof("Recreated lease %x with its remaining TTL of %d seconds.", id, lease.TTL)
+	l.keepAlive(id)
+	return true, nil
+}
+
+// keepAlive keeps the given lease alive until the lease applier is closed.
+func (l *leaseApplier) keepAlive(id int64) {
+	l.keptAliveMutex.Lock()
+	defer l.keptAliveMutex.Unlock()
+	l.keptAlive = append(l.keptAlive, id)
+	if l.stopKeepAlive != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	l.stopKeepAlive = cancel
+	l.keepAliveClosed = make(chan struct{})
+	go l.runKeepAlive(ctx)
+}
+
+// runKeepAlive keeps the leases alive until the given context is cancelled.
+func (l *leaseApplier) runKeepAlive(ctx context.Context) {
+	defer close(l.keepAliveClosed)
+	stream, err := l.clientLease.LeaseKeepAlive(ctx)
+	if err != nil {
+		l.logger.Warnf("Failed to keep the leases of the applied events alive: %v", err)
+		return
+	}
+	go func() {
+		// the responses are only drained
+		for {
+			if _, err := stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+	ticker := time.NewTicker(leaseKeepAlivePeriod)
+	defer ticker.Stop()
+	for {
+		l.keptAliveMutex.Lock()
+		keptAlive := l.keptAlive
+		l.keptAliveMutex.Unlock()
+		for _, id := range keptAlive {
+			if err := stream.Send(&etcdserverpb.LeaseKeepAliveRequest{ID: id}); err != nil {
+				if ctx.Err() == nil {
+					l.logger.Warnf("Failed to keep the leases of the applied events alive: %v", err)
+				}
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// close stops keeping the leases alive.
+func (l *leaseApplier) close() {
+	l.keptAliveMutex.Lock()
+	stopKeepAlive, keepAliveClosed := l.stopKeepAlive, l.keepAliveClosed
+	l.keptAliveMutex.Unlock()
+	if stopKeepAlive == nil {
+		return
+	}
+	stopKeepAlive()
+	<-keepAliveClosed
+}
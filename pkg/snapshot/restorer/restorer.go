@@ -8,7 +8,6 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math"
@@ -16,6 +15,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +25,7 @@ import (
 	"github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
 	"github.com/gardener/etcd-backup-restore/pkg/member"
 	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
 
 	"github.com/sirupsen/logrus"
@@ -40,8 +41,65 @@ const (
 	etcdDefragTimeout                                     = 5 * time.Minute
 	periodicallyMakeEtcdLeanDeltaSnapshotInterval         = 10
 	thresholdPercentageForDBSizeAlarm             float64 = 80.0 / 100.0
+
+	// revisionPaddingKey is the key written to for revisions whose events were filtered out of the restoration.
+	revisionPaddingKey = "/etcd-backup-restore/revision-padding"
 )
 
+// restoreTarget is the point in time up to which the delta snapshots are applied during restoration.
+// A zero revision and a zero time mean no bound on revision and time respectively.
+type restoreTarget struct {
+	revision int64
+	time     time.Time
+}
+
+// newRestoreTarget returns the restoreTarget as per the given restoration config.
+func newRestoreTarget(config *brtypes.RestorationConfig) (restoreTarget, error) {
+	targetTime, err := config.GetTargetTime()
+	if err != nil {
+		return restoreTarget{}, err
+	}
+	return restoreTarget{
+		revision: config.TargetRevision,
+		time:     targetTime,
+	}, nil
+}
+
+// isBeyond returns whether the given event is beyond the target.
+func (t restoreTarget) isBeyond(event *brtypes.Event) bool {
+	if t.revision > 0 && event.EtcdEvent.Kv.ModRevision > t.revision {
+		return true
+	}
+	return !t.time.IsZero() && event.Time.After(t.time)
+}
+
+// checkBaseSnapshot checks that the base snapshot doesn't go beyond the target.
+func (t restoreTarget) checkBaseSnapshot(snap *brtypes.Snapshot) error {
+	if t.revision > 0 && snap.LastRevision > t.revision {
+		return fmt.Errorf("base snapshot %s with revision %d is beyond the target revision %d", snap.SnapName, snap.LastRevision, t.revision)
+	}
+	if !t.time.IsZero() && snap.CreatedOn.After(t.time) {
+		return fmt.Errorf("base snapshot %s created on %v is beyond the target time %v", snap.SnapName, snap.CreatedOn, t.time)
+	}
+	return nil
+}
+
+// checkSnapshots checks that the given base and delta snapshots reach the target revision, so that a restoration never
+// silently ends before it. A target time reached by the snapshots ends the restoration before the target revision.
+func (t restoreTarget) checkSnapshots(baseSnap *brtypes.Snapshot, deltaSnapList brtypes.SnapList) error {
+	if t.revision == 0 {
+		return nil
+	}
+	lastSnap := baseSnap
+	if len(deltaSnapList) > 0 {
+		lastSnap = deltaSnapList[len(deltaSnapList)-1]
+	}
+	if lastSnap.LastRevision >= t.revision || (!t.time.IsZero() && lastSnap.CreatedOn.After(t.time)) {
+		return nil
+	}
+	return fmt.Errorf("target revision %d is beyond the last backed up revision %d of snapshot %s", t.revision, lastSnap.LastRevision, lastSnap.SnapName)
+}
+
 // Restorer is a struct for etcd data directory restorer
 type Restorer struct {
 	logger    *logrus.Entry
@@ -49,6 +107,59 @@ type Restorer struct {
 	store     brtypes.SnapStore
 }
 
+// progressTracker tracks the progress of a restoration and reports it to the progress callback of the restore options.
+type progressTracker struct {
+	mutex      sync.Mutex
+	progress   brtypes.RestoreProgress
+	onProgress brtypes.RestoreProgressFunc
+}
+
+// newProgressTracker returns a tracker of the progress of the restoration with the given options.
+func newProgressTracker(ro brtypes.RestoreOptions) *progressTracker {
+	return &progressTracker{
+		progress:   brtypes.RestoreProgress{SnapshotsTotal: 1 + len(ro.DeltaSnapList)},
+		onProgress: ro.OnProgress,
+	}
+}
+
+// snapshotFetched records that a snapshot has been fetched from the store.
+func (t *progressTracker) snapshotFetched() {
+	t.update(func(p *brtypes.RestoreProgress) {
+		p.SnapshotsFetched++
+	})
+}
+
+// snapshotApplied records that a snapshot has been applied, after which the restored data has the given revision.
+func (t *progressTracker) snapshotApplied(revision int64) {
+	t.update(func(p *brtypes.RestoreProgress) {
+		p.SnapshotsApplied++
+		p.Revision = max(p.Revision, revision)
+	})
+}
+
+func (t *progressTracker) update(fn func(*brtypes.RestoreProgress)) {
+	if t == nil || t.onProgress == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	fn(&t.progress)
+	t.onProgress(t.progress)
+}
+
+// contextReader is a reader which stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
 // NewRestorer returns the restorer object.
 func NewRestorer(store brtypes.SnapStore, logger *logrus.Entry) (*Restorer, error) {
 	zapLogger, err := zap.NewProduction()
@@ -63,8 +174,9 @@ func NewRestorer(store brtypes.SnapStore, logger *logrus.Entry) (*Restorer, erro
 }
 
 // RestoreAndStopEtcd restore the etcd data directory as per specified restore options but doesn't return the ETCD server that it statrted.
-func (r *Restorer) RestoreAndStopEtcd(ro brtypes.RestoreOptions, m member.Control) error {
-	embeddedEtcd, err := r.Restore(ro, m)
+// The restoration is aborted once the given context is done.
+func (r *Restorer) RestoreAndStopEtcd(ctx context.Context, ro brtypes.RestoreOptions, m member.Control) error {
+	embeddedEtcd, err := r.Restore(ctx, ro, m)
 	defer func() {
 		if embeddedEtcd != nil {
 			embeddedEtcd.Close()
@@ -73,8 +185,84 @@ func (r *Restorer) RestoreAndStopEtcd(ro brtypes.RestoreOptions, m member.Contro
 	return err
 }
 
+// removeFilteredKeys removes the keys filtered out by the key filter of the restoration from the restored data.
+func (r *Restorer) removeFilteredKeys(clientFactory client.Factory, ro brtypes.RestoreOptions) error {
+	clientKV, err := clientFactory.NewKV()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := clientKV.Close(); err != nil {
+			r.logger.Errorf("failed to close etcd KV client: %v", err)
+		}
+	}()
+
+	keyFilter := ro.Config.KeyFilter()
+	var ops []clientv3.Op
+	for _, prefix := range keyFilter.ExcludePrefixes {
+		ops = append(ops, clientv3.OpDelete(prefix, clientv3.WithPrefix()))
+	}
+	if len(keyFilter.IncludePrefixes) != 0 {
+		// delete the key ranges in between the sorted include prefixes
+		includePrefixes := slices.Clone(keyFilter.IncludePrefixes)
+		slices.Sort(includePrefixes)
+		rangeStart := "\x00"
+		for _, prefix := range includePrefixes {
+			if prefix > rangeStart {
+				ops = append(ops, clientv3.OpDelete(rangeStart, clientv3.WithRange(prefix)))
+			}
+			if rangeEnd := clientv3.GetPrefixRangeEnd(prefix); rangeEnd == "\x00" {
+				// all keys after the prefix have the prefix
+				rangeStart = ""
+				break
+			} else if rangeEnd > rangeStart {
+				rangeStart = rangeEnd
+			}
+		}
+		if len(rangeStart) != 0 {
+			ops = append(ops, clientv3.OpDelete(rangeStart, clientv3.WithFromKey()))
+		}
+	}
+
+	var deleted int64
+	for _, op := range ops {
+		ctx, cancel := context.WithTimeout(context.TODO(), etcdConnectionTimeout)
+		resp, err := clientKV.Do(ctx, op)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to remove filtered out keys: %v", err)
+		}
+		deleted += resp.Del().Deleted
+	}
+	r.logger.Infof("Removed %d filtered out keys from the restored data.", deleted)
+	return nil
+}
+
+// removeRevisionPadding removes the revision padding key, which is written while applying the delta snapshots for
+// revisions whose events were filtered out, from the restored data. Removing it takes one more revision if it exists.
+func (r *Restorer) removeRevisionPadding(clientFactory client.Factory) error {
+	clientKV, err := clientFactory.NewKV()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := clientKV.Close(); err != nil {
+			r.logger.Errorf("failed to close etcd KV client: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), etcdConnectionTimeout)
+	defer cancel()
+	if _, err := clientKV.Delete(ctx, revisionPaddingKey); err != nil {
+		return fmt.Errorf("failed to remove the revision padding key: %v", err)
+	}
+	return nil
+}
+
 // Restore restores the etcd data directory as per specified restore options but returns the ETCD server that it statrted.
-func (r *Restorer) Restore(ro brtypes.RestoreOptions, m member.Control) (*miscellaneous.EmbeddedEtcd, error) {
+// The restoration is aborted once the given context is done.
+func (r *Restorer) Restore(ctx context.Context, ro brtypes.RestoreOptions, m member.Control) (*miscellaneous.EmbeddedEtcd, error) {
+	progress := newProgressTracker(ro)
 	r.logger.Infof("Creating temporary directory %s for persisting full and delta snapshots locally.", ro.Config.TempSnapshotsDir)
 	err := os.MkdirAll(ro.Config.TempSnapshotsDir, 0700)
 	if err != nil {
@@ -86,16 +274,37 @@ func (r *Restorer) Restore(ro brtypes.RestoreOptions, m member.Control) (*miscel
 		}
 	}()
 
-	if err := r.restoreFromBaseSnapshot(ro); err != nil {
+	target, err := newRestoreTarget(ro.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := target.checkBaseSnapshot(ro.BaseSnapshot); err != nil {
+		return nil, err
+	}
+	if err := target.checkSnapshots(ro.BaseSnapshot, ro.DeltaSnapList); err != nil {
+		return nil, err
+	}
+	if ro.Config.IsPointInTimeRestore() {
+		r.logger.Infof("Restoring up to target revision %d, target time %q and target snapshot labels %q.", ro.Config.TargetRevision, ro.Config.TargetTime, ro.Config.TargetSnapshotLabels)
+	}
+
+	if err := r.restoreFromBaseSnapshot(ctx, ro, progress); err != nil {
 		return nil, fmt.Errorf("failed to restore from the base snapshot: %v", err)
 	}
+	progress.snapshotApplied(ro.BaseSnapshot.LastRevision)
 
 	if len(ro.DeltaSnapList) == 0 {
 		r.logger.Infof("No delta snapshots present over base snapshot.")
-		return nil, nil
+		if ro.Config.KeyFilter().IsEmpty() {
+			if ro.Config.RevisionBump > 0 {
+				return nil, r.bumpRevision(ro.Config.DataDir, ro.Config.RevisionBump, ro.Config.MarkCompacted)
+			}
+			return nil, nil
+		}
+	} else {
+		r.logger.Infof("Attempting to apply %d delta snapshots for restoration.", len(ro.DeltaSnapList))
 	}
 
-	r.logger.Infof("Attempting to apply %d delta snapshots for restoration.", len(ro.DeltaSnapList))
 	r.logger.Infof("Starting an embedded etcd server...")
 	e, err := miscellaneous.StartEmbeddedEtcd(r.logger, &ro)
 	if err != nil {
@@ -110,9 +319,38 @@ func (r *Restorer) Restore(ro brtypes.RestoreOptions, m member.Control) (*miscel
 		InsecureTransport:  true,
 	})
 
-	r.logger.Infof("Applying delta snapshots...")
-	if err := r.applyDeltaSnapshots(clientFactory, embeddedEtcdEndpoints, ro); err != nil {
-		return e, err
+	if len(ro.DeltaSnapList) != 0 {
+		r.logger.Infof("Applying delta snapshots...")
+		if err := r.applyDeltaSnapshots(ctx, clientFactory, embeddedEtcdEndpoints, ro, target, progress); err != nil {
+			return e, err
+		}
+		if err := r.removeRevisionPadding(clientFactory); err != nil {
+			return e, err
+		}
+	}
+	if !ro.Config.KeyFilter().IsEmpty() {
+		// the delta snapshots are applied filtered, but the base snapshot is restored with all of its keys.
+		if err := r.removeFilteredKeys(clientFactory, ro); err != nil {
+			return e, err
+		}
+	}
+
+	if ro.Config.RevisionBump > 0 {
+		// the revision is bumped in the backend, which requires the embedded etcd to be stopped.
+		r.logger.Infof("Stopping the embedded etcd server to bump the revision...")
+		e.Close()
+		if err := r.bumpRevision(ro.Config.DataDir, ro.Config.RevisionBump, ro.Config.MarkCompacted); err != nil {
+			return nil, err
+		}
+		r.logger.Infof("Restarting the embedded etcd server...")
+		if e, err = miscellaneous.StartEmbeddedEtcd(r.logger, &ro); err != nil {
+			return e, err
+		}
+		clientFactory = etcdutil.NewClientFactory(ro.NewClientFactory, brtypes.EtcdConnectionConfig{
+			MaxCallSendMsgSize: ro.Config.MaxCallSendMsgSize,
+			Endpoints:          []string{e.Clients[0].Addr().String()},
+			InsecureTransport:  true,
+		})
 	}
 
 	if m != nil {
@@ -126,7 +364,7 @@ func (r *Restorer) Restore(ro brtypes.RestoreOptions, m member.Control) (*miscel
 			}
 		}()
 
-		if err := m.UpdateMemberPeerURL(context.TODO(), clientCluster); err != nil {
+		if err := m.UpdateMemberPeerURL(ctx, clientCluster); err != nil {
 			return e, err
 		}
 	}
@@ -134,7 +372,7 @@ func (r *Restorer) Restore(ro brtypes.RestoreOptions, m member.Control) (*miscel
 }
 
 // restoreFromBaseSnapshot restores the etcd data directory from the base snapshot.
-func (r *Restorer) restoreFromBaseSnapshot(ro brtypes.RestoreOptions) error {
+func (r *Restorer) restoreFromBaseSnapshot(ctx context.Context, ro brtypes.RestoreOptions, progress *progressTracker) error {
 	baseSnapshotPath := path.Join(ro.BaseSnapshot.SnapDir, ro.BaseSnapshot.SnapName)
 	if baseSnapshotPath == "" {
 		r.logger.Warnf("Base snapshot path not provided. Will do nothing.")
@@ -178,9 +416,10 @@ func (r *Restorer) restoreFromBaseSnapshot(ro brtypes.RestoreOptions) error {
 		}
 	}()
 
-	if _, err := io.Copy(db, rc); err != nil {
+	if _, err := io.Copy(db, &contextReader{ctx: ctx, r: rc}); err != nil {
 		return fmt.Errorf("failed to copy snapshot data into the temporary file on disk needed for restoration with error: %w", err)
 	}
+	progress.snapshotFetched()
 
 	elapsedTime := time.Since(startTime).Seconds()
 	r.logger.Infof("Fetched the snapshot from the object store in %v seconds", elapsedTime)
@@ -210,7 +449,10 @@ func (r *Restorer) restoreFromBaseSnapshot(ro brtypes.RestoreOptions) error {
 }
 
 // applyDeltaSnapshots fetches the events from delta snapshots in parallel and applies them to the embedded etcd sequentially.
-func (r *Restorer) applyDeltaSnapshots(clientFactory client.Factory, endPoints []string, ro brtypes.RestoreOptions) error {
+func (r *Restorer) applyDeltaSnapshots(ctx context.Context, clientFactory client.Factory, endPoints []string, ro brtypes.RestoreOptions, target restoreTarget, progress *progressTracker) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("restoration aborted: %w", err)
+	}
 
 	clientKV, err := clientFactory.NewKV()
 	if err != nil {
@@ -232,14 +474,33 @@ func (r *Restorer) applyDeltaSnapshots(clientFactory client.Factory, endPoints [
 		}
 	}()
 
+	clientLease, err := clientFactory.NewLease()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := clientLease.Close(); err != nil {
+			r.logger.Errorf("failed to close etcd lease client: %v", err)
+		}
+	}()
+	leases := newLeaseApplier(r.logger, clientLease)
+	defer leases.close()
+
 	snapList := ro.DeltaSnapList
 	numMaxFetchers := ro.Config.MaxFetchers
 
 	firstDeltaSnap := snapList[0]
 
-	if err := r.applyFirstDeltaSnapshot(clientKV, firstDeltaSnap); err != nil {
+	keyFilter := ro.Config.KeyFilter()
+
+	targetReached, err := r.applyFirstDeltaSnapshot(clientKV, firstDeltaSnap, target, keyFilter, leases, progress)
+	if err != nil {
 		return err
 	}
+	if targetReached {
+		r.logger.Infof("Restoration target reached in delta snapshot %s.", firstDeltaSnap.SnapName)
+		return nil
+	}
 
 	embeddedEtcdQuotaBytes := float64(ro.Config.EmbeddedEtcdQuotaBytes)
 
@@ -267,10 +528,10 @@ func (r *Restorer) applyDeltaSnapshots(clientFactory client.Factory, endPoints [
 		dbSizeAlarmDisarmCh = make(chan bool)
 	)
 
-	go r.applySnaps(clientKV, clientMaintenance, remainingSnaps, dbSizeAlarmCh, dbSizeAlarmDisarmCh, applierInfoCh, errCh, stopCh, &wg, endPoints, embeddedEtcdQuotaBytes)
+	go r.applySnaps(ctx, clientKV, clientMaintenance, remainingSnaps, target, keyFilter, leases, progress, dbSizeAlarmCh, dbSizeAlarmDisarmCh, applierInfoCh, errCh, stopCh, &wg, endPoints, embeddedEtcdQuotaBytes)
 
 	for f := 0; f < numFetchers; f++ {
-		go r.fetchSnaps(f, fetcherInfoCh, applierInfoCh, snapLocationsCh, errCh, stopCh, &wg, ro.Config.TempSnapshotsDir)
+		go r.fetchSnaps(f, fetcherInfoCh, applierInfoCh, snapLocationsCh, errCh, stopCh, &wg, ro.Config.TempSnapshotsDir, progress)
 	}
 
 	go r.HandleAlarm(stopHandleAlarmCh, dbSizeAlarmCh, dbSizeAlarmDisarmCh, clientMaintenance)
@@ -332,7 +593,7 @@ func (r *Restorer) cleanup(snapLocationsCh chan string, stopCh chan bool, wg *sy
 }
 
 // fetchSnaps fetches delta snapshots as events and persists them onto disk.
-func (r *Restorer) fetchSnaps(fetcherIndex int, fetcherInfoCh <-chan brtypes.FetcherInfo, applierInfoCh chan<- brtypes.ApplierInfo, snapLocationsCh chan<- string, errCh chan<- error, stopCh chan bool, wg *sync.WaitGroup, tempDir string) {
+func (r *Restorer) fetchSnaps(fetcherIndex int, fetcherInfoCh <-chan brtypes.FetcherInfo, applierInfoCh chan<- brtypes.ApplierInfo, snapLocationsCh chan<- string, errCh chan<- error, stopCh chan bool, wg *sync.WaitGroup, tempDir string, progress *progressTracker) {
 	defer wg.Done()
 	wg.Add(1)
 
@@ -358,6 +619,7 @@ func (r *Restorer) fetchSnaps(fetcherIndex int, fetcherInfoCh <-chan brtypes.Fet
 			}
 
 			snapLocationsCh <- snapTempFilePath // used for cleanup later
+			progress.snapshotFetched()
 
 			applierInfo := brtypes.ApplierInfo{
 				SnapFilePath: snapTempFilePath,
@@ -369,7 +631,7 @@ func (r *Restorer) fetchSnaps(fetcherIndex int, fetcherInfoCh <-chan brtypes.Fet
 }
 
 // applySnaps applies delta snapshot events to the embedded etcd sequentially, in the right order of snapshots, regardless of the order in which they were fetched.
-func (r *Restorer) applySnaps(clientKV client.KVCloser, clientMaintenance client.MaintenanceCloser, remainingSnaps brtypes.SnapList, dbSizeAlarmCh chan string, dbSizeAlarmDisarmCh <-chan bool, applierInfoCh <-chan brtypes.ApplierInfo, errCh chan<- error, stopCh <-chan bool, wg *sync.WaitGroup, endPoints []string, embeddedEtcdQuotaBytes float64) {
+func (r *Restorer) applySnaps(ctx context.Context, clientKV client.KVCloser, clientMaintenance client.MaintenanceCloser, remainingSnaps brtypes.SnapList, target restoreTarget, keyFilter brtypes.KeyPrefixFilter, leases *leaseApplier, progress *progressTracker, dbSizeAlarmCh chan string, dbSizeAlarmDisarmCh <-chan bool, applierInfoCh <-chan brtypes.ApplierInfo, errCh chan<- error, stopCh <-chan bool, wg *sync.WaitGroup, endPoints []string, embeddedEtcdQuotaBytes float64) {
 	defer wg.Done()
 	wg.Add(1)
 
@@ -391,6 +653,9 @@ func (r *Restorer) applySnaps(clientKV client.KVCloser, clientMaintenance client
 			if !more {
 				return
 			}
+		case <-ctx.Done():
+			errCh <- fmt.Errorf("restoration aborted: %w", ctx.Err())
+			return
 		case applierInfo := <-applierInfoCh:
 			if applierInfo.SnapIndex == -1 {
 				return
@@ -408,34 +673,33 @@ func (r *Restorer) applySnaps(clientKV client.KVCloser, clientMaintenance client
 					if pathList[currSnapIndex] == "" {
 						break
 					}
+					if err := ctx.Err(); err != nil {
+						errCh <- fmt.Errorf("restoration aborted: %w", err)
+						return
+					}
 
 					filePath := pathList[currSnapIndex]
 					snapName := remainingSnaps[currSnapIndex].SnapName
 
-					r.logger.Infof("Reading snapshot contents %s from raw snapshot file %s", snapName, filePath)
-					eventsData, err := r.readSnapshotContentsFromFile(filePath, remainingSnaps[currSnapIndex])
+					r.logger.Infof("Applying delta snapshot %s [%d/%d] from raw snapshot file %s", path.Join(remainingSnaps[currSnapIndex].SnapDir, remainingSnaps[currSnapIndex].SnapName), currSnapIndex+2, len(remainingSnaps)+1, filePath)
+					appliedRevision, targetReached, err := r.applyDeltaSnapshotFromFile(clientKV, filePath, remainingSnaps[currSnapIndex], target, keyFilter, leases)
 					if err != nil {
-						errCh <- fmt.Errorf("failed to read events data from delta snapshot file %s : %v", filePath, err)
-						return
-					}
-
-					var events []brtypes.Event
-					if err = json.Unmarshal(eventsData, &events); err != nil {
-						errCh <- fmt.Errorf("failed to unmarshal events from events data for delta snapshot %s : %v", snapName, err)
-						return
-					}
-
-					r.logger.Infof("Applying delta snapshot %s [%d/%d]", path.Join(remainingSnaps[currSnapIndex].SnapDir, remainingSnaps[currSnapIndex].SnapName), currSnapIndex+2, len(remainingSnaps)+1)
-					if err := applyEventsAndVerify(clientKV, events, remainingSnaps[currSnapIndex]); err != nil {
 						errCh <- err
 						return
 					}
+					progress.snapshotApplied(appliedRevision)
 
 					r.logger.Infof("Removing temporary delta snapshot events file %s for snapshot %s", filePath, snapName)
 					if err = os.Remove(filePath); err != nil {
 						r.logger.Warnf("Unable to remove file: %s; err: %v", filePath, err)
 					}
 
+					if targetReached {
+						r.logger.Infof("Restoration target reached in delta snapshot %s.", snapName)
+						errCh <- nil // restore finished
+						return
+					}
+
 					nextSnapIndexToApply++
 					if nextSnapIndexToApply == len(remainingSnaps) {
 						errCh <- nil // restore finished
@@ -464,37 +728,37 @@ func (r *Restorer) applySnaps(clientKV client.KVCloser, clientMaintenance client
 	}
 }
 
-// applyEventsAndVerify applies events from one snapshot to the embedded etcd and verifies the correctness of the sequence of snapshot applied.
-func applyEventsAndVerify(clientKV client.KVCloser, events []brtypes.Event, snap *brtypes.Snapshot) error {
-	if err := applyEventsToEtcd(clientKV, events); err != nil {
-		return fmt.Errorf("failed to apply events to etcd for delta snapshot %s : %v", snap.SnapName, err)
+// applyDeltaSnapshotFromFile applies the events of the delta snapshot persisted to the given file to the embedded etcd,
+// and verifies that the embedded etcd reached the revision of the delta snapshot, or of the last applied event if the
+// restoration target was reached within the delta snapshot.
+// It returns the revision of the restored data and whether the restoration target was reached.
+func (r *Restorer) applyDeltaSnapshotFromFile(clientKV client.KVCloser, filePath string, snap *brtypes.Snapshot, target restoreTarget, keyFilter brtypes.KeyPrefixFilter, leases *leaseApplier) (int64, bool, error) {
+	file, err := os.Open(filePath) // #nosec G304 -- this is a trusted snapshot file.
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open file %s for delta snapshot %s : %v", filePath, snap.SnapName, err)
 	}
 
-	if err := verifySnapshotRevision(clientKV, snap); err != nil {
-		return fmt.Errorf("snapshot revision verification failed for delta snapshot %s : %v", snap.SnapName, err)
+	appliedRevision, targetReached, err := r.applyDeltaSnapshotEvents(clientKV, file, snap, target, keyFilter, leases, 0)
+	if err != nil {
+		return 0, false, err
 	}
-	return nil
+	if !targetReached {
+		appliedRevision = snap.LastRevision
+	} else if appliedRevision == 0 {
+		// no event of the delta snapshot is within the target
+		return 0, true, nil
+	}
+	if err := verifyRevision(clientKV, appliedRevision); err != nil {
+		return 0, false, fmt.Errorf("snapshot revision verification failed for delta snapshot %s : %v", snap.SnapName, err)
+	}
+	return appliedRevision, targetReached, nil
 }
 
 // applyFirstDeltaSnapshot applies the events from first delta snapshot to etcd.
-func (r *Restorer) applyFirstDeltaSnapshot(clientKV client.KVCloser, snap *brtypes.Snapshot) error {
+// It returns true if the restoration target was reached within the first delta snapshot.
+func (r *Restorer) applyFirstDeltaSnapshot(clientKV client.KVCloser, snap *brtypes.Snapshot, target restoreTarget, keyFilter brtypes.KeyPrefixFilter, leases *leaseApplier, progress *progressTracker) (bool, error) {
 	r.logger.Infof("Applying first delta snapshot %s", path.Join(snap.SnapDir, snap.SnapName))
 
-	rc, err := r.store.Fetch(*snap)
-	if err != nil {
-		return fmt.Errorf("failed to fetch delta snapshot %s from store : %v", snap.SnapName, err)
-	}
-
-	eventsData, err := r.readSnapshotContentsFromReadCloser(rc, snap)
-	if err != nil {
-		return fmt.Errorf("failed to read events data from delta snapshot %s : %v", snap.SnapName, err)
-	}
-
-	var events []brtypes.Event
-	if err = json.Unmarshal(eventsData, &events); err != nil {
-		return fmt.Errorf("failed to unmarshal events data from delta snapshot %s : %v", snap.SnapName, err)
-	}
-
 	// Note: Since revision in full snapshot file name might be lower than actual revision stored in snapshot.
 	// This is because of issue referred below. So, as per workaround used in our logic of taking delta snapshot,
 	// the latest revision from full snapshot may overlap with first few revision on first delta snapshot
@@ -504,30 +768,91 @@ func (r *Restorer) applyFirstDeltaSnapshot(clientKV client.KVCloser, snap *brtyp
 	defer cancel()
 	resp, err := clientKV.Get(ctx, "", clientv3.WithLastRev()...)
 	if err != nil {
-		return fmt.Errorf("failed to get etcd latest revision: %v", err)
+		return false, fmt.Errorf("failed to get etcd latest revision: %v", err)
 	}
 	lastRevision := resp.Header.Revision
 
-	if lastRevision == snap.LastRevision {
-		// there is no need to apply this fist delta snapshot
-		// as it's completely overlaps with full snapshot data.
+	rc, err := r.store.Fetch(*snap)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch delta snapshot %s from store : %v", snap.SnapName, err)
+	}
+	progress.snapshotFetched()
+
+	appliedRevision, targetReached, err := r.applyDeltaSnapshotEvents(clientKV, rc, snap, target, keyFilter, leases, lastRevision)
+	if err != nil {
+		return false, err
+	}
+	if appliedRevision == lastRevision {
 		// please refer: https://github.com/gardener/etcd-backup-restore/issues/844
 		r.logger.Infof("First delta snapshot %s found to be completely overlap with full snapshot with db revisions: %d", path.Join(snap.SnapDir, snap.SnapName), lastRevision)
-		r.logger.Info("Skipping this delta snapshot...")
-		return nil
 	}
+	if !targetReached {
+		progress.snapshotApplied(snap.LastRevision)
+		return false, nil
+	}
+	if appliedRevision != lastRevision {
+		if err := verifyRevision(clientKV, appliedRevision); err != nil {
+			return true, fmt.Errorf("snapshot revision verification failed for delta snapshot %s : %v", snap.SnapName, err)
+		}
+	}
+	progress.snapshotApplied(appliedRevision)
+	return true, nil
+}
 
-	var newRevisionIndex int
-	for index, event := range events {
-		if event.EtcdEvent.Kv.ModRevision > lastRevision {
-			newRevisionIndex = index
+// applyDeltaSnapshotEvents decodes the events of the delta snapshot read from the given ReadCloser, and applies them to
+// the embedded etcd as they are decoded, up to the restoration target. Events up to the given revision, which are part
+// of the restored data already, are skipped.
+// It returns the revision of the restored data and whether the restoration target was reached within the snapshot.
+func (r *Restorer) applyDeltaSnapshotEvents(clientKV client.KVCloser, rc io.ReadCloser, snap *brtypes.Snapshot, target restoreTarget, keyFilter brtypes.KeyPrefixFilter, leases *leaseApplier, skipRevision int64) (int64, bool, error) {
+	startTime := time.Now()
+	defer rc.Close()
+	decompressed, wasCompressed, compressionPolicy, err := getNormalizedSnapshotReadCloser(rc, snap)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to decompress delta snapshot %s : %v", snap.SnapName, err)
+	}
+	defer decompressed.Close()
+
+	decoder, err := delta.NewDecoder(decompressed)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read events data from delta snapshot %s : %v", snap.SnapName, err)
+	}
+
+	applier := &eventApplier{clientKV: clientKV, keyFilter: keyFilter, leases: leases, appliedRevision: skipRevision}
+	targetReached := false
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
 			break
 		}
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to decode events from delta snapshot %s : %v", snap.SnapName, err)
+		}
+		if target.isBeyond(event) {
+			// the events of a revision are part of one transaction, they are either applied or dropped together
+			if event.EtcdEvent.Kv.ModRevision == applier.revision {
+				applier.discard()
+			}
+			targetReached = true
+			break
+		}
+		if event.EtcdEvent.Kv.ModRevision <= skipRevision {
+			continue
+		}
+		if err := applier.apply(event); err != nil {
+			return 0, false, fmt.Errorf("failed to apply events to etcd for delta snapshot %s : %v", snap.SnapName, err)
+		}
+	}
+	if err := applier.commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to apply events to etcd for delta snapshot %s : %v", snap.SnapName, err)
 	}
 
-	r.logger.Infof("Applying first delta snapshot %s", path.Join(snap.SnapDir, snap.SnapName))
-
-	return applyEventsToEtcd(clientKV, events[newRevisionIndex:])
+	totalTime := time.Since(startTime).Seconds()
+	if wasCompressed {
+		r.logger.Infof("successfully decompressed and applied %s delta snapshot in %v seconds [CompressionPolicy:%v]", decoder.Format(), totalTime, compressionPolicy)
+	} else {
+		r.logger.Infof("successfully applied %s delta snapshot in %v seconds", decoder.Format(), totalTime)
+	}
+	return applier.appliedRevision, targetReached, nil
 }
 
 func persistRawDeltaSnapshot(rc io.ReadCloser, tempFilePath string) error {
@@ -548,47 +873,89 @@ func persistRawDeltaSnapshot(rc io.ReadCloser, tempFilePath string) error {
 	return rc.Close()
 }
 
-// applyEventsToEtcd performs operations in events sequentially.
-func applyEventsToEtcd(clientKV client.KVCloser, events []brtypes.Event) error {
-	var (
-		lastRev int64
-		ops     = []clientv3.Op{}
-		ctx     = context.TODO()
-	)
+// eventApplier applies events to the embedded etcd, with the events of each revision in one transaction.
+// Events of keys filtered out by the key filter are dropped. Revisions which only consist of dropped events or
+// revision markers are applied as a write to the revision padding key, so that the revisions of the restored data
+// stay identical to the revisions of the original data. Keys are attached to their leases, which are recreated by the
+// lease applier.
+type eventApplier struct {
+	clientKV  client.KVCloser
+	keyFilter brtypes.KeyPrefixFilter
+	leases    *leaseApplier
+	// ops and padRev make up the transaction of the revision which is not committed yet, zero if there is none.
+	ops      []clientv3.Op
+	padRev   bool
+	revision int64
+	// appliedRevision is the revision of the last committed transaction.
+	appliedRevision int64
+}
 
-	for _, e := range events {
-		ev := e.EtcdEvent
-		nextRev := ev.Kv.ModRevision
-		if lastRev != 0 && nextRev > lastRev {
-			if _, err := clientKV.Txn(ctx).Then(ops...).Commit(); err != nil {
-				return err
-			}
-			ops = []clientv3.Op{}
+// apply adds the given event to the transaction of its revision, and commits the transaction of the previous
+// revision.
+func (a *eventApplier) apply(event *brtypes.Event) error {
+	ev := event.EtcdEvent
+	if a.revision != 0 && ev.Kv.ModRevision > a.revision {
+		if err := a.commit(); err != nil {
+			return err
 		}
-		lastRev = nextRev
-		switch ev.Type {
-		case mvccpb.PUT:
-			ops = append(ops, clientv3.OpPut(string(ev.Kv.Key), string(ev.Kv.Value))) //, clientv3.WithLease(clientv3.LeaseID(ev.Kv.Lease))))
-
-		case mvccpb.DELETE:
-			ops = append(ops, clientv3.OpDelete(string(ev.Kv.Key)))
-		default:
-			return fmt.Errorf("unexpected event type")
+	}
+	a.revision = ev.Kv.ModRevision
+	if len(ev.Kv.Key) == 0 || !a.keyFilter.Includes(ev.Kv.Key) {
+		a.padRev = true
+		return nil
+	}
+	switch ev.Type {
+	case mvccpb.PUT:
+		opts, err := a.leases.putOptions(event)
+		if err != nil {
+			return err
 		}
+		a.ops = append(a.ops, clientv3.OpPut(string(ev.Kv.Key), string(ev.Kv.Value), opts...))
+	case mvccpb.DELETE:
+		a.ops = append(a.ops, clientv3.OpDelete(string(ev.Kv.Key)))
+	default:
+		return fmt.Errorf("unexpected event type")
 	}
-	_, err := clientKV.Txn(ctx).Then(ops...).Commit()
-	return err
+	return nil
+}
+
+// commit commits the transaction of the pending revision, if any.
+func (a *eventApplier) commit() error {
+	if a.revision == 0 {
+		return nil
+	}
+	if len(a.ops) == 0 && a.padRev {
+		a.ops = append(a.ops, clientv3.OpPut(revisionPaddingKey, ""))
+	}
+	if _, err := a.clientKV.Txn(context.TODO()).Then(a.ops...).Commit(); err != nil {
+		return err
+	}
+	a.appliedRevision = a.revision
+	a.discard()
+	return nil
+}
+
+// discard drops the transaction of the pending revision.
+func (a *eventApplier) discard() {
+	a.ops = []clientv3.Op{}
+	a.padRev = false
+	a.revision = 0
 }
 
 func verifySnapshotRevision(clientKV client.KVCloser, snap *brtypes.Snapshot) error {
+	return verifyRevision(clientKV, snap.LastRevision)
+}
+
+// verifyRevision verifies that the embedded etcd is at the given revision.
+func verifyRevision(clientKV client.KVCloser, revision int64) error {
 	ctx := context.TODO()
 	getResponse, err := clientKV.Get(ctx, "foo")
 	if err != nil {
 		return fmt.Errorf("failed to connect to etcd KV client: %v", err)
 	}
 	etcdRevision := getResponse.Header.GetRevision()
-	if snap.LastRevision != etcdRevision {
-		return fmt.Errorf("mismatched event revision while applying delta snapshot, expected %d but applied %d ", snap.LastRevision, etcdRevision)
+	if revision != etcdRevision {
+		return fmt.Errorf("mismatched event revision while applying delta snapshot, expected %d but applied %d ", revision, etcdRevision)
 	}
 	return nil
 }
@@ -615,7 +982,9 @@ func getNormalizedSnapshotReadCloser(rc io.ReadCloser, snap *brtypes.Snapshot) (
 	return rc, isCompressed, compressionPolicy, nil
 }
 
-func (r *Restorer) readSnapshotContentsFromReadCloser(rc io.ReadCloser, snap *brtypes.Snapshot) ([]byte, error) {
+// ReadSnapshotContentsFromReadCloser reads the contents of the delta snapshot from the given ReadCloser,
+// decompressing them if required, and verifies them against the SHA256 hash appended to the snapshot.
+func (r *Restorer) ReadSnapshotContentsFromReadCloser(rc io.ReadCloser, snap *brtypes.Snapshot) ([]byte, error) {
 	startTime := time.Now()
 
 	rc, wasCompressed, compressionPolicy, err := getNormalizedSnapshotReadCloser(rc, snap)
@@ -658,15 +1027,6 @@ func (r *Restorer) readSnapshotContentsFromReadCloser(rc io.ReadCloser, snap *br
 	return data, nil
 }
 
-func (r *Restorer) readSnapshotContentsFromFile(filePath string, snap *brtypes.Snapshot) ([]byte, error) {
-	file, err := os.Open(filePath) // #nosec G304 -- this is a trusted snapshot file.
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s for delta snapshot %s : %v", filePath, snap.SnapName, err)
-	}
-
-	return r.readSnapshotContentsFromReadCloser(file, snap)
-}
-
 // ErrorArrayToError takes an array of errors and returns a single concatenated error
 func ErrorArrayToError(errs []error) error {
 	if len(errs) == 0 {
@@ -18,11 +18,13 @@ import (
 	"github.com/gardener/etcd-backup-restore/pkg/compressor"
 	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
 	mockfactory "github.com/gardener/etcd-backup-restore/pkg/mock/etcdutil/client"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/snapshotter"
 	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
 	"github.com/gardener/etcd-backup-restore/test/utils"
 
 	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/mock/gomock"
@@ -134,7 +136,7 @@ var _ = Describe("Running Restorer", func() {
 				restoreOpts.Config.InitialAdvertisePeerURLs = []string{"http://localhost:2390"}
 				restoreOpts.ClusterURLs, err = types.NewURLsMap(restoreOpts.Config.InitialCluster)
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).Should(HaveOccurred())
 			})
 		})
@@ -143,7 +145,7 @@ var _ = Describe("Running Restorer", func() {
 			It("should fail to restore", func() {
 				restoreOpts.Config.DataDir = ""
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).Should(HaveOccurred())
 			})
 		})
@@ -153,7 +155,7 @@ var _ = Describe("Running Restorer", func() {
 				restoreOpts.BaseSnapshot.SnapDir = "test"
 				restoreOpts.BaseSnapshot.SnapName = "test"
 
-				err := restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err := restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).Should(HaveOccurred())
 			})
 		})
@@ -179,7 +181,7 @@ var _ = Describe("Running Restorer", func() {
 		Context("with maximum of one fetcher allowed", func() {
 			It("should restore etcd data directory", func() {
 				restoreOpts.Config.MaxFetchers = 1
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
@@ -191,7 +193,7 @@ var _ = Describe("Running Restorer", func() {
 			It("should restore etcd data directory", func() {
 				restoreOpts.Config.MaxFetchers = 4
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
@@ -203,13 +205,58 @@ var _ = Describe("Running Restorer", func() {
 			It("should restore etcd data directory", func() {
 				restoreOpts.Config.MaxFetchers = 100
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
 				Expect(err).ShouldNot(HaveOccurred())
 			})
 		})
+
+		Context("with a target revision within the delta snapshots", func() {
+			It("should restore etcd data directory up to the target revision", func() {
+				Expect(deltaSnapList).ShouldNot(BeEmpty())
+				targetRevision := (deltaSnapList[0].StartRevision + deltaSnapList[len(deltaSnapList)-1].LastRevision) / 2
+				restoreOpts.Config.TargetRevision = targetRevision
+
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				etcd, err := utils.StartEmbeddedEtcd(testCtx, restoreOpts.Config.DataDir, logger, utils.DefaultEtcdName, embeddedEtcdPortNo)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer etcd.Close()
+
+				cli, err := clientv3.New(clientv3.Config{
+					Endpoints:   []string{etcd.Clients[0].Addr().String()},
+					DialTimeout: 10 * time.Second,
+				})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				resp, err := cli.Get(testCtx, "", clientv3.WithLastRev()...)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(targetRevision))
+			})
+		})
+
+		Context("with a target revision beyond the delta snapshots", func() {
+			It("should fail to restore", func() {
+				Expect(deltaSnapList).ShouldNot(BeEmpty())
+				restoreOpts.Config.TargetRevision = deltaSnapList[len(deltaSnapList)-1].LastRevision + 1
+
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
+				Expect(err).Should(MatchError(ContainSubstring("is beyond the last backed up revision")))
+			})
+		})
+
+		Context("with a target time before the base snapshot", func() {
+			It("should fail to restore", func() {
+				restoreOpts.Config.TargetTime = baseSnapshot.CreatedOn.Add(-time.Hour).Format(time.RFC3339)
+
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
+				Expect(err).Should(MatchError(ContainSubstring("is beyond the target time")))
+			})
+		})
 	})
 
 	Describe("NEGATIVE: Negative Compression Scenarios", func() {
@@ -228,6 +275,24 @@ var _ = Describe("Running Restorer", func() {
 			})
 		})
 
+		Context("with compression level out of range of the compressionPolicy", func() {
+			It("should return error", func() {
+				compressionConfig.Enabled = true
+				compressionConfig.CompressionPolicy = compressor.GzipCompressionPolicy
+				compressionConfig.CompressionLevel = 12
+				err = compressionConfig.Validate()
+				Expect(err).Should(HaveOccurred())
+
+				compressionConfig.CompressionPolicy = compressor.ZstdCompressionPolicy
+				err = compressionConfig.Validate()
+				Expect(err).ShouldNot(HaveOccurred())
+
+				compressionConfig.CompressionLevel = 23
+				err = compressionConfig.Validate()
+				Expect(err).Should(HaveOccurred())
+			})
+		})
+
 		Context("with compression is not enabled and invalid compressionPolicy ", func() {
 			It("should not return error", func() {
 				compressionConfig.Enabled = false
@@ -325,7 +390,7 @@ var _ = Describe("Running Restorer", func() {
 					restoreOpts.BaseSnapshot.SnapName = ""
 				}
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
@@ -368,7 +433,7 @@ var _ = Describe("Running Restorer", func() {
 					PeerURLs:      peerUrls,
 				}
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 
 				Expect(err).ShouldNot(HaveOccurred())
 
@@ -414,7 +479,7 @@ var _ = Describe("Running Restorer", func() {
 					PeerURLs:      peerUrls,
 				}
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).Should(HaveOccurred())
 				// the below consistency fails with index out of range error hence commented,
 				// but the etcd directory is filled partially as part of the restore which should be relooked.
@@ -454,7 +519,7 @@ var _ = Describe("Running Restorer", func() {
 				}
 
 				logger.Infoln("starting restore, restore directory exists already")
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				logger.Infof("Failed to restore because :: %s", err)
 
 				Expect(err).Should(HaveOccurred())
@@ -536,13 +601,484 @@ var _ = Describe("Running Restorer", func() {
 					PeerURLs:      peerUrls,
 				}
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
 				Expect(err).ShouldNot(HaveOccurred())
 			})
 		})
 
+		Context("when full snapshot is compressed using zstd followed by delta snapshots which are compressed using zstd and gzip", func() {
+			It("Should able to restore", func() {
+				// start the Snapshotter with compressionPolicy = "zstd" to take full snapshot.
+				compressionConfig := compressor.NewCompressorConfig()
+				compressionConfig.Enabled = true
+				compressionConfig.CompressionPolicy = compressor.ZstdCompressionPolicy
+				compressionConfig.CompressionLevel = 19
+				ctx, cancel := context.WithTimeout(testCtx, time.Duration(2*time.Second))
+				snapstoreConfig := brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+				err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, ep, "", "", ctx.Done(), true, compressionConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				cancel()
+
+				// populate the etcd with some data
+				resp := &utils.EtcdDataPopulationResponse{}
+				utils.PopulateEtcd(testCtx, logger, ep, "", "", 0, keyTo, resp)
+				Expect(resp.Err).ShouldNot(HaveOccurred())
+
+				// start the Snapshotter with compressionPolicy = "zstd" to take delta snapshot.
+				ctx, cancel = context.WithTimeout(testCtx, time.Duration(2*time.Second))
+				err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, ep, "", "", ctx.Done(), false, compressionConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				cancel()
+
+				// populate the etcd with some more data
+				resp = &utils.EtcdDataPopulationResponse{}
+				utils.PopulateEtcd(testCtx, logger, ep, "", "", 0, keyTo, resp)
+				Expect(resp.Err).ShouldNot(HaveOccurred())
+
+				// start the Snapshotter with compressionPolicy = "gzip"(default) to take delta snapshot.
+				compressionConfig = compressor.NewCompressorConfig()
+				compressionConfig.Enabled = true
+				ctx, cancel = context.WithTimeout(testCtx, time.Duration(2*time.Second))
+				snapstoreConfig = brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+				err = utils.RunSnapshotter(logger, snapstoreConfig, deltaSnapshotPeriod, ep, "", "", ctx.Done(), false, compressionConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				cancel()
+
+				// remove the etcd data dir
+				err = os.RemoveAll(etcdDir)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(baseSnapshot.CompressionSuffix).Should(Equal(compressor.ZstdCompressionExtension))
+
+				restorer, err = NewRestorer(store, logger)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				restoreOpts := brtypes.RestoreOptions{
+					Config:        restorationConfig,
+					BaseSnapshot:  baseSnapshot,
+					DeltaSnapList: deltaSnapList,
+					ClusterURLs:   clusterUrlsMap,
+					PeerURLs:      peerUrls,
+				}
+
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
+				Expect(err).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with key prefix filters", func() {
+			const (
+				podsPrefix   = "/registry/pods/"
+				eventsPrefix = "/registry/events/"
+			)
+			var lastRevision int64
+
+			runSnapshotter := func(startWithFullSnapshot bool) {
+				store, err := snapstore.GetSnapstore(&brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"})
+				Expect(err).ShouldNot(HaveOccurred())
+				etcdConnectionConfig := brtypes.NewEtcdConnectionConfig()
+				etcdConnectionConfig.ConnectionTimeout.Duration = 10 * time.Second
+				etcdConnectionConfig.Endpoints = ep
+				snapshotterConfig := snapshotter.NewSnapshotterConfig()
+				snapshotterConfig.FullSnapshotSchedule = "0 0 1 1 *"
+				snapshotterConfig.DeltaSnapshotPeriod.Duration = deltaSnapshotPeriod
+				snapshotterConfig.ExcludeKeyPrefixes = []string{eventsPrefix}
+				snapstoreConfig := brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+				ssr, err := snapshotter.NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressor.NewCompressorConfig(), brtypes.NewHealthConfig(), &snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				ctx, cancel := context.WithTimeout(testCtx, 2*time.Second)
+				defer cancel()
+				Expect(ssr.Run(ctx.Done(), startWithFullSnapshot)).To(Succeed())
+			}
+
+			getRestoredData := func(prefix string) (map[string]string, int64) {
+				e, err := utils.StartEmbeddedEtcd(testCtx, restorationConfig.DataDir, logger, utils.DefaultEtcdName, embeddedEtcdPortNo)
+				Expect(err).ShouldNot(HaveOccurred())
+				defer e.Close()
+				cli, err := clientv3.New(clientv3.Config{Endpoints: []string{e.Clients[0].Addr().String()}})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+				resp, err := cli.Get(testCtx, prefix, clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				data := map[string]string{}
+				for _, kv := range resp.Kvs {
+					data[string(kv.Key)] = string(kv.Value)
+				}
+				return data, resp.Header.Revision
+			}
+
+			restore := func() {
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(deltaSnapList).ShouldNot(BeEmpty())
+				Expect(deltaSnapList[deltaSnapList.Len()-1].LastRevision).Should(Equal(lastRevision))
+
+				restorer, err = NewRestorer(store, logger)
+				Expect(err).ShouldNot(HaveOccurred())
+				restoreOpts := brtypes.RestoreOptions{
+					Config:        restorationConfig,
+					BaseSnapshot:  baseSnapshot,
+					DeltaSnapList: deltaSnapList,
+					ClusterURLs:   clusterUrlsMap,
+					PeerURLs:      peerUrls,
+				}
+				Expect(restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				_, err = cli.Put(testCtx, podsPrefix+"pod-0", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = cli.Put(testCtx, eventsPrefix+"event-0", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				runSnapshotter(true)
+
+				for i := 1; i <= 3; i++ {
+					_, err = cli.Put(testCtx, fmt.Sprintf("%sevent-%d", eventsPrefix, i), "v1")
+					Expect(err).ShouldNot(HaveOccurred())
+					_, err = cli.Put(testCtx, fmt.Sprintf("%spod-%d", podsPrefix, i), "v1")
+					Expect(err).ShouldNot(HaveOccurred())
+				}
+				_, err = cli.Txn(testCtx).Then(
+					clientv3.OpPut(podsPrefix+"pod-0", "v2"),
+					clientv3.OpPut(eventsPrefix+"event-0", "v2"),
+				).Commit()
+				Expect(err).ShouldNot(HaveOccurred())
+				resp, err := cli.Put(testCtx, eventsPrefix+"event-4", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				lastRevision = resp.Header.Revision
+				runSnapshotter(false)
+
+				etcd.Server.Stop()
+				etcd.Close()
+				etcd = nil
+				Expect(os.RemoveAll(etcdDir)).To(Succeed())
+			})
+
+			It("should restore the revisions of the original data without the events of excluded keys", func() {
+				restore()
+
+				data, revision := getRestoredData("/registry/")
+				// the revisions of the excluded events are padded, and the padding is removed in one more revision
+				Expect(revision).Should(Equal(lastRevision + 1))
+				Expect(data).Should(HaveLen(5))
+				for i := 0; i <= 3; i++ {
+					Expect(data).Should(HaveKey(fmt.Sprintf("%spod-%d", podsPrefix, i)))
+				}
+				Expect(data).Should(HaveKeyWithValue(podsPrefix+"pod-0", "v2"))
+				// the full snapshot is restored as is
+				Expect(data).Should(HaveKeyWithValue(eventsPrefix+"event-0", "v1"))
+				for i := 1; i <= 4; i++ {
+					Expect(data).ShouldNot(HaveKey(fmt.Sprintf("%sevent-%d", eventsPrefix, i)))
+				}
+			})
+
+			It("should not keep the revision padding in the data restored by the embedded etcd", func() {
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				restorer, err = NewRestorer(store, logger)
+				Expect(err).ShouldNot(HaveOccurred())
+				e, err := restorer.Restore(testCtx, brtypes.RestoreOptions{
+					Config:        restorationConfig,
+					BaseSnapshot:  baseSnapshot,
+					DeltaSnapList: deltaSnapList,
+					ClusterURLs:   clusterUrlsMap,
+					PeerURLs:      peerUrls,
+				}, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(e).ShouldNot(BeNil())
+				defer e.Close()
+				cli, err := clientv3.New(clientv3.Config{Endpoints: []string{e.Clients[0].Addr().String()}})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				resp, err := cli.Get(testCtx, "/etcd-backup-restore/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Kvs).Should(BeEmpty())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision + 1))
+			})
+
+			It("should remove the excluded keys from the restored data", func() {
+				restorationConfig.ExcludeKeyPrefixes = []string{eventsPrefix}
+				restore()
+
+				data, _ := getRestoredData("/registry/")
+				Expect(data).Should(HaveLen(4))
+				for i := 0; i <= 3; i++ {
+					Expect(data).Should(HaveKey(fmt.Sprintf("%spod-%d", podsPrefix, i)))
+				}
+			})
+
+			It("should remove the keys which are not included from the restored data", func() {
+				restorationConfig.IncludeKeyPrefixes = []string{podsPrefix + "pod-1", podsPrefix + "pod-2"}
+				restore()
+
+				data, _ := getRestoredData("/")
+				Expect(data).Should(HaveLen(2))
+				Expect(data).Should(HaveKey(podsPrefix + "pod-1"))
+				Expect(data).Should(HaveKey(podsPrefix + "pod-2"))
+			})
+
+			It("should remove the excluded keys from the data restored by the embedded etcd", func() {
+				restorationConfig.ExcludeKeyPrefixes = []string{eventsPrefix}
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				restorer, err = NewRestorer(store, logger)
+				Expect(err).ShouldNot(HaveOccurred())
+				e, err := restorer.Restore(testCtx, brtypes.RestoreOptions{
+					Config:        restorationConfig,
+					BaseSnapshot:  baseSnapshot,
+					DeltaSnapList: deltaSnapList,
+					ClusterURLs:   clusterUrlsMap,
+					PeerURLs:      peerUrls,
+				}, nil)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(e).ShouldNot(BeNil())
+				defer e.Close()
+				cli, err := clientv3.New(clientv3.Config{Endpoints: []string{e.Clients[0].Addr().String()}})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				resp, err := cli.Get(testCtx, eventsPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Count).Should(BeZero())
+				resp, err = cli.Get(testCtx, podsPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Count).Should(Equal(int64(4)))
+			})
+		})
+
+		Context("with delta snapshots in the json and the binary format", func() {
+			runSnapshotter := func(format string, startWithFullSnapshot bool) {
+				etcdConnectionConfig := brtypes.NewEtcdConnectionConfig()
+				etcdConnectionConfig.ConnectionTimeout.Duration = 10 * time.Second
+				etcdConnectionConfig.Endpoints = ep
+				snapshotterConfig := snapshotter.NewSnapshotterConfig()
+				snapshotterConfig.FullSnapshotSchedule = "0 0 1 1 *"
+				snapshotterConfig.DeltaSnapshotPeriod.Duration = deltaSnapshotPeriod
+				snapshotterConfig.DeltaSnapshotFormat = format
+				compressionConfig := compressor.NewCompressorConfig()
+				compressionConfig.Enabled = true
+				snapstoreConfig := brtypes.SnapstoreConfig{Container: snapstoreDir, Provider: "Local"}
+				ssr, err := snapshotter.NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, brtypes.NewHealthConfig(), &snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				ctx, cancel := context.WithTimeout(testCtx, 2*time.Second)
+				defer cancel()
+				Expect(ssr.Run(ctx.Done(), startWithFullSnapshot)).To(Succeed())
+			}
+
+			// restoreDeltaSnapshots restores the snapshots taken by the snapshotter, and returns a client of the restored etcd.
+			restoreDeltaSnapshots := func(expectedDeltaSnapshots int) *clientv3.Client {
+				etcd.Server.Stop()
+				etcd.Close()
+				etcd = nil
+				Expect(os.RemoveAll(etcdDir)).To(Succeed())
+
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(deltaSnapList).Should(HaveLen(expectedDeltaSnapshots))
+				restorer, err = NewRestorer(store, logger)
+				Expect(err).ShouldNot(HaveOccurred())
+				restoreOpts := brtypes.RestoreOptions{
+					Config:        restorationConfig,
+					BaseSnapshot:  baseSnapshot,
+					DeltaSnapList: deltaSnapList,
+					ClusterURLs:   clusterUrlsMap,
+					PeerURLs:      peerUrls,
+				}
+				Expect(restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)).To(Succeed())
+
+				e, err := utils.StartEmbeddedEtcd(testCtx, restorationConfig.DataDir, logger, utils.DefaultEtcdName, embeddedEtcdPortNo)
+				Expect(err).ShouldNot(HaveOccurred())
+				DeferCleanup(e.Close)
+				restoredCli, err := clientv3.New(clientv3.Config{Endpoints: []string{e.Clients[0].Addr().String()}})
+				Expect(err).ShouldNot(HaveOccurred())
+				DeferCleanup(restoredCli.Close)
+				return restoredCli
+			}
+
+			It("should restore the events of both formats", func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				_, err = cli.Put(testCtx, "/registry/pods/pod-0", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				runSnapshotter(brtypes.DeltaSnapshotFormatJSON, true)
+				var lastRevision int64
+				for _, format := range []string{brtypes.DeltaSnapshotFormatJSON, brtypes.DeltaSnapshotFormatBinary} {
+					for i := 0; i < 5; i++ {
+						resp, err := cli.Put(testCtx, fmt.Sprintf("/registry/pods/%s-%d", format, i), format)
+						Expect(err).ShouldNot(HaveOccurred())
+						lastRevision = resp.Header.Revision
+					}
+					runSnapshotter(format, false)
+				}
+
+				restoredCli := restoreDeltaSnapshots(2)
+				resp, err := restoredCli.Get(testCtx, "/registry/pods/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision))
+				Expect(resp.Kvs).Should(HaveLen(11))
+			})
+
+			It("should recreate the leases of the keys in both formats", func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				leases := map[string]clientv3.LeaseID{}
+				baseLease, err := cli.Grant(testCtx, 3600)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = cli.Put(testCtx, "/registry/events/event-0", "v1", clientv3.WithLease(baseLease.ID))
+				Expect(err).ShouldNot(HaveOccurred())
+				leases["/registry/events/event-0"] = baseLease.ID
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, true)
+
+				var lastRevision int64
+				for _, format := range []string{brtypes.DeltaSnapshotFormatJSON, brtypes.DeltaSnapshotFormatBinary} {
+					lease, err := cli.Grant(testCtx, 600)
+					Expect(err).ShouldNot(HaveOccurred())
+					for i := 0; i < 2; i++ {
+						key := fmt.Sprintf("/registry/events/%s-%d", format, i)
+						resp, err := cli.Put(testCtx, key, format, clientv3.WithLease(lease.ID))
+						Expect(err).ShouldNot(HaveOccurred())
+						leases[key] = lease.ID
+						lastRevision = resp.Header.Revision
+					}
+					// the keys of a revoked lease are deleted along with it
+					revokedLease, err := cli.Grant(testCtx, 600)
+					Expect(err).ShouldNot(HaveOccurred())
+					_, err = cli.Put(testCtx, fmt.Sprintf("/registry/events/%s-revoked", format), format, clientv3.WithLease(revokedLease.ID))
+					Expect(err).ShouldNot(HaveOccurred())
+					revokeResp, err := cli.Revoke(testCtx, revokedLease.ID)
+					Expect(err).ShouldNot(HaveOccurred())
+					lastRevision = revokeResp.Header.Revision
+					runSnapshotter(format, false)
+				}
+
+				restoredCli := restoreDeltaSnapshots(2)
+				resp, err := restoredCli.Get(testCtx, "/registry/events/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision))
+				Expect(resp.Kvs).Should(HaveLen(len(leases)))
+				for _, kv := range resp.Kvs {
+					Expect(clientv3.LeaseID(kv.Lease)).Should(Equal(leases[string(kv.Key)]), "lease of key %s", kv.Key)
+					ttl, err := restoredCli.TimeToLive(testCtx, clientv3.LeaseID(kv.Lease), clientv3.WithAttachedKeys())
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(ttl.TTL).Should(BeNumerically(">", 0))
+					Expect(ttl.GrantedTTL).Should(BeNumerically("<=", 3600))
+					Expect(ttl.Keys).Should(ContainElement(kv.Key))
+				}
+			})
+
+			It("should keep the short leases of the base snapshot alive while the delta snapshots are applied", func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				lease, err := cli.Grant(testCtx, 2)
+				Expect(err).ShouldNot(HaveOccurred())
+				keepAliveCtx, stopKeepAlive := context.WithCancel(testCtx)
+				defer stopKeepAlive()
+				_, err = cli.KeepAlive(keepAliveCtx, lease.ID)
+				Expect(err).ShouldNot(HaveOccurred())
+				_, err = cli.Put(testCtx, "/registry/masterleases/ip-0", "v1", clientv3.WithLease(lease.ID))
+				Expect(err).ShouldNot(HaveOccurred())
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, true)
+				var lastRevision int64
+				for i := 1; i <= 2; i++ {
+					resp, err := cli.Put(testCtx, "/registry/masterleases/ip-0", fmt.Sprintf("v%d", i+1), clientv3.WithLease(lease.ID))
+					Expect(err).ShouldNot(HaveOccurred())
+					lastRevision = resp.Header.Revision
+					runSnapshotter(brtypes.DeltaSnapshotFormatBinary, false)
+				}
+				stopKeepAlive()
+
+				// the lease of the base snapshot would expire before the last delta snapshot is applied
+				baseSnapshot, deltaSnapList, err = miscellaneous.GetLatestFullSnapshotAndDeltaSnapList(store)
+				Expect(err).ShouldNot(HaveOccurred())
+				store = &slowSnapStore{SnapStore: store, slowSnapName: deltaSnapList[len(deltaSnapList)-1].SnapName, delay: 6 * time.Second}
+				restoredCli := restoreDeltaSnapshots(2)
+				resp, err := restoredCli.Get(testCtx, "/registry/masterleases/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision))
+				Expect(resp.Kvs).Should(HaveLen(1))
+				Expect(string(resp.Kvs[0].Value)).Should(Equal("v3"))
+				Expect(clientv3.LeaseID(resp.Kvs[0].Lease)).Should(Equal(lease.ID))
+			})
+
+			It("should bump the revision and mark it compacted after applying the delta snapshots", func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				_, err = cli.Put(testCtx, "/registry/pods/pod-0", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, true)
+				var lastRevision int64
+				for i := 0; i < 5; i++ {
+					resp, err := cli.Put(testCtx, fmt.Sprintf("/registry/pods/pod-%d", i), "v2")
+					Expect(err).ShouldNot(HaveOccurred())
+					lastRevision = resp.Header.Revision
+				}
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, false)
+
+				restorationConfig.RevisionBump = 1000
+				restorationConfig.MarkCompacted = true
+				restoredCli := restoreDeltaSnapshots(1)
+				resp, err := restoredCli.Get(testCtx, "/registry/pods/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision + 1000))
+				Expect(resp.Kvs).Should(HaveLen(5))
+				for _, kv := range resp.Kvs {
+					Expect(string(kv.Value)).Should(Equal("v2"))
+				}
+				_, err = restoredCli.Get(testCtx, "/registry/pods/", clientv3.WithPrefix(), clientv3.WithRev(lastRevision))
+				Expect(err).Should(MatchError(rpctypes.ErrCompacted))
+
+				putResp, err := restoredCli.Put(testCtx, "/registry/pods/pod-5", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(putResp.Header.Revision).Should(Equal(lastRevision + 1001))
+			})
+
+			It("should bump the revision without marking it compacted", func() {
+				cli, err := clientv3.New(clientv3.Config{Endpoints: ep})
+				Expect(err).ShouldNot(HaveOccurred())
+				defer cli.Close()
+
+				_, err = cli.Put(testCtx, "/registry/pods/pod-0", "v1")
+				Expect(err).ShouldNot(HaveOccurred())
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, true)
+				var lastRevision int64
+				for i := 0; i < 5; i++ {
+					resp, err := cli.Put(testCtx, fmt.Sprintf("/registry/pods/pod-%d", i), "v2")
+					Expect(err).ShouldNot(HaveOccurred())
+					lastRevision = resp.Header.Revision
+				}
+				runSnapshotter(brtypes.DeltaSnapshotFormatBinary, false)
+
+				restorationConfig.RevisionBump = 1000
+				restoredCli := restoreDeltaSnapshots(1)
+				resp, err := restoredCli.Get(testCtx, "/registry/pods/", clientv3.WithPrefix())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Header.Revision).Should(Equal(lastRevision + 1000))
+				Expect(resp.Kvs).Should(HaveLen(5))
+				resp, err = restoredCli.Get(testCtx, "/registry/pods/", clientv3.WithPrefix(), clientv3.WithRev(lastRevision))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(resp.Kvs).Should(HaveLen(5))
+			})
+		})
+
 		Context("when full snapshot is compressed followed by multiple delta Snapshots which are uncompressed as well as compressed", func() {
 			It("Should able to restore", func() {
 
@@ -621,7 +1157,7 @@ var _ = Describe("Running Restorer", func() {
 					PeerURLs:      peerUrls,
 				}
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).ShouldNot(HaveOccurred())
 				err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, keyTo, "", "", logger)
 				Expect(err).ShouldNot(HaveOccurred())
@@ -932,7 +1468,7 @@ var _ = Describe("Running Restorer when both v1 and v2 directory structures are
 			err = os.RemoveAll(etcdDataDir)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+			err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 			err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, resp.KeyTo, "", "", logger)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -965,7 +1501,7 @@ var _ = Describe("Running Restorer when both v1 and v2 directory structures are
 			err = os.RemoveAll(etcdDataDir)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+			err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 			err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, resp.KeyTo, "", "", logger)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -998,7 +1534,7 @@ var _ = Describe("Running Restorer when both v1 and v2 directory structures are
 			err = os.RemoveAll(etcdDataDir)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+			err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 			err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, resp.KeyTo, "", "", logger)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -1033,7 +1569,7 @@ var _ = Describe("Running Restorer when both v1 and v2 directory structures are
 			err = os.RemoveAll(etcdDataDir)
 			Expect(err).ShouldNot(HaveOccurred())
 
-			err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+			err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 			Expect(err).ShouldNot(HaveOccurred())
 			err = utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, resp.KeyTo, "", "", logger)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -1067,7 +1603,7 @@ var _ = Describe("Running Restorer when both v1 and v2 directory structures are
 				err = os.RemoveAll(etcdDataDir)
 				Expect(err).ShouldNot(HaveOccurred())
 
-				err = restorer.RestoreAndStopEtcd(restoreOpts, nil)
+				err = restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)
 				Expect(err).Should(HaveOccurred())
 			})
 		})
@@ -1188,7 +1724,7 @@ var _ = Describe("Running Restorer when base snapshot has etcd auth enabled", fu
 		}
 
 		Expect(os.RemoveAll(etcdDataDir)).To(Succeed())
-		Expect(restorer.RestoreAndStopEtcd(restoreOpts, nil)).To(Succeed())
+		Expect(restorer.RestoreAndStopEtcd(testCtx, restoreOpts, nil)).To(Succeed())
 		Expect(utils.CheckDataConsistency(testCtx, restoreOpts.Config.DataDir, resp.KeyTo, rootUsername, rootPssword, logger)).To(Succeed())
 	})
 
@@ -1384,3 +1920,17 @@ func takeInvalidV1Snaps(container string) error {
 
 	return nil
 }
+
+// slowSnapStore delays fetching the snapshot with the given name.
+type slowSnapStore struct {
+	brtypes.SnapStore
+	slowSnapName string
+	delay        time.Duration
+}
+
+func (s *slowSnapStore) Fetch(snap brtypes.Snapshot) (io.ReadCloser, error) {
+	if snap.SnapName == s.slowSnapName {
+		time.Sleep(s.delay)
+	}
+	return s.SnapStore.Fetch(snap)
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package restorer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	"go.etcd.io/etcd/server/v3/mvcc"
+	"go.etcd.io/etcd/server/v3/mvcc/backend"
+	"go.etcd.io/etcd/server/v3/mvcc/buckets"
+)
+
+// revisionBytesLen is the length of a revision key in the key bucket of the etcd backend: the main revision in big-endian
+// format, a '_' and the sub revision in big-endian format.
+const revisionBytesLen = 8 + 1 + 8
+
+// bumpRevision bumps the latest revision of the restored etcd backend in the given data directory by the given amount,
+// and optionally marks the bumped revision as compacted, like `etcdutl snapshot restore --bump-revision
+// --mark-compacted`. The etcd server must not run on the data directory.
+func (r *Restorer) bumpRevision(dataDir string, amount uint64, markCompacted bool) error {
+	dbPath := filepath.Join(dataDir, "member", "snap", "db")
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("unable to stat backend db file: %v", err)
+	}
+	be := backend.NewDefaultBackend(dbPath)
+	defer func() {
+		be.ForceCommit()
+		if err := be.Close(); err != nil {
+			r.logger.Errorf("failed to close etcd backend %s: %v", dbPath, err)
+		}
+	}()
+
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+
+	var latest int64
+	if err := tx.UnsafeForEach(buckets.Key, func(k, _ []byte) error {
+		if len(k) >= 8 {
+			latest = max(latest, int64(binary.BigEndian.Uint64(k[0:8]))) // #nosec G115 -- revisions are positive int64 values.
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to find the latest revision: %v", err)
+	}
+
+	if amount > uint64(math.MaxInt64-latest) { // #nosec G115 -- the latest revision is not negative.
+		return fmt.Errorf("failed to bump the latest revision %d by %d: the revision would overflow", latest, amount)
+	}
+	bumped := latest + int64(amount) // #nosec G115 -- the amount is checked to not overflow.
+	r.logger.Infof("Bumping the latest revision %d by %d to %d.", latest, amount, bumped)
+	// etcd resumes from the highest revision in the key bucket, so an empty value is written at the bumped revision.
+	k := make([]byte, revisionBytesLen)
+	binary.BigEndian.PutUint64(k, uint64(bumped)) // #nosec G115 -- the bumped revision is positive.
+	k[8] = '_'
+	tx.UnsafePut(buckets.Key, k, []byte{})
+	if markCompacted {
+		r.logger.Infof("Marking the bumped revision %d compacted.", bumped)
+		mvcc.UnsafeSetScheduledCompact(tx, bumped)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package restorer
+
+import (
+	"fmt"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+)
+
+// NewScratchRestoreOptions returns the options to restore the given snapshots into the given data directory, which does
+// not interfere with the etcd member, e.g. for restore drills, jobs or exports. They use a copy of the given restoration
+// config, which keeps its tuning, like the number of fetchers and the quota of the embedded etcd, but restores all keys
+// of the snapshots into a single member cluster with the default name and URLs. Callers set the target of the
+// restoration on the returned config, if any.
+func NewScratchRestoreOptions(restorationConfig *brtypes.RestorationConfig, dataDir, tempSnapshotsDir string, baseSnap *brtypes.Snapshot, deltaSnapList brtypes.SnapList) (*brtypes.RestoreOptions, error) {
+	defaults := brtypes.NewRestorationConfig()
+	config := restorationConfig.DeepCopy()
+	config.DataDir = dataDir
+	config.TempSnapshotsDir = tempSnapshotsDir
+	config.Name = defaults.Name
+	config.InitialCluster = defaults.InitialCluster
+	config.InitialClusterToken = defaults.InitialClusterToken
+	config.InitialAdvertisePeerURLs = defaults.InitialAdvertisePeerURLs
+	config.TargetRevision = 0
+	config.TargetTime = ""
+	config.TargetSnapshotLabels = ""
+	config.IncludeKeyPrefixes = nil
+	config.ExcludeKeyPrefixes = nil
+	config.RevisionBump = 0
+	config.MarkCompacted = false
+
+	clusterURLsMap, err := types.NewURLsMap(config.InitialCluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating url map for restore cluster: %v", err)
+	}
+	peerURLs, err := types.NewURLs(config.InitialAdvertisePeerURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing peers urls for restore cluster: %v", err)
+	}
+	return &brtypes.RestoreOptions{
+		Config:              config,
+		ClusterURLs:         clusterURLsMap,
+		PeerURLs:            peerURLs,
+		BaseSnapshot:        baseSnap,
+		DeltaSnapList:       deltaSnapList,
+		OriginalClusterSize: 1,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// eventBuffer holds the encoded events collected since the previous snapshot. The events are kept in memory up to the
+// memory limit. Beyond it, they are spilled to a segment file in the spill directory if one is set, and kept in memory
+// otherwise.
+type eventBuffer struct {
+	logger      *logrus.Entry
+	spillDir    string
+	memoryLimit int
+	memory      bytes.Buffer
+	// segment is the file to which the events are spilled, nil while they are kept in memory. It is removed right
+	// after it has been created, so that it is freed once closed, or once the process ends without closing it.
+	segment       *os.File
+	segmentWriter *bufio.Writer
+	size          int64
+}
+
+// newEventBuffer returns an event buffer which spills the events to the given directory once they exceed the given
+// memory limit. The events are never spilled if no directory is given.
+func newEventBuffer(logger *logrus.Entry, spillDir string, memoryLimit int) *eventBuffer {
+	return &eventBuffer{
+		logger:      logger,
+		spillDir:    spillDir,
+		memoryLimit: memoryLimit,
+	}
+}
+
+// Write appends the given encoded events to the buffer, and spills the buffer to a segment file once it exceeds the
+// memory limit.
+func (b *eventBuffer) Write(p []byte) (int, error) {
+	if b.segment == nil && len(b.spillDir) != 0 && b.memory.Len()+len(p) > b.memoryLimit {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+	}
+	var (
+		n   int
+		err error
+	)
+	if b.segment != nil {
+		n, err = b.segmentWriter.Write(p)
+	} else {
+		n, err = b.memory.Write(p)
+	}
+	b.size += int64(n)
+	return n, err
+}
+
+// spill moves the events held in memory to a new segment file in the spill directory.
+func (b *eventBuffer) spill() error {
+	if err := os.MkdirAll(b.spillDir, 0700); err != nil {
+		return fmt.Errorf("failed to create delta events spill directory %s: %v", b.spillDir, err)
+	}
+	segment, err := os.CreateTemp(b.spillDir, "delta-events-*.segment")
+	if err != nil {
+		return fmt.Errorf("failed to create delta events segment file: %v", err)
+	}
+	if err := os.Remove(segment.Name()); err != nil {
+		segment.Close()
+		return fmt.Errorf("failed to unlink delta events segment file %s: %v", segment.Name(), err)
+	}
+	b.logger.Infof("Delta events memory crossed the memory limit: %d Bytes, spilling the events to disk", b.memoryLimit)
+	b.segment, b.segmentWriter = segment, bufio.NewWriter(segment)
+	if _, err := b.segmentWriter.Write(b.memory.Bytes()); err != nil {
+		return fmt.Errorf("failed to spill delta events: %v", err)
+	}
+	b.memory = bytes.Buffer{}
+	return nil
+}
+
+// Len returns the size of the events in the buffer, including the spilled ones.
+func (b *eventBuffer) Len() int64 {
+	return b.size
+}
+
+// Reader returns a reader of the events in the buffer. Each reader reads the events from the beginning.
+func (b *eventBuffer) Reader() (io.Reader, error) {
+	if b.segment == nil {
+		return bytes.NewReader(b.memory.Bytes()), nil
+	}
+	if err := b.segmentWriter.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to spill delta events: %v", err)
+	}
+	return io.NewSectionReader(b.segment, 0, b.size), nil
+}
+
+// Close frees the events in the buffer, along with the segment file they have been spilled to.
+func (b *eventBuffer) Close() error {
+	b.memory = bytes.Buffer{}
+	if b.segment == nil {
+		return nil
+	}
+	err := b.segment.Close()
+	b.segment, b.segmentWriter = nil, nil
+	return err
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// recordLeaseID records the ID of the lease to which the key of the given event is attached. The leases are only
+// looked up once the delta snapshot is taken, so that the events are collected without waiting for etcd.
+func (ssr *Snapshotter) recordLeaseID(event *brtypes.Event) {
+	id := event.EtcdEvent.Kv.Lease
+	if event.EtcdEvent.Type != mvccpb.PUT || id == 0 {
+		return
+	}
+	if ssr.eventLeaseIDs == nil {
+		ssr.eventLeaseIDs = map[int64]struct{}{}
+	}
+	ssr.eventLeaseIDs[id] = struct{}{}
+}
+
+// attachLeases looks up the leases recorded since the previous snapshot, and attaches each of them to the first event
+// of the lease in the collected events, so that the lease can be recreated on restoration. The events are encoded
+// again for this, into a new event buffer which replaces the collected events. The encoder of the collected events
+// must have been closed.
+//
+// Revoked or expired leases need no further handling: the deletion of their keys is collected as events like any other,
+// and a lease which does not exist anymore when it is looked up is not recorded.
+func (ssr *Snapshotter) attachLeases() error {
+	if len(ssr.eventLeaseIDs) == 0 {
+		return nil
+	}
+	leases := map[int64]*brtypes.Lease{}
+	for id := range ssr.eventLeaseIDs {
+		lease, err := ssr.lookupLease(id)
+		if err != nil {
+			// the keys of the lease are restored without lease, as if the lease had expired
+			ssr.logger.Warnf("Failed to look up lease %x, it is not recorded: %v", id, err)
+			continue
+		}
+		if lease == nil {
+			ssr.logger.Debugf("Lease %x does not exist anymore, it is not recorded", id)
+			continue
+		}
+		leases[id] = lease
+	}
+	if len(leases) == 0 {
+		return nil
+	}
+
+	r, err := ssr.events.Reader()
+	if err != nil {
+		return err
+	}
+	decoder, err := delta.NewDecoder(r)
+	if err != nil {
+		return fmt.Errorf("failed to decode collected events: %v", err)
+	}
+	config := ssr.currentConfig()
+	events := newEventBuffer(ssr.logger, config.DeltaSnapshotSpillDir, int(config.DeltaSnapshotMemoryLimit)) // #nosec G115 -- validated for size to be lesser than MaxInt.
+	if err := ssr.encodeWithLeases(decoder, events, leases); err != nil {
+		if closeErr := events.Close(); closeErr != nil {
+			ssr.logger.Warnf("Failed to remove spilled delta events: %v", closeErr)
+		}
+		return err
+	}
+	if err := ssr.events.Close(); err != nil {
+		ssr.logger.Warnf("Failed to remove spilled delta events: %v", err)
+	}
+	ssr.events = events
+	return nil
+}
+
+// encodeWithLeases encodes the events of the given decoder to the given writer in the same format, and attaches each of
+// the given leases to the first event of the lease.
+func (ssr *Snapshotter) encodeWithLeases(decoder *delta.Decoder, w io.Writer, leases map[int64]*brtypes.Lease) error {
+	encoder, err := delta.NewEncoder(w, decoder.Format())
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode collected events: %v", err)
+		}
+		if event.EtcdEvent.Type == mvccpb.PUT {
+			if lease, ok := leases[event.EtcdEvent.Kv.Lease]; ok {
+				event.Lease = lease
+				delete(leases, lease.ID)
+			}
+		}
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode events: %v", err)
+	}
+	return nil
+}
+
+// lookupLease looks up the remaining TTL of the lease with the given ID. It returns nil if the lease has expired or has
+// been revoked meanwhile.
+func (ssr *Snapshotter) lookupLease(id int64) (*brtypes.Lease, error) {
+	if ssr.etcdLeaseClient == nil {
+		clientLease, err := etcdutil.NewFactory(*ssr.etcdConnectionConfig).NewLease()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create etcd lease client: %v", err)
+		}
+		ssr.etcdLeaseClient = clientLease
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), ssr.etcdConnectionConfig.ConnectionTimeout.Duration)
+	defer cancel()
+	resp, err := ssr.etcdLeaseClient.LeaseTimeToLive(ctx, &etcdserverpb.LeaseTimeToLiveRequest{ID: id})
+	if err != nil {
+		return nil, err
+	}
+	if resp.TTL < 0 {
+		return nil, nil
+	}
+	return &brtypes.Lease{ID: id, GrantedTTL: resp.GrantedTTL, TTL: resp.TTL}, nil
+}
+
+// closeEtcdLeaseClient closes the etcd lease client, if it has been created.
+func (ssr *Snapshotter) closeEtcdLeaseClient() {
+	if ssr.etcdLeaseClient == nil {
+		return
+	}
+	if err := ssr.etcdLeaseClient.Close(); err != nil {
+		ssr.logger.Warnf("Failed to close the etcd lease client: %v", err)
+	}
+	ssr.etcdLeaseClient = nil
+}
@@ -6,10 +6,13 @@ package snapshotter
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"path"
+	"strings"
 	"time"
 
+	"github.com/gardener/etcd-backup-restore/pkg/events"
 	"github.com/gardener/etcd-backup-restore/pkg/metrics"
 	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
@@ -22,8 +25,9 @@ const DeltaSnapshotGCErrorThreshold = 5
 
 // RunGarbageCollector basically consider the older backups as garbage and deletes it
 func (ssr *Snapshotter) RunGarbageCollector(stopCh <-chan struct{}) {
-	if ssr.config.GarbageCollectionPeriod.Duration <= time.Second {
-		ssr.logger.Infof("GC: Not running garbage collector since GarbageCollectionPeriod [%s] set to less than 1 second.", ssr.config.GarbageCollectionPeriod)
+	gcPeriod := ssr.currentConfig().GarbageCollectionPeriod
+	if gcPeriod.Duration <= time.Second {
+		ssr.logger.Infof("GC: Not running garbage collector since GarbageCollectionPeriod [%s] set to less than 1 second.", gcPeriod)
 		return
 	}
 
@@ -32,179 +36,275 @@ func (ssr *Snapshotter) RunGarbageCollector(stopCh <-chan struct{}) {
 		case <-stopCh:
 			ssr.logger.Info("GC: Stop signal received. Closing garbage collector.")
 			return
-		case <-time.After(ssr.config.GarbageCollectionPeriod.Duration):
-
-			var err error
-			// Update the snapstore object before taking any action on object storage bucket.
-			// Refer: https://github.com/gardener/etcd-backup-restore/issues/422
-			ssr.store, err = snapstore.GetSnapstore(ssr.snapstoreConfig)
-			if err != nil {
-				ssr.logger.Warnf("GC: Failed to create snapstore from configured storage provider: %v", err)
+		case <-time.After(gcPeriod.Duration):
+			if ssr.IsPaused() {
+				ssr.logger.Info("GC: Skipping garbage collection while snapshotting is paused.")
 				continue
 			}
-
-			total := 0
-			ssr.logger.Info("GC: Executing garbage collection...")
-			// List all (tagged and untagged) snapshots to garbage collect them according to the garbage collection policy.
-			snapList, err := ssr.store.List(true)
+			total, err := ssr.GarbageCollect()
+			ssr.recordGarbageCollection(total, err)
 			if err != nil {
-				metrics.SnapshotterOperationFailure.With(prometheus.Labels{metrics.LabelError: err.Error()}).Inc()
-				ssr.logger.Warnf("GC: Failed to list snapshots: %v", err)
-				continue
+				ssr.logger.Warnf("GC: %v", err)
 			}
+		}
+	}
+}
+
+// GarbageCollect deletes the snapshots which are not retained by the garbage collection policy, and returns the number of deleted snapshots.
+func (ssr *Snapshotter) GarbageCollect() (int, error) {
+	var err error
+	// Update the snapstore object before taking any action on object storage bucket.
+	// Refer: https://github.com/gardener/etcd-backup-restore/issues/422
+	ssr.store, err = snapstore.GetSnapstore(ssr.snapstoreConfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+	}
+
+	total := 0
+	ssr.logger.Info("GC: Executing garbage collection...")
+	// List all (tagged and untagged) snapshots to garbage collect them according to the garbage collection policy.
+	snapList, err := ssr.store.List(true)
+	if err != nil {
+		metrics.SnapshotterOperationFailure.With(prometheus.Labels{metrics.LabelError: err.Error()}).Inc()
+		return 0, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+
+	// Skip chunk deletion for openstack swift provider, since the manifest object is a virtual
+	// representation of the object, and the actual data is stored in the segment objects, aka chunks
+	// Chunk deletion for this provider is handled in regular snapshot deletion
+	if ssr.snapstoreConfig.Provider == brtypes.SnapstoreProviderSwift {
+		snapList = withoutChunks(snapList)
+	} else {
+		// chunksDeleted stores the no of chunks deleted in the current iteration of GC.
+		var chunksDeleted int
+		// GarbageCollectChunks returns a filtered SnapList which does not contain chunks.
+		chunksDeleted, snapList = ssr.GarbageCollectChunks(snapList)
+		ssr.logger.Infof("GC: Total number garbage collected chunks: %d", chunksDeleted)
+	}
 
-			// Skip chunk deletion for openstack swift provider, since the manifest object is a virtual
-			// representation of the object, and the actual data is stored in the segment objects, aka chunks
-			// Chunk deletion for this provider is handled in regular snapshot deletion
-			if ssr.snapstoreConfig.Provider == brtypes.SnapstoreProviderSwift {
-				var filteredSnapList brtypes.SnapList
-				for _, snap := range snapList {
-					if !snap.IsChunk {
-						filteredSnapList = append(filteredSnapList, snap)
-					}
+	fullSnapshotIndexList := getFullSnapshotIndexList(snapList)
+	retained := ssr.retainedFullSnapshots(snapList, fullSnapshotIndexList, time.Now().UTC())
+	// snapStream indicates a list of snapshots, where the first snapshot is base/full snapshot followed by a list of incremental snapshots based on it.
+	// Garbage collection is performed on one snapStream at a time.
+	// Delete delta snapshots in all snapStream but the latest one, and the full snapshots which are not retained by the policy.
+	for fullSnapshotIndex := 0; fullSnapshotIndex < len(fullSnapshotIndexList)-1; fullSnapshotIndex++ {
+		snapStream := snapList[fullSnapshotIndexList[fullSnapshotIndex]:fullSnapshotIndexList[fullSnapshotIndex+1]]
+		numDeletedSnapshots, err := ssr.GarbageCollectDeltaSnapshots(snapStream)
+		total += numDeletedSnapshots
+		if err != nil {
+			continue
+		}
+		snap := snapStream[0]
+		if snap.Kind != brtypes.SnapshotKindFull {
+			continue
+		}
+		if reason, ok := retained[snap]; ok {
+			ssr.logger.Debugf("GC: Keeping the snapshot: %s, since it is %s", snap.SnapName, reason)
+			continue
+		}
+		if ssr.garbageCollectFullSnapshot(snap) {
+			total++
+		}
+	}
+	ssr.logger.Infof("GC: Total number garbage collected snapshots: %d", total)
+	return total, nil
+}
+
+// retainedFullSnapshots returns the full snapshots of all snapStreams but the latest one, which are retained by the garbage collection policy,
+// mapped to the reason for retaining them.
+func (ssr *Snapshotter) retainedFullSnapshots(snapList brtypes.SnapList, fullSnapshotIndexList []int, now time.Time) map[*brtypes.Snapshot]string {
+	config := ssr.currentConfig()
+	retained := make(map[*brtypes.Snapshot]string)
+	switch config.GarbageCollectionPolicy {
+	case brtypes.GarbageCollectionPolicyExponential:
+		// Overall policy:
+		// Keep only the last 24 hourly backups and of all other backups only the last backup in a day.
+		// Keep only the last 7 daily backups and of all other backups only the last backup in a week.
+		// Keep only the last 4 weekly backups.
+		var (
+			threshold int
+			reason    string
+			// Round off current time to EOD
+			eod          = now.Truncate(24 * time.Hour).Add(23 * time.Hour).Add(59 * time.Minute).Add(59 * time.Second)
+			trackingWeek = 0
+		)
+		// Here we start processing from second last snapstream, because we want to keep last snapstream
+		// including delta snapshots in it.
+		for fullSnapshotIndex := len(fullSnapshotIndexList) - 1; fullSnapshotIndex > 0; fullSnapshotIndex-- {
+			snap := snapList[fullSnapshotIndexList[fullSnapshotIndex]]
+			nextSnap := snapList[fullSnapshotIndexList[fullSnapshotIndex-1]]
+
+			delta := eod.Sub(nextSnap.CreatedOn)
+			// Depending on how old the nextSnap is, decide what is the criteria of saving it (1 per hour or day or week)
+			switch {
+			case delta < time.Duration(24)*time.Hour:
+				// Snapshot of current day
+				if nextSnap.CreatedOn.Hour() == now.Hour() {
+					// Save snapshot of current hour
+					threshold = 0
+					reason = "taken in the current hour"
+					break
 				}
-				snapList = filteredSnapList
-			} else {
-				// chunksDeleted stores the no of chunks deleted in the current iteration of GC.
-				var chunksDeleted int
-				// GarbageCollectChunks returns a filtered SnapList which does not contain chunks.
-				chunksDeleted, snapList = ssr.GarbageCollectChunks(snapList)
-				ssr.logger.Infof("GC: Total number garbage collected chunks: %d", chunksDeleted)
+				threshold = 1
+				reason = "the latest full snapshot of its hour"
+			case delta < time.Duration(8*24)*time.Hour:
+				// Snapshot of week ending with previous day
+				threshold = 24
+				reason = "the latest full snapshot of its day"
+			case delta < time.Duration(5*7*24)*time.Hour:
+				// Snapshot of month ending 8 days back (i.e., lesser than 5 weeks old)
+				if trackingWeek == 0 {
+					// As The week ends previous day, to keep track of change in week
+					// we shift eod to previous day's EOD when start tracking week
+					eod = eod.Add(-24 * time.Hour)
+					trackingWeek = 1
+				}
+				threshold = 24 * 7
+				reason = "the latest full snapshot of its week"
+			default:
+				// Delete snapshots older than 4 weeks
+				threshold = math.MaxInt32
 			}
 
-			fullSnapshotIndexList := getFullSnapshotIndexList(snapList)
-			// snapStream indicates a list of snapshots, where the first snapshot is base/full snapshot followed by a list of incremental snapshots based on it.
-			// Garbage collection is performed on one snapStream at a time.
-			switch ssr.config.GarbageCollectionPolicy {
-			case brtypes.GarbageCollectionPolicyExponential:
-				// Overall policy:
-				// Delete delta snapshots in all snapStream but the latest one.
-				// Keep only the last 24 hourly backups and of all other backups only the last backup in a day.
-				// Keep only the last 7 daily backups and of all other backups only the last backup in a week.
-				// Keep only the last 4 weekly backups.
-				var (
-					deleteSnap bool
-					threshold  int
-					now        = time.Now().UTC()
-					// Round off current time to EOD
-					eod          = now.Truncate(24 * time.Hour).Add(23 * time.Hour).Add(59 * time.Minute).Add(59 * time.Second)
-					trackingWeek = 0
-				)
-				// Here we start processing from second last snapstream, because we want to keep last snapstream
-				// including delta snapshots in it.
-				for fullSnapshotIndex := len(fullSnapshotIndexList) - 1; fullSnapshotIndex > 0; fullSnapshotIndex-- {
-					snap := snapList[fullSnapshotIndexList[fullSnapshotIndex]]
-					nextSnap := snapList[fullSnapshotIndexList[fullSnapshotIndex-1]]
-
-					// garbage collect delta snapshots.
-					snapStream := snapList[fullSnapshotIndexList[fullSnapshotIndex-1]:fullSnapshotIndexList[fullSnapshotIndex]]
-					numDeletedSnapshots, err := ssr.GarbageCollectDeltaSnapshots(snapStream)
-					total += numDeletedSnapshots
-					if err != nil {
-						continue
-					}
-
-					delta := eod.Sub(nextSnap.CreatedOn)
-					// Depending on how old the nextSnap is, decide what is the criteria of saving it (1 per hour or day or week)
-					switch {
-					case delta < time.Duration(24)*time.Hour:
-						// Snapshot of current day
-						if nextSnap.CreatedOn.Hour() == now.Hour() {
-							// Save snapshot of current hour
-							threshold = 0
-							break
-						}
-						threshold = 1
-					case delta < time.Duration(8*24)*time.Hour:
-						// Snapshot of week ending with previous day
-						threshold = 24
-					case delta < time.Duration(5*7*24)*time.Hour:
-						// Snapshot of month ending 8 days back (i.e., lesser than 5 weeks old)
-						if trackingWeek == 0 {
-							// As The week ends previous day, to keep track of change in week
-							// we shift eod to previous day's EOD when start tracking week
-							eod = eod.Add(-24 * time.Hour)
-							trackingWeek = 1
-						}
-						threshold = 24 * 7
-					default:
-						// Delete snapshots older than 4 weeks
-						threshold = math.MaxInt32
-					}
-
-					// Were snap and nextSnap created in different hour windows
-					hourChange := int(eod.Sub(nextSnap.CreatedOn).Hours()) - int(eod.Sub(snap.CreatedOn).Hours())
-					// Were snap and nextSnap created in different day windows
-					dayChange := int(eod.Sub(nextSnap.CreatedOn).Hours()/24) - int(eod.Sub(snap.CreatedOn).Hours()/24)
-					// Were snap and nextSnap created in different week windows
-					weekChange := int(eod.Sub(nextSnap.CreatedOn).Hours()/(24*7)) - int(eod.Sub(snap.CreatedOn).Hours()/(24*7))
-
-					if threshold == 0 || hourChange/threshold != 0 || dayChange*24/threshold != 0 || weekChange*24*7/threshold != 0 {
-						// The change in parameter was more than the threshold, so don't delete the snapshot
-						deleteSnap = false
-					} else {
-						// The change in parameter was less than the threshold, so delete the snapshot
-						deleteSnap = true
-					}
-
-					if deleteSnap {
-						if !nextSnap.IsDeletable() {
-							ssr.logger.Infof("GC: Skipping the snapshot: %s, since its immutability period hasn't expired yet", nextSnap.SnapName)
-							continue
-						}
-						ssr.logger.Infof("GC: Deleting old full snapshot: %s %v", nextSnap.CreatedOn.UTC(), deleteSnap)
-						if err := ssr.store.Delete(*nextSnap); errors.Is(err, brtypes.ErrSnapshotDeleteFailDueToImmutability) {
-							// The snapshot is still immutable, attempt to gargbage collect it in the next run
-							ssr.logger.Warnf("GC: Skipping the snapshot: %s, since it is still immutable", nextSnap.SnapName)
-							continue
-						} else if err != nil {
-							ssr.logger.Warnf("GC: Failed to delete snapshot %s: %v", path.Join(nextSnap.SnapDir, nextSnap.SnapName), err)
-							metrics.SnapshotterOperationFailure.With(prometheus.Labels{metrics.LabelError: err.Error()}).Inc()
-							metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Inc()
-							continue
-						}
-						metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Inc()
-						total++
-					}
-				}
+			// Were snap and nextSnap created in different hour windows
+			hourChange := int(eod.Sub(nextSnap.CreatedOn).Hours()) - int(eod.Sub(snap.CreatedOn).Hours())
+			// Were snap and nextSnap created in different day windows
+			dayChange := int(eod.Sub(nextSnap.CreatedOn).Hours()/24) - int(eod.Sub(snap.CreatedOn).Hours()/24)
+			// Were snap and nextSnap created in different week windows
+			weekChange := int(eod.Sub(nextSnap.CreatedOn).Hours()/(24*7)) - int(eod.Sub(snap.CreatedOn).Hours()/(24*7))
 
-			case brtypes.GarbageCollectionPolicyLimitBased:
-				// Delete delta snapshots in all snapStream but the latest one.
-				// Delete all snapshots beyond limit set by ssr.maxBackups.
-				for fullSnapshotIndex := 0; fullSnapshotIndex < len(fullSnapshotIndexList)-1; fullSnapshotIndex++ {
-					snapStream := snapList[fullSnapshotIndexList[fullSnapshotIndex]:fullSnapshotIndexList[fullSnapshotIndex+1]]
-					numDeletedSnapshots, err := ssr.GarbageCollectDeltaSnapshots(snapStream)
-					total += numDeletedSnapshots
-					if err != nil {
-						continue
-					}
-					// #nosec G115 -- validated for size to be lesser than MaxInt.
-					if fullSnapshotIndex < len(fullSnapshotIndexList)-int(ssr.config.MaxBackups) {
-						snap := snapList[fullSnapshotIndexList[fullSnapshotIndex]]
-						snapPath := path.Join(snap.SnapDir, snap.SnapName)
-						if !snap.IsDeletable() {
-							ssr.logger.Infof("GC: Skipping the snapshot: %s, since its immutability period hasn't expired yet", snap.SnapName)
-							continue
-						}
-						ssr.logger.Infof("GC: Deleting old full snapshot: %s", snapPath)
-						if err := ssr.store.Delete(*snap); errors.Is(err, brtypes.ErrSnapshotDeleteFailDueToImmutability) {
-							// The snapshot is still immutable, attempt to gargbage collect it in the next run
-							ssr.logger.Warnf("GC: Skipping the snapshot: %s, since it is still immutable", snapPath)
-							continue
-						} else if err != nil {
-							ssr.logger.Warnf("GC: Failed to delete snapshot %s: %v", snapPath, err)
-							metrics.SnapshotterOperationFailure.With(prometheus.Labels{metrics.LabelError: err.Error()}).Inc()
-							metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Inc()
-							continue
-						}
-						metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Inc()
-						total++
-					}
-				}
+			if threshold == 0 || hourChange/threshold != 0 || dayChange*24/threshold != 0 || weekChange*24*7/threshold != 0 {
+				// The change in parameter was more than the threshold, so don't delete the snapshot
+				retained[nextSnap] = reason
+			}
+		}
+
+	case brtypes.GarbageCollectionPolicyLimitBased:
+		// Keep the full snapshots within the limit set by ssr.config.MaxBackups.
+		for fullSnapshotIndex := 0; fullSnapshotIndex < len(fullSnapshotIndexList)-1; fullSnapshotIndex++ {
+			// #nosec G115 -- validated for size to be lesser than MaxInt.
+			if fullSnapshotIndex >= len(fullSnapshotIndexList)-int(config.MaxBackups) {
+				retained[snapList[fullSnapshotIndexList[fullSnapshotIndex]]] = fmt.Sprintf("one of the latest %d full snapshots", config.MaxBackups)
 			}
-			ssr.logger.Infof("GC: Total number garbage collected snapshots: %d", total)
+		}
+
+	case brtypes.GarbageCollectionPolicyGFS:
+		// Keep the latest full snapshot of each of the most recent hours, days, weeks, months and years
+		// as configured by ssr.config.GFSRetention.
+		for snap, tiers := range gfsRetainedFullSnapshots(snapList, fullSnapshotIndexList, config.GFSRetention) {
+			retained[snap] = fmt.Sprintf("retained by the %s tiers", strings.Join(tiers, ", "))
 		}
 	}
+
+	// Pinned full snapshots are retained irrespective of the garbage collection policy.
+	for fullSnapshotIndex := 0; fullSnapshotIndex < len(fullSnapshotIndexList)-1; fullSnapshotIndex++ {
+		snap := snapList[fullSnapshotIndexList[fullSnapshotIndex]]
+		if !snap.IsPinned() {
+			continue
+		}
+		if snap.PinExpiryTime.IsZero() {
+			retained[snap] = "pinned"
+		} else {
+			retained[snap] = fmt.Sprintf("pinned until %s", snap.PinExpiryTime.Format(time.RFC3339))
+		}
+	}
+	return retained
+}
+
+// garbageCollectFullSnapshot deletes the given full snapshot, and returns true if it was deleted.
+func (ssr *Snapshotter) garbageCollectFullSnapshot(snap *brtypes.Snapshot) bool {
+	snapPath := path.Join(snap.SnapDir, snap.SnapName)
+	if !snap.IsDeletable() {
+		ssr.logger.Infof("GC: Skipping the snapshot: %s, since its immutability period hasn't expired yet", snap.SnapName)
+		return false
+	}
+	ssr.logger.Infof("GC: Deleting old full snapshot: %s", snapPath)
+	if err := ssr.store.Delete(*snap); errors.Is(err, brtypes.ErrSnapshotDeleteFailDueToImmutability) {
+		// The snapshot is still immutable, attempt to gargbage collect it in the next run
+		ssr.logger.Warnf("GC: Skipping the snapshot: %s, since it is still immutable", snapPath)
+		return false
+	} else if err != nil {
+		ssr.logger.Warnf("GC: Failed to delete snapshot %s: %v", snapPath, err)
+		metrics.SnapshotterOperationFailure.With(prometheus.Labels{metrics.LabelError: err.Error()}).Inc()
+		metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Inc()
+		return false
+	}
+	metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull, metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Inc()
+	ssr.garbageCollectSnapshotSidecars(snap)
+	ssr.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotDeleted, Snapshot: snap})
+	return true
+}
+
+// garbageCollectSnapshotSidecars deletes the pin marker and the labels of the given deleted snapshot.
+func (ssr *Snapshotter) garbageCollectSnapshotSidecars(snap *brtypes.Snapshot) {
+	snapPath := path.Join(snap.SnapDir, snap.SnapName)
+	if snap.Pinned {
+		// The pin of the snapshot has expired, remove its pin marker along with it.
+		if err := snapstore.UnpinSnapshot(ssr.store, snap); err != nil {
+			ssr.logger.Warnf("GC: Failed to remove the expired pin of snapshot %s: %v", snapPath, err)
+		}
+	}
+	if snap.HasLabels {
+		if err := snapstore.DeleteSnapshotLabels(ssr.store, snap); err != nil {
+			ssr.logger.Warnf("GC: Failed to remove the labels of snapshot %s: %v", snapPath, err)
+		}
+	}
+}
+
+// gfsTier is a tier of the GFS garbage collection policy, which keeps the latest full snapshot of each of the most recent periods.
+type gfsTier struct {
+	name   string
+	count  uint
+	period func(t time.Time) string
+}
+
+// gfsTiers returns the tiers of the GFS garbage collection policy for the given retention.
+func gfsTiers(retention brtypes.GFSRetention) []gfsTier {
+	return []gfsTier{
+		{name: "hourly", count: retention.Hourly, period: func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{name: "daily", count: retention.Daily, period: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{name: "weekly", count: retention.Weekly, period: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{name: "monthly", count: retention.Monthly, period: func(t time.Time) string { return t.Format("2006-01") }},
+		{name: "yearly", count: retention.Yearly, period: func(t time.Time) string { return t.Format("2006") }},
+	}
+}
+
+// gfsRetainedFullSnapshots returns the full snapshots which are kept by the GFS garbage collection policy, mapped to the names of the tiers keeping them.
+// For every tier, the latest full snapshot of each of the most recent periods which have full snapshots is kept, up to the configured number of periods.
+func gfsRetainedFullSnapshots(snapList brtypes.SnapList, fullSnapshotIndexList []int, retention brtypes.GFSRetention) map[*brtypes.Snapshot][]string {
+	retained := make(map[*brtypes.Snapshot][]string)
+	for _, tier := range gfsTiers(retention) {
+		var (
+			kept       uint
+			lastPeriod string
+		)
+		for i := len(fullSnapshotIndexList) - 1; i >= 0 && kept < tier.count; i-- {
+			snap := snapList[fullSnapshotIndexList[i]]
+			if snap.Kind != brtypes.SnapshotKindFull {
+				continue
+			}
+			if period := tier.period(snap.CreatedOn.UTC()); period != lastPeriod {
+				retained[snap] = append(retained[snap], tier.name)
+				lastPeriod = period
+				kept++
+			}
+		}
+	}
+	return retained
+}
+
+// withoutChunks returns the snapshots of the given list which are not chunks.
+func withoutChunks(snapList brtypes.SnapList) brtypes.SnapList {
+	var filteredSnapList brtypes.SnapList
+	for _, snap := range snapList {
+		if !snap.IsChunk {
+			filteredSnapList = append(filteredSnapList, snap)
+		}
+	}
+	return filteredSnapList
 }
 
 // getFullSnapshotIndexList returns the indices of Full snapshots in the snapList.
@@ -235,7 +335,7 @@ func (ssr *Snapshotter) GarbageCollectChunks(snapList brtypes.SnapList) (int, br
 			continue
 		}
 		// Skip the chunk deletion if it's corresponding full/delta snapshot is not uploaded yet
-		if ssr.PrevSnapshot.LastRevision == 0 || snap.StartRevision > ssr.PrevSnapshot.LastRevision {
+		if ssr.isChunkOfSnapshotInUpload(snap) {
 			continue
 		}
 		// delete the chunk object
@@ -261,6 +361,11 @@ func (ssr *Snapshotter) GarbageCollectChunks(snapList brtypes.SnapList) (int, br
 	return chunksDeleted, nonChunkSnapList
 }
 
+// isChunkOfSnapshotInUpload returns true if the given chunk may belong to a snapshot which is not uploaded yet.
+func (ssr *Snapshotter) isChunkOfSnapshotInUpload(chunk *brtypes.Snapshot) bool {
+	return ssr.PrevSnapshot.LastRevision == 0 || chunk.StartRevision > ssr.PrevSnapshot.LastRevision
+}
+
 /*
 GarbageCollectDeltaSnapshots traverses the list of snapshots and removes delta snapshots that are older than the retention period specified in the Snapshotter's configuration.
 
@@ -275,7 +380,7 @@ Returns:
 */
 func (ssr *Snapshotter) GarbageCollectDeltaSnapshots(snapStream brtypes.SnapList) (int, error) {
 	totalDeleted := 0
-	cutoffTime := time.Now().UTC().Add(-ssr.config.DeltaSnapshotRetentionPeriod.Duration)
+	cutoffTime := time.Now().UTC().Add(-ssr.currentConfig().DeltaSnapshotRetentionPeriod.Duration)
 	var finalError error
 	for i, errorCount := len(snapStream)-1, 0; i >= 0; i-- {
 		if (*snapStream[i]).Kind == brtypes.SnapshotKindDelta && snapStream[i].CreatedOn.Before(cutoffTime) {
@@ -301,6 +406,8 @@ func (ssr *Snapshotter) GarbageCollectDeltaSnapshots(snapStream brtypes.SnapList
 				}
 			} else {
 				metrics.GCSnapshotCounter.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta, metrics.LabelSucceeded: metrics.ValueSucceededTrue}).Inc()
+				ssr.garbageCollectSnapshotSidecars(snapStream[i])
+				ssr.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotDeleted, Snapshot: snapStream[i]})
 				totalDeleted++
 			}
 		}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// GarbageCollectionAction is the action of the garbage collector on a snapshot.
+type GarbageCollectionAction string
+
+const (
+	// GarbageCollectionActionDelete indicates that the snapshot is deleted.
+	GarbageCollectionActionDelete GarbageCollectionAction = "Delete"
+	// GarbageCollectionActionKeep indicates that the snapshot is retained by the garbage collection policy.
+	GarbageCollectionActionKeep GarbageCollectionAction = "Keep"
+	// GarbageCollectionActionSkip indicates that the snapshot is not retained by the garbage collection policy, but cannot be deleted.
+	GarbageCollectionActionSkip GarbageCollectionAction = "Skip"
+)
+
+// GarbageCollectionDecision is the decision of the garbage collector on a snapshot.
+type GarbageCollectionDecision struct {
+	Snapshot  string                  `json:"snapshot"`
+	Kind      string                  `json:"kind"`
+	CreatedOn time.Time               `json:"createdOn"`
+	Action    GarbageCollectionAction `json:"action"`
+	Reason    string                  `json:"reason"`
+}
+
+// GarbageCollectionReport is the result of evaluating the garbage collection policy against the snapshots in the store.
+type GarbageCollectionReport struct {
+	Policy      string                      `json:"policy"`
+	EvaluatedOn time.Time                   `json:"evaluatedOn"`
+	Delete      int                         `json:"delete"`
+	Keep        int                         `json:"keep"`
+	Skip        int                         `json:"skip"`
+	Snapshots   []GarbageCollectionDecision `json:"snapshots"`
+}
+
+// add adds the decision on the given snapshot to the report.
+func (r *GarbageCollectionReport) add(snap *brtypes.Snapshot, action GarbageCollectionAction, reason string) {
+	kind := snap.Kind
+	if snap.IsChunk {
+		kind = brtypes.SnapshotKindChunk
+	}
+	r.Snapshots = append(r.Snapshots, GarbageCollectionDecision{
+		Snapshot:  path.Join(snap.SnapDir, snap.SnapName),
+		Kind:      kind,
+		CreatedOn: snap.CreatedOn,
+		Action:    action,
+		Reason:    reason,
+	})
+	switch action {
+	case GarbageCollectionActionDelete:
+		r.Delete++
+	case GarbageCollectionActionKeep:
+		r.Keep++
+	case GarbageCollectionActionSkip:
+		r.Skip++
+	}
+}
+
+// addGarbage adds the decision on the given snapshot, which is not retained by the garbage collection policy, to the report.
+func (r *GarbageCollectionReport) addGarbage(snap *brtypes.Snapshot, reason string) {
+	if !snap.IsDeletable() {
+		r.add(snap, GarbageCollectionActionSkip, "its immutability period has not expired yet")
+		return
+	}
+	r.add(snap, GarbageCollectionActionDelete, reason)
+}
+
+// GarbageCollectionDryRun evaluates the garbage collection policy against all snapshots in the store without deleting any of them.
+func (ssr *Snapshotter) GarbageCollectionDryRun() (*GarbageCollectionReport, error) {
+	store, err := snapstore.GetSnapstore(ssr.snapstoreConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+	}
+	snapList, err := store.List(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+	return ssr.EvaluateGarbageCollection(snapList, time.Now().UTC()), nil
+}
+
+// EvaluateGarbageCollection returns the decisions of the garbage collector on the given snapshots at the given time.
+// The snapshots are expected to be sorted as returned by SnapStore.List.
+func (ssr *Snapshotter) EvaluateGarbageCollection(snapList brtypes.SnapList, now time.Time) *GarbageCollectionReport {
+	config := ssr.currentConfig()
+	report := &GarbageCollectionReport{
+		Policy:      config.GarbageCollectionPolicy,
+		EvaluatedOn: now,
+		Snapshots:   []GarbageCollectionDecision{},
+	}
+
+	for _, snap := range snapList {
+		switch {
+		case !snap.IsChunk:
+			continue
+		case ssr.snapstoreConfig.Provider == brtypes.SnapstoreProviderSwift:
+			report.add(snap, GarbageCollectionActionSkip, "chunks are deleted along with their snapshot for this storage provider")
+		case ssr.isChunkOfSnapshotInUpload(snap):
+			report.add(snap, GarbageCollectionActionSkip, "its snapshot might still be uploading")
+		default:
+			report.addGarbage(snap, "its snapshot is already uploaded")
+		}
+	}
+	snapList = withoutChunks(snapList)
+	if len(snapList) == 0 {
+		return report
+	}
+
+	fullSnapshotIndexList := getFullSnapshotIndexList(snapList)
+	retained := ssr.retainedFullSnapshots(snapList, fullSnapshotIndexList, now)
+	cutoffTime := now.Add(-config.DeltaSnapshotRetentionPeriod.Duration)
+	latestSnapStreamIndex := fullSnapshotIndexList[len(fullSnapshotIndexList)-1]
+	for index, snap := range snapList {
+		switch {
+		case index >= latestSnapStreamIndex:
+			report.add(snap, GarbageCollectionActionKeep, "it belongs to the latest snapStream")
+		case snap.Kind == brtypes.SnapshotKindDelta && !snap.CreatedOn.Before(cutoffTime):
+			report.add(snap, GarbageCollectionActionKeep, "it is within the delta snapshot retention period")
+		case snap.Kind == brtypes.SnapshotKindDelta:
+			report.addGarbage(snap, "it is older than the delta snapshot retention period")
+		case len(retained[snap]) != 0:
+			report.add(snap, GarbageCollectionActionKeep, fmt.Sprintf("it is %s", retained[snap]))
+		default:
+			report.addGarbage(snap, fmt.Sprintf("it is not retained by the %s garbage collection policy", config.GarbageCollectionPolicy))
+		}
+	}
+	return report
+}
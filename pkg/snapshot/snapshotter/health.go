@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+const (
+	// fullSnapshotGracePeriod is the time a scheduled full snapshot may take before it is reported as overdue.
+	fullSnapshotGracePeriod = 30 * time.Minute
+	// deltaSnapshotOverdueFactor is the number of delta snapshot periods after which the events since the previous
+	// snapshot are reported as overdue, if they have not been saved.
+	deltaSnapshotOverdueFactor = 2
+)
+
+// garbageCollectionResult is the outcome of a garbage collection run.
+type garbageCollectionResult struct {
+	finishedOn  time.Time
+	lastSuccess time.Time
+	deleted     int
+	err         error
+}
+
+// recordEventsSaved records that the events since the previous snapshot have been saved, or that there were none.
+func (ssr *Snapshotter) recordEventsSaved() {
+	ssr.healthMutex.Lock()
+	defer ssr.healthMutex.Unlock()
+	ssr.eventsSavedOn = time.Now().UTC()
+}
+
+// recordGarbageCollection records the outcome of a garbage collection run.
+func (ssr *Snapshotter) recordGarbageCollection(deleted int, err error) {
+	ssr.healthMutex.Lock()
+	defer ssr.healthMutex.Unlock()
+	result := &garbageCollectionResult{finishedOn: time.Now().UTC(), deleted: deleted, err: err}
+	if ssr.lastGarbageCollection != nil {
+		result.lastSuccess = ssr.lastGarbageCollection.lastSuccess
+	}
+	if err == nil {
+		result.lastSuccess = result.finishedOn
+	}
+	ssr.lastGarbageCollection = result
+}
+
+// FullSnapshotHealth returns the health of the full snapshots, comparing the age of the latest full snapshot with the
+// full snapshot schedule.
+func (ssr *Snapshotter) FullSnapshotHealth(now time.Time) brtypes.ComponentHealth {
+	prevFullSnapshot := ssr.PrevFullSnapshot
+	if prevFullSnapshot == nil {
+		if !ssr.PrevFullSnapshotSucceeded {
+			return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnhealthy, Message: "taking the first full snapshot has failed"}
+		}
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "no full snapshot has been taken yet"}
+	}
+
+	createdOn := prevFullSnapshot.CreatedOn
+	health := brtypes.ComponentHealth{
+		Status:      brtypes.ComponentStatusHealthy,
+		Message:     fmt.Sprintf("latest full snapshot %s was taken at %s", path.Join(prevFullSnapshot.SnapDir, prevFullSnapshot.SnapName), createdOn.Format(time.RFC3339)),
+		LastSuccess: &createdOn,
+	}
+	due := ssr.currentSchedule().Next(createdOn)
+	switch {
+	case !ssr.PrevFullSnapshotSucceeded:
+		health.Status = brtypes.ComponentStatusUnhealthy
+		health.Message = "taking the scheduled full snapshot has failed, " + health.Message
+	case due.IsZero():
+		health.Message += ", no further full snapshot is scheduled"
+	case ssr.IsPaused():
+		health.Message += fmt.Sprintf(", the next one was due at %s but snapshotting is paused", due.Format(time.RFC3339))
+	case now.After(due.Add(fullSnapshotGracePeriod)):
+		health.Status = brtypes.ComponentStatusUnhealthy
+		health.Message += fmt.Sprintf(", the next one is overdue since %s", due.Format(time.RFC3339))
+	default:
+		health.Message += fmt.Sprintf(", the next one is due at %s", due.Format(time.RFC3339))
+	}
+	return health
+}
+
+// DeltaSnapshotHealth returns the health of the delta snapshots, comparing the time at which the events since the
+// previous snapshot have last been saved with the delta snapshot period. Delta snapshots are skipped while there are no
+// events, so the age of the latest delta snapshot alone does not tell whether they are overdue.
+func (ssr *Snapshotter) DeltaSnapshotHealth(now time.Time) brtypes.ComponentHealth {
+	period := ssr.currentConfig().DeltaSnapshotPeriod.Duration
+	if period < brtypes.DeltaSnapshotIntervalThreshold {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "delta snapshots are disabled"}
+	}
+
+	ssr.healthMutex.Lock()
+	savedOn := ssr.eventsSavedOn
+	ssr.healthMutex.Unlock()
+	if savedOn.IsZero() {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "no snapshot has been taken since the snapshotter started"}
+	}
+
+	health := brtypes.ComponentHealth{
+		Status:      brtypes.ComponentStatusHealthy,
+		Message:     fmt.Sprintf("events up to revision %d were last saved at %s, delta snapshots are taken every %s", ssr.PrevSnapshot.LastRevision, savedOn.Format(time.RFC3339), period),
+		LastSuccess: &savedOn,
+	}
+	switch {
+	case ssr.IsPaused():
+		health.Message += ", but snapshotting is paused"
+	case now.Sub(savedOn) > deltaSnapshotOverdueFactor*period:
+		health.Status = brtypes.ComponentStatusUnhealthy
+		health.Message = fmt.Sprintf("events since revision %d have not been saved since %s, delta snapshots are expected every %s", ssr.PrevSnapshot.LastRevision, savedOn.Format(time.RFC3339), period)
+	}
+	if ssr.spool != nil && ssr.spool.Len() != 0 {
+		health.Message += fmt.Sprintf(", %d delta snapshots of %d bytes are spooled waiting to be uploaded", ssr.spool.Len(), ssr.spool.Size())
+	}
+	return health
+}
+
+// GarbageCollectionHealth returns the health of the garbage collection, based on the outcome of its latest run.
+func (ssr *Snapshotter) GarbageCollectionHealth() brtypes.ComponentHealth {
+	if gcPeriod := ssr.currentConfig().GarbageCollectionPeriod; gcPeriod.Duration <= time.Second {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusDisabled, Message: "garbage collection is disabled"}
+	}
+
+	ssr.healthMutex.Lock()
+	defer ssr.healthMutex.Unlock()
+	result := ssr.lastGarbageCollection
+	if result == nil {
+		return brtypes.ComponentHealth{Status: brtypes.ComponentStatusUnknown, Message: "garbage collection has not run yet"}
+	}
+	health := brtypes.ComponentHealth{Status: brtypes.ComponentStatusHealthy}
+	if !result.lastSuccess.IsZero() {
+		lastSuccess := result.lastSuccess
+		health.LastSuccess = &lastSuccess
+	}
+	if result.err != nil {
+		health.Status = brtypes.ComponentStatusUnhealthy
+		health.Message = fmt.Sprintf("garbage collection at %s failed: %v", result.finishedOn.Format(time.RFC3339), result.err)
+		return health
+	}
+	health.Message = fmt.Sprintf("garbage collection at %s deleted %d snapshots", result.finishedOn.Format(time.RFC3339), result.deleted)
+	return health
+}
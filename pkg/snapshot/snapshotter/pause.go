@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/events"
+	"github.com/gardener/etcd-backup-restore/pkg/metrics"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrSnapshottingPaused is the error returned when triggering a snapshot while the snapshotting is paused.
+var ErrSnapshottingPaused = errors.New("snapshotting is paused")
+
+// Pause pauses the scheduled full and delta snapshots and the garbage collection, so that the snapshotter stops writing
+// to the snapstore. Out-of-schedule snapshots are rejected with ErrSnapshottingPaused while paused. Unless the given
+// deadline is zero, the snapshotting is resumed automatically at the deadline. Pausing again while paused replaces the
+// deadline. It returns the resulting pause status.
+func (ssr *Snapshotter) Pause(until time.Time) (*brtypes.PauseStatus, error) {
+	now := time.Now().UTC()
+	if !until.IsZero() && !until.After(now) {
+		return nil, fmt.Errorf("pause deadline %s is not in the future", until.Format(time.RFC3339))
+	}
+
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	ssr.pause(now, until)
+	return ssr.copyPauseStatus(), nil
+}
+
+// RestorePauseStatus applies the given pause status of a previous snapshotter, e.g. the one of a previous leader,
+// keeping the time the snapshotting has been paused at. A pause whose deadline has passed in the meantime is not applied.
+func (ssr *Snapshotter) RestorePauseStatus(status brtypes.PauseStatus) {
+	now := time.Now().UTC()
+	if !status.Paused || (status.Until != nil && !status.Until.After(now)) {
+		return
+	}
+	since := now
+	if status.Since != nil {
+		since = status.Since.UTC()
+	}
+	var until time.Time
+	if status.Until != nil {
+		until = *status.Until
+	}
+
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	ssr.logger.Infof("Restoring pause of snapshotting since %s.", since)
+	ssr.pause(since, until)
+}
+
+// Resume resumes the scheduled full and delta snapshots and the garbage collection. The events since the previous
+// snapshot are collected again, and a full snapshot scheduled while paused is taken right away. It returns the
+// resulting pause status.
+func (ssr *Snapshotter) Resume() *brtypes.PauseStatus {
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	ssr.resume()
+	return ssr.copyPauseStatus()
+}
+
+// GetPauseStatus returns the pause status of the snapshotter.
+func (ssr *Snapshotter) GetPauseStatus() *brtypes.PauseStatus {
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	return ssr.copyPauseStatus()
+}
+
+// IsPaused returns true if the snapshotting is paused.
+func (ssr *Snapshotter) IsPaused() bool {
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	return ssr.pauseStatus.Paused
+}
+
+// WaitUntilResumed blocks while the snapshotting is paused. It returns false if the given stop channel has been closed
+// before the snapshotting has been resumed.
+func (ssr *Snapshotter) WaitUntilResumed(stopCh <-chan struct{}) bool {
+	ssr.pauseMutex.Lock()
+	resumedCh := ssr.resumedCh
+	ssr.pauseMutex.Unlock()
+	if resumedCh == nil {
+		return true
+	}
+	ssr.logger.Info("Waiting for snapshotting to be resumed...")
+	select {
+	case <-resumedCh:
+		return true
+	case <-stopCh:
+		return false
+	}
+}
+
+// pause pauses the snapshotting, which has been paused at the given time if it is not paused yet. Unless the given
+// deadline is zero, the snapshotting is resumed automatically at the deadline. The caller must hold the pause mutex.
+func (ssr *Snapshotter) pause(since, until time.Time) {
+	if !ssr.pauseStatus.Paused {
+		ssr.logger.Info("Pausing snapshotting and garbage collection...")
+		ssr.pauseStatus = brtypes.PauseStatus{Paused: true, Since: &since}
+		ssr.resumedCh = make(chan struct{})
+	}
+	ssr.stopPauseDeadlineTimer()
+	ssr.pauseStatus.Until = nil
+	if !until.IsZero() {
+		until = until.UTC()
+		ssr.pauseStatus.Until = &until
+		ssr.pauseDeadlineTimer = time.AfterFunc(time.Until(until), ssr.resumeAtDeadline)
+		ssr.logger.Infof("Snapshotting will be resumed at %s.", until)
+	}
+	ssr.publishPauseStatus()
+}
+
+// resumeAtDeadline resumes the snapshotting once the deadline of the pause has passed.
+func (ssr *Snapshotter) resumeAtDeadline() {
+	ssr.pauseMutex.Lock()
+	defer ssr.pauseMutex.Unlock()
+	// the pause might have been resumed or given another deadline in the meantime
+	if !ssr.pauseStatus.Paused || ssr.pauseStatus.Until == nil || time.Now().Before(*ssr.pauseStatus.Until) {
+		return
+	}
+	ssr.logger.Info("Deadline of the pause has passed.")
+	ssr.resume()
+}
+
+// resume resumes the snapshotting if it is paused. The caller must hold the pause mutex.
+func (ssr *Snapshotter) resume() {
+	if !ssr.pauseStatus.Paused {
+		return
+	}
+	ssr.logger.Info("Resuming snapshotting and garbage collection...")
+	ssr.stopPauseDeadlineTimer()
+	ssr.pauseStatus = brtypes.PauseStatus{}
+	close(ssr.resumedCh)
+	ssr.resumedCh = nil
+	ssr.publishPauseStatus()
+}
+
+// stopPauseDeadlineTimer stops the timer resuming the snapshotting at the deadline of the pause, if any. The caller
+// must hold the pause mutex.
+func (ssr *Snapshotter) stopPauseDeadlineTimer() {
+	if ssr.pauseDeadlineTimer != nil {
+		ssr.pauseDeadlineTimer.Stop()
+		ssr.pauseDeadlineTimer = nil
+	}
+}
+
+// publishPauseStatus updates the metrics and publishes the event for the pause status, passes it to the pause status
+// callback, and signals the snapshot event handler to apply it. The caller must hold the pause mutex.
+func (ssr *Snapshotter) publishPauseStatus() {
+	event := events.Event{Type: events.TypeSnapshottingResumed}
+	metrics.SnapshotterPaused.With(prometheus.Labels{}).Set(0)
+	metrics.SnapshotterPausedUntilTimestamp.With(prometheus.Labels{}).Set(0)
+	if ssr.pauseStatus.Paused {
+		event = events.Event{Type: events.TypeSnapshottingPaused}
+		metrics.SnapshotterPaused.With(prometheus.Labels{}).Set(1)
+		if ssr.pauseStatus.Until != nil {
+			event.Message = fmt.Sprintf("paused until %s", ssr.pauseStatus.Until.Format(time.RFC3339))
+			metrics.SnapshotterPausedUntilTimestamp.With(prometheus.Labels{}).Set(float64(ssr.pauseStatus.Until.Unix()))
+		}
+	}
+	ssr.EventBroadcaster.Publish(event)
+	if ssr.OnPauseStatusChange != nil {
+		ssr.OnPauseStatusChange(*ssr.copyPauseStatus())
+	}
+	// the watch and the timers are owned by the snapshot event handler, a pending update already applies the latest status
+	select {
+	case ssr.pauseUpdateCh <- struct{}{}:
+	default:
+	}
+}
+
+// copyPauseStatus returns a copy of the pause status. The caller must hold the pause mutex.
+func (ssr *Snapshotter) copyPauseStatus() *brtypes.PauseStatus {
+	status := ssr.pauseStatus
+	return &status
+}
+
+// applyPauseStatus stops or restarts the collection of the events to match the pause status. It is called by the
+// snapshot event handler, which owns the etcd watch and the timers.
+func (ssr *Snapshotter) applyPauseStatus() error {
+	paused := ssr.IsPaused()
+	if paused == ssr.watchPaused {
+		return nil
+	}
+	ssr.watchPaused = paused
+	if paused {
+		// the events which have not been saved yet are collected again once resumed
+		ssr.closeEtcdClient()
+		ssr.cleanupInMemoryEvents()
+		ssr.logger.Infof("Paused snapshotting after revision %d.", ssr.PrevSnapshot.LastRevision)
+		return nil
+	}
+
+	deltaSnapshotPeriod := ssr.currentConfig().DeltaSnapshotPeriod.Duration
+	if deltaSnapshotPeriod >= brtypes.DeltaSnapshotIntervalThreshold {
+		if err := ssr.watchEventsSincePrevSnapshot(); err != nil {
+			return err
+		}
+		ssr.deltaSnapshotTimer.Stop()
+		ssr.deltaSnapshotTimer.Reset(deltaSnapshotPeriod)
+	}
+	if ssr.fullSnapshotMissed {
+		ssr.fullSnapshotMissed = false
+		ssr.logger.Info("Taking the full snapshot scheduled while snapshotting was paused...")
+		ssr.fullSnapshotTimer.Stop()
+		ssr.fullSnapshotTimer.Reset(0)
+	}
+	ssr.logger.Infof("Resumed snapshotting after revision %d.", ssr.PrevSnapshot.LastRevision)
+	return nil
+}
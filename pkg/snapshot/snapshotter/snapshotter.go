@@ -5,12 +5,8 @@
 package snapshotter
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/json"
 	"fmt"
-	"io"
 	"path"
 	"strconv"
 	"strings"
@@ -20,9 +16,13 @@ import (
 	"github.com/gardener/etcd-backup-restore/pkg/compressor"
 	"github.com/gardener/etcd-backup-restore/pkg/errors"
 	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	etcdclient "github.com/gardener/etcd-backup-restore/pkg/etcdutil/client"
+	"github.com/gardener/etcd-backup-restore/pkg/events"
 	"github.com/gardener/etcd-backup-restore/pkg/health/heartbeat"
 	"github.com/gardener/etcd-backup-restore/pkg/metrics"
 	"github.com/gardener/etcd-backup-restore/pkg/miscellaneous"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/spool"
 	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
 	"github.com/gardener/etcd-backup-restore/pkg/wrappers"
@@ -30,6 +30,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	cron "github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -47,12 +48,6 @@ var (
 	emptyStruct struct{}
 )
 
-// event is wrapper over etcd event to keep track of time of event
-type event struct {
-	EtcdEvent *clientv3.Event `json:"etcdEvent"`
-	Time      time.Time       `json:"time"`
-}
-
 type result struct {
 	Snapshot *brtypes.Snapshot `json:"snapshot"`
 	Err      error             `json:"error"`
@@ -61,18 +56,27 @@ type result struct {
 // NewSnapshotterConfig returns the snapshotter config.
 func NewSnapshotterConfig() *brtypes.SnapshotterConfig {
 	return &brtypes.SnapshotterConfig{
-		FullSnapshotSchedule:     brtypes.DefaultFullSnapshotSchedule,
-		DeltaSnapshotPeriod:      wrappers.Duration{Duration: brtypes.DefaultDeltaSnapshotInterval},
-		DeltaSnapshotMemoryLimit: brtypes.DefaultDeltaSnapMemoryLimit,
-		GarbageCollectionPeriod:  wrappers.Duration{Duration: brtypes.DefaultGarbageCollectionPeriod},
-		GarbageCollectionPolicy:  brtypes.GarbageCollectionPolicyExponential,
-		MaxBackups:               brtypes.DefaultMaxBackups,
+		FullSnapshotSchedule:        brtypes.DefaultFullSnapshotSchedule,
+		DeltaSnapshotPeriod:         wrappers.Duration{Duration: brtypes.DefaultDeltaSnapshotInterval},
+		DeltaSnapshotMemoryLimit:    brtypes.DefaultDeltaSnapMemoryLimit,
+		GarbageCollectionPeriod:     wrappers.Duration{Duration: brtypes.DefaultGarbageCollectionPeriod},
+		GarbageCollectionPolicy:     brtypes.GarbageCollectionPolicyExponential,
+		MaxBackups:                  brtypes.DefaultMaxBackups,
+		GFSRetention:                brtypes.NewGFSRetention(),
+		DeltaSnapshotSpoolSizeLimit: brtypes.DefaultDeltaSnapshotSpoolSizeLimit,
+		DeltaSnapshotSpillSizeLimit: brtypes.DefaultDeltaSnapshotSpillSizeLimit,
+		DeltaSnapshotFormat:         brtypes.DeltaSnapshotFormatJSON,
 	}
 }
 
 // Snapshotter is a struct for etcd snapshot taker
 type Snapshotter struct {
 	lastSecretModifiedTime       time.Time
+	configMutex                  sync.RWMutex
+	pauseMutex                   sync.Mutex
+	pauseStatus                  brtypes.PauseStatus
+	pauseDeadlineTimer           *time.Timer
+	resumedCh                    chan struct{}
 	schedule                     cron.Schedule
 	store                        brtypes.SnapStore
 	K8sClientset                 client.Client
@@ -86,8 +90,11 @@ type Snapshotter struct {
 	deltaSnapshotReqCh           chan struct{}
 	fullSnapshotAckCh            chan result
 	deltaSnapshotAckCh           chan result
+	configUpdateCh               chan struct{}
+	pauseUpdateCh                chan struct{}
 	logger                       *logrus.Entry
 	HealthConfig                 *brtypes.HealthConfig
+	EventBroadcaster             *events.Broadcaster
 	deltaSnapshotTimer           *time.Timer
 	snapstoreConfig              *brtypes.SnapstoreConfig
 	watchCh                      clientv3.WatchChan
@@ -95,11 +102,31 @@ type Snapshotter struct {
 	cancelWatch                  context.CancelFunc
 	SsrStateMutex                *sync.RWMutex
 	config                       *brtypes.SnapshotterConfig
-	events                       []byte
 	PrevDeltaSnapshots           brtypes.SnapList
 	lastEventRevision            int64
 	SnapshotterStateActive       bool
 	PrevFullSnapshotSucceeded    bool
+	// OnPauseStatusChange is called with the new pause status whenever it changes, e.g. to persist it. It is called while
+	// the pause status is locked, so it must not call the snapshotter.
+	OnPauseStatusChange func(status brtypes.PauseStatus)
+	// watchPaused and fullSnapshotMissed are owned by the snapshot event handler, which stops watching the events while
+	// the snapshotting is paused, and takes a full snapshot scheduled while paused once resumed.
+	watchPaused        bool
+	fullSnapshotMissed bool
+	// events holds the events collected since the previous snapshot, encoded by the events encoder, which is nil while
+	// no event has been collected.
+	events        *eventBuffer
+	eventsEncoder delta.Encoder
+	// eventLeaseIDs are the IDs of the leases of the events collected since the previous snapshot. etcdLeaseClient
+	// looks them up when the delta snapshot is taken, it is created along with the first lookup.
+	eventLeaseIDs   map[int64]struct{}
+	etcdLeaseClient etcdclient.LeaseCloser
+	// spool keeps the delta snapshots which cannot be saved to the snapstore until they are uploaded, nil if not enabled.
+	spool *spool.Spool
+	// healthMutex guards the outcomes recorded for the health report.
+	healthMutex           sync.Mutex
+	eventsSavedOn         time.Time
+	lastGarbageCollection *garbageCollectionResult
 }
 
 // NewSnapshotter returns the snapshotter object.
@@ -128,6 +155,21 @@ func NewSnapshotter(logger *logrus.Entry, config *brtypes.SnapshotterConfig, sto
 		prevSnapshot = snapstore.NewSnapshot(brtypes.SnapshotKindFull, 0, 0, "", false)
 	}
 
+	var deltaSnapshotSpool *spool.Spool
+	if len(config.DeltaSnapshotSpoolDir) != 0 {
+		var spooled brtypes.SnapList
+		deltaSnapshotSpool, spooled, err = newDeltaSnapshotSpool(logger, config, prevSnapshot)
+		if err != nil {
+			return nil, err
+		}
+		if len(spooled) != 0 {
+			// the spooled delta snapshots have been taken after the latest snapshot in the snapstore
+			deltaSnapList = append(deltaSnapList, spooled...)
+			prevSnapshot = spooled[len(spooled)-1]
+			metrics.LatestSnapshotTimestamp.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta}).Set(float64(prevSnapshot.CreatedOn.Unix()))
+		}
+	}
+
 	metrics.LatestSnapshotRevision.With(prometheus.Labels{metrics.LabelKind: prevSnapshot.Kind}).Set(float64(prevSnapshot.LastRevision))
 
 	//Attempt to create clientset only if `enable-snapshot-lease-renewal` flag of healthConfig is set
@@ -156,10 +198,13 @@ func NewSnapshotter(logger *logrus.Entry, config *brtypes.SnapshotterConfig, sto
 		deltaSnapshotReqCh:        make(chan struct{}),
 		fullSnapshotAckCh:         make(chan result),
 		deltaSnapshotAckCh:        make(chan result),
+		configUpdateCh:            make(chan struct{}, 1),
+		pauseUpdateCh:             make(chan struct{}, 1),
 		cancelWatch:               func() {},
 		K8sClientset:              clientSet,
 		snapstoreConfig:           storeConfig,
 		PrevFullSnapshotSucceeded: true,
+		spool:                     deltaSnapshotSpool,
 	}, nil
 }
 
@@ -193,9 +238,9 @@ func (ssr *Snapshotter) Run(stopCh <-chan struct{}, startWithFullSnapshot bool)
 		go ssr.RenewFullSnapshotLeasePeriodically(fullSnapshotLeaseStopCh, brtypes.FullSnapshotLeaseUpdateInterval)
 	}
 	ssr.deltaSnapshotTimer = time.NewTimer(brtypes.DefaultDeltaSnapshotInterval)
-	if ssr.config.DeltaSnapshotPeriod.Duration >= brtypes.DeltaSnapshotIntervalThreshold {
+	if deltaSnapshotPeriod := ssr.currentConfig().DeltaSnapshotPeriod.Duration; deltaSnapshotPeriod >= brtypes.DeltaSnapshotIntervalThreshold {
 		ssr.deltaSnapshotTimer.Stop()
-		ssr.deltaSnapshotTimer.Reset(ssr.config.DeltaSnapshotPeriod.Duration)
+		ssr.deltaSnapshotTimer.Reset(deltaSnapshotPeriod)
 	}
 
 	return ssr.snapshotEventHandler(stopCh)
@@ -208,6 +253,9 @@ func (ssr *Snapshotter) TriggerFullSnapshot(_ context.Context, isFinal bool) (*b
 	if !ssr.IsSnapshotterStateActive() {
 		return nil, fmt.Errorf("snapshotter is not active")
 	}
+	if ssr.IsPaused() {
+		return nil, ErrSnapshottingPaused
+	}
 	ssr.logger.Info("Triggering out of schedule full snapshot...")
 	ssr.fullSnapshotReqCh <- isFinal
 	res := <-ssr.fullSnapshotAckCh
@@ -221,8 +269,11 @@ func (ssr *Snapshotter) TriggerDeltaSnapshot() (*brtypes.Snapshot, error) {
 	if !ssr.IsSnapshotterStateActive() {
 		return nil, fmt.Errorf("snapshotter is not active")
 	}
-	if ssr.config.DeltaSnapshotPeriod.Duration < brtypes.DeltaSnapshotIntervalThreshold {
-		return nil, fmt.Errorf("found delta snapshot interval %s less than %v. Delta snapshotting is disabled. ", ssr.config.DeltaSnapshotPeriod.Duration, time.Duration(brtypes.DeltaSnapshotIntervalThreshold))
+	if ssr.IsPaused() {
+		return nil, ErrSnapshottingPaused
+	}
+	if deltaSnapshotPeriod := ssr.currentConfig().DeltaSnapshotPeriod.Duration; deltaSnapshotPeriod < brtypes.DeltaSnapshotIntervalThreshold {
+		return nil, fmt.Errorf("found delta snapshot interval %s less than %v. Delta snapshotting is disabled. ", deltaSnapshotPeriod, time.Duration(brtypes.DeltaSnapshotIntervalThreshold))
 	}
 	ssr.logger.Info("Triggering out of schedule delta snapshot...")
 	ssr.deltaSnapshotReqCh <- emptyStruct
@@ -269,7 +320,7 @@ func (ssr *Snapshotter) IsSnapshotterStateActive() bool {
 	return ssr.SnapshotterStateActive
 }
 
-// closeEtcdClient closes the Etcd watch client.
+// closeEtcdClient closes the Etcd watch client and the Etcd lease client.
 func (ssr *Snapshotter) closeEtcdClient() {
 	ssr.logger.Info("Closing the etcd watch client.")
 
@@ -285,6 +336,7 @@ func (ssr *Snapshotter) closeEtcdClient() {
 		}
 		ssr.etcdWatchClient = nil
 	}
+	ssr.closeEtcdLeaseClient()
 }
 
 // TakeFullSnapshotAndResetTimer takes a full snapshot and resets the full snapshot
@@ -296,6 +348,7 @@ func (ssr *Snapshotter) TakeFullSnapshotAndResetTimer(isFinal bool) (*brtypes.Sn
 		// As per design principle, in business critical service if backup is not working,
 		// it's better to fail the process. So, we are quiting here.
 		ssr.logger.Warnf("Taking scheduled full snapshot failed: %v", err)
+		ssr.publishSnapshotFailedEvent(brtypes.SnapshotKindFull, err)
 		return nil, err
 	}
 
@@ -380,6 +433,7 @@ func (ssr *Snapshotter) takeFullSnapshot(isFinal bool) (*brtypes.Snapshot, error
 		metrics.SnapstoreLatestDeltasRevisionsTotal.With(prometheus.Labels{}).Set(0)
 
 		ssr.logger.Infof("Successfully saved full snapshot at: %s", path.Join(s.SnapDir, s.SnapName))
+		ssr.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken, Snapshot: s})
 	}
 	// setting `snapshotRequired` to 0 for both full and delta snapshot
 	// for the following cases:
@@ -387,15 +441,24 @@ func (ssr *Snapshotter) takeFullSnapshot(isFinal bool) (*brtypes.Snapshot, error
 	// ii. Successfully took a full snapshot
 	metrics.SnapshotRequired.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull}).Set(0)
 	metrics.SnapshotRequired.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta}).Set(0)
+	ssr.recordEventsSaved()
 
-	if ssr.config.DeltaSnapshotPeriod.Duration < time.Second {
+	if ssr.currentConfig().DeltaSnapshotPeriod.Duration < time.Second {
 		// return without creating a watch on events
 		return ssr.PrevSnapshot, nil
 	}
 
-	ssrEtcdWatchClient, err := clientFactory.NewWatcher()
+	if err := ssr.watchEventsSincePrevSnapshot(); err != nil {
+		return nil, err
+	}
+	return ssr.PrevSnapshot, nil
+}
+
+// watchEventsSincePrevSnapshot watches the etcd events since the previous snapshot.
+func (ssr *Snapshotter) watchEventsSincePrevSnapshot() error {
+	ssrEtcdWatchClient, err := etcdutil.NewFactory(*ssr.etcdConnectionConfig).NewWatcher()
 	if err != nil {
-		return nil, &errors.EtcdError{
+		return &errors.EtcdError{
 			Message: fmt.Sprintf("failed to create etcd watch client for snapshotter: %v", err),
 		}
 	}
@@ -404,12 +467,18 @@ func (ssr *Snapshotter) takeFullSnapshot(isFinal bool) (*brtypes.Snapshot, error
 	ssr.etcdWatchClient = &ssrEtcdWatchClient
 	ssr.watchCh = ssrEtcdWatchClient.Watch(watchCtx, "", clientv3.WithPrefix(), clientv3.WithRev(ssr.PrevSnapshot.LastRevision+1))
 	ssr.logger.Infof("Applied watch on etcd from revision: %d", ssr.PrevSnapshot.LastRevision+1)
-
-	return ssr.PrevSnapshot, nil
+	return nil
 }
 
 func (ssr *Snapshotter) cleanupInMemoryEvents() {
-	ssr.events = []byte{}
+	if ssr.events != nil {
+		if err := ssr.events.Close(); err != nil {
+			ssr.logger.Warnf("Failed to remove spilled delta events: %v", err)
+		}
+	}
+	ssr.events = nil
+	ssr.eventsEncoder = nil
+	ssr.eventLeaseIDs = nil
 	ssr.lastEventRevision = -1
 }
 
@@ -419,16 +488,18 @@ func (ssr *Snapshotter) takeDeltaSnapshotAndResetTimer() (*brtypes.Snapshot, err
 		// As per design principle, in business critical service if backup is not working,
 		// it's better to fail the process. So, we are quiting here.
 		ssr.logger.Warnf("Taking delta snapshot failed: %v", err)
+		ssr.publishSnapshotFailedEvent(brtypes.SnapshotKindDelta, err)
 		return nil, err
 	}
 
+	config := ssr.currentConfig()
 	if ssr.deltaSnapshotTimer == nil {
-		ssr.deltaSnapshotTimer = time.NewTimer(ssr.config.DeltaSnapshotPeriod.Duration)
+		ssr.deltaSnapshotTimer = time.NewTimer(config.DeltaSnapshotPeriod.Duration)
 	} else {
 		ssr.logger.Infof("Stopping delta snapshot...")
 		ssr.deltaSnapshotTimer.Stop()
-		ssr.logger.Infof("Resetting delta snapshot to run after %s.", ssr.config.DeltaSnapshotPeriod.String())
-		ssr.deltaSnapshotTimer.Reset(ssr.config.DeltaSnapshotPeriod.Duration)
+		ssr.logger.Infof("Resetting delta snapshot to run after %s.", config.DeltaSnapshotPeriod.String())
+		ssr.deltaSnapshotTimer.Reset(config.DeltaSnapshotPeriod.Duration)
 	}
 	return s, nil
 }
@@ -439,12 +510,19 @@ func (ssr *Snapshotter) TakeDeltaSnapshot() (*brtypes.Snapshot, error) {
 	defer ssr.cleanupInMemoryEvents()
 	ssr.logger.Infof("Taking delta snapshot for time: %s", time.Now().Local())
 
-	if len(ssr.events) == 0 {
+	if ssr.eventsEncoder == nil {
 		ssr.logger.Infof("No events received to save snapshot. Skipping delta snapshot.")
 		metrics.SnapshotRequired.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta}).Set(0)
+		ssr.recordEventsSaved()
 		return nil, nil
 	}
-	ssr.events = append(ssr.events, byte(']'))
+	// ends the encoded events with their hash
+	if err := ssr.eventsEncoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode events: %v", err)
+	}
+	if err := ssr.attachLeases(); err != nil {
+		return nil, fmt.Errorf("failed to record the leases of the events: %v", err)
+	}
 
 	// Update the snapstore object before taking a delta snapshot if the credentials have changed
 	// Refer: https://github.com/gardener/etcd-backup-restore/issues/449
@@ -469,28 +547,9 @@ func (ssr *Snapshotter) TakeDeltaSnapshot() (*brtypes.Snapshot, error) {
 	}
 	snap := snapstore.NewSnapshot(brtypes.SnapshotKindDelta, ssr.PrevSnapshot.LastRevision+1, ssr.lastEventRevision, compressionSuffix, false)
 
-	// compute hash
-	hash := sha256.New()
-	if _, err := hash.Write(ssr.events); err != nil {
-		return nil, fmt.Errorf("failed to compute hash of events: %v", err)
-	}
-	ssr.events = hash.Sum(ssr.events)
-
 	startTime := time.Now()
-	rc := io.NopCloser(bytes.NewReader(ssr.events))
-
-	// if compression is enabled
-	//    then compress the snapshot.
-	if ssr.compressionConfig.Enabled {
-		ssr.logger.Info("start the Compression of delta snapshot")
-		rc, err = compressor.CompressSnapshot(rc, ssr.compressionConfig.CompressionPolicy)
-		if err != nil {
-			return nil, fmt.Errorf("unable to compress delta snapshot: %v", err)
-		}
-	}
-	defer rc.Close()
-
-	if err := ssr.store.Save(*snap, rc); err != nil {
+	spooled, err := ssr.saveDeltaSnapshot(snap)
+	if err != nil {
 		timeTaken := time.Since(startTime).Seconds()
 		metrics.SnapshotDurationSeconds.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta, metrics.LabelSucceeded: metrics.ValueSucceededFalse}).Observe(timeTaken)
 		ssr.logger.Errorf("Error saving delta snapshots. %v", err)
@@ -508,10 +567,27 @@ func (ssr *Snapshotter) TakeDeltaSnapshot() (*brtypes.Snapshot, error) {
 	metrics.SnapstoreLatestDeltasTotal.With(prometheus.Labels{}).Inc()
 	metrics.SnapstoreLatestDeltasRevisionsTotal.With(prometheus.Labels{}).Add(float64(snap.LastRevision - snap.StartRevision))
 
+	if spooled {
+		ssr.logger.Infof("Successfully spooled delta snapshot %s, it is uploaded once the snapstore is available.", snap.SnapName)
+		ssr.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken, Snapshot: snap, Message: "spooled locally, waiting to be uploaded"})
+		ssr.recordEventsSaved()
+		return snap, nil
+	}
 	ssr.logger.Infof("Successfully saved delta snapshot at: %s", path.Join(snap.SnapDir, snap.SnapName))
+	ssr.EventBroadcaster.Publish(events.Event{Type: events.TypeSnapshotTaken, Snapshot: snap})
+	ssr.recordEventsSaved()
 	return snap, nil
 }
 
+// publishSnapshotFailedEvent publishes the event that taking a snapshot of the given kind has failed with the given error.
+func (ssr *Snapshotter) publishSnapshotFailedEvent(kind string, err error) {
+	ssr.EventBroadcaster.Publish(events.Event{
+		Type:     events.TypeSnapshotFailed,
+		Snapshot: &brtypes.Snapshot{Kind: kind},
+		Message:  err.Error(),
+	})
+}
+
 // CollectEventsSincePrevSnapshot takes the first delta snapshot on etcd startup.
 func (ssr *Snapshotter) CollectEventsSincePrevSnapshot(stopCh <-chan struct{}) (bool, error) {
 	// close any previous watch and client.
@@ -596,40 +672,75 @@ func (ssr *Snapshotter) handleDeltaWatchEvents(wr clientv3.WatchResponse) error
 	if err := wr.Err(); err != nil {
 		return err
 	}
+	config := ssr.currentConfig()
+	keyFilter := config.KeyFilter()
 	// aggregate events
 	for _, ev := range wr.Events {
-		timedEvent := newEvent(ev)
-		jsonByte, err := json.Marshal(timedEvent)
-		if err != nil {
-			return fmt.Errorf("failed to marshal events to json: %v", err)
+		if !keyFilter.Includes(ev.Kv.Key) {
+			if ssr.eventsEncoder != nil && ev.Kv.ModRevision == ssr.lastEventRevision {
+				// the revision is already recorded by another event of the same transaction
+				continue
+			}
+			ev = newRevisionMarker(ev.Kv.ModRevision)
+		}
+		if ssr.eventsEncoder == nil {
+			events := newEventBuffer(ssr.logger, config.DeltaSnapshotSpillDir, int(config.DeltaSnapshotMemoryLimit)) // #nosec G115 -- validated for size to be lesser than MaxInt.
+			eventsEncoder, err := delta.NewEncoder(events, config.DeltaSnapshotFormat)
+			if err != nil {
+				return err
+			}
+			ssr.events, ssr.eventsEncoder = events, eventsEncoder
 		}
-		if len(ssr.events) == 0 {
-			ssr.events = append(ssr.events, byte('['))
-		} else {
-			ssr.events = append(ssr.events, byte(','))
+		event := newEvent(ev)
+		ssr.recordLeaseID(event)
+		if err := ssr.eventsEncoder.Encode(event); err != nil {
+			return err
 		}
-		ssr.events = append(ssr.events, jsonByte...)
 		ssr.lastEventRevision = ev.Kv.ModRevision
 		metrics.SnapshotRequired.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindFull}).Set(1)
 		metrics.SnapshotRequired.With(prometheus.Labels{metrics.LabelKind: brtypes.SnapshotKindDelta}).Set(1)
 	}
 	ssr.logger.Debugf("Added events till revision: %d", ssr.lastEventRevision)
-	// #nosec G115 -- validated for size to be lesser than MaxInt.
-	if len(ssr.events) >= int(ssr.config.DeltaSnapshotMemoryLimit) {
-		ssr.logger.Infof("Delta events memory crossed the memory limit: %d Bytes", len(ssr.events))
+	if ssr.eventsEncoder == nil {
+		return nil
+	}
+	if err := ssr.eventsEncoder.Flush(); err != nil {
+		return err
+	}
+	if len(config.DeltaSnapshotSpillDir) == 0 {
+		if ssr.events.Len() >= int64(config.DeltaSnapshotMemoryLimit) { // #nosec G115 -- validated for size to be lesser than MaxInt.
+			ssr.logger.Infof("Delta events memory crossed the memory limit: %d Bytes", ssr.events.Len())
+			_, err := ssr.takeDeltaSnapshotAndResetTimer()
+			return err
+		}
+		return nil
+	}
+	if ssr.events.Len() >= int64(config.DeltaSnapshotSpillSizeLimit) { // #nosec G115 -- validated for size to be lesser than MaxInt.
+		ssr.logger.Infof("Delta events crossed the spill size limit: %d Bytes", ssr.events.Len())
 		_, err := ssr.takeDeltaSnapshotAndResetTimer()
 		return err
 	}
 	return nil
 }
 
-func newEvent(e *clientv3.Event) *event {
-	return &event{
+func newEvent(e *clientv3.Event) *brtypes.Event {
+	return &brtypes.Event{
 		EtcdEvent: e,
 		Time:      time.Now(),
 	}
 }
 
+// newRevisionMarker returns an event without key and value, which only records the revision of filtered out events.
+// It keeps the revisions of delta snapshots contiguous, so that the restored data keeps the revisions of the original data.
+func newRevisionMarker(revision int64) *clientv3.Event {
+	return &clientv3.Event{
+		Type: mvccpb.PUT,
+		Kv: &mvccpb.KeyValue{
+			ModRevision: revision,
+		},
+	}
+}
+
 func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 	leaseUpdateCtx, leaseUpdateCancel := context.WithCancel(context.TODO())
 	defer func() {
@@ -637,9 +748,18 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 		ssr.SetSnapshotterInactive()
 	}()
 	ssr.logger.Info("Starting the Snapshot EventHandler.")
+	ssr.watchPaused = false
+	ssr.fullSnapshotMissed = false
+	if err := ssr.applyPauseStatus(); err != nil {
+		return err
+	}
 	for {
 		select {
 		case isFinal := <-ssr.fullSnapshotReqCh:
+			if ssr.IsPaused() {
+				ssr.fullSnapshotAckCh <- result{Err: ErrSnapshottingPaused}
+				break
+			}
 			s, err := ssr.TakeFullSnapshotAndResetTimer(isFinal)
 			res := result{
 				Snapshot: s,
@@ -647,8 +767,10 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 			}
 			ssr.fullSnapshotAckCh <- res
 			if err != nil {
-				ssr.PrevFullSnapshotSucceeded = false
-				return err
+				if err := ssr.handleFullSnapshotFailure(err); err != nil {
+					return err
+				}
+				break
 			}
 			ssr.PrevFullSnapshotSucceeded = true
 			if ssr.HealthConfig.SnapshotLeaseRenewalEnabled {
@@ -657,6 +779,10 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 			}
 
 		case <-ssr.deltaSnapshotReqCh:
+			if ssr.IsPaused() {
+				ssr.deltaSnapshotAckCh <- result{Err: ErrSnapshottingPaused}
+				break
+			}
 			s, err := ssr.takeDeltaSnapshotAndResetTimer()
 			res := result{
 				Snapshot: s,
@@ -675,9 +801,19 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 			}
 
 		case <-ssr.fullSnapshotTimer.C:
+			if ssr.IsPaused() {
+				ssr.logger.Info("Skipping scheduled full snapshot while snapshotting is paused.")
+				ssr.fullSnapshotMissed = true
+				if err := ssr.resetFullSnapshotTimer(); err != nil {
+					return err
+				}
+				break
+			}
 			if _, err := ssr.TakeFullSnapshotAndResetTimer(false); err != nil {
-				ssr.PrevFullSnapshotSucceeded = false
-				return err
+				if err := ssr.handleFullSnapshotFailure(err); err != nil {
+					return err
+				}
+				break
 			}
 			ssr.PrevFullSnapshotSucceeded = true
 			if ssr.HealthConfig.SnapshotLeaseRenewalEnabled {
@@ -686,7 +822,8 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 			}
 
 		case <-ssr.deltaSnapshotTimer.C:
-			if ssr.config.DeltaSnapshotPeriod.Duration >= time.Second {
+			// the delta snapshot timer is reset once the snapshotting has been resumed
+			if ssr.currentConfig().DeltaSnapshotPeriod.Duration >= time.Second && !ssr.IsPaused() {
 				if _, err := ssr.takeDeltaSnapshotAndResetTimer(); err != nil {
 					return err
 				}
@@ -703,6 +840,13 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 			if !ok {
 				return fmt.Errorf("watch channel closed")
 			}
+			if ssr.IsPaused() {
+				// stop watching right away, the events are collected again once resumed
+				if err := ssr.applyPauseStatus(); err != nil {
+					return err
+				}
+				break
+			}
 			snapshots := len(ssr.PrevDeltaSnapshots)
 			if err := ssr.handleDeltaWatchEvents(wr); err != nil {
 				return err
@@ -718,6 +862,16 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 				}
 			}
 
+		case <-ssr.configUpdateCh:
+			if err := ssr.resetTimersForConfig(); err != nil {
+				return err
+			}
+
+		case <-ssr.pauseUpdateCh:
+			if err := ssr.applyPauseStatus(); err != nil {
+				return err
+			}
+
 		case <-stopCh:
 			ssr.logger.Info("Closing the Snapshot EventHandler.")
 			ssr.cleanupInMemoryEvents()
@@ -726,9 +880,66 @@ func (ssr *Snapshotter) snapshotEventHandler(stopCh <-chan struct{}) error {
 	}
 }
 
+// UpdateConfig applies the given config to the snapshotter while it runs. The full snapshot and delta snapshot timers are
+// reset by the snapshot event handler if the full snapshot schedule or the delta snapshot period has changed. Delta
+// snapshots which have been disabled are only enabled once the next full snapshot has been taken.
+func (ssr *Snapshotter) UpdateConfig(config *brtypes.SnapshotterConfig) error {
+	sdl, err := cron.ParseStandard(config.FullSnapshotSchedule)
+	if err != nil {
+		return fmt.Errorf("invalid full snapshot schedule provided %s : %v", config.FullSnapshotSchedule, err)
+	}
+	newConfig := *config
+
+	ssr.configMutex.Lock()
+	oldConfig := ssr.config
+	ssr.config = &newConfig
+	ssr.schedule = sdl
+	ssr.configMutex.Unlock()
+
+	if oldConfig.FullSnapshotSchedule != newConfig.FullSnapshotSchedule || oldConfig.DeltaSnapshotPeriod != newConfig.DeltaSnapshotPeriod {
+		ssr.logger.Infof("Updated full snapshot schedule to %q and delta snapshot period to %s.", newConfig.FullSnapshotSchedule, newConfig.DeltaSnapshotPeriod)
+		// the timers are owned by the snapshot event handler, a pending update already resets them to the latest config
+		select {
+		case ssr.configUpdateCh <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// currentConfig returns the current config of the snapshotter, which must not be modified. The config is guarded by the
+// config mutex, as it can be updated while the snapshotter runs.
+func (ssr *Snapshotter) currentConfig() *brtypes.SnapshotterConfig {
+	ssr.configMutex.RLock()
+	defer ssr.configMutex.RUnlock()
+	return ssr.config
+}
+
+// currentSchedule returns the current full snapshot schedule of the snapshotter.
+func (ssr *Snapshotter) currentSchedule() cron.Schedule {
+	ssr.configMutex.RLock()
+	defer ssr.configMutex.RUnlock()
+	return ssr.schedule
+}
+
+// resetTimersForConfig resets the full snapshot and delta snapshot timers to the current config.
+func (ssr *Snapshotter) resetTimersForConfig() error {
+	if err := ssr.resetFullSnapshotTimer(); err != nil {
+		return err
+	}
+	deltaSnapshotPeriod := ssr.currentConfig().DeltaSnapshotPeriod.Duration
+	if deltaSnapshotPeriod < brtypes.DeltaSnapshotIntervalThreshold {
+		return nil
+	}
+	ssr.deltaSnapshotTimer.Stop()
+	ssr.logger.Infof("Resetting delta snapshot to run after %s.", deltaSnapshotPeriod)
+	ssr.deltaSnapshotTimer.Reset(deltaSnapshotPeriod)
+	return nil
+}
+
 func (ssr *Snapshotter) resetFullSnapshotTimer() error {
 	now := time.Now()
-	effective := ssr.schedule.Next(now)
+	effective := ssr.currentSchedule().Next(now)
 	if effective.IsZero() {
 		ssr.logger.Info("There are no backups scheduled for the future. Stopping now.")
 		return fmt.Errorf("error in full snapshot schedule")
@@ -780,7 +991,7 @@ func (ssr *Snapshotter) IsFullSnapshotRequiredAtStartup(timeWindow float64) bool
 // WasScheduledFullSnapshotMissed determines whether the preceding full-snapshot was missed or not.
 func (ssr *Snapshotter) WasScheduledFullSnapshotMissed(timeWindow float64) bool {
 	now := time.Now()
-	nextSnapSchedule := ssr.schedule.Next(now)
+	nextSnapSchedule := ssr.currentSchedule().Next(now)
 
 	if miscellaneous.GetPrevScheduledSnapTime(nextSnapSchedule, timeWindow).Equal(ssr.PrevFullSnapshot.CreatedOn) {
 		ssr.logger.Info("previous full snapshot was taken at scheduled time, skipping the full snapshot at startup")
@@ -792,7 +1003,7 @@ func (ssr *Snapshotter) WasScheduledFullSnapshotMissed(timeWindow float64) bool
 // IsNextFullSnapshotBeyondTimeWindow determines whether the next scheduled full snapshot will exceed the given time window or not.
 func (ssr *Snapshotter) IsNextFullSnapshotBeyondTimeWindow(timeWindow float64) bool {
 	now := time.Now()
-	nextSnapSchedule := ssr.schedule.Next(now)
+	nextSnapSchedule := ssr.currentSchedule().Next(now)
 	timeLeftToTakeNextSnap := nextSnapSchedule.Sub(now)
 
 	return timeLeftToTakeNextSnap.Hours()+time.Since(ssr.PrevFullSnapshot.CreatedOn).Hours() > timeWindow
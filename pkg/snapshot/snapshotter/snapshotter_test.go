@@ -10,9 +10,11 @@ import (
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gardener/etcd-backup-restore/pkg/compressor"
@@ -21,6 +23,7 @@ import (
 	"github.com/gardener/etcd-backup-restore/pkg/wrappers"
 	"github.com/gardener/etcd-backup-restore/test/utils"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	v1 "k8s.io/api/coordination/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -420,6 +423,229 @@ var _ = Describe("Snapshotter", func() {
 				}
 			})
 
+			It("should garbage collect as per the GFS policy", func() {
+				snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "garbagecollector_gfs.bkp"), Prefix: "v2"}
+				store, err = snapstore.GetSnapstore(snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				fullSnapTimes := []time.Time{
+					time.Date(2023, time.January, 15, 10, 0, 0, 0, time.UTC),
+					time.Date(2023, time.June, 10, 10, 0, 0, 0, time.UTC),
+					time.Date(2024, time.January, 10, 10, 0, 0, 0, time.UTC),
+					time.Date(2024, time.February, 10, 10, 0, 0, 0, time.UTC),
+					time.Date(2024, time.February, 20, 10, 0, 0, 0, time.UTC),
+					time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+					time.Date(2024, time.March, 1, 11, 0, 0, 0, time.UTC),
+					time.Date(2024, time.March, 1, 11, 30, 0, 0, time.UTC),
+				}
+				var revision int64
+				for _, snapTime := range fullSnapTimes {
+					for i, kind := range []string{brtypes.SnapshotKindFull, brtypes.SnapshotKindDelta} {
+						revision++
+						snap := brtypes.Snapshot{
+							Kind:          kind,
+							CreatedOn:     snapTime.Add(time.Duration(i) * time.Minute),
+							StartRevision: revision,
+							LastRevision:  revision,
+						}
+						if kind == brtypes.SnapshotKindFull {
+							snap.StartRevision = 0
+						}
+						snap.GenerateSnapshotName()
+						Expect(store.Save(snap, io.NopCloser(strings.NewReader("dummy-snapshot-content")))).To(Succeed())
+					}
+				}
+
+				snapshotterConfig := &brtypes.SnapshotterConfig{
+					FullSnapshotSchedule:     schedule,
+					DeltaSnapshotPeriod:      wrappers.Duration{Duration: 10 * time.Second},
+					DeltaSnapshotMemoryLimit: brtypes.DefaultDeltaSnapMemoryLimit,
+					GarbageCollectionPeriod:  wrappers.Duration{Duration: garbageCollectionPeriod},
+					GarbageCollectionPolicy:  brtypes.GarbageCollectionPolicyGFS,
+					GFSRetention: brtypes.GFSRetention{
+						Hourly:  2,
+						Monthly: 2,
+						Yearly:  2,
+					},
+				}
+				Expect(snapshotterConfig.Validate()).To(Succeed())
+
+				ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				gcCtx, cancel := context.WithTimeout(testCtx, testTimeout)
+				defer cancel()
+				ssr.RunGarbageCollector(gcCtx.Done())
+
+				list, err := store.List(false)
+				Expect(err).ShouldNot(HaveOccurred())
+				var remaining []time.Time
+				for _, snap := range list {
+					remaining = append(remaining, snap.CreatedOn.UTC())
+				}
+				Expect(remaining).To(Equal([]time.Time{
+					// latest full snapshot of 2023
+					fullSnapTimes[1],
+					// latest full snapshot of February 2024
+					fullSnapTimes[4],
+					// latest full snapshot of the hour before the latest hour
+					fullSnapTimes[5],
+					// latest snapStream, including its delta snapshot
+					fullSnapTimes[7],
+					fullSnapTimes[7].Add(time.Minute),
+				}))
+			})
+
+			It("should not garbage collect pinned full snapshots", func() {
+				snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "garbagecollector_pinned.bkp"), Prefix: "v2"}
+				store, err = snapstore.GetSnapstore(snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				snapTime := time.Now().UTC().Add(-4 * time.Hour)
+				for revision := int64(1); revision <= 4; revision++ {
+					snap := brtypes.Snapshot{
+						Kind:          brtypes.SnapshotKindFull,
+						CreatedOn:     snapTime.Add(time.Duration(revision) * time.Hour),
+						StartRevision: 0,
+						LastRevision:  revision,
+					}
+					snap.GenerateSnapshotName()
+					Expect(store.Save(snap, io.NopCloser(strings.NewReader("dummy-snapshot-content")))).To(Succeed())
+				}
+				list, err := store.List(false)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(list).To(HaveLen(4))
+				Expect(snapstore.PinSnapshot(store, list[0], time.Time{})).To(Succeed())
+				expiry := time.Now().Add(2 * time.Second)
+				Expect(snapstore.PinSnapshot(store, list[1], expiry)).To(Succeed())
+				Expect(snapstore.SaveSnapshotLabels(store, list[1], map[string]string{brtypes.SnapshotLabelReason: "pre-upgrade"})).To(Succeed())
+				time.Sleep(time.Until(expiry))
+
+				snapshotterConfig := &brtypes.SnapshotterConfig{
+					FullSnapshotSchedule:    schedule,
+					GarbageCollectionPeriod: wrappers.Duration{Duration: garbageCollectionPeriod},
+					GarbageCollectionPolicy: brtypes.GarbageCollectionPolicyLimitBased,
+					MaxBackups:              1,
+				}
+				ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				deleted, err := ssr.GarbageCollect()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(deleted).To(Equal(2))
+
+				remaining, err := store.List(false)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(remaining).To(HaveLen(2))
+				// the indefinitely pinned snapshot and the latest snapshot are kept
+				Expect(remaining[0].SnapName).To(Equal(list[0].SnapName))
+				Expect(remaining[0].IsPinned()).To(BeTrue())
+				Expect(remaining[1].SnapName).To(Equal(list[3].SnapName))
+				// the expired pin is removed along with its snapshot
+				markers, err := filepath.Glob(path.Join(outputDir, "garbagecollector_pinned.bkp", "v2", "*.pin*"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(markers).To(HaveLen(1))
+				// the labels are removed along with their snapshot
+				labels, err := filepath.Glob(path.Join(outputDir, "garbagecollector_pinned.bkp", "v2", "*.labels"))
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(labels).To(BeEmpty())
+			})
+
+			It("should report the decisions of the garbage collector without deleting snapshots", func() {
+				snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "garbagecollector_dry_run.bkp")}
+				store, err = snapstore.GetSnapstore(snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				snapshotterConfig := &brtypes.SnapshotterConfig{
+					FullSnapshotSchedule:    schedule,
+					GarbageCollectionPeriod: wrappers.Duration{Duration: garbageCollectionPeriod},
+					GarbageCollectionPolicy: brtypes.GarbageCollectionPolicyLimitBased,
+					MaxBackups:              2,
+				}
+				ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				ssr.PrevSnapshot = &brtypes.Snapshot{Kind: brtypes.SnapshotKindDelta, StartRevision: 31, LastRevision: 40}
+
+				newSnap := func(kind string, startRevision, lastRevision int64, createdOn time.Time) *brtypes.Snapshot {
+					snap := &brtypes.Snapshot{Kind: kind, StartRevision: startRevision, LastRevision: lastRevision, CreatedOn: createdOn}
+					snap.GenerateSnapshotName()
+					return snap
+				}
+				newChunk := func(startRevision int64) *brtypes.Snapshot {
+					return &brtypes.Snapshot{
+						Kind:          brtypes.SnapshotKindFull,
+						SnapName:      fmt.Sprintf("Full-00000000-%08d-1700000000/0000000001", startRevision),
+						StartRevision: startRevision,
+						IsChunk:       true,
+					}
+				}
+				snapTime := now.Add(-3 * time.Hour)
+				var (
+					full1         = newSnap(brtypes.SnapshotKindFull, 0, 10, snapTime)
+					delta1        = newSnap(brtypes.SnapshotKindDelta, 11, 20, snapTime.Add(5*time.Minute))
+					full2         = newSnap(brtypes.SnapshotKindFull, 0, 20, snapTime.Add(time.Hour))
+					delta2        = newSnap(brtypes.SnapshotKindDelta, 21, 30, snapTime.Add(time.Hour+5*time.Minute))
+					uploadedChunk = newChunk(0)
+					full3         = newSnap(brtypes.SnapshotKindFull, 0, 30, snapTime.Add(2*time.Hour))
+					delta3        = newSnap(brtypes.SnapshotKindDelta, 31, 40, snapTime.Add(2*time.Hour+5*time.Minute))
+					uploadChunk   = newChunk(41)
+				)
+				delta1.ImmutabilityExpiryTime = now.Add(time.Hour)
+
+				report := ssr.EvaluateGarbageCollection(brtypes.SnapList{full1, delta1, full2, delta2, uploadedChunk, full3, delta3, uploadChunk}, now)
+				Expect(report.Policy).To(Equal(brtypes.GarbageCollectionPolicyLimitBased))
+				actions := map[string]GarbageCollectionAction{}
+				for _, decision := range report.Snapshots {
+					Expect(decision.Reason).ToNot(BeEmpty())
+					actions[decision.Snapshot] = decision.Action
+				}
+				Expect(actions).To(Equal(map[string]GarbageCollectionAction{
+					full1.SnapName:         GarbageCollectionActionDelete,
+					delta1.SnapName:        GarbageCollectionActionSkip,
+					full2.SnapName:         GarbageCollectionActionKeep,
+					delta2.SnapName:        GarbageCollectionActionDelete,
+					uploadedChunk.SnapName: GarbageCollectionActionDelete,
+					full3.SnapName:         GarbageCollectionActionKeep,
+					delta3.SnapName:        GarbageCollectionActionKeep,
+					uploadChunk.SnapName:   GarbageCollectionActionSkip,
+				}))
+				Expect([]int{report.Delete, report.Keep, report.Skip}).To(Equal([]int{3, 3, 2}))
+			})
+
+			It("should apply the updated config to the garbage collection", func() {
+				snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "garbagecollector_update_config.bkp")}
+				store, err = snapstore.GetSnapstore(snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+				now := time.Now().UTC()
+				snapshotterConfig := &brtypes.SnapshotterConfig{
+					FullSnapshotSchedule:    schedule,
+					GarbageCollectionPeriod: wrappers.Duration{Duration: garbageCollectionPeriod},
+					GarbageCollectionPolicy: brtypes.GarbageCollectionPolicyLimitBased,
+					MaxBackups:              1,
+				}
+				ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				var snapList brtypes.SnapList
+				for i := int64(0); i < 3; i++ {
+					snap := &brtypes.Snapshot{Kind: brtypes.SnapshotKindFull, StartRevision: 0, LastRevision: (i + 1) * 10, CreatedOn: now.Add(time.Duration(i-3) * time.Hour)}
+					snap.GenerateSnapshotName()
+					snapList = append(snapList, snap)
+				}
+				report := ssr.EvaluateGarbageCollection(snapList, now)
+				Expect([]int{report.Delete, report.Keep}).To(Equal([]int{2, 1}))
+
+				updatedConfig := *snapshotterConfig
+				updatedConfig.MaxBackups = 3
+				Expect(ssr.UpdateConfig(&updatedConfig)).To(Succeed())
+				report = ssr.EvaluateGarbageCollection(snapList, now)
+				Expect(report.Policy).To(Equal(brtypes.GarbageCollectionPolicyLimitBased))
+				Expect([]int{report.Delete, report.Keep}).To(Equal([]int{0, 3}))
+
+				updatedConfig.FullSnapshotSchedule = "invalid schedule"
+				Expect(ssr.UpdateConfig(&updatedConfig)).NotTo(Succeed())
+				report = ssr.EvaluateGarbageCollection(snapList, now)
+				Expect([]int{report.Delete, report.Keep}).To(Equal([]int{0, 3}))
+			})
+
 			Describe("###GarbageCollectDeltaSnapshots", func() {
 				const (
 					deltaSnapshotCount = 6
@@ -1100,8 +1326,297 @@ var _ = Describe("Snapshotter", func() {
 			})
 		})
 	})
+
+	Describe("pausing snapshotter", func() {
+		var ssr *Snapshotter
+		BeforeEach(func() {
+			snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "snapshotter_pause.bkp")}
+			store, err = snapstore.GetSnapstore(snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			snapshotterConfig := &brtypes.SnapshotterConfig{
+				FullSnapshotSchedule:     "0 0 1 1 *",
+				DeltaSnapshotPeriod:      wrappers.Duration{Duration: 10 * time.Second},
+				DeltaSnapshotMemoryLimit: brtypes.DefaultDeltaSnapMemoryLimit,
+				GarbageCollectionPeriod:  wrappers.Duration{Duration: garbageCollectionPeriod},
+				GarbageCollectionPolicy:  brtypes.GarbageCollectionPolicyExponential,
+				MaxBackups:               1,
+			}
+			ssr, err = NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(snapstoreConfig.Container)).To(Succeed())
+		})
+
+		It("should reject triggered snapshots while paused and resume at the deadline", func() {
+			ssr.SetSnapshotterActive()
+			_, err := ssr.Pause(time.Now().Add(-time.Second))
+			Expect(err).Should(HaveOccurred())
+			Expect(ssr.IsPaused()).To(BeFalse())
+
+			until := time.Now().Add(2 * time.Second)
+			status, err := ssr.Pause(until)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status.Paused).To(BeTrue())
+			Expect(status.Since).NotTo(BeNil())
+			Expect(status.Until.Equal(until)).To(BeTrue())
+
+			_, err = ssr.TriggerFullSnapshot(testCtx, false)
+			Expect(err).To(MatchError(ErrSnapshottingPaused))
+			_, err = ssr.TriggerDeltaSnapshot()
+			Expect(err).To(MatchError(ErrSnapshottingPaused))
+
+			Eventually(ssr.IsPaused).WithTimeout(5 * time.Second).Should(BeFalse())
+			Expect(ssr.GetPauseStatus().Since).To(BeNil())
+		})
+
+		It("should take the full snapshot scheduled while paused once resumed", func() {
+			_, err := ssr.Pause(time.Time{})
+			Expect(err).ShouldNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(testCtx)
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- ssr.Run(ctx.Done(), true)
+			}()
+
+			Consistently(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(3 * time.Second).Should(BeEmpty())
+
+			Expect(ssr.Resume().Paused).To(BeFalse())
+			Eventually(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(10 * time.Second).ShouldNot(BeEmpty())
+			list, err := store.List(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(list[0].Kind).To(Equal(brtypes.SnapshotKindFull))
+
+			cancel()
+			Eventually(errCh).WithTimeout(10 * time.Second).Should(Receive(BeNil()))
+		})
+	})
+
+	Describe("reporting health", func() {
+		var snapshotterConfig *brtypes.SnapshotterConfig
+		BeforeEach(func() {
+			snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "snapshotter_health.bkp")}
+			store, err = snapstore.GetSnapstore(snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			snapshotterConfig = &brtypes.SnapshotterConfig{
+				FullSnapshotSchedule:     "0 */1 * * *",
+				DeltaSnapshotPeriod:      wrappers.Duration{Duration: 10 * time.Second},
+				DeltaSnapshotMemoryLimit: brtypes.DefaultDeltaSnapMemoryLimit,
+				GarbageCollectionPeriod:  wrappers.Duration{Duration: 2 * time.Second},
+				GarbageCollectionPolicy:  brtypes.GarbageCollectionPolicyExponential,
+				MaxBackups:               1,
+			}
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(snapstoreConfig.Container)).To(Succeed())
+		})
+
+		It("should report an overdue full snapshot", func() {
+			snap := snapstore.NewSnapshot(brtypes.SnapshotKindFull, 0, 1, "", false)
+			snap.CreatedOn = time.Now().UTC().Add(-3 * time.Hour)
+			snap.GenerateSnapshotName()
+			Expect(store.Save(*snap, io.NopCloser(strings.NewReader("dummy-snapshot-content")))).To(Succeed())
+			ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			health := ssr.FullSnapshotHealth(time.Now())
+			Expect(health.Status).To(Equal(brtypes.ComponentStatusUnhealthy))
+			Expect(health.LastSuccess.Equal(snap.CreatedOn.Truncate(time.Second))).To(BeTrue())
+			Expect(ssr.FullSnapshotHealth(snap.CreatedOn.Add(30 * time.Minute)).Status).To(Equal(brtypes.ComponentStatusHealthy))
+
+			_, err = ssr.Pause(time.Time{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ssr.FullSnapshotHealth(time.Now()).Status).To(Equal(brtypes.ComponentStatusHealthy))
+			ssr.Resume()
+		})
+
+		It("should report the delta snapshots and the garbage collection", func() {
+			ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ssr.FullSnapshotHealth(time.Now()).Status).To(Equal(brtypes.ComponentStatusUnknown))
+			Expect(ssr.DeltaSnapshotHealth(time.Now()).Status).To(Equal(brtypes.ComponentStatusUnknown))
+			Expect(ssr.GarbageCollectionHealth().Status).To(Equal(brtypes.ComponentStatusUnknown))
+
+			// there are no events to save, which does not make the delta snapshots overdue
+			_, err = ssr.TakeDeltaSnapshot()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ssr.DeltaSnapshotHealth(time.Now()).Status).To(Equal(brtypes.ComponentStatusHealthy))
+			Expect(ssr.DeltaSnapshotHealth(time.Now().Add(time.Minute)).Status).To(Equal(brtypes.ComponentStatusUnhealthy))
+
+			gcStopCh := make(chan struct{})
+			defer close(gcStopCh)
+			go ssr.RunGarbageCollector(gcStopCh)
+			Eventually(func() brtypes.ComponentStatus {
+				return ssr.GarbageCollectionHealth().Status
+			}).WithTimeout(10 * time.Second).Should(Equal(brtypes.ComponentStatusHealthy))
+		})
+	})
+
+	Describe("spooling delta snapshots", func() {
+		var (
+			unavailable       *unavailableStore
+			snapshotterConfig *brtypes.SnapshotterConfig
+		)
+		BeforeEach(func() {
+			snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "snapshotter_spool.bkp")}
+			store, err = snapstore.GetSnapstore(snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			unavailable = &unavailableStore{SnapStore: store}
+			snapshotterConfig = &brtypes.SnapshotterConfig{
+				FullSnapshotSchedule:        "0 0 1 1 *",
+				DeltaSnapshotPeriod:         wrappers.Duration{Duration: time.Hour},
+				DeltaSnapshotMemoryLimit:    brtypes.DefaultDeltaSnapMemoryLimit,
+				GarbageCollectionPeriod:     wrappers.Duration{Duration: garbageCollectionPeriod},
+				GarbageCollectionPolicy:     brtypes.GarbageCollectionPolicyExponential,
+				MaxBackups:                  1,
+				DeltaSnapshotSpoolDir:       path.Join(outputDir, "snapshotter_spool"),
+				DeltaSnapshotSpoolSizeLimit: brtypes.DefaultDeltaSnapshotSpoolSizeLimit,
+			}
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(snapstoreConfig.Container)).To(Succeed())
+			Expect(os.RemoveAll(snapshotterConfig.DeltaSnapshotSpoolDir)).To(Succeed())
+		})
+
+		It("should spool the delta snapshots while the snapstore is unavailable and upload them once available", func() {
+			ssr, err := NewSnapshotter(logger, snapshotterConfig, unavailable, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(testCtx)
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- ssr.Run(ctx.Done(), true)
+			}()
+			Eventually(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(10 * time.Second).Should(HaveLen(1))
+			ssr.SetSnapshotterActive()
+
+			unavailable.unavailable.Store(true)
+			takeDeltaSnapshot := func(keyFrom, keyTo int) *brtypes.Snapshot {
+				utils.PopulateEtcd(ctx, logger, etcdConnectionConfig.Endpoints, "", "", keyFrom, keyTo, nil)
+				var snap *brtypes.Snapshot
+				Eventually(func() (*brtypes.Snapshot, error) {
+					snap, err = ssr.TriggerDeltaSnapshot()
+					return snap, err
+				}).WithTimeout(10 * time.Second).ShouldNot(BeNil())
+				return snap
+			}
+			first := takeDeltaSnapshot(1, 2)
+
+			// the failed full snapshot does not stop the delta snapshots
+			_, err = ssr.TriggerFullSnapshot(ctx, false)
+			Expect(err).Should(HaveOccurred())
+			Consistently(errCh).WithTimeout(time.Second).ShouldNot(Receive())
+			second := takeDeltaSnapshot(3, 4)
+			Expect(second.StartRevision).To(Equal(first.LastRevision + 1))
+			Expect(ssr.PrevSnapshot).To(Equal(second))
+			Expect(store.List(false)).To(HaveLen(1))
+			Expect(ssr.DeltaSnapshotHealth(time.Now()).Message).To(ContainSubstring("2 delta snapshots"))
+
+			// a new snapshotter continues after the spooled delta snapshots
+			restarted, err := NewSnapshotter(logger, snapshotterConfig, unavailable, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(restarted.PrevSnapshot.SnapName).To(Equal(second.SnapName))
+			Expect(restarted.PrevDeltaSnapshots).To(HaveLen(2))
+
+			uploaderStopCh := make(chan struct{})
+			defer close(uploaderStopCh)
+			go ssr.RunDeltaSnapshotSpoolUploader(uploaderStopCh)
+			unavailable.unavailable.Store(false)
+			Eventually(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(30 * time.Second).Should(HaveLen(3))
+			Expect(os.ReadDir(snapshotterConfig.DeltaSnapshotSpoolDir)).To(BeEmpty())
+
+			cancel()
+			Eventually(errCh).WithTimeout(10 * time.Second).Should(Receive(BeNil()))
+		})
+	})
+
+	Describe("spilling delta events", func() {
+		var snapshotterConfig *brtypes.SnapshotterConfig
+		BeforeEach(func() {
+			snapstoreConfig = &brtypes.SnapstoreConfig{Container: path.Join(outputDir, "snapshotter_spill.bkp")}
+			store, err = snapstore.GetSnapstore(snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			snapshotterConfig = &brtypes.SnapshotterConfig{
+				FullSnapshotSchedule:        "0 0 1 1 *",
+				DeltaSnapshotPeriod:         wrappers.Duration{Duration: time.Hour},
+				DeltaSnapshotMemoryLimit:    1024,
+				GarbageCollectionPeriod:     wrappers.Duration{Duration: garbageCollectionPeriod},
+				GarbageCollectionPolicy:     brtypes.GarbageCollectionPolicyExponential,
+				MaxBackups:                  1,
+				DeltaSnapshotSpillDir:       path.Join(outputDir, "snapshotter_spill"),
+				DeltaSnapshotSpillSizeLimit: brtypes.DefaultDeltaSnapshotSpillSizeLimit,
+			}
+		})
+		AfterEach(func() {
+			Expect(os.RemoveAll(snapstoreConfig.Container)).To(Succeed())
+			Expect(os.RemoveAll(snapshotterConfig.DeltaSnapshotSpillDir)).To(Succeed())
+		})
+
+		It("should take a single delta snapshot of the events beyond the memory limit", func() {
+			ssr, err := NewSnapshotter(logger, snapshotterConfig, store, etcdConnectionConfig, compressionConfig, healthConfig, snapstoreConfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			ctx, cancel := context.WithCancel(testCtx)
+			defer cancel()
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- ssr.Run(ctx.Done(), true)
+			}()
+			Eventually(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(10 * time.Second).Should(HaveLen(1))
+			ssr.SetSnapshotterActive()
+
+			cli, err := clientv3.New(clientv3.Config{Endpoints: etcdConnectionConfig.Endpoints, DialTimeout: 10 * time.Second})
+			Expect(err).ShouldNot(HaveOccurred())
+			defer cli.Close()
+			var lastRevision int64
+			for i := 0; i < 50; i++ {
+				resp, err := cli.Put(ctx, fmt.Sprintf("/spill/key-%d", i), strings.Repeat("v", 100))
+				Expect(err).ShouldNot(HaveOccurred())
+				lastRevision = resp.Header.Revision
+			}
+
+			// the events beyond the memory limit are spilled instead of taking a delta snapshot
+			Consistently(func() (brtypes.SnapList, error) {
+				return store.List(false)
+			}).WithTimeout(2 * time.Second).Should(HaveLen(1))
+			snap, err := ssr.TriggerDeltaSnapshot()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(snap.LastRevision).To(Equal(lastRevision))
+			snapList, err := store.List(false)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(snapList).To(HaveLen(2))
+			Expect(snapList[1].StartRevision).To(Equal(snapList[0].LastRevision + 1))
+			Expect(os.ReadDir(snapshotterConfig.DeltaSnapshotSpillDir)).To(BeEmpty())
+
+			cancel()
+			Eventually(errCh).WithTimeout(10 * time.Second).Should(Receive(BeNil()))
+		})
+	})
 })
 
+// unavailableStore is a snapstore which fails to save snapshots while it is unavailable.
+type unavailableStore struct {
+	brtypes.SnapStore
+	unavailable atomic.Bool
+}
+
+func (s *unavailableStore) Save(snap brtypes.Snapshot, rc io.ReadCloser) error {
+	if s.unavailable.Load() {
+		return fmt.Errorf("snapstore is unavailable")
+	}
+	return s.SnapStore.Save(snap, rc)
+}
+
 // prepareExpectedSnapshotsList prepares the expected snapshot list based on directory structure
 func prepareExpectedSnapshotsList(snapTime time.Time, now time.Time, expectedSnapList brtypes.SnapList, directoryStruct string) brtypes.SnapList {
 	// weekly snapshot
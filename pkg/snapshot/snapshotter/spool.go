@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshotter
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/spool"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fullSnapshotRetryPeriod is the period after which a failed full snapshot is retried, while the delta snapshots are
+// spooled.
+const fullSnapshotRetryPeriod = 5 * time.Minute
+
+// newDeltaSnapshotSpool returns the spool for the delta snapshots in the directory of the given config, and the spooled
+// delta snapshots which follow the given previous snapshot. Spooled delta snapshots which precede it belong to an older
+// full snapshot, they are only uploaded.
+func newDeltaSnapshotSpool(logger *logrus.Entry, config *brtypes.SnapshotterConfig, prevSnapshot *brtypes.Snapshot) (*spool.Spool, brtypes.SnapList, error) {
+	deltaSnapshotSpool, err := spool.NewSpool(logger, config.DeltaSnapshotSpoolDir, int64(config.DeltaSnapshotSpoolSizeLimit)) // #nosec G115 -- validated to be lesser than MaxInt.
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create delta snapshot spool: %v", err)
+	}
+	var spooled brtypes.SnapList
+	for _, snap := range deltaSnapshotSpool.Pending() {
+		if snap.LastRevision > prevSnapshot.LastRevision {
+			spooled = append(spooled, snap)
+			prevSnapshot = snap
+		}
+	}
+	return deltaSnapshotSpool, spooled, nil
+}
+
+// deltaSnapshotReader returns a reader of the collected events, compressed if compression is enabled.
+func (ssr *Snapshotter) deltaSnapshotReader() (io.ReadCloser, error) {
+	events, err := ssr.events.Reader()
+	if err != nil {
+		return nil, err
+	}
+	rc := io.NopCloser(events)
+	if !ssr.compressionConfig.Enabled {
+		return rc, nil
+	}
+	ssr.logger.Info("start the Compression of delta snapshot")
+	rc, err = compressor.CompressSnapshot(rc, ssr.compressionConfig.CompressionPolicy, ssr.compressionConfig.CompressionLevel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compress delta snapshot: %v", err)
+	}
+	return rc, nil
+}
+
+// saveDeltaSnapshot saves the collected events as the given delta snapshot to the snapstore. With the spool enabled, the
+// snapshot is spooled instead if saving it fails, or if earlier snapshots are still waiting in the spool, so that the
+// delta snapshots are uploaded in order. It returns whether the snapshot has been spooled.
+func (ssr *Snapshotter) saveDeltaSnapshot(snap *brtypes.Snapshot) (bool, error) {
+	if ssr.spool == nil || ssr.spool.Len() == 0 {
+		rc, err := ssr.deltaSnapshotReader()
+		if err != nil {
+			return false, err
+		}
+		err = ssr.store.Save(*snap, rc)
+		rc.Close()
+		if err == nil || ssr.spool == nil {
+			return false, err
+		}
+		ssr.logger.Warnf("Failed to save delta snapshot, spooling it to be uploaded later: %v", err)
+	}
+
+	rc, err := ssr.deltaSnapshotReader()
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+	if err := ssr.spool.Add(*snap, rc); err != nil {
+		return false, fmt.Errorf("failed to spool delta snapshot: %v", err)
+	}
+	return true, nil
+}
+
+// handleFullSnapshotFailure is called by the snapshot event handler once a full snapshot has failed with the given
+// error. Without the spool, the error is returned, so that the snapshotter is restarted, which takes delta snapshots
+// again only once a full snapshot has succeeded. With the spool, the snapshotter carries on taking delta snapshots into
+// the spool, and retries the full snapshot after the full snapshot retry period.
+func (ssr *Snapshotter) handleFullSnapshotFailure(err error) error {
+	ssr.PrevFullSnapshotSucceeded = false
+	if ssr.spool == nil || ssr.currentConfig().DeltaSnapshotPeriod.Duration < brtypes.DeltaSnapshotIntervalThreshold {
+		return err
+	}
+	ssr.logger.Warnf("Continuing to take delta snapshots into the spool, retrying the full snapshot in %s.", fullSnapshotRetryPeriod)
+	// the watch has been closed for the full snapshot, the events since the previous snapshot are watched again
+	if err := ssr.watchEventsSincePrevSnapshot(); err != nil {
+		return err
+	}
+	ssr.fullSnapshotTimer.Stop()
+	ssr.fullSnapshotTimer.Reset(fullSnapshotRetryPeriod)
+	return nil
+}
+
+// RunDeltaSnapshotSpoolUploader uploads the spooled delta snapshots to the snapstore until the stop channel is closed.
+// The uploads are held back while the snapshotting is paused. It returns right away if the spool is not enabled.
+func (ssr *Snapshotter) RunDeltaSnapshotSpoolUploader(stopCh <-chan struct{}) {
+	if ssr.spool == nil {
+		return
+	}
+	ssr.spool.Run(stopCh, func() (brtypes.SnapStore, error) {
+		return snapstore.GetSnapstore(ssr.snapstoreConfig)
+	}, ssr)
+}
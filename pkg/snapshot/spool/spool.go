@@ -0,0 +1,368 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package spool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/backoff"
+	"github.com/gardener/etcd-backup-restore/pkg/metrics"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// metadataSuffix is the suffix of the file next to each spooled snapshot which holds its metadata.
+	metadataSuffix = ".json"
+	// tempSuffix is the suffix of the files being written to the spool.
+	tempSuffix = ".tmp"
+
+	// uploadAttemptLimit is the number of failed uploads after which the backoff between the uploads stops growing.
+	uploadAttemptLimit = 6
+	// uploadBackoffMultiplier is the factor by which the backoff between failed uploads grows.
+	uploadBackoffMultiplier = 2
+	// maxUploadBackoff is the maximum time to wait between failed uploads.
+	maxUploadBackoff = 2 * time.Minute
+)
+
+// Pauser holds back the uploads of the spool while the snapshotting is paused.
+type Pauser interface {
+	// IsPaused returns true if the snapshotting is paused.
+	IsPaused() bool
+	// WaitUntilResumed blocks while the snapshotting is paused. It returns false if the given stop channel has been
+	// closed before the snapshotting has been resumed.
+	WaitUntilResumed(stopCh <-chan struct{}) bool
+}
+
+// Spool persists snapshots in a local directory until they have been uploaded to the snapstore. Each snapshot is
+// stored as a data file named after the snapshot, and a metadata file which is written once the data file is complete.
+type Spool struct {
+	logger    *logrus.Entry
+	dir       string
+	sizeLimit int64
+	mutex     sync.Mutex
+	pending   brtypes.SnapList
+	sizes     map[string]int64
+	size      int64
+	addedCh   chan struct{}
+}
+
+// NewSpool returns the spool in the given directory, which is created if it does not exist. The snapshots left in the
+// directory by a previous process are loaded as pending, incomplete files are removed.
+func NewSpool(logger *logrus.Entry, dir string, sizeLimit int64) (*Spool, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory %s: %v", dir, err)
+	}
+	s := &Spool{
+		logger:    logger.WithField("actor", "spool"),
+		dir:       dir,
+		sizeLimit: sizeLimit,
+		sizes:     map[string]int64{},
+		addedCh:   make(chan struct{}, 1),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.updateMetrics()
+	if len(s.pending) != 0 {
+		s.logger.Infof("Found %d snapshots of %d bytes in spool %s waiting to be uploaded.", len(s.pending), s.size, dir)
+	}
+	return s, nil
+}
+
+// load loads the snapshots in the spool directory.
+func (s *Spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read spool directory %s: %v", s.dir, err)
+	}
+	spooled := map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metadataSuffix) {
+			continue
+		}
+		metadataPath := filepath.Join(s.dir, entry.Name())
+		data, err := os.ReadFile(metadataPath) // #nosec G304 -- the path is within the configured spool directory.
+		if err != nil {
+			return fmt.Errorf("failed to read spooled snapshot metadata %s: %v", metadataPath, err)
+		}
+		snap := &brtypes.Snapshot{}
+		if err := json.Unmarshal(data, snap); err != nil {
+			return fmt.Errorf("failed to parse spooled snapshot metadata %s: %v", metadataPath, err)
+		}
+		info, err := os.Stat(s.dataPath(snap))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to find spooled snapshot %s: %v", snap.SnapName, err)
+			}
+			// the snapshot has been uploaded, but the process stopped before its metadata was removed
+			continue
+		}
+		spooled[entry.Name()] = true
+		spooled[snap.SnapName] = true
+		s.pending = append(s.pending, snap)
+		s.sizes[snap.SnapName] = info.Size()
+		s.size += info.Size()
+	}
+	// the other files belong to snapshots which have not been spooled completely, or which have already been uploaded
+	for _, entry := range entries {
+		if entry.IsDir() || spooled[entry.Name()] {
+			continue
+		}
+		s.logger.Warnf("Removing %s of an incompletely spooled or already uploaded snapshot.", entry.Name())
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove %s from spool: %v", entry.Name(), err)
+		}
+	}
+	sort.Sort(s.pending)
+	return nil
+}
+
+// Add writes the snapshot read from the given reader to the spool. It fails if the spool would grow beyond its size
+// limit.
+func (s *Spool) Add(snap brtypes.Snapshot, r io.Reader) error {
+	tempDataPath := s.dataPath(&snap) + tempSuffix
+	size, err := writeFile(tempDataPath, r)
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot %s to spool: %v", snap.SnapName, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.size+size > s.sizeLimit {
+		if err := os.Remove(tempDataPath); err != nil {
+			s.logger.Warnf("Failed to remove %s: %v", tempDataPath, err)
+		}
+		return fmt.Errorf("spooling snapshot %s of %d bytes exceeds the spool size limit of %d bytes, %d bytes are already spooled", snap.SnapName, size, s.sizeLimit, s.size)
+	}
+	if err := os.Rename(tempDataPath, s.dataPath(&snap)); err != nil {
+		return fmt.Errorf("failed to rename spooled snapshot %s: %v", snap.SnapName, err)
+	}
+	metadata, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata of snapshot %s: %v", snap.SnapName, err)
+	}
+	// the metadata is written last, so that a snapshot is only loaded from the spool if its data is complete
+	tempMetadataPath := s.metadataPath(&snap) + tempSuffix
+	if _, err := writeFile(tempMetadataPath, strings.NewReader(string(metadata))); err != nil {
+		return fmt.Errorf("failed to write metadata of snapshot %s to spool: %v", snap.SnapName, err)
+	}
+	if err := os.Rename(tempMetadataPath, s.metadataPath(&snap)); err != nil {
+		return fmt.Errorf("failed to rename metadata of spooled snapshot %s: %v", snap.SnapName, err)
+	}
+	if err := syncDir(s.dir); err != nil {
+		return fmt.Errorf("failed to sync spool directory %s: %v", s.dir, err)
+	}
+
+	s.pending = append(s.pending, &snap)
+	s.sizes[snap.SnapName] = size
+	s.size += size
+	s.updateMetrics()
+	select {
+	case s.addedCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pending returns the snapshots in the spool which have not been uploaded yet, in the order they have been added.
+func (s *Spool) Pending() brtypes.SnapList {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append(brtypes.SnapList(nil), s.pending...)
+}
+
+// Len returns the number of snapshots in the spool which have not been uploaded yet.
+func (s *Spool) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.pending)
+}
+
+// Size returns the total size in bytes of the snapshots in the spool which have not been uploaded yet.
+func (s *Spool) Size() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.size
+}
+
+// Upload uploads the pending snapshots to the given snapstore in the order they have been added, and removes them from
+// the spool. It stops at the first snapshot which fails to be uploaded.
+func (s *Spool) Upload(store brtypes.SnapStore) (int, error) {
+	return s.uploadUnlessPaused(store, func() bool { return false })
+}
+
+// uploadUnlessPaused uploads the pending snapshots like Upload, but stops before the next snapshot once the given
+// function reports a pause.
+func (s *Spool) uploadUnlessPaused(store brtypes.SnapStore, isPaused func() bool) (int, error) {
+	uploaded := 0
+	for {
+		if isPaused() {
+			return uploaded, nil
+		}
+		s.mutex.Lock()
+		if len(s.pending) == 0 {
+			s.mutex.Unlock()
+			return uploaded, nil
+		}
+		// snapshots are only removed from the spool here, so the oldest one stays the same while it is uploaded
+		snap := s.pending[0]
+		s.mutex.Unlock()
+
+		if err := s.upload(snap, store); err != nil {
+			return uploaded, fmt.Errorf("failed to upload spooled snapshot %s: %v", snap.SnapName, err)
+		}
+		s.mutex.Lock()
+		s.pending = s.pending[1:]
+		s.size -= s.sizes[snap.SnapName]
+		delete(s.sizes, snap.SnapName)
+		s.updateMetrics()
+		s.mutex.Unlock()
+		uploaded++
+	}
+}
+
+// upload uploads the given spooled snapshot to the snapstore and removes its files.
+func (s *Spool) upload(snap *brtypes.Snapshot, store brtypes.SnapStore) error {
+	dataPath := s.dataPath(snap)
+	f, err := os.Open(dataPath) // #nosec G304 -- the path is within the configured spool directory.
+	if err != nil {
+		if os.IsNotExist(err) {
+			// another spool on the same directory, left over from a previous leadership, has uploaded the snapshot
+			s.logger.Warnf("Spooled snapshot %s has been removed, skipping its upload.", snap.SnapName)
+			return s.remove(snap)
+		}
+		return err
+	}
+	startTime := time.Now()
+	err = store.Save(*snap, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	s.logger.Infof("Uploaded spooled snapshot %s in %f seconds.", snap.SnapName, time.Since(startTime).Seconds())
+	return s.remove(snap)
+}
+
+// remove removes the files of the given snapshot from the spool. The metadata file is removed first, so that the
+// snapshot is not loaded again if the process stops in between.
+func (s *Spool) remove(snap *brtypes.Snapshot) error {
+	for _, path := range []string{s.metadataPath(snap), s.dataPath(snap)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s from spool: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// Run uploads the pending snapshots until the stop channel is closed. Failed uploads are retried with an exponential
+// backoff, using a new snapstore created with the given function for each attempt, so that updated credentials are
+// picked up. No snapshot is uploaded while the given pauser reports a pause.
+func (s *Spool) Run(stopCh <-chan struct{}, newStore func() (brtypes.SnapStore, error), pauser Pauser) {
+	exponentialBackoff := backoff.NewExponentialBackOffConfig(uploadAttemptLimit, uploadBackoffMultiplier, maxUploadBackoff)
+	for {
+		var (
+			retryCh <-chan time.Time
+			addedCh = s.addedCh
+		)
+		if s.Len() != 0 {
+			if !pauser.WaitUntilResumed(stopCh) {
+				s.logger.Info("Stopping the spool uploader.")
+				return
+			}
+			if err := s.uploadWithNewStore(newStore, pauser.IsPaused); err != nil {
+				backoffTime := exponentialBackoff.GetNextBackoffTime()
+				s.logger.Warnf("%v, retrying in %s. %d snapshots of %d bytes are waiting in the spool.", err, backoffTime, s.Len(), s.Size())
+				retryCh = time.After(backoffTime)
+				// snapshots added in the meantime are uploaded with the retry
+				addedCh = nil
+			} else {
+				exponentialBackoff.ResetExponentialBackoff()
+				if s.Len() != 0 && pauser.IsPaused() {
+					// the uploads have been paused in the meantime, they are resumed with a new snapstore
+					continue
+				}
+			}
+		}
+		select {
+		case <-stopCh:
+			s.logger.Info("Stopping the spool uploader.")
+			return
+		case <-addedCh:
+		case <-retryCh:
+		}
+	}
+}
+
+// uploadWithNewStore uploads the pending snapshots to a snapstore created with the given function, until the given
+// function reports a pause.
+func (s *Spool) uploadWithNewStore(newStore func() (brtypes.SnapStore, error), isPaused func() bool) error {
+	store, err := newStore()
+	if err != nil {
+		return fmt.Errorf("failed to create snapstore from configured storage provider: %v", err)
+	}
+	uploaded, err := s.uploadUnlessPaused(store, isPaused)
+	if uploaded != 0 {
+		s.logger.Infof("Uploaded %d spooled snapshots.", uploaded)
+	}
+	return err
+}
+
+// updateMetrics updates the metrics of the pending snapshots. The caller must hold the mutex.
+func (s *Spool) updateMetrics() {
+	metrics.SnapshotSpoolPendingTotal.With(prometheus.Labels{}).Set(float64(len(s.pending)))
+	metrics.SnapshotSpoolPendingBytes.With(prometheus.Labels{}).Set(float64(s.size))
+}
+
+func (s *Spool) dataPath(snap *brtypes.Snapshot) string {
+	return filepath.Join(s.dir, snap.SnapName)
+}
+
+func (s *Spool) metadataPath(snap *brtypes.Snapshot) string {
+	return filepath.Join(s.dir, snap.SnapName+metadataSuffix)
+}
+
+// writeFile writes the data read from the given reader to a new file at the given path and syncs it to the disk. It
+// returns the number of bytes written.
+func writeFile(path string, r io.Reader) (int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) // #nosec G304 -- the path is within the configured spool directory.
+	if err != nil {
+		return 0, err
+	}
+	size, err := io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
+			return 0, fmt.Errorf("%v, and failed to remove the file: %v", err, removeErr)
+		}
+		return 0, err
+	}
+	return size, nil
+}
+
+// syncDir syncs the given directory to the disk, so that the files renamed in it persist.
+func syncDir(dir string) error {
+	d, err := os.Open(dir) // #nosec G304 -- the path is the configured spool directory.
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
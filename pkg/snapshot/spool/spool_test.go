@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package spool_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/spool"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// unavailableStore is a snapstore which fails to save snapshots while it is unavailable.
+type unavailableStore struct {
+	brtypes.SnapStore
+	unavailable atomic.Bool
+}
+
+func (s *unavailableStore) Save(snap brtypes.Snapshot, rc io.ReadCloser) error {
+	if s.unavailable.Load() {
+		return fmt.Errorf("snapstore is unavailable")
+	}
+	return s.SnapStore.Save(snap, rc)
+}
+
+// testPauser is a pauser whose pause is controlled by the test.
+type testPauser struct {
+	mutex     sync.Mutex
+	resumedCh chan struct{}
+}
+
+func (p *testPauser) pause() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resumedCh == nil {
+		p.resumedCh = make(chan struct{})
+	}
+}
+
+func (p *testPauser) resume() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resumedCh != nil {
+		close(p.resumedCh)
+		p.resumedCh = nil
+	}
+}
+
+func (p *testPauser) IsPaused() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.resumedCh != nil
+}
+
+func (p *testPauser) WaitUntilResumed(stopCh <-chan struct{}) bool {
+	p.mutex.Lock()
+	resumedCh := p.resumedCh
+	p.mutex.Unlock()
+	if resumedCh == nil {
+		return true
+	}
+	select {
+	case <-resumedCh:
+		return true
+	case <-stopCh:
+		return false
+	}
+}
+
+var _ = Describe("Spool", func() {
+	var (
+		dir    string
+		store  *unavailableStore
+		logger = logrus.New().WithField("suite", "spool")
+	)
+
+	BeforeEach(func() {
+		dir = filepath.Join(GinkgoT().TempDir(), "spool")
+		localStore, err := snapstore.NewLocalSnapStore(filepath.Join(GinkgoT().TempDir(), "v2"))
+		Expect(err).ShouldNot(HaveOccurred())
+		store = &unavailableStore{SnapStore: localStore}
+	})
+
+	newDeltaSnapshot := func(startRevision, lastRevision int64) brtypes.Snapshot {
+		return *snapstore.NewSnapshot(brtypes.SnapshotKindDelta, startRevision, lastRevision, "", false)
+	}
+
+	add := func(s *spool.Spool, snap brtypes.Snapshot) {
+		Expect(s.Add(snap, strings.NewReader(snap.SnapName))).To(Succeed())
+	}
+
+	listFiles := func() []string {
+		entries, err := os.ReadDir(dir)
+		Expect(err).ShouldNot(HaveOccurred())
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	It("should keep the spooled snapshots until they are uploaded in order", func() {
+		s, err := spool.NewSpool(logger, dir, 1024)
+		Expect(err).ShouldNot(HaveOccurred())
+		first, second := newDeltaSnapshot(1, 10), newDeltaSnapshot(11, 20)
+		add(s, first)
+		add(s, second)
+		Expect(s.Len()).To(Equal(2))
+		Expect(s.Size()).To(Equal(int64(len(first.SnapName) + len(second.SnapName))))
+		Expect(listFiles()).To(ConsistOf(first.SnapName, first.SnapName+".json", second.SnapName, second.SnapName+".json"))
+
+		store.unavailable.Store(true)
+		uploaded, err := s.Upload(store)
+		Expect(err).Should(HaveOccurred())
+		Expect(uploaded).To(BeZero())
+		Expect(s.Len()).To(Equal(2))
+
+		store.unavailable.Store(false)
+		uploaded, err = s.Upload(store)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(uploaded).To(Equal(2))
+		Expect(s.Len()).To(BeZero())
+		Expect(s.Size()).To(BeZero())
+		Expect(listFiles()).To(BeEmpty())
+
+		list, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[1].LastRevision).To(Equal(second.LastRevision))
+		rc, err := store.Fetch(*list[1])
+		Expect(err).ShouldNot(HaveOccurred())
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(string(data)).To(Equal(second.SnapName))
+	})
+
+	It("should load the complete snapshots left by a previous spool", func() {
+		s, err := spool.NewSpool(logger, dir, 1024)
+		Expect(err).ShouldNot(HaveOccurred())
+		second, first := newDeltaSnapshot(11, 20), newDeltaSnapshot(1, 10)
+		add(s, second)
+		add(s, first)
+		// a snapshot being written and a snapshot without metadata have not been spooled completely
+		Expect(os.WriteFile(filepath.Join(dir, "Incr-00000021-00000030-1.tmp"), []byte("partial"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "Incr-00000021-00000030-1"), []byte("partial"), 0600)).To(Succeed())
+		// the metadata of an uploaded snapshot is left over
+		uploaded := newDeltaSnapshot(31, 40)
+		Expect(os.WriteFile(filepath.Join(dir, uploaded.SnapName+".json"), []byte(`{"snapName":"`+uploaded.SnapName+`"}`), 0600)).To(Succeed())
+
+		s, err = spool.NewSpool(logger, dir, 1024)
+		Expect(err).ShouldNot(HaveOccurred())
+		pending := s.Pending()
+		Expect(pending).To(HaveLen(2))
+		Expect(pending[0].SnapName).To(Equal(first.SnapName))
+		Expect(pending[1].SnapName).To(Equal(second.SnapName))
+		Expect(s.Size()).To(Equal(int64(len(first.SnapName) + len(second.SnapName))))
+		Expect(listFiles()).To(ConsistOf(first.SnapName, first.SnapName+".json", second.SnapName, second.SnapName+".json"))
+	})
+
+	It("should reject snapshots beyond the size limit", func() {
+		first := newDeltaSnapshot(1, 10)
+		s, err := spool.NewSpool(logger, dir, int64(len(first.SnapName)+1))
+		Expect(err).ShouldNot(HaveOccurred())
+		add(s, first)
+
+		second := newDeltaSnapshot(11, 20)
+		Expect(s.Add(second, strings.NewReader(second.SnapName))).To(MatchError(ContainSubstring("exceeds the spool size limit")))
+		Expect(s.Len()).To(Equal(1))
+		Expect(listFiles()).To(ConsistOf(first.SnapName, first.SnapName+".json"))
+	})
+
+	It("should upload the spooled snapshots once the snapstore is available", func() {
+		s, err := spool.NewSpool(logger, dir, 1024)
+		Expect(err).ShouldNot(HaveOccurred())
+		store.unavailable.Store(true)
+		stopCh := make(chan struct{})
+		stoppedCh := make(chan struct{})
+		go func() {
+			defer close(stoppedCh)
+			s.Run(stopCh, func() (brtypes.SnapStore, error) {
+				return store, nil
+			}, &testPauser{})
+		}()
+
+		add(s, newDeltaSnapshot(1, 10))
+		Consistently(s.Len).WithTimeout(time.Second).Should(Equal(1))
+		store.unavailable.Store(false)
+		Eventually(s.Len).WithTimeout(10 * time.Second).Should(BeZero())
+
+		add(s, newDeltaSnapshot(11, 20))
+		Eventually(s.Len).WithTimeout(5 * time.Second).Should(BeZero())
+		Expect(store.List(false)).To(HaveLen(2))
+
+		close(stopCh)
+		Eventually(stoppedCh).WithTimeout(5 * time.Second).Should(BeClosed())
+	})
+
+	It("should not upload the spooled snapshots while paused", func() {
+		s, err := spool.NewSpool(logger, dir, 1024)
+		Expect(err).ShouldNot(HaveOccurred())
+		pauser := &testPauser{}
+		pauser.pause()
+		stopCh := make(chan struct{})
+		stoppedCh := make(chan struct{})
+		go func() {
+			defer close(stoppedCh)
+			s.Run(stopCh, func() (brtypes.SnapStore, error) {
+				return store, nil
+			}, pauser)
+		}()
+
+		add(s, newDeltaSnapshot(1, 10))
+		Consistently(s.Len).WithTimeout(time.Second).Should(Equal(1))
+		Expect(store.List(false)).To(BeEmpty())
+		pauser.resume()
+		Eventually(s.Len).WithTimeout(5 * time.Second).Should(BeZero())
+		Expect(store.List(false)).To(HaveLen(1))
+
+		pauser.pause()
+		add(s, newDeltaSnapshot(11, 20))
+		close(stopCh)
+		Eventually(stoppedCh).WithTimeout(5 * time.Second).Should(BeClosed())
+		Expect(s.Len()).To(Equal(1))
+	})
+})
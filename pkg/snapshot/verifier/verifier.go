@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/etcdutil"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/delta"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProblemType is the type of a problem found while verifying the backups.
+type ProblemType string
+
+const (
+	// ProblemRevisionGap indicates that the revisions between two consecutive snapshots of a chain are missing.
+	ProblemRevisionGap ProblemType = "RevisionGap"
+	// ProblemRevisionOverlap indicates that a snapshot starts at a revision which is already contained in the previous snapshot of a chain.
+	ProblemRevisionOverlap ProblemType = "RevisionOverlap"
+	// ProblemMissingFullSnapshot indicates that delta snapshots are not preceded by any full snapshot.
+	ProblemMissingFullSnapshot ProblemType = "MissingFullSnapshot"
+	// ProblemOrphanedChunk indicates that a chunk does not belong to any snapshot in the store.
+	ProblemOrphanedChunk ProblemType = "OrphanedChunk"
+	// ProblemIntegrityCheckFailed indicates that the contents of a snapshot do not match its SHA256 hash or its revisions.
+	ProblemIntegrityCheckFailed ProblemType = "IntegrityCheckFailed"
+)
+
+// Problem is a problem found while verifying the backups.
+type Problem struct {
+	Type ProblemType `json:"type"`
+	// Snapshot is the path of the snapshot in the store which has the problem.
+	Snapshot string `json:"snapshot"`
+	// StartRevision and LastRevision are the affected revisions, e.g. the missing revisions of a RevisionGap.
+	StartRevision int64  `json:"startRevision,omitempty"`
+	LastRevision  int64  `json:"lastRevision,omitempty"`
+	Message       string `json:"message"`
+}
+
+// Chain is a full snapshot followed by the delta snapshots based on it.
+type Chain struct {
+	// FullSnapshot is the path of the full snapshot in the store. It is empty if the delta snapshots are not preceded by any full snapshot.
+	FullSnapshot   string `json:"fullSnapshot,omitempty"`
+	DeltaSnapshots int    `json:"deltaSnapshots"`
+	StartRevision  int64  `json:"startRevision"`
+	LastRevision   int64  `json:"lastRevision"`
+	Healthy        bool   `json:"healthy"`
+}
+
+// Report is the result of the verification of the backups.
+type Report struct {
+	VerifiedOn       time.Time `json:"verifiedOn"`
+	Healthy          bool      `json:"healthy"`
+	Snapshots        int       `json:"snapshots"`
+	Chunks           int       `json:"chunks"`
+	IntegrityChecked bool      `json:"integrityChecked"`
+	Chains           []Chain   `json:"chains"`
+	Problems         []Problem `json:"problems"`
+}
+
+// Verifier verifies the backups in a snapstore.
+type Verifier struct {
+	logger *logrus.Entry
+	store  brtypes.SnapStore
+	config *brtypes.VerifierConfig
+}
+
+// NewVerifier returns a new Verifier for the backups in the given store.
+func NewVerifier(store brtypes.SnapStore, config *brtypes.VerifierConfig, logger *logrus.Entry) (*Verifier, error) {
+	return &Verifier{
+		logger: logger.WithField("actor", "verifier"),
+		store:  store,
+		config: config,
+	}, nil
+}
+
+// Verify walks all the snapshots in the store, checks that the revisions of the delta snapshots of every chain
+// follow each other without gaps and overlaps, looks for orphaned chunks, and unless configured otherwise,
+// downloads every snapshot to check its contents against its SHA256 hash.
+// Problems with the backups are part of the report; an error is only returned if the verification could not be done.
+func (v *Verifier) Verify(ctx context.Context) (*Report, error) {
+	snapList, err := v.store.List(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %v", err)
+	}
+
+	report := &Report{
+		VerifiedOn:       time.Now().UTC(),
+		IntegrityChecked: !v.config.SkipIntegrityCheck,
+		Chains:           []Chain{},
+		Problems:         []Problem{},
+	}
+
+	snapshots := map[string]bool{}
+	for _, snap := range snapList {
+		if !snap.IsChunk {
+			snapshots[snapshotPath(snap)] = true
+		}
+	}
+
+	var (
+		chain *Chain
+		prev  *brtypes.Snapshot
+	)
+	for _, snap := range snapList {
+		if snap.IsChunk {
+			report.Chunks++
+			parent := path.Join(snap.Prefix, snap.SnapDir, path.Dir(snap.SnapName))
+			if !snapshots[parent] {
+				report.addProblem(nil, Problem{
+					Type:     ProblemOrphanedChunk,
+					Snapshot: snapshotPath(snap),
+					Message:  fmt.Sprintf("chunk does not belong to any snapshot, its snapshot %s does not exist", parent),
+				})
+			}
+			continue
+		}
+		report.Snapshots++
+
+		switch {
+		case snap.Kind == brtypes.SnapshotKindFull:
+			report.addChain(chain)
+			chain = &Chain{
+				FullSnapshot:  snapshotPath(snap),
+				StartRevision: snap.StartRevision,
+				LastRevision:  snap.LastRevision,
+				Healthy:       true,
+			}
+		case chain == nil:
+			chain = &Chain{StartRevision: snap.StartRevision, LastRevision: snap.LastRevision, Healthy: true}
+			report.addProblem(chain, Problem{
+				Type:          ProblemMissingFullSnapshot,
+				Snapshot:      snapshotPath(snap),
+				StartRevision: snap.StartRevision,
+				LastRevision:  snap.LastRevision,
+				Message:       "delta snapshot is not preceded by any full snapshot",
+			})
+			chain.DeltaSnapshots++
+		default:
+			if problem := checkRevisions(prev, snap); problem != nil {
+				report.addProblem(chain, *problem)
+			}
+			chain.LastRevision = snap.LastRevision
+			chain.DeltaSnapshots++
+		}
+		prev = snap
+
+		if v.config.SkipIntegrityCheck {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := v.checkIntegrity(snap); err != nil {
+			report.addProblem(chain, Problem{
+				Type:          ProblemIntegrityCheckFailed,
+				Snapshot:      snapshotPath(snap),
+				StartRevision: snap.StartRevision,
+				LastRevision:  snap.LastRevision,
+				Message:       err.Error(),
+			})
+		}
+	}
+	report.addChain(chain)
+	report.Healthy = len(report.Problems) == 0
+
+	v.logger.Infof("Verified %d snapshots in %d chains, found %d problems", report.Snapshots, len(report.Chains), len(report.Problems))
+	return report, nil
+}
+
+// checkRevisions checks that the delta snapshot snap directly follows the previous snapshot prev of its chain.
+func checkRevisions(prev, snap *brtypes.Snapshot) *Problem {
+	switch {
+	case snap.StartRevision > prev.LastRevision+1:
+		return &Problem{
+			Type:          ProblemRevisionGap,
+			Snapshot:      snapshotPath(snap),
+			StartRevision: prev.LastRevision + 1,
+			LastRevision:  snap.StartRevision - 1,
+			Message:       fmt.Sprintf("revisions %d to %d between snapshot %s and this snapshot are missing", prev.LastRevision+1, snap.StartRevision-1, snapshotPath(prev)),
+		}
+	case snap.StartRevision <= prev.LastRevision:
+		return &Problem{
+			Type:          ProblemRevisionOverlap,
+			Snapshot:      snapshotPath(snap),
+			StartRevision: snap.StartRevision,
+			LastRevision:  min(prev.LastRevision, snap.LastRevision),
+			Message:       fmt.Sprintf("revisions %d to %d are also contained in the previous snapshot %s", snap.StartRevision, min(prev.LastRevision, snap.LastRevision), snapshotPath(prev)),
+		}
+	}
+	return nil
+}
+
+// checkIntegrity downloads the snapshot and checks its contents against the SHA256 hash appended to it, and the
+// checksums of the blocks of delta snapshots in the binary format.
+// The events of delta snapshots are also checked to be within the revisions of the snapshot.
+func (v *Verifier) checkIntegrity(snap *brtypes.Snapshot) error {
+	v.logger.Infof("Checking the integrity of snapshot %s", snapshotPath(snap))
+	rc, err := v.store.Fetch(*snap)
+	if err != nil {
+		return fmt.Errorf("failed to fetch snapshot: %v", err)
+	}
+	defer rc.Close()
+
+	if snap.Kind == brtypes.SnapshotKindFull {
+		return v.checkFullSnapshotIntegrity(rc, snap)
+	}
+
+	isCompressed, compressionPolicy, err := compressor.IsSnapshotCompressed(snap.CompressionSuffix)
+	if err != nil {
+		return err
+	}
+	if isCompressed {
+		if rc, err = compressor.DecompressSnapshot(rc, compressionPolicy); err != nil {
+			return fmt.Errorf("unable to decompress the snapshot: %v", err)
+		}
+		defer rc.Close()
+	}
+	decoder, err := delta.NewDecoder(rc)
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := decoder.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if rev := event.EtcdEvent.Kv.ModRevision; rev < snap.StartRevision || rev > snap.LastRevision {
+			return fmt.Errorf("snapshot contains an event with revision %d outside of its revisions %d to %d", rev, snap.StartRevision, snap.LastRevision)
+		}
+	}
+}
+
+// checkFullSnapshotIntegrity decompresses the full snapshot into a temporary file to check its SHA256 hash.
+func (v *Verifier) checkFullSnapshotIntegrity(rc io.ReadCloser, snap *brtypes.Snapshot) error {
+	isCompressed, compressionPolicy, err := compressor.IsSnapshotCompressed(snap.CompressionSuffix)
+	if err != nil {
+		return err
+	}
+	if isCompressed {
+		if rc, err = compressor.DecompressSnapshot(rc, compressionPolicy); err != nil {
+			return fmt.Errorf("unable to decompress the snapshot: %v", err)
+		}
+		defer rc.Close()
+	}
+
+	tempFile, err := os.CreateTemp(v.config.TempDir, "verify-*.db")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	tempFilePath := tempFile.Name()
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %v", err)
+	}
+	defer func() {
+		if err := os.Remove(tempFilePath); err != nil && !os.IsNotExist(err) {
+			v.logger.Warnf("Failed to remove temporary file %s: %v", tempFilePath, err)
+		}
+	}()
+
+	db, err := etcdutil.CheckFullSnapshotIntegrity(rc, tempFilePath, v.logger)
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// addProblem adds the problem to the report, and marks the chain it belongs to as unhealthy.
+func (r *Report) addProblem(chain *Chain, problem Problem) {
+	r.Problems = append(r.Problems, problem)
+	if chain != nil {
+		chain.Healthy = false
+	}
+}
+
+// addChain adds the chain to the report once all its snapshots have been verified.
+func (r *Report) addChain(chain *Chain) {
+	if chain != nil {
+		r.Chains = append(r.Chains, *chain)
+	}
+}
+
+func snapshotPath(snap *brtypes.Snapshot) string {
+	return path.Join(snap.Prefix, snap.SnapDir, snap.SnapName)
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package verifier_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/compressor"
+	"github.com/gardener/etcd-backup-restore/pkg/snapshot/verifier"
+	"github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verifier", func() {
+	var (
+		store  brtypes.SnapStore
+		config *brtypes.VerifierConfig
+		logger = logrus.New().WithField("suite", "verifier")
+	)
+
+	BeforeEach(func() {
+		var err error
+		store, err = snapstore.NewLocalSnapStore(filepath.Join(GinkgoT().TempDir(), "v2"))
+		Expect(err).ShouldNot(HaveOccurred())
+		config = brtypes.NewVerifierConfig()
+		config.TempDir = GinkgoT().TempDir()
+	})
+
+	verify := func() *verifier.Report {
+		v, err := verifier.NewVerifier(store, config, logger)
+		Expect(err).ShouldNot(HaveOccurred())
+		report, err := v.Verify(context.TODO())
+		Expect(err).ShouldNot(HaveOccurred())
+		return report
+	}
+
+	problemTypes := func(report *verifier.Report) []verifier.ProblemType {
+		var types []verifier.ProblemType
+		for _, problem := range report.Problems {
+			types = append(types, problem.Type)
+		}
+		return types
+	}
+
+	Context("with consistent backups", func() {
+		It("should report healthy chains", func() {
+			saveFullSnapshot(store, 0, 10, compressor.GzipCompressionPolicy, false)
+			saveDeltaSnapshot(store, 11, 15, false)
+			saveDeltaSnapshot(store, 16, 20, false)
+			saveFullSnapshot(store, 0, 20, "", false)
+			saveDeltaSnapshot(store, 21, 30, false)
+
+			report := verify()
+			Expect(report.Healthy).To(BeTrue())
+			Expect(report.IntegrityChecked).To(BeTrue())
+			Expect(report.Problems).To(BeEmpty())
+			Expect(report.Snapshots).To(Equal(5))
+			Expect(report.Chains).To(HaveLen(2))
+			Expect(report.Chains[0].DeltaSnapshots).To(Equal(2))
+			Expect(report.Chains[0].LastRevision).To(Equal(int64(20)))
+			Expect(report.Chains[0].Healthy).To(BeTrue())
+			Expect(report.Chains[1].DeltaSnapshots).To(Equal(1))
+			Expect(report.Chains[1].LastRevision).To(Equal(int64(30)))
+			Expect(report.Chains[1].Healthy).To(BeTrue())
+		})
+	})
+
+	Context("with inconsistent revisions", func() {
+		It("should report gaps and overlaps", func() {
+			saveFullSnapshot(store, 0, 10, "", false)
+			saveDeltaSnapshot(store, 11, 15, false)
+			saveDeltaSnapshot(store, 18, 20, false)
+			saveFullSnapshot(store, 0, 20, "", false)
+			saveDeltaSnapshot(store, 21, 30, false)
+			saveDeltaSnapshot(store, 28, 35, false)
+
+			report := verify()
+			Expect(report.Healthy).To(BeFalse())
+			Expect(problemTypes(report)).To(Equal([]verifier.ProblemType{verifier.ProblemRevisionGap, verifier.ProblemRevisionOverlap}))
+			Expect(report.Problems[0].StartRevision).To(Equal(int64(16)))
+			Expect(report.Problems[0].LastRevision).To(Equal(int64(17)))
+			Expect(report.Problems[1].StartRevision).To(Equal(int64(28)))
+			Expect(report.Problems[1].LastRevision).To(Equal(int64(30)))
+			Expect(report.Chains).To(HaveLen(2))
+			Expect(report.Chains[0].Healthy).To(BeFalse())
+			Expect(report.Chains[1].Healthy).To(BeFalse())
+		})
+
+		It("should report delta snapshots without full snapshot", func() {
+			saveDeltaSnapshot(store, 11, 15, false)
+			saveFullSnapshot(store, 0, 15, "", false)
+
+			report := verify()
+			Expect(problemTypes(report)).To(Equal([]verifier.ProblemType{verifier.ProblemMissingFullSnapshot}))
+			Expect(report.Chains).To(HaveLen(2))
+			Expect(report.Chains[0].FullSnapshot).To(BeEmpty())
+			Expect(report.Chains[0].Healthy).To(BeFalse())
+			Expect(report.Chains[1].Healthy).To(BeTrue())
+		})
+	})
+
+	Context("with orphaned chunks", func() {
+		It("should report the chunks", func() {
+			saveFullSnapshot(store, 0, 10, "", false)
+			chunk := brtypes.Snapshot{
+				Kind:     brtypes.SnapshotKindFull,
+				SnapName: "Full-00000000-00000020-1700000000/0000000001",
+				IsChunk:  true,
+			}
+			Expect(store.Save(chunk, io.NopCloser(bytes.NewReader([]byte("chunk"))))).To(Succeed())
+
+			report := verify()
+			Expect(report.Chunks).To(Equal(1))
+			Expect(problemTypes(report)).To(Equal([]verifier.ProblemType{verifier.ProblemOrphanedChunk}))
+			Expect(report.Problems[0].Snapshot).To(HaveSuffix(chunk.SnapName))
+			Expect(report.Chains).To(HaveLen(1))
+			Expect(report.Chains[0].Healthy).To(BeTrue())
+		})
+	})
+
+	Context("with corrupted snapshots", func() {
+		BeforeEach(func() {
+			saveFullSnapshot(store, 0, 10, compressor.ZstdCompressionPolicy, true)
+			saveDeltaSnapshot(store, 11, 15, true)
+		})
+
+		It("should report the snapshots failing the integrity check", func() {
+			report := verify()
+			Expect(problemTypes(report)).To(Equal([]verifier.ProblemType{verifier.ProblemIntegrityCheckFailed, verifier.ProblemIntegrityCheckFailed}))
+			Expect(report.Chains[0].Healthy).To(BeFalse())
+		})
+
+		It("should not download the snapshots if the integrity check is skipped", func() {
+			config.SkipIntegrityCheck = true
+			report := verify()
+			Expect(report.Healthy).To(BeTrue())
+			Expect(report.IntegrityChecked).To(BeFalse())
+		})
+	})
+
+	It("should report delta snapshots with events outside of their revisions", func() {
+		saveFullSnapshot(store, 0, 10, "", false)
+		snap := newSnapshot(brtypes.SnapshotKindDelta, 11, 15, "")
+		Expect(store.Save(*snap, withHash(eventsData(11, 16), false))).To(Succeed())
+
+		report := verify()
+		Expect(problemTypes(report)).To(Equal([]verifier.ProblemType{verifier.ProblemIntegrityCheckFailed}))
+		Expect(report.Problems[0].Message).To(ContainSubstring("revision 16"))
+	})
+})
+
+// createdOn is advanced for every saved snapshot, so that snapshots with the same last revision are listed in the order they were saved.
+var createdOn = time.Now().UTC()
+
+func newSnapshot(kind string, startRevision, lastRevision int64, compressionSuffix string) *brtypes.Snapshot {
+	createdOn = createdOn.Add(time.Second)
+	snap := snapstore.NewSnapshot(kind, startRevision, lastRevision, compressionSuffix, false)
+	snap.CreatedOn = createdOn
+	snap.GenerateSnapshotName()
+	return snap
+}
+
+func saveFullSnapshot(store brtypes.SnapStore, startRevision, lastRevision int64, compressionPolicy string, corrupt bool) {
+	suffix, err := compressor.GetCompressionSuffix(len(compressionPolicy) != 0, compressionPolicy)
+	Expect(err).ShouldNot(HaveOccurred())
+	snap := newSnapshot(brtypes.SnapshotKindFull, startRevision, lastRevision, suffix)
+
+	// the size of the etcd database is a multiple of the page size, which the integrity check relies on.
+	rc := withHash(bytes.Repeat([]byte{byte(lastRevision)}, 4096), corrupt)
+	if len(compressionPolicy) != 0 {
+		rc, err = compressor.CompressSnapshot(rc, compressionPolicy, 0)
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	Expect(store.Save(*snap, rc)).To(Succeed())
+}
+
+func saveDeltaSnapshot(store brtypes.SnapStore, startRevision, lastRevision int64, corrupt bool) {
+	snap := newSnapshot(brtypes.SnapshotKindDelta, startRevision, lastRevision, "")
+	Expect(store.Save(*snap, withHash(eventsData(startRevision, lastRevision), corrupt))).To(Succeed())
+}
+
+func eventsData(startRevision, lastRevision int64) []byte {
+	var events []brtypes.Event
+	for rev := startRevision; rev <= lastRevision; rev++ {
+		events = append(events, brtypes.Event{
+			EtcdEvent: &clientv3.Event{
+				Type: mvccpb.PUT,
+				Kv:   &mvccpb.KeyValue{Key: []byte("/key"), Value: []byte("value"), ModRevision: rev},
+			},
+		})
+	}
+	data, err := json.Marshal(events)
+	Expect(err).ShouldNot(HaveOccurred())
+	return data
+}
+
+func withHash(data []byte, corrupt bool) io.ReadCloser {
+	hash := sha256.Sum256(data)
+	if corrupt {
+		data[0]++
+	}
+	return io.NopCloser(bytes.NewReader(append(data, hash[:]...)))
+}
@@ -271,7 +271,10 @@ func (a *ABSSnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 	// Last element of the tokens is backup version
 	// Consider the parent of the backup version level (Required for Backward Compatibility)
 	prefix := path.Join(strings.Join(prefixTokens[:len(prefixTokens)-1], "/"))
-	var snapList brtypes.SnapList
+	var (
+		snapList     brtypes.SnapList
+		sidecarPaths []string
+	)
 
 	// Prefix is compulsory here, since the container could potentially be used by other instances of etcd-backup-restore
 	pager := a.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix,
@@ -291,6 +294,10 @@ func (a *ABSSnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 	blob:
 		for _, blobItem := range resp.Segment.BlobItems {
 			// process the blobs returned in the result segment
+			if isSnapshotSidecar(*blobItem.Name) {
+				sidecarPaths = append(sidecarPaths, *blobItem.Name)
+				continue
+			}
 			if strings.Contains(*blobItem.Name, backupVersionV1) || strings.Contains(*blobItem.Name, backupVersionV2) {
 				snapshot, err := ParseSnapshot(*blobItem.Name)
 				if err != nil {
@@ -310,12 +317,16 @@ func (a *ABSSnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 					if blobItem.Properties.ImmutabilityPolicyExpiresOn != nil {
 						snapshot.ImmutabilityExpiryTime = *blobItem.Properties.ImmutabilityPolicyExpiresOn
 					}
+					if blobItem.Properties.ContentLength != nil {
+						snapshot.Size = *blobItem.Properties.ContentLength
+					}
 					snapList = append(snapList, snapshot)
 				}
 			}
 		}
 	}
 
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 	return snapList, nil
 }
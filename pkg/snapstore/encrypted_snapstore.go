@@ -0,0 +1,361 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// encryptionMagic marks the beginning of a snapshot encrypted by the EncryptedSnapStore.
+	encryptionMagic = "EBRENC"
+	// encryptionFormatVersion is the version of the format of the encrypted snapshots.
+	encryptionFormatVersion byte = 1
+	// encryptionKeySize is the size of both the key-encryption key and the data keys, i.e. AES-256.
+	encryptionKeySize = 32
+	// encryptionKeyIDSize is the size of the identifier of the key-encryption key stored in the header.
+	encryptionKeyIDSize = 8
+	// encryptionNoncePrefixSize is the size of the random nonce prefix of the segments of a snapshot.
+	encryptionNoncePrefixSize = 7
+	// encryptionSegmentSize is the size of plaintext sealed in one segment.
+	encryptionSegmentSize = 64 * 1024
+	// encryptionSegmentHeaderSize is the size of the header of a segment, i.e. the final flag and the ciphertext length.
+	encryptionSegmentHeaderSize = 5
+)
+
+var (
+	// ErrEncryptionKeyMismatch is returned if a snapshot was encrypted with a different key-encryption key.
+	ErrEncryptionKeyMismatch = errors.New("snapshot was encrypted with a different key-encryption key")
+	// ErrEncryptedSnapshotTruncated is returned if an encrypted snapshot ends before its final segment.
+	ErrEncryptedSnapshotTruncated = errors.New("encrypted snapshot is truncated")
+	// ErrSnapshotNotEncrypted is returned if a snapshot saved without encryption is fetched while plaintext snapshots are not allowed.
+	ErrSnapshotNotEncrypted = errors.New("snapshot is not encrypted")
+)
+
+// EncryptedSnapStore wraps a SnapStore to encrypt the snapshots on the client side before they are saved,
+// and to decrypt them when they are fetched.
+// Every snapshot is encrypted with its own random data key using AES-256-GCM. The data key is wrapped
+// with the key-encryption key and stored in the header of the snapshot, which every segment authenticates.
+// Snapshots which were saved without encryption are only fetched, as is, if plaintext snapshots are allowed,
+// e.g. during the migration of an existing store to encryption.
+type EncryptedSnapStore struct {
+	brtypes.SnapStore
+	kek            cipher.AEAD
+	kekID          []byte
+	allowPlaintext bool
+}
+
+// NewEncryptedSnapStore returns an EncryptedSnapStore which wraps the given store and
+// uses the key-encryption key read from keyFile. Fetching a snapshot saved without encryption
+// fails unless allowPlaintext is set.
+func NewEncryptedSnapStore(store brtypes.SnapStore, keyFile string, allowPlaintext bool) (*EncryptedSnapStore, error) {
+	key, err := readEncryptionKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	keyHash := sha256.Sum256(key)
+	return &EncryptedSnapStore{
+		SnapStore:      store,
+		kek:            kek,
+		kekID:          keyHash[:encryptionKeyIDSize],
+		allowPlaintext: allowPlaintext,
+	}, nil
+}
+
+// readEncryptionKey reads the key-encryption key from the given file.
+// The file must contain the 32 byte key either as is or base64 encoded.
+func readEncryptionKey(keyFile string) ([]byte, error) {
+	data, err := os.ReadFile(keyFile) // #nosec G304 -- this is a trusted file path of the mounted key-encryption key.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the encryption key file %s: %v", keyFile, err)
+	}
+	if len(data) == encryptionKeySize {
+		return data, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf("encryption key in file %s must be %d bytes long, either raw or base64 encoded", keyFile, encryptionKeySize)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the AES cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// headerPrefix returns the part of the header which is authenticated along with the wrapped data key.
+func (s *EncryptedSnapStore) headerPrefix() []byte {
+	return append(append([]byte(encryptionMagic), encryptionFormatVersion), s.kekID...)
+}
+
+// Save encrypts the snapshot and saves it to the underlying store.
+func (s *EncryptedSnapStore) Save(snap brtypes.Snapshot, rc io.ReadCloser) error {
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return fmt.Errorf("failed to generate data key for snapshot %s: %v", snap.SnapName, err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return err
+	}
+
+	// header: magic | version | kek id | data key nonce | wrapped data key | segment nonce prefix
+	header := s.headerPrefix()
+	wrapNonce := make([]byte, s.kek.NonceSize())
+	noncePrefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := rand.Read(wrapNonce); err != nil {
+		return fmt.Errorf("failed to generate nonce for snapshot %s: %v", snap.SnapName, err)
+	}
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return fmt.Errorf("failed to generate nonce for snapshot %s: %v", snap.SnapName, err)
+	}
+	wrappedKey := s.kek.Seal(nil, wrapNonce, dataKey, header)
+	header = append(header, wrapNonce...)
+	header = append(header, wrappedKey...)
+	header = append(header, noncePrefix...)
+
+	return s.SnapStore.Save(snap, &encryptingReadCloser{
+		src:         rc,
+		aead:        aead,
+		header:      header,
+		noncePrefix: noncePrefix,
+		out:         header,
+		plain:       make([]byte, encryptionSegmentSize),
+	})
+}
+
+// Fetch fetches the snapshot from the underlying store and decrypts it. A snapshot saved without
+// encryption is returned as is if plaintext snapshots are allowed, and ErrSnapshotNotEncrypted otherwise.
+func (s *EncryptedSnapStore) Fetch(snap brtypes.Snapshot) (io.ReadCloser, error) {
+	rc, err := s.SnapStore.Fetch(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(rc)
+	magic, err := br.Peek(len(encryptionMagic))
+	if err != nil || string(magic) != encryptionMagic {
+		// snapshot was saved before encryption got enabled, or by someone without the key-encryption key
+		if !s.allowPlaintext {
+			_ = rc.Close()
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotNotEncrypted, snap.SnapName)
+		}
+		logrus.Warnf("Snapshot %s is not encrypted, fetching it as plaintext.", snap.SnapName)
+		return &readCloser{Reader: br, Closer: rc}, nil
+	}
+
+	header, dataKey, noncePrefix, err := s.readHeader(br)
+	if err != nil {
+		_ = rc.Close()
+		return nil, fmt.Errorf("failed to read encryption header of snapshot %s: %w", snap.SnapName, err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return &decryptingReadCloser{
+		src:         br,
+		closer:      rc,
+		aead:        aead,
+		header:      header,
+		noncePrefix: noncePrefix,
+	}, nil
+}
+
+// readHeader reads the header of an encrypted snapshot and returns the header, the unwrapped data key and the segment
+// nonce prefix.
+func (s *EncryptedSnapStore) readHeader(r io.Reader) ([]byte, []byte, []byte, error) {
+	prefix := make([]byte, len(encryptionMagic)+1+encryptionKeyIDSize)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, nil, nil, err
+	}
+	if version := prefix[len(encryptionMagic)]; version != encryptionFormatVersion {
+		return nil, nil, nil, fmt.Errorf("unsupported encryption format version %d", version)
+	}
+	if !bytes.Equal(prefix[len(encryptionMagic)+1:], s.kekID) {
+		return nil, nil, nil, ErrEncryptionKeyMismatch
+	}
+
+	rest := make([]byte, s.kek.NonceSize()+encryptionKeySize+s.kek.Overhead()+encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, nil, nil, err
+	}
+	wrapNonce := rest[:s.kek.NonceSize()]
+	wrappedKey := rest[s.kek.NonceSize() : len(rest)-encryptionNoncePrefixSize]
+	noncePrefix := rest[len(rest)-encryptionNoncePrefixSize:]
+
+	dataKey, err := s.kek.Open(nil, wrapNonce, wrappedKey, prefix)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+	return append(prefix, rest...), dataKey, noncePrefix, nil
+}
+
+// segmentNonce returns the nonce of the segment with the given index.
+// The final flag is part of the nonce, so that a truncated snapshot can't pass as complete.
+func segmentNonce(noncePrefix []byte, index uint32, final bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefixSize+5)
+	nonce = append(nonce, noncePrefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, index)
+	if final {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// encryptingReadCloser encrypts the plaintext read from src in segments. Every segment authenticates the header of
+// the snapshot as additional data.
+type encryptingReadCloser struct {
+	src         io.ReadCloser
+	aead        cipher.AEAD
+	header      []byte
+	noncePrefix []byte
+	index       uint32
+	plain       []byte
+	out         []byte
+	done        bool
+}
+
+func (e *encryptingReadCloser) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.sealNextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+func (e *encryptingReadCloser) sealNextSegment() error {
+	n, err := io.ReadFull(e.src, e.plain)
+	final := false
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		final = true
+	} else if err != nil {
+		return err
+	}
+	if e.index == math.MaxUint32 && !final {
+		return fmt.Errorf("snapshot is too large to be encrypted")
+	}
+
+	ciphertext := e.aead.Seal(nil, segmentNonce(e.noncePrefix, e.index, final), e.plain[:n], e.header)
+	segment := make([]byte, encryptionSegmentHeaderSize, encryptionSegmentHeaderSize+len(ciphertext))
+	if final {
+		segment[0] = 1
+	}
+	binary.BigEndian.PutUint32(segment[1:], uint32(len(ciphertext))) // #nosec G115 -- ciphertext of a segment is much smaller than MaxUint32.
+	e.out = append(segment, ciphertext...)
+	e.index++
+	e.done = final
+	return nil
+}
+
+func (e *encryptingReadCloser) Close() error {
+	return e.src.Close()
+}
+
+// decryptingReadCloser decrypts the segments read from src, which must authenticate the given header of the snapshot.
+type decryptingReadCloser struct {
+	src         io.Reader
+	closer      io.Closer
+	aead        cipher.AEAD
+	header      []byte
+	noncePrefix []byte
+	index       uint32
+	out         []byte
+	done        bool
+}
+
+func (d *decryptingReadCloser) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.openNextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+func (d *decryptingReadCloser) openNextSegment() error {
+	segmentHeader := make([]byte, encryptionSegmentHeaderSize)
+	if _, err := io.ReadFull(d.src, segmentHeader); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrEncryptedSnapshotTruncated
+		}
+		return err
+	}
+	final := segmentHeader[0] == 1
+	length := binary.BigEndian.Uint32(segmentHeader[1:])
+	if length > encryptionSegmentSize+uint32(d.aead.Overhead()) { // #nosec G115 -- overhead of GCM is 16 bytes.
+		return fmt.Errorf("invalid encrypted segment length %d", length)
+	}
+
+	ciphertext := make([]byte, length)
+	if _, err := io.ReadFull(d.src, ciphertext); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrEncryptedSnapshotTruncated
+		}
+		return err
+	}
+	plain, err := d.aead.Open(nil, segmentNonce(d.noncePrefix, d.index, final), ciphertext, d.header)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt segment %d of snapshot: %v", d.index, err)
+	}
+	if final {
+		// data appended after the final segment is not authenticated.
+		if _, err := io.ReadFull(d.src, make([]byte, 1)); err != io.EOF {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("unexpected data after the final segment of the encrypted snapshot")
+		}
+	}
+	d.out = plain
+	d.index++
+	d.done = final
+	return nil
+}
+
+func (d *decryptingReadCloser) Close() error {
+	return d.closer.Close()
+}
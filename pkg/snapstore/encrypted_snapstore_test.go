@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encrypted snapstore", func() {
+	var (
+		localStore *LocalSnapStore
+		store      *EncryptedSnapStore
+		keyDir     string
+		snap       brtypes.Snapshot
+		data       []byte
+	)
+
+	writeKey := func(name string, key []byte) string {
+		keyFile := filepath.Join(keyDir, name)
+		Expect(os.WriteFile(keyFile, key, 0600)).To(Succeed())
+		return keyFile
+	}
+
+	readAll := func(rc io.ReadCloser) ([]byte, error) {
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+
+	BeforeEach(func() {
+		var err error
+		keyDir = GinkgoT().TempDir()
+		storeDir := GinkgoT().TempDir()
+		localStore, err = NewLocalSnapStore(storeDir)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		key := make([]byte, 32)
+		_, err = rand.Read(key)
+		Expect(err).ShouldNot(HaveOccurred())
+		store, err = NewEncryptedSnapStore(localStore, writeKey("kek", key), false)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// spans multiple segments with a partial final segment
+		data = make([]byte, 200*1024+7)
+		_, err = rand.Read(data)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		snap = brtypes.Snapshot{
+			Kind:          brtypes.SnapshotKindFull,
+			StartRevision: 0,
+			LastRevision:  1,
+			CreatedOn:     time.Now().UTC(),
+			Prefix:        storeDir,
+		}
+		snap.GenerateSnapshotName()
+	})
+
+	It("should encrypt the snapshot on save and decrypt it on fetch", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		rc, err := localStore.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		raw, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(bytes.Contains(raw, data[:1024])).To(BeFalse())
+
+		rc, err = store.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		decrypted, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(decrypted).To(Equal(data))
+	})
+
+	It("should encrypt and decrypt an empty snapshot", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(nil)))).To(Succeed())
+
+		rc, err := store.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		decrypted, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(decrypted).To(BeEmpty())
+	})
+
+	It("should fetch snapshots saved without encryption as is if plaintext snapshots are allowed", func() {
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		plaintextStore, err := NewEncryptedSnapStore(localStore, writeKey("kek-plaintext", bytes.Repeat([]byte{5}, 32)), true)
+		Expect(err).ShouldNot(HaveOccurred())
+		rc, err := plaintextStore.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		fetched, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fetched).To(Equal(data))
+	})
+
+	It("should fail to fetch snapshots saved without encryption if plaintext snapshots are not allowed", func() {
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		_, err := store.Fetch(snap)
+		Expect(err).Should(MatchError(ErrSnapshotNotEncrypted))
+
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(nil)))).To(Succeed())
+		_, err = store.Fetch(snap)
+		Expect(err).Should(MatchError(ErrSnapshotNotEncrypted))
+	})
+
+	It("should accept a base64 encoded key-encryption key", func() {
+		key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+		_, err := NewEncryptedSnapStore(localStore, writeKey("kek-base64", []byte(key+"\n")), false)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should fail to create the store with a key of invalid length", func() {
+		_, err := NewEncryptedSnapStore(localStore, writeKey("kek-short", []byte("too-short")), false)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should fail to fetch a snapshot encrypted with a different key-encryption key", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		otherStore, err := NewEncryptedSnapStore(localStore, writeKey("other-kek", bytes.Repeat([]byte{3}, 32)), false)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = otherStore.Fetch(snap)
+		Expect(err).Should(MatchError(ErrEncryptionKeyMismatch))
+	})
+
+	It("should fail to read a truncated snapshot", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		rc, err := localStore.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		raw, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(raw[:len(raw)/2])))).To(Succeed())
+
+		rc, err = store.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = readAll(rc)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("should fail to decrypt the segments of a snapshot whose header was modified", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		rc, err := localStore.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		raw, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		// the nonce prefix of the segments ends the header, after magic, version, kek id, nonce and wrapped data key
+		raw[len("EBRENC")+1+8+12+32+16+6] ^= 0xff
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(raw)))).To(Succeed())
+
+		rc, err = store.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = readAll(rc)
+		Expect(err).Should(MatchError(ContainSubstring("failed to decrypt segment 0")))
+	})
+
+	It("should fail to read a snapshot with data appended after its final segment", func() {
+		Expect(store.Save(snap, io.NopCloser(bytes.NewReader(data)))).To(Succeed())
+
+		rc, err := localStore.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		raw, err := readAll(rc)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(localStore.Save(snap, io.NopCloser(bytes.NewReader(append(raw, "appended"...))))).To(Succeed())
+
+		rc, err = store.Fetch(snap)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = readAll(rc)
+		Expect(err).Should(MatchError(ContainSubstring("unexpected data after the final segment")))
+	})
+})
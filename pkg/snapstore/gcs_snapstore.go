@@ -328,8 +328,15 @@ func (s *GCSSnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 		attrs = append(attrs, attr)
 	}
 
-	var snapList brtypes.SnapList
+	var (
+		snapList     brtypes.SnapList
+		sidecarPaths []string
+	)
 	for _, v := range attrs {
+		if isSnapshotSidecar(v.Name) {
+			sidecarPaths = append(sidecarPaths, v.Name)
+			continue
+		}
 		if strings.Contains(v.Name, backupVersionV1) || strings.Contains(v.Name, backupVersionV2) {
 			snap, err := ParseSnapshot(v.Name)
 			if err != nil {
@@ -337,10 +344,12 @@ func (s *GCSSnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 				continue
 			}
 			snap.ImmutabilityExpiryTime = v.RetentionExpirationTime
+			snap.Size = v.Size
 			snapList = append(snapList, snap)
 		}
 	}
 
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 	return snapList, nil
 }
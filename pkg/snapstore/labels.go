@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// labelsManifestSuffix is the suffix appended to the name of a snapshot to form the name of its labels manifest.
+const labelsManifestSuffix = ".labels"
+
+// labelsManifest is the content of the object which holds the labels of a snapshot.
+type labelsManifest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// isLabelsManifest returns true if the object at the given path is a labels manifest.
+func isLabelsManifest(objectPath string) bool {
+	return strings.HasSuffix(objectPath, labelsManifestSuffix) && len(path.Base(objectPath)) > len(labelsManifestSuffix)
+}
+
+// applyLabelsManifests marks the snapshots of the list which have a labels manifest at one of the given paths as labelled.
+func applyLabelsManifests(snapList brtypes.SnapList, manifestPaths []string) {
+	if len(manifestPaths) == 0 {
+		return
+	}
+	snapPaths := make(map[string]struct{}, len(manifestPaths))
+	for _, manifestPath := range manifestPaths {
+		snapPaths[path.Clean(strings.TrimSuffix(manifestPath, labelsManifestSuffix))] = struct{}{}
+	}
+	for _, snap := range snapList {
+		if _, ok := snapPaths[path.Join(snap.Prefix, snap.SnapDir, snap.SnapName)]; ok {
+			snap.HasLabels = true
+		}
+	}
+}
+
+// labelsManifestOf returns the labels manifest of the given snapshot.
+func labelsManifestOf(snap *brtypes.Snapshot) brtypes.Snapshot {
+	return brtypes.Snapshot{
+		Prefix:   snap.Prefix,
+		SnapDir:  snap.SnapDir,
+		SnapName: snap.SnapName + labelsManifestSuffix,
+	}
+}
+
+// SaveSnapshotLabels stores the given labels of the given snapshot in a labels manifest next to the snapshot.
+func SaveSnapshotLabels(store brtypes.SnapStore, snap *brtypes.Snapshot, labels map[string]string) error {
+	if snap.IsChunk {
+		return fmt.Errorf("chunks cannot be labelled, %s is a chunk", snap.SnapName)
+	}
+	if len(snap.SnapDir) != 0 {
+		return fmt.Errorf("snapshot %s has the %s backup format, which does not support labels", path.Join(snap.SnapDir, snap.SnapName), backupVersionV1)
+	}
+	if err := brtypes.ValidateSnapshotLabels(labels); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(labelsManifest{Labels: labels})
+	if err != nil {
+		return err
+	}
+	if err := store.Save(labelsManifestOf(snap), io.NopCloser(bytes.NewReader(data))); err != nil {
+		return fmt.Errorf("failed to save labels of snapshot %s: %v", snap.SnapName, err)
+	}
+	snap.Labels = labels
+	snap.HasLabels = true
+	return nil
+}
+
+// LoadSnapshotLabels loads the labels of the snapshots of the given list which are labelled.
+// The labels are not loaded by SnapStore.List, since they are stored in separate objects.
+func LoadSnapshotLabels(store brtypes.SnapStore, snapList brtypes.SnapList) error {
+	for _, snap := range snapList {
+		if !snap.HasLabels || snap.Labels != nil {
+			continue
+		}
+		rc, err := store.Fetch(labelsManifestOf(snap))
+		if err != nil {
+			return fmt.Errorf("failed to fetch labels of snapshot %s: %v", snap.SnapName, err)
+		}
+		var manifest labelsManifest
+		err = json.NewDecoder(rc).Decode(&manifest)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to decode labels of snapshot %s: %v", snap.SnapName, err)
+		}
+		snap.Labels = manifest.Labels
+	}
+	return nil
+}
+
+// DeleteSnapshotLabels deletes the labels manifest of the given snapshot.
+func DeleteSnapshotLabels(store brtypes.SnapStore, snap *brtypes.Snapshot) error {
+	if err := store.Delete(labelsManifestOf(snap)); err != nil {
+		return fmt.Errorf("failed to delete labels of snapshot %s: %v", snap.SnapName, err)
+	}
+	snap.Labels = nil
+	snap.HasLabels = false
+	return nil
+}
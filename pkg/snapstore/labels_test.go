@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore_test
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot labels", func() {
+	var (
+		store    *LocalSnapStore
+		fullSnap brtypes.Snapshot
+	)
+
+	BeforeEach(func() {
+		var err error
+		// the prefix of the store ends with the backup version, as for the configured stores.
+		storeDir := filepath.Join(GinkgoT().TempDir(), "v2")
+		store, err = NewLocalSnapStore(storeDir)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		fullSnap = brtypes.Snapshot{
+			Kind:          brtypes.SnapshotKindFull,
+			StartRevision: 0,
+			LastRevision:  10,
+			CreatedOn:     time.Now().UTC(),
+			Prefix:        storeDir,
+		}
+		fullSnap.GenerateSnapshotName()
+		Expect(store.Save(fullSnap, io.NopCloser(bytes.NewReader([]byte("data"))))).To(Succeed())
+	})
+
+	It("should list a labelled snapshot as labelled and load its labels on demand", func() {
+		labels := map[string]string{brtypes.SnapshotLabelReason: "before the upgrade", "ticket": "OPS-1"}
+		Expect(SaveSnapshotLabels(store, &fullSnap, labels)).To(Succeed())
+
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(1))
+		Expect(snapList[0].HasLabels).To(BeTrue())
+		Expect(snapList[0].Labels).To(BeNil())
+
+		Expect(LoadSnapshotLabels(store, snapList)).To(Succeed())
+		Expect(snapList[0].Labels).To(Equal(labels))
+		Expect(snapList[0].MatchesLabels(map[string]string{"ticket": "OPS-1"})).To(BeTrue())
+		Expect(snapList[0].MatchesLabels(map[string]string{"ticket": "OPS-2"})).To(BeFalse())
+	})
+
+	It("should delete the labels of a snapshot", func() {
+		Expect(SaveSnapshotLabels(store, &fullSnap, map[string]string{"ticket": "OPS-1"})).To(Succeed())
+		Expect(DeleteSnapshotLabels(store, &fullSnap)).To(Succeed())
+
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(1))
+		Expect(snapList[0].HasLabels).To(BeFalse())
+	})
+
+	It("should list a snapshot which is both pinned and labelled", func() {
+		Expect(SaveSnapshotLabels(store, &fullSnap, map[string]string{"ticket": "OPS-1"})).To(Succeed())
+		Expect(PinSnapshot(store, &fullSnap, time.Time{})).To(Succeed())
+
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(1))
+		Expect(snapList[0].HasLabels).To(BeTrue())
+		Expect(snapList[0].IsPinned()).To(BeTrue())
+	})
+
+	It("should reject invalid labels", func() {
+		Expect(SaveSnapshotLabels(store, &fullSnap, map[string]string{"-ticket": "OPS-1"})).NotTo(Succeed())
+		_, err := brtypes.ParseSnapshotLabels("reason")
+		Expect(err).To(HaveOccurred())
+		labels, err := brtypes.ParseSnapshotLabels("reason=pre-upgrade, ticket=OPS-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(labels).To(Equal(map[string]string{"reason": "pre-upgrade", "ticket": "OPS-1"}))
+	})
+})
@@ -76,6 +76,7 @@ func (s *LocalSnapStore) List(_ bool) (brtypes.SnapList, error) {
 	prefix := path.Join(strings.Join(prefixTokens[:len(prefixTokens)-1], "/"))
 
 	snapList := brtypes.SnapList{}
+	var sidecarPaths []string
 	err := filepath.Walk(prefix, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			fmt.Printf("prevent panic by handling failure accessing a path %q: %v\n", path, err)
@@ -84,12 +85,17 @@ func (s *LocalSnapStore) List(_ bool) (brtypes.SnapList, error) {
 		if info.IsDir() {
 			return nil
 		}
+		if isSnapshotSidecar(path) {
+			sidecarPaths = append(sidecarPaths, path)
+			return nil
+		}
 		if strings.Contains(path, backupVersionV1) || strings.Contains(path, backupVersionV2) {
 			snap, err := ParseSnapshot(path)
 			if err != nil {
 				// Warning
 				logrus.Warnf("Invalid snapshot found. Ignoring it:%s\n", path)
 			} else {
+				snap.Size = info.Size()
 				snapList = append(snapList, snap)
 			}
 		}
@@ -99,6 +105,7 @@ func (s *LocalSnapStore) List(_ bool) (brtypes.SnapList, error) {
 		return nil, fmt.Errorf("error walking the path %q: %v", prefix, err)
 	}
 
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 	return snapList, nil
 }
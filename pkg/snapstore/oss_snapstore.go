@@ -218,7 +218,10 @@ func (s *OSSSnapStore) List(_ bool) (brtypes.SnapList, error) {
 	// Consider the parent of the backup version level (Required for Backward Compatibility)
 	prefix := path.Join(strings.Join(prefixTokens[:len(prefixTokens)-1], "/"))
 
-	var snapList brtypes.SnapList
+	var (
+		snapList     brtypes.SnapList
+		sidecarPaths []string
+	)
 	var bucketImmutableExpiryTimeInDays *int
 
 	wormCfg, err := s.client.GetBucketWorm(s.bucketName)
@@ -243,6 +246,10 @@ func (s *OSSSnapStore) List(_ bool) (brtypes.SnapList, error) {
 			return nil, err
 		}
 		for _, object := range lsRes.Objects {
+			if isSnapshotSidecar(object.Key) {
+				sidecarPaths = append(sidecarPaths, object.Key)
+				continue
+			}
 			if strings.Contains(object.Key, backupVersionV1) || strings.Contains(object.Key, backupVersionV2) {
 				snap, err := ParseSnapshot(object.Key)
 				if err != nil {
@@ -255,6 +262,7 @@ func (s *OSSSnapStore) List(_ bool) (brtypes.SnapList, error) {
 						// ImmutabilityExpiryTime = SnapshotCreationTime + bucketImmutabilityTimeInDays
 						snap.ImmutabilityExpiryTime = snap.CreatedOn.Add(time.Duration(*bucketImmutableExpiryTimeInDays) * 24 * time.Hour)
 					}
+					snap.Size = object.Size
 					snapList = append(snapList, snap)
 				}
 			}
@@ -264,6 +272,7 @@ func (s *OSSSnapStore) List(_ bool) (brtypes.SnapList, error) {
 		}
 		marker = lsRes.NextMarker
 	}
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 
 	return snapList, nil
@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// pinMarkerSuffix is the suffix appended to the name of a snapshot to form the name of its pin marker.
+// The pin marker of a snapshot pinned until an expiry time is suffixed with the expiry time in unix seconds, e.g. `.pin-1700000000`.
+const pinMarkerSuffix = ".pin"
+
+// pinMarker is the content of the object which marks a snapshot as pinned.
+type pinMarker struct {
+	PinnedOn      time.Time `json:"pinnedOn"`
+	PinExpiryTime time.Time `json:"pinExpiryTime,omitempty"`
+}
+
+// pinMarkerName returns the name of the pin marker of the snapshot with the given name.
+func pinMarkerName(snapName string, expiry time.Time) string {
+	if expiry.IsZero() {
+		return snapName + pinMarkerSuffix
+	}
+	return fmt.Sprintf("%s%s-%d", snapName, pinMarkerSuffix, expiry.Unix())
+}
+
+// parsePinMarker returns the path of the snapshot pinned by the pin marker at the given path, and the expiry time of the pin.
+// It returns false if the path is not a path of a pin marker.
+func parsePinMarker(markerPath string) (string, time.Time, bool) {
+	dir, name := path.Split(markerPath)
+	if snapName, ok := strings.CutSuffix(name, pinMarkerSuffix); ok {
+		if len(snapName) == 0 {
+			return "", time.Time{}, false
+		}
+		return dir + snapName, time.Time{}, true
+	}
+	index := strings.LastIndex(name, pinMarkerSuffix+"-")
+	if index <= 0 {
+		return "", time.Time{}, false
+	}
+	unixTime, err := strconv.ParseInt(name[index+len(pinMarkerSuffix)+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return dir + name[:index], time.Unix(unixTime, 0).UTC(), true
+}
+
+// isPinMarker returns true if the object at the given path is a pin marker.
+func isPinMarker(objectPath string) bool {
+	_, _, ok := parsePinMarker(objectPath)
+	return ok
+}
+
+// applyPinMarkers marks the snapshots of the list which are pinned by the pin markers at the given paths as pinned.
+func applyPinMarkers(snapList brtypes.SnapList, markerPaths []string) {
+	if len(markerPaths) == 0 {
+		return
+	}
+	expiryTimes := make(map[string]time.Time, len(markerPaths))
+	for _, markerPath := range markerPaths {
+		snapPath, expiry, ok := parsePinMarker(markerPath)
+		if !ok {
+			continue
+		}
+		snapPath = path.Clean(snapPath)
+		// an indefinite pin takes precedence over pins with expiry, and a later expiry over an earlier one.
+		if current, found := expiryTimes[snapPath]; found && (current.IsZero() || (!expiry.IsZero() && current.After(expiry))) {
+			continue
+		}
+		expiryTimes[snapPath] = expiry
+	}
+	for _, snap := range snapList {
+		if expiry, ok := expiryTimes[path.Join(snap.Prefix, snap.SnapDir, snap.SnapName)]; ok {
+			snap.Pinned = true
+			snap.PinExpiryTime = expiry
+		}
+	}
+}
+
+// PinSnapshot protects the given full snapshot from garbage collection until the given expiry time, or indefinitely if the expiry time is zero.
+func PinSnapshot(store brtypes.SnapStore, snap *brtypes.Snapshot, expiry time.Time) error {
+	if snap.Kind != brtypes.SnapshotKindFull || snap.IsChunk {
+		return fmt.Errorf("only full snapshots can be pinned, %s is not a full snapshot", snap.SnapName)
+	}
+	if len(snap.SnapDir) != 0 {
+		return fmt.Errorf("snapshot %s has the %s backup format, which does not support pins", path.Join(snap.SnapDir, snap.SnapName), backupVersionV1)
+	}
+	if !expiry.IsZero() {
+		if !expiry.After(time.Now()) {
+			return fmt.Errorf("pin expiry time %s is in the past", expiry.Format(time.RFC3339))
+		}
+		// the expiry time is stored in the name of the pin marker in unix seconds.
+		expiry = time.Unix(expiry.Unix(), 0).UTC()
+	}
+
+	data, err := json.Marshal(pinMarker{PinnedOn: time.Now().UTC(), PinExpiryTime: expiry})
+	if err != nil {
+		return err
+	}
+	marker := brtypes.Snapshot{
+		Prefix:   snap.Prefix,
+		SnapName: pinMarkerName(snap.SnapName, expiry),
+	}
+	if err := store.Save(marker, io.NopCloser(bytes.NewReader(data))); err != nil {
+		return fmt.Errorf("failed to save pin marker of snapshot %s: %v", snap.SnapName, err)
+	}
+	if snap.Pinned && pinMarkerName(snap.SnapName, snap.PinExpiryTime) != marker.SnapName {
+		if err := UnpinSnapshot(store, snap); err != nil {
+			return fmt.Errorf("failed to remove previous pin of snapshot %s: %v", snap.SnapName, err)
+		}
+	}
+	snap.Pinned = true
+	snap.PinExpiryTime = expiry
+	return nil
+}
+
+// UnpinSnapshot removes the pin of the given snapshot, so that it is garbage collected as per the garbage collection policy.
+func UnpinSnapshot(store brtypes.SnapStore, snap *brtypes.Snapshot) error {
+	if !snap.Pinned {
+		return fmt.Errorf("snapshot %s is not pinned", snap.SnapName)
+	}
+	marker := brtypes.Snapshot{
+		Prefix:   snap.Prefix,
+		SnapDir:  snap.SnapDir,
+		SnapName: pinMarkerName(snap.SnapName, snap.PinExpiryTime),
+	}
+	if err := store.Delete(marker); err != nil {
+		return fmt.Errorf("failed to delete pin marker of snapshot %s: %v", snap.SnapName, err)
+	}
+	snap.Pinned = false
+	snap.PinExpiryTime = time.Time{}
+	return nil
+}
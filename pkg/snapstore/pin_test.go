@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore_test
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"time"
+
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+
+	. "github.com/gardener/etcd-backup-restore/pkg/snapstore"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot pins", func() {
+	var (
+		store     *LocalSnapStore
+		fullSnap  brtypes.Snapshot
+		deltaSnap brtypes.Snapshot
+	)
+
+	listSnapshot := func(snapName string) *brtypes.Snapshot {
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		for _, snap := range snapList {
+			if snap.SnapName == snapName {
+				return snap
+			}
+		}
+		Fail("snapshot " + snapName + " not listed")
+		return nil
+	}
+
+	BeforeEach(func() {
+		var err error
+		// the prefix of the store ends with the backup version, as for the configured stores.
+		storeDir := filepath.Join(GinkgoT().TempDir(), "v2")
+		store, err = NewLocalSnapStore(storeDir)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		now := time.Now().UTC()
+		fullSnap = brtypes.Snapshot{
+			Kind:          brtypes.SnapshotKindFull,
+			StartRevision: 0,
+			LastRevision:  10,
+			CreatedOn:     now,
+			Prefix:        storeDir,
+		}
+		fullSnap.GenerateSnapshotName()
+		deltaSnap = brtypes.Snapshot{
+			Kind:          brtypes.SnapshotKindDelta,
+			StartRevision: 11,
+			LastRevision:  20,
+			CreatedOn:     now.Add(time.Second),
+			Prefix:        storeDir,
+		}
+		deltaSnap.GenerateSnapshotName()
+		for _, snap := range []brtypes.Snapshot{fullSnap, deltaSnap} {
+			Expect(store.Save(snap, io.NopCloser(bytes.NewReader([]byte("data"))))).To(Succeed())
+		}
+	})
+
+	It("should list a pinned snapshot as pinned without listing its pin marker", func() {
+		snap := listSnapshot(fullSnap.SnapName)
+		Expect(snap.IsPinned()).To(BeFalse())
+
+		Expect(PinSnapshot(store, snap, time.Time{})).To(Succeed())
+
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(2))
+		snap = listSnapshot(fullSnap.SnapName)
+		Expect(snap.Pinned).To(BeTrue())
+		Expect(snap.PinExpiryTime.IsZero()).To(BeTrue())
+		Expect(snap.IsPinned()).To(BeTrue())
+		Expect(listSnapshot(deltaSnap.SnapName).Pinned).To(BeFalse())
+	})
+
+	It("should list the expiry time of a pin and replace the previous pin", func() {
+		snap := listSnapshot(fullSnap.SnapName)
+		Expect(PinSnapshot(store, snap, time.Time{})).To(Succeed())
+
+		expiry := time.Now().Add(time.Hour)
+		Expect(PinSnapshot(store, snap, expiry)).To(Succeed())
+
+		snap = listSnapshot(fullSnap.SnapName)
+		Expect(snap.IsPinned()).To(BeTrue())
+		Expect(snap.PinExpiryTime).To(Equal(time.Unix(expiry.Unix(), 0).UTC()))
+	})
+
+	It("should not consider a snapshot with an expired pin as pinned", func() {
+		snap := listSnapshot(fullSnap.SnapName)
+		snap.Pinned = true
+		snap.PinExpiryTime = time.Now().Add(-time.Minute)
+		Expect(snap.IsPinned()).To(BeFalse())
+	})
+
+	It("should unpin a pinned snapshot", func() {
+		snap := listSnapshot(fullSnap.SnapName)
+		Expect(PinSnapshot(store, snap, time.Now().Add(time.Hour))).To(Succeed())
+		Expect(UnpinSnapshot(store, listSnapshot(fullSnap.SnapName))).To(Succeed())
+
+		Expect(listSnapshot(fullSnap.SnapName).Pinned).To(BeFalse())
+		Expect(UnpinSnapshot(store, listSnapshot(fullSnap.SnapName))).NotTo(Succeed())
+	})
+
+	It("should not pin delta snapshots", func() {
+		Expect(PinSnapshot(store, listSnapshot(deltaSnap.SnapName), time.Time{})).NotTo(Succeed())
+	})
+
+	It("should not pin a snapshot until a time in the past", func() {
+		Expect(PinSnapshot(store, listSnapshot(fullSnap.SnapName), time.Now().Add(-time.Hour))).NotTo(Succeed())
+		Expect(listSnapshot(fullSnap.SnapName).Pinned).To(BeFalse())
+	})
+
+	It("should only consider the name of an object to find pin markers", func() {
+		var err error
+		storeDir := filepath.Join(GinkgoT().TempDir(), "etcd.pin-1700000000", "v2")
+		store, err = NewLocalSnapStore(storeDir)
+		Expect(err).ShouldNot(HaveOccurred())
+		fullSnap.Prefix = storeDir
+		Expect(store.Save(fullSnap, io.NopCloser(bytes.NewReader([]byte("data"))))).To(Succeed())
+
+		snap := listSnapshot(fullSnap.SnapName)
+		Expect(snap.Pinned).To(BeFalse())
+		Expect(PinSnapshot(store, snap, time.Now().Add(time.Hour))).To(Succeed())
+
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(1))
+		Expect(listSnapshot(fullSnap.SnapName).IsPinned()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Snapshot pins in a versioned S3 bucket", func() {
+	var (
+		store    *S3SnapStore
+		fullSnap brtypes.Snapshot
+	)
+
+	listSnapshot := func() *brtypes.Snapshot {
+		snapList, err := store.List(false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(snapList).To(HaveLen(1))
+		return snapList[0]
+	}
+
+	BeforeEach(func() {
+		store = NewS3FromClient(s3ObjectLockedBucket, prefixV2, GinkgoT().TempDir(), 5, brtypes.MinChunkSize, &mockS3Client{
+			objects:          map[string]*[]byte{},
+			prefix:           prefixV2,
+			multiPartUploads: map[string]*[][]byte{},
+			deleteMarkers:    map[string]time.Time{},
+		}, SSECredentials{})
+
+		fullSnap = brtypes.Snapshot{
+			Kind:          brtypes.SnapshotKindFull,
+			StartRevision: 0,
+			LastRevision:  10,
+			CreatedOn:     time.Now().UTC(),
+			Prefix:        prefixV2,
+		}
+		fullSnap.GenerateSnapshotName()
+		Expect(store.Save(fullSnap, io.NopCloser(bytes.NewReader([]byte("data"))))).To(Succeed())
+	})
+
+	It("should list a snapshot which is pinned again after it was unpinned as pinned", func() {
+		Expect(PinSnapshot(store, listSnapshot(), time.Time{})).To(Succeed())
+		Expect(listSnapshot().Pinned).To(BeTrue())
+
+		Expect(UnpinSnapshot(store, listSnapshot())).To(Succeed())
+		Expect(listSnapshot().Pinned).To(BeFalse())
+
+		Expect(PinSnapshot(store, listSnapshot(), time.Time{})).To(Succeed())
+		snap := listSnapshot()
+		Expect(snap.Pinned).To(BeTrue())
+		Expect(snap.PinExpiryTime.IsZero()).To(BeTrue())
+	})
+})
@@ -588,7 +588,10 @@ func (s *S3SnapStore) partUploader(wg *sync.WaitGroup, stopCh <-chan struct{}, s
 //   - It returns a sorted list of all firstCreated/oldest snapshot files present in the object store.
 //   - It also captures the "ImmutabilityExpiryTime" and "VersionID" of corresponding versioned snapshot.
 func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
-	var snapList brtypes.SnapList
+	var (
+		snapList     brtypes.SnapList
+		sidecarPaths []string
+	)
 	prefixTokens := strings.Split(s.prefix, "/")
 	// Last element of the tokens is backup version
 	// Consider the parent of the backup version level (Required for Backward Compatibility)
@@ -635,6 +638,7 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 		type snapshotMetaInfo struct {
 			creationTime time.Time
 			versionID    string
+			size         int64
 		}
 
 		// allSnapKeyMapToSnapshotInfo contains oldest snapshots keys mapped to their versionID and creation timestamp.
@@ -643,6 +647,18 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 		// allDeleteMarkersInfo contains key of all delete markers present(if any) in the S3 bucket.
 		allDeleteMarkersInfo := make(map[string]struct{})
 
+		// latestSidecarVersions contains the latest version of the sidecars, which tells whether a sidecar has been removed.
+		latestSidecarVersions := make(map[string]*latestObjectVersion)
+		observeSidecarVersion := func(key string, lastModified *time.Time, isLatest *bool, isDeleteMarker bool) {
+			if !isSnapshotSidecar(key) {
+				return
+			}
+			if _, ok := latestSidecarVersions[key]; !ok {
+				latestSidecarVersions[key] = &latestObjectVersion{}
+			}
+			latestSidecarVersions[key].observe(lastModified, isLatest, isDeleteMarker)
+		}
+
 		paginator := s3.NewListObjectVersionsPaginator(s.client, in)
 		for paginator.HasMorePages() {
 			page, err := paginator.NextPage(context.TODO())
@@ -653,6 +669,7 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 			for _, version := range page.Versions {
 				snapKey := (*version.Key)[len(*page.Prefix):]
 				if strings.Contains(snapKey, backupVersionV1) || strings.Contains(snapKey, backupVersionV2) {
+					observeSidecarVersion(*version.Key, version.LastModified, version.IsLatest, false)
 					// Add snapshot key to map
 					//   - if snapshot key not found to be already present in map
 					//   - or if the incoming version of snapshot key is older
@@ -668,6 +685,7 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 						allSnapKeyMapToSnapshotInfo[*version.Key] = &snapshotMetaInfo{
 							creationTime: *version.LastModified,
 							versionID:    *version.VersionId,
+							size:         aws.ToInt64(version.Size),
 						}
 					}
 				}
@@ -678,6 +696,7 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 				deletionKey := (*deletionMarker.Key)[len(*page.Prefix):]
 				if strings.Contains(deletionKey, backupVersionV1) || strings.Contains(deletionKey, backupVersionV2) {
 					allDeleteMarkersInfo[*deletionMarker.Key] = struct{}{}
+					observeSidecarVersion(*deletionMarker.Key, deletionMarker.LastModified, deletionMarker.IsLatest, true)
 				}
 			}
 		}
@@ -692,6 +711,15 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 				}
 			}
 
+			if isSnapshotSidecar(key) {
+				// sidecars are removed by creating a delete marker, and may be saved again afterwards, e.g. when a
+				// snapshot is pinned again. So only the latest version tells whether a sidecar is present.
+				if !latestSidecarVersions[key].isDeleteMarker {
+					sidecarPaths = append(sidecarPaths, key)
+				}
+				continue
+			}
+
 			snap, err := ParseSnapshot(key)
 			if err != nil {
 				// Warning
@@ -699,6 +727,7 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 			} else {
 				// capture the versionID of snapshot and immutability expiry time of snapshot.
 				snap.VersionID = aws.String(val.versionID)
+				snap.Size = val.size
 				if bucketImmutableExpiryTimeInDays != nil {
 					// To get S3 object's "RetainUntilDate" or "ImmutabilityExpiryTime", backup-restore need to make an API call for each snapshot.
 					// To avoid API calls for each snapshot, backup-restore is calculating the "ImmutabilityExpiryTime" using bucket retention period.
@@ -725,12 +754,17 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 
 			for _, key := range page.Contents {
 				k := (*key.Key)[len(*page.Prefix):]
+				if isSnapshotSidecar(k) {
+					sidecarPaths = append(sidecarPaths, path.Join(prefix, k))
+					continue
+				}
 				if strings.Contains(k, backupVersionV1) || strings.Contains(k, backupVersionV2) {
 					snap, err := ParseSnapshot(path.Join(prefix, k))
 					if err != nil {
 						// Warning
 						logrus.Warnf("Invalid snapshot found. Ignoring it: %s", k)
 					} else {
+						snap.Size = aws.ToInt64(key.Size)
 						snapList = append(snapList, snap)
 					}
 				}
@@ -738,10 +772,33 @@ func (s *S3SnapStore) List(includeAll bool) (brtypes.SnapList, error) {
 		}
 	}
 
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 	return snapList, nil
 }
 
+// latestObjectVersion is the latest version of an object in a versioned bucket seen so far.
+type latestObjectVersion struct {
+	lastModified   time.Time
+	isLatest       bool
+	isDeleteMarker bool
+}
+
+// observe records the given version or delete marker of the object if it is newer than the latest version seen so far.
+// A version flagged as the latest one by S3 takes precedence, since versions may have the same modification time.
+func (v *latestObjectVersion) observe(lastModified *time.Time, isLatest *bool, isDeleteMarker bool) {
+	if v.isLatest {
+		return
+	}
+	if aws.ToBool(isLatest) || aws.ToTime(lastModified).After(v.lastModified) {
+		*v = latestObjectVersion{
+			lastModified:   aws.ToTime(lastModified),
+			isLatest:       aws.ToBool(isLatest),
+			isDeleteMarker: isDeleteMarker,
+		}
+	}
+}
+
 // Delete should delete the snapshot file from store
 func (s *S3SnapStore) Delete(snap brtypes.Snapshot) error {
 	deleteObjectInput := &s3.DeleteObjectInput{
@@ -31,6 +31,8 @@ type mockS3Client struct {
 	multiPartUploads      map[string]*[][]byte
 	prefix                string
 	multiPartUploadsMutex sync.Mutex
+	// deleteMarkers holds the creation times of the delete markers of the objects, if the mock bucket is versioned.
+	deleteMarkers map[string]time.Time
 }
 
 // GetObject returns the object from map for mock test
@@ -186,6 +188,14 @@ func (m *mockS3Client) ListObjectVersions(_ context.Context, in *s3.ListObjectVe
 				LastModified: aws.Time(time.Now()),
 			}
 			out.Versions = append(out.Versions, tempObj)
+			if deletedOn, ok := m.deleteMarkers[key]; ok {
+				// the object has been saved again after it was deleted
+				out.DeleteMarkers = append(out.DeleteMarkers, s3types.DeleteMarkerEntry{
+					Key:          aws.String(key),
+					IsLatest:     aws.Bool(false),
+					LastModified: aws.Time(deletedOn),
+				})
+			}
 			count++
 		}
 
@@ -273,6 +283,9 @@ func (m *mockS3Client) GetObjectTagging(_ context.Context, input *s3.GetObjectTa
 
 // DeleteObject deletes the object from map for mock test
 func (m *mockS3Client) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	if m.deleteMarkers != nil && in.VersionId == nil {
+		m.deleteMarkers[*in.Key] = time.Now()
+	}
 	delete(m.objects, *in.Key)
 	return &s3.DeleteObjectOutput{}, nil
 }
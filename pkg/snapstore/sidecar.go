@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package snapstore
+
+import (
+	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
+)
+
+// isSnapshotSidecar returns true if the object at the given path is not a snapshot, but holds metadata
+// of the snapshot next to it, i.e. a pin marker or a labels manifest.
+func isSnapshotSidecar(objectPath string) bool {
+	return isPinMarker(objectPath) || isLabelsManifest(objectPath)
+}
+
+// applySnapshotSidecars applies the metadata of the sidecars at the given paths to the snapshots of the list.
+func applySnapshotSidecars(snapList brtypes.SnapList, sidecarPaths []string) {
+	var pinMarkerPaths, labelsManifestPaths []string
+	for _, sidecarPath := range sidecarPaths {
+		if isLabelsManifest(sidecarPath) {
+			labelsManifestPaths = append(labelsManifestPaths, sidecarPath)
+		} else {
+			pinMarkerPaths = append(pinMarkerPaths, sidecarPath)
+		}
+	}
+	applyPinMarkers(snapList, pinMarkerPaths)
+	applyLabelsManifests(snapList, labelsManifestPaths)
+}
@@ -467,7 +467,10 @@ func (s *SwiftSnapStore) List(_ bool) (brtypes.SnapList, error) {
 	}
 	// Retrieve a pager (i.e. a paginated collection)
 	pager := objects.List(s.client, s.bucket, opts)
-	var snapList brtypes.SnapList
+	var (
+		snapList     brtypes.SnapList
+		sidecarPaths []string
+	)
 	// Define an anonymous function to be executed on each page's iteration
 	err := pager.EachPage(func(page pagination.Page) (bool, error) {
 
@@ -476,6 +479,10 @@ func (s *SwiftSnapStore) List(_ bool) (brtypes.SnapList, error) {
 			return false, err
 		}
 		for _, object := range objectList {
+			if isSnapshotSidecar(object) {
+				sidecarPaths = append(sidecarPaths, object)
+				continue
+			}
 			if strings.Contains(object, backupVersionV1) || strings.Contains(object, backupVersionV2) {
 				snap, err := ParseSnapshot(object)
 				if err != nil {
@@ -493,6 +500,7 @@ func (s *SwiftSnapStore) List(_ bool) (brtypes.SnapList, error) {
 		return nil, err
 	}
 
+	applySnapshotSidecars(snapList, sidecarPaths)
 	sort.Sort(snapList)
 	return snapList, nil
 }
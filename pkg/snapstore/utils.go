@@ -62,6 +62,15 @@ func GetSnapstore(config *brtypes.SnapstoreConfig) (brtypes.SnapStore, error) {
 		}
 	}
 
+	store, err := getProviderSnapstore(config)
+	if err != nil || len(config.EncryptionKeyFile) == 0 {
+		return store, err
+	}
+	return NewEncryptedSnapStore(store, config.EncryptionKeyFile, config.EncryptionAllowPlaintext)
+}
+
+// getProviderSnapstore returns the snapstore object of the configured storage provider.
+func getProviderSnapstore(config *brtypes.SnapstoreConfig) (brtypes.SnapStore, error) {
 	switch config.Provider {
 	case brtypes.SnapstoreProviderLocal, "":
 		if config.Container == "" {
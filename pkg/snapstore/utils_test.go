@@ -1,7 +1,9 @@
 package snapstore_test
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 
 	brtypes "github.com/gardener/etcd-backup-restore/pkg/types"
 
@@ -110,6 +112,20 @@ var _ = Describe("GetSnapstore", func() {
 		})
 	})
 
+	Context("when an encryption key file is configured", func() {
+		BeforeEach(func() {
+			config.Provider = brtypes.SnapstoreProviderLocal
+			config.EncryptionKeyFile = filepath.Join(GinkgoT().TempDir(), "kek")
+			Expect(os.WriteFile(config.EncryptionKeyFile, bytes.Repeat([]byte{1}, 32), 0600)).To(Succeed())
+		})
+		It("should return an encrypted snapstore", func() {
+			snapstore, err := GetSnapstore(config)
+			Expect(err).ToNot(HaveOccurred())
+			_, ok := snapstore.(*EncryptedSnapStore)
+			Expect(ok).To(BeTrue())
+		})
+	})
+
 	Context("when snapstore provider is unknown", func() {
 		BeforeEach(func() {
 			config.Provider = "unknown"
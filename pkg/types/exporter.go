@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	// ExportFormatNDJSON is the export format which writes one JSON object per key, holding the key, its value, its
+	// create and mod revisions, its version and its lease ID.
+	ExportFormatNDJSON = "ndjson"
+	// ExportFormatKeys is the export format which writes one key per line.
+	ExportFormatKeys = "keys"
+
+	// defaultExportBatchSize is the default number of keys fetched at once from the restored data.
+	defaultExportBatchSize = 1000
+)
+
+// ExporterConfig holds all configuration options related to `export` subcommand.
+type ExporterConfig struct {
+	// Snapshot is the name of the full snapshot to export. The latest full snapshot at or before the target revision is
+	// exported if it is empty.
+	Snapshot string `json:"snapshot,omitempty"`
+	// TargetRevision is the revision up to which the delta snapshots are applied. 0 applies all delta snapshots of the
+	// full snapshot.
+	TargetRevision     int64    `json:"targetRevision,omitempty"`
+	IncludeKeyPrefixes []string `json:"includeKeyPrefixes,omitempty"`
+	ExcludeKeyPrefixes []string `json:"excludeKeyPrefixes,omitempty"`
+	Format             string   `json:"format,omitempty"`
+	// Output is the file the keys are written to. They are written to stdout if it is empty or "-".
+	Output string `json:"output,omitempty"`
+	// DataDir is the directory in which the snapshots are restored into a temporary directory removed after the export.
+	DataDir   string `json:"dataDir,omitempty"`
+	BatchSize int64  `json:"batchSize,omitempty"`
+}
+
+// NewExporterConfig returns the exporter config.
+func NewExporterConfig() *ExporterConfig {
+	return &ExporterConfig{
+		Format:    ExportFormatNDJSON,
+		DataDir:   filepath.Join(os.TempDir(), "etcd-export"),
+		BatchSize: defaultExportBatchSize,
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *ExporterConfig) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Snapshot, "snapshot", c.Snapshot, "name of the full snapshot to export. The latest full snapshot at or before the target revision is exported if not set")
+	fs.Int64Var(&c.TargetRevision, "target-revision", c.TargetRevision, "etcd revision up to which the delta snapshots of the full snapshot are applied. 0 applies all of them")
+	fs.StringSliceVar(&c.IncludeKeyPrefixes, "include-key-prefixes", c.IncludeKeyPrefixes, "prefixes of keys to export. All keys are exported if not set")
+	fs.StringSliceVar(&c.ExcludeKeyPrefixes, "exclude-key-prefixes", c.ExcludeKeyPrefixes, "prefixes of keys which are not exported")
+	fs.StringVar(&c.Format, "format", c.Format, "format of the export: 'ndjson' for one JSON object per key with its value, revisions and lease ID, 'keys' for one key per line")
+	fs.StringVarP(&c.Output, "output", "o", c.Output, "path to the file the export is written to, '-' or empty for stdout")
+	fs.StringVar(&c.DataDir, "export-data-dir", c.DataDir, "path to the directory in which the snapshots are restored into a temporary scratch directory for the export")
+	fs.Int64Var(&c.BatchSize, "export-batch-size", c.BatchSize, "number of keys fetched at once from the restored data")
+}
+
+// Validate validates the config.
+func (c *ExporterConfig) Validate() error {
+	if c.TargetRevision < 0 {
+		return fmt.Errorf("target revision must not be negative")
+	}
+	if err := c.KeyFilter().Validate(); err != nil {
+		return fmt.Errorf("invalid key prefix filter: %v", err)
+	}
+	if c.Format != ExportFormatNDJSON && c.Format != ExportFormatKeys {
+		return fmt.Errorf("unsupported export format %q, must be one of %q and %q", c.Format, ExportFormatNDJSON, ExportFormatKeys)
+	}
+	if len(c.DataDir) == 0 {
+		return fmt.Errorf("export data dir must be set")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("export batch size should be greater than zero")
+	}
+	return nil
+}
+
+// KeyFilter returns the filter of the keys to export.
+func (c *ExporterConfig) KeyFilter() KeyPrefixFilter {
+	return KeyPrefixFilter{
+		IncludePrefixes: c.IncludeKeyPrefixes,
+		ExcludePrefixes: c.ExcludeKeyPrefixes,
+	}
+}
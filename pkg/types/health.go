@@ -81,3 +81,40 @@ func (c *HealthConfig) Validate() error {
 	return nil
 
 }
+
+// ComponentStatus is the status of a component of backup-restore in the health report.
+type ComponentStatus string
+
+const (
+	// ComponentStatusHealthy indicates that the component works as expected.
+	ComponentStatusHealthy ComponentStatus = "Healthy"
+	// ComponentStatusUnhealthy indicates that the component is broken and needs attention.
+	ComponentStatusUnhealthy ComponentStatus = "Unhealthy"
+	// ComponentStatusUnknown indicates that the status of the component cannot be determined yet, e.g. during startup.
+	ComponentStatusUnknown ComponentStatus = "Unknown"
+	// ComponentStatusDisabled indicates that the component is disabled, or not run on this member.
+	ComponentStatusDisabled ComponentStatus = "Disabled"
+)
+
+// ComponentHealth is the health of a component of backup-restore.
+type ComponentHealth struct {
+	Status  ComponentStatus `json:"status"`
+	Message string          `json:"message,omitempty"`
+	// LastSuccess is the time at which the component last succeeded, e.g. the time the latest snapshot was taken.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// HealthReport is the health of each component of backup-restore, so that a failure can be attributed to a component.
+type HealthReport struct {
+	// Healthy is false if any component is unhealthy.
+	Healthy           bool            `json:"healthy"`
+	CheckedOn         time.Time       `json:"checkedOn"`
+	Etcd              ComponentHealth `json:"etcd"`
+	LeaderElection    ComponentHealth `json:"leaderElection"`
+	Snapshotter       ComponentHealth `json:"snapshotter"`
+	FullSnapshot      ComponentHealth `json:"fullSnapshot"`
+	DeltaSnapshot     ComponentHealth `json:"deltaSnapshot"`
+	Snapstore         ComponentHealth `json:"snapstore"`
+	LeaseRenewal      ComponentHealth `json:"leaseRenewal"`
+	GarbageCollection ComponentHealth `json:"garbageCollection"`
+}
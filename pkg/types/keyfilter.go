@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// KeyPrefixFilter selects etcd keys by their prefix.
+type KeyPrefixFilter struct {
+	// IncludePrefixes are the prefixes of keys to select. All keys are selected if it is empty.
+	IncludePrefixes []string
+	// ExcludePrefixes are the prefixes of keys to leave out, even if they match one of the IncludePrefixes.
+	ExcludePrefixes []string
+}
+
+// IsEmpty returns true if the filter selects all keys.
+func (f KeyPrefixFilter) IsEmpty() bool {
+	return len(f.IncludePrefixes) == 0 && len(f.ExcludePrefixes) == 0
+}
+
+// Includes returns true if the given key is selected by the filter.
+func (f KeyPrefixFilter) Includes(key []byte) bool {
+	for _, prefix := range f.ExcludePrefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) {
+			return false
+		}
+	}
+	if len(f.IncludePrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range f.IncludePrefixes {
+		if bytes.HasPrefix(key, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the filter.
+func (f KeyPrefixFilter) Validate() error {
+	for _, prefixes := range [][]string{f.IncludePrefixes, f.ExcludePrefixes} {
+		for _, prefix := range prefixes {
+			if len(prefix) == 0 {
+				return fmt.Errorf("key prefix must not be empty")
+			}
+		}
+	}
+	return nil
+}
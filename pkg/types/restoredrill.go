@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gardener/etcd-backup-restore/pkg/wrappers"
+
+	"github.com/robfig/cron/v3"
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	// defaultRestoreDrillTimeout defines the default timeout duration for a restore drill.
+	defaultRestoreDrillTimeout = 30 * time.Minute
+)
+
+// RestoreDrillConfig holds the configuration of the periodic restore drills, which restore the latest backups
+// into a scratch directory to prove that they can be restored.
+type RestoreDrillConfig struct {
+	// Schedule is the cron schedule of the restore drills. Restore drills are disabled if it is empty.
+	Schedule string `json:"schedule,omitempty"`
+	// DataDir is the directory in which every drill restores the backups into a temporary directory removed after the drill.
+	DataDir string            `json:"dataDir,omitempty"`
+	Timeout wrappers.Duration `json:"timeout,omitempty"`
+}
+
+// NewRestoreDrillConfig returns the restore drill config.
+func NewRestoreDrillConfig() *RestoreDrillConfig {
+	return &RestoreDrillConfig{
+		DataDir: filepath.Join(os.TempDir(), "restore-drill"),
+		Timeout: wrappers.Duration{Duration: defaultRestoreDrillTimeout},
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *RestoreDrillConfig) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Schedule, "restore-drill-schedule", c.Schedule, "schedule to restore the latest backups into a scratch directory to verify that they can be restored, restore drills are disabled if not set")
+	fs.StringVar(&c.DataDir, "restore-drill-data-dir", c.DataDir, "path to the directory in which the backups are restored into a temporary scratch directory during restore drills")
+	fs.DurationVar(&c.Timeout.Duration, "restore-drill-timeout", c.Timeout.Duration, "timeout duration for a restore drill")
+}
+
+// IsEnabled returns true if restore drills are enabled.
+func (c *RestoreDrillConfig) IsEnabled() bool {
+	return len(c.Schedule) != 0
+}
+
+// Validate validates the config.
+func (c *RestoreDrillConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+	if _, err := cron.ParseStandard(c.Schedule); err != nil {
+		return fmt.Errorf("invalid restore drill schedule: %v", err)
+	}
+	if len(c.DataDir) == 0 {
+		return fmt.Errorf("restore drill data dir must be set when restore drills are enabled")
+	}
+	if c.Timeout.Duration <= 0 {
+		return fmt.Errorf("restore drill timeout should be greater than zero")
+	}
+	return nil
+}
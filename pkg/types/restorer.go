@@ -6,6 +6,7 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"net/url"
 	"path"
 	"time"
@@ -45,8 +46,25 @@ type RestoreOptions struct {
 	DeltaSnapList    SnapList
 	// OriginalClusterSize indicates the actual cluster size from the ETCD config
 	OriginalClusterSize int
+	// OnProgress is called with the progress of the restoration whenever a snapshot has been fetched or applied.
+	OnProgress RestoreProgressFunc
 }
 
+// RestoreProgress is the progress of a restoration.
+type RestoreProgress struct {
+	// SnapshotsTotal is the number of snapshots to restore, i.e. the base snapshot and the delta snapshots.
+	SnapshotsTotal int `json:"snapshotsTotal"`
+	// SnapshotsFetched is the number of snapshots fetched from the store.
+	SnapshotsFetched int `json:"snapshotsFetched"`
+	// SnapshotsApplied is the number of snapshots applied to the restored data.
+	SnapshotsApplied int `json:"snapshotsApplied"`
+	// Revision is the revision of the restored data after the last applied snapshot.
+	Revision int64 `json:"revision"`
+}
+
+// RestoreProgressFunc is called with the progress of a restoration.
+type RestoreProgressFunc func(RestoreProgress)
+
 // RestorationConfig holds the restoration configuration.
 // Note: Please ensure DeepCopy and DeepCopyInto are properly implemented.
 type RestorationConfig struct {
@@ -64,6 +82,13 @@ type RestorationConfig struct {
 	EmbeddedEtcdQuotaBytes   int64    `json:"embeddedEtcdQuotaBytes,omitempty"`
 	MaxFetchers              uint     `json:"maxFetchers,omitempty"`
 	SkipHashCheck            bool     `json:"skipHashCheck,omitempty"`
+	TargetRevision           int64    `json:"targetRevision,omitempty"`
+	TargetTime               string   `json:"targetTime,omitempty"`
+	TargetSnapshotLabels     string   `json:"targetSnapshotLabels,omitempty"`
+	IncludeKeyPrefixes       []string `json:"includeKeyPrefixes,omitempty"`
+	ExcludeKeyPrefixes       []string `json:"excludeKeyPrefixes,omitempty"`
+	RevisionBump             uint64   `json:"revisionBump,omitempty"`
+	MarkCompacted            bool     `json:"markCompacted,omitempty"`
 }
 
 // NewRestorationConfig returns the restoration config.
@@ -102,6 +127,13 @@ func (c *RestorationConfig) AddFlags(fs *flag.FlagSet) {
 	fs.Int64Var(&c.EmbeddedEtcdQuotaBytes, "embedded-etcd-quota-bytes", c.EmbeddedEtcdQuotaBytes, "maximum backend quota for the embedded etcd used for applying delta snapshots")
 	fs.StringVar(&c.AutoCompactionMode, "auto-compaction-mode", c.AutoCompactionMode, "mode for auto-compaction: 'periodic' for duration based retention. 'revision' for revision number based retention.")
 	fs.StringVar(&c.AutoCompactionRetention, "auto-compaction-retention", c.AutoCompactionRetention, "Auto-compaction retention length.")
+	fs.Int64Var(&c.TargetRevision, "target-revision", c.TargetRevision, "etcd revision up to which the data should be restored (point-in-time restore). 0 restores up to the latest revision")
+	fs.StringVar(&c.TargetTime, "target-time", c.TargetTime, "timestamp in RFC3339 format up to which the data should be restored (point-in-time restore). Empty restores up to the latest revision")
+	fs.StringVar(&c.TargetSnapshotLabels, "target-snapshot-labels", c.TargetSnapshotLabels, "comma separated key=value labels, e.g. reason=pre-upgrade, of the snapshot up to which the data should be restored (point-in-time restore). The latest snapshot with all of the labels is selected")
+	fs.StringSliceVar(&c.IncludeKeyPrefixes, "restore-include-key-prefixes", c.IncludeKeyPrefixes, "prefixes of keys to restore. Other keys are dropped from the delta snapshots and removed from the restored data. All keys are restored if not set")
+	fs.StringSliceVar(&c.ExcludeKeyPrefixes, "restore-exclude-key-prefixes", c.ExcludeKeyPrefixes, "prefixes of keys which are dropped from the delta snapshots and removed from the restored data")
+	fs.Uint64Var(&c.RevisionBump, "bump-revision", c.RevisionBump, "amount by which the latest revision of the restored data is increased, so that clients never see the revision decrease. 0 does not bump the revision")
+	fs.BoolVar(&c.MarkCompacted, "mark-compacted", c.MarkCompacted, "mark the bumped revision of the restored data as compacted, so that watches on older revisions are cancelled (requires --bump-revision to be greater than 0)")
 }
 
 // Validate validates the config.
@@ -124,11 +156,43 @@ func (c *RestorationConfig) Validate() error {
 	if c.AutoCompactionMode != "periodic" && c.AutoCompactionMode != "revision" {
 		return fmt.Errorf("UnSupported auto-compaction-mode")
 	}
+	if c.TargetRevision < 0 {
+		return fmt.Errorf("target revision must not be negative")
+	}
+	if _, err := c.GetTargetTime(); err != nil {
+		return err
+	}
+	if len(c.TargetSnapshotLabels) != 0 {
+		if c.TargetRevision > 0 || len(c.TargetTime) != 0 {
+			return fmt.Errorf("target snapshot labels must not be combined with a target revision or a target time")
+		}
+		if _, err := c.GetTargetSnapshotLabels(); err != nil {
+			return err
+		}
+	}
+	if err := c.KeyFilter().Validate(); err != nil {
+		return fmt.Errorf("invalid key prefix filter: %v", err)
+	}
+	if c.RevisionBump > math.MaxInt64 {
+		return fmt.Errorf("revision bump must not be greater than %d", int64(math.MaxInt64))
+	}
+	if c.MarkCompacted && c.RevisionBump == 0 {
+		return fmt.Errorf("mark compacted requires a revision bump")
+	}
 	c.DataDir = path.Clean(c.DataDir)
 	c.TempSnapshotsDir = path.Clean(c.TempSnapshotsDir)
 	return nil
 }
 
+// ValidateInitialization validates the config for the restorations of the initializer, which restores the data
+// directory whenever it is found invalid, while the config stays the same.
+func (c *RestorationConfig) ValidateInitialization() error {
+	if c.IsPointInTimeRestore() && c.RevisionBump == 0 {
+		return fmt.Errorf("a target of the restoration requires a revision bump, so that the revisions after the restoration move past the ones of the backups taken before it")
+	}
+	return nil
+}
+
 // DeepCopyInto copies the structure deeply from in to out.
 func (c *RestorationConfig) DeepCopyInto(out *RestorationConfig) {
 	*out = *c
@@ -137,6 +201,16 @@ func (c *RestorationConfig) DeepCopyInto(out *RestorationConfig) {
 		*out = make([]string, len(*c))
 		copy(*out, *c)
 	}
+	if c.IncludeKeyPrefixes != nil {
+		c, out := &c.IncludeKeyPrefixes, &out.IncludeKeyPrefixes
+		*out = make([]string, len(*c))
+		copy(*out, *c)
+	}
+	if c.ExcludeKeyPrefixes != nil {
+		c, out := &c.ExcludeKeyPrefixes, &out.ExcludeKeyPrefixes
+		*out = make([]string, len(*c))
+		copy(*out, *c)
+	}
 }
 
 // DeepCopy returns a deeply copied structure.
@@ -150,6 +224,48 @@ func (c *RestorationConfig) DeepCopy() *RestorationConfig {
 	return out
 }
 
+// IsPointInTimeRestore returns true if the restoration is bounded by a target revision, a target time or target snapshot labels.
+func (c *RestorationConfig) IsPointInTimeRestore() bool {
+	return c.TargetRevision > 0 || len(c.TargetTime) != 0 || len(c.TargetSnapshotLabels) != 0
+}
+
+// GetTargetTime returns the parsed target time of the restoration.
+// It returns the zero time if no target time is configured.
+func (c *RestorationConfig) GetTargetTime() (time.Time, error) {
+	if len(c.TargetTime) == 0 {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, c.TargetTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed parsing target time %s, expected RFC3339 format: %v", c.TargetTime, err)
+	}
+	return t, nil
+}
+
+// GetTargetSnapshotLabels returns the parsed labels of the snapshot up to which the data should be restored.
+// It returns nil if no target snapshot labels are configured.
+func (c *RestorationConfig) GetTargetSnapshotLabels() (map[string]string, error) {
+	if len(c.TargetSnapshotLabels) == 0 {
+		return nil, nil
+	}
+	labels, err := ParseSnapshotLabels(c.TargetSnapshotLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing target snapshot labels %s: %v", c.TargetSnapshotLabels, err)
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("target snapshot labels %q contain no label", c.TargetSnapshotLabels)
+	}
+	return labels, nil
+}
+
+// KeyFilter returns the filter for the keys to restore.
+func (c *RestorationConfig) KeyFilter() KeyPrefixFilter {
+	return KeyPrefixFilter{
+		IncludePrefixes: c.IncludeKeyPrefixes,
+		ExcludePrefixes: c.ExcludeKeyPrefixes,
+	}
+}
+
 func initialClusterFromName(name string) string {
 	n := name
 	if name == "" {
@@ -170,6 +286,18 @@ func (i *InitIndex) ConsistentIndex() uint64 {
 type Event struct {
 	EtcdEvent *clientv3.Event `json:"etcdEvent"`
 	Time      time.Time       `json:"time"`
+	// Lease is the lease to which the key of the event is attached. It is recorded along with the first event of each
+	// lease in a delta snapshot only.
+	Lease *Lease `json:"lease,omitempty"`
+}
+
+// Lease describes a lease to which keys are attached, so that it can be recreated on restoration.
+type Lease struct {
+	ID int64 `json:"id"`
+	// GrantedTTL is the TTL in seconds with which the lease was granted.
+	GrantedTTL int64 `json:"grantedTTL"`
+	// TTL is the remaining TTL in seconds of the lease when it was recorded.
+	TTL int64 `json:"ttl"`
 }
 
 // FetcherInfo stores the information about fetcher
@@ -21,14 +21,27 @@ const (
 	GarbageCollectionPolicyExponential = "Exponential"
 	// GarbageCollectionPolicyLimitBased defines the limit based policy for garbage collecting old backups
 	GarbageCollectionPolicyLimitBased = "LimitBased"
+	// GarbageCollectionPolicyGFS defines the grandfather-father-son policy for garbage collecting old backups,
+	// which keeps the latest full snapshot of a configurable number of hours, days, weeks, months and years.
+	GarbageCollectionPolicyGFS = "GFS"
 	// DefaultMaxBackups is default number of maximum backups for limit based garbage collection policy.
 	DefaultMaxBackups = 7
+	// DefaultHourlyBackups is default number of hourly backups for GFS garbage collection policy.
+	DefaultHourlyBackups = 24
+	// DefaultDailyBackups is default number of daily backups for GFS garbage collection policy.
+	DefaultDailyBackups = 7
+	// DefaultWeeklyBackups is default number of weekly backups for GFS garbage collection policy.
+	DefaultWeeklyBackups = 4
 
 	// SnapshotterActive is set when the snapshotter has started taking snapshots.
 	SnapshotterActive = true
 
 	// DefaultDeltaSnapMemoryLimit is default memory limit for delta snapshots.
 	DefaultDeltaSnapMemoryLimit = 10 * 1024 * 1024 //10Mib
+	// DefaultDeltaSnapshotSpoolSizeLimit is the default size limit of the local spool for delta snapshots.
+	DefaultDeltaSnapshotSpoolSizeLimit = 1024 * 1024 * 1024 //1Gib
+	// DefaultDeltaSnapshotSpillSizeLimit is the default size limit of the events of a delta snapshot spilled to disk.
+	DefaultDeltaSnapshotSpillSizeLimit = 1024 * 1024 * 1024 //1Gib
 	// DefaultDeltaSnapshotInterval is the default interval for delta snapshots.
 	DefaultDeltaSnapshotInterval = 20 * time.Second
 
@@ -39,6 +52,13 @@ const (
 
 	// DeltaSnapshotIntervalThreshold is interval between delta snapshot
 	DeltaSnapshotIntervalThreshold = time.Second
+
+	// DeltaSnapshotFormatBinary is the format of delta snapshots in which the events are framed in checksummed blocks
+	// of protobuf encoded events, which can be decoded as a stream.
+	DeltaSnapshotFormatBinary = "binary"
+	// DeltaSnapshotFormatJSON is the format of delta snapshots in which the events are a JSON array, which is read as a
+	// whole. It is the format of delta snapshots taken by earlier versions.
+	DeltaSnapshotFormatJSON = "json"
 )
 
 // SnapshotterConfig holds the snapshotter config.
@@ -50,6 +70,14 @@ type SnapshotterConfig struct {
 	GarbageCollectionPeriod      wrappers.Duration `json:"garbageCollectionPeriod,omitempty"`
 	MaxBackups                   uint              `json:"maxBackups,omitempty"`
 	DeltaSnapshotRetentionPeriod wrappers.Duration `json:"deltaSnapshotRetentionPeriod,omitempty"`
+	GFSRetention                 GFSRetention      `json:"gfsRetention,omitempty"`
+	IncludeKeyPrefixes           []string          `json:"includeKeyPrefixes,omitempty"`
+	ExcludeKeyPrefixes           []string          `json:"excludeKeyPrefixes,omitempty"`
+	DeltaSnapshotSpoolDir        string            `json:"deltaSnapshotSpoolDir,omitempty"`
+	DeltaSnapshotSpoolSizeLimit  uint              `json:"deltaSnapshotSpoolSizeLimit,omitempty"`
+	DeltaSnapshotFormat          string            `json:"deltaSnapshotFormat,omitempty"`
+	DeltaSnapshotSpillDir        string            `json:"deltaSnapshotSpillDir,omitempty"`
+	DeltaSnapshotSpillSizeLimit  uint              `json:"deltaSnapshotSpillSizeLimit,omitempty"`
 }
 
 // AddFlags adds the flags to flagset.
@@ -61,6 +89,14 @@ func (c *SnapshotterConfig) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.GarbageCollectionPolicy, "garbage-collection-policy", c.GarbageCollectionPolicy, "Policy for garbage collecting old backups")
 	fs.UintVarP(&c.MaxBackups, "max-backups", "m", c.MaxBackups, "maximum number of previous backups to keep")
 	fs.DurationVar(&c.DeltaSnapshotRetentionPeriod.Duration, "delta-snapshot-retention-period", c.DeltaSnapshotRetentionPeriod.Duration, "Defines the retention period for older delta snapshots, excluding the latest snapshot set which is always retained for data safety.")
+	c.GFSRetention.AddFlags(fs)
+	fs.StringSliceVar(&c.IncludeKeyPrefixes, "include-key-prefixes", c.IncludeKeyPrefixes, "prefixes of keys whose events are recorded in delta snapshots. Events of all keys are recorded if not set")
+	fs.StringSliceVar(&c.ExcludeKeyPrefixes, "exclude-key-prefixes", c.ExcludeKeyPrefixes, "prefixes of keys whose events are not recorded in delta snapshots, e.g. /registry/events/")
+	fs.StringVar(&c.DeltaSnapshotSpoolDir, "delta-snapshot-spool-dir", c.DeltaSnapshotSpoolDir, "local directory in which delta snapshots are kept while the snapstore is not available, until they are uploaded. Delta snapshots are not spooled if not set")
+	fs.UintVar(&c.DeltaSnapshotSpoolSizeLimit, "delta-snapshot-spool-size-limit", c.DeltaSnapshotSpoolSizeLimit, "maximum size in bytes of the delta snapshots kept in the spool directory")
+	fs.StringVar(&c.DeltaSnapshotFormat, "delta-snapshot-format", c.DeltaSnapshotFormat, "format in which delta snapshots are written, either json or binary. Restoration reads both formats, but earlier versions can only restore delta snapshots in the json format")
+	fs.StringVar(&c.DeltaSnapshotSpillDir, "delta-snapshot-spill-dir", c.DeltaSnapshotSpillDir, "local directory to which the events of a delta snapshot are spilled once they exceed the delta snapshot memory limit, instead of taking a delta snapshot. Events are not spilled if not set")
+	fs.UintVar(&c.DeltaSnapshotSpillSizeLimit, "delta-snapshot-spill-size-limit", c.DeltaSnapshotSpillSizeLimit, "size limit in bytes of the events of a delta snapshot, including the ones spilled to disk, after which delta snapshots will be taken if the spill directory is set")
 }
 
 // Validate validates the config.
@@ -68,7 +104,7 @@ func (c *SnapshotterConfig) Validate() error {
 	if _, err := cron.ParseStandard(c.FullSnapshotSchedule); err != nil {
 		return err
 	}
-	if c.GarbageCollectionPolicy != GarbageCollectionPolicyLimitBased && c.GarbageCollectionPolicy != GarbageCollectionPolicyExponential {
+	if c.GarbageCollectionPolicy != GarbageCollectionPolicyLimitBased && c.GarbageCollectionPolicy != GarbageCollectionPolicyExponential && c.GarbageCollectionPolicy != GarbageCollectionPolicyGFS {
 		return fmt.Errorf("invalid garbage collection policy: %s", c.GarbageCollectionPolicy)
 	}
 	if c.GarbageCollectionPolicy == GarbageCollectionPolicyLimitBased && c.MaxBackups <= 0 {
@@ -77,6 +113,15 @@ func (c *SnapshotterConfig) Validate() error {
 	if c.MaxBackups > math.MaxInt {
 		return fmt.Errorf("max backups %d is greater than %d", c.MaxBackups, math.MaxInt)
 	}
+	if c.GarbageCollectionPolicy == GarbageCollectionPolicyGFS {
+		if err := c.GFSRetention.Validate(); err != nil {
+			return fmt.Errorf("invalid GFS retention: %v", err)
+		}
+	}
+
+	if err := c.KeyFilter().Validate(); err != nil {
+		return fmt.Errorf("invalid key prefix filter: %v", err)
+	}
 
 	if c.DeltaSnapshotPeriod.Duration < DeltaSnapshotIntervalThreshold {
 		logrus.Infof("Found delta snapshot interval %s less than 1 second. Disabling delta snapshotting. ", c.DeltaSnapshotPeriod)
@@ -88,5 +133,89 @@ func (c *SnapshotterConfig) Validate() error {
 	} else if c.DeltaSnapshotMemoryLimit > math.MaxInt {
 		return fmt.Errorf("delta snapshot memory limit %d bytes is greater than %d bytes", c.DeltaSnapshotMemoryLimit, math.MaxInt)
 	}
+
+	if len(c.DeltaSnapshotFormat) == 0 {
+		c.DeltaSnapshotFormat = DeltaSnapshotFormatJSON
+	} else if c.DeltaSnapshotFormat != DeltaSnapshotFormatBinary && c.DeltaSnapshotFormat != DeltaSnapshotFormatJSON {
+		return fmt.Errorf("invalid delta snapshot format: %s", c.DeltaSnapshotFormat)
+	}
+
+	if len(c.DeltaSnapshotSpoolDir) != 0 {
+		if c.DeltaSnapshotSpoolSizeLimit < 1 {
+			return fmt.Errorf("delta snapshot spool size limit should be greater than zero if the delta snapshot spool directory is set")
+		}
+		if c.DeltaSnapshotSpoolSizeLimit > math.MaxInt {
+			return fmt.Errorf("delta snapshot spool size limit %d bytes is greater than %d bytes", c.DeltaSnapshotSpoolSizeLimit, math.MaxInt)
+		}
+	}
+
+	if len(c.DeltaSnapshotSpillDir) != 0 {
+		if c.DeltaSnapshotSpillSizeLimit < c.DeltaSnapshotMemoryLimit {
+			return fmt.Errorf("delta snapshot spill size limit %d bytes is less than the delta snapshot memory limit %d bytes", c.DeltaSnapshotSpillSizeLimit, c.DeltaSnapshotMemoryLimit)
+		}
+		if c.DeltaSnapshotSpillSizeLimit > math.MaxInt {
+			return fmt.Errorf("delta snapshot spill size limit %d bytes is greater than %d bytes", c.DeltaSnapshotSpillSizeLimit, math.MaxInt)
+		}
+	}
+	return nil
+}
+
+// GFSRetention holds the number of periods for which the latest full snapshot is kept by the GFS garbage collection policy.
+type GFSRetention struct {
+	Hourly  uint `json:"hourly,omitempty"`
+	Daily   uint `json:"daily,omitempty"`
+	Weekly  uint `json:"weekly,omitempty"`
+	Monthly uint `json:"monthly,omitempty"`
+	Yearly  uint `json:"yearly,omitempty"`
+}
+
+// NewGFSRetention returns the GFS retention which keeps the same full snapshots as the exponential garbage collection policy.
+func NewGFSRetention() GFSRetention {
+	return GFSRetention{
+		Hourly: DefaultHourlyBackups,
+		Daily:  DefaultDailyBackups,
+		Weekly: DefaultWeeklyBackups,
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (r *GFSRetention) AddFlags(fs *flag.FlagSet) {
+	fs.UintVar(&r.Hourly, "gfs-hourly-backups", r.Hourly, "number of hours for which the latest full snapshot is kept by the GFS garbage collection policy")
+	fs.UintVar(&r.Daily, "gfs-daily-backups", r.Daily, "number of days for which the latest full snapshot is kept by the GFS garbage collection policy")
+	fs.UintVar(&r.Weekly, "gfs-weekly-backups", r.Weekly, "number of weeks for which the latest full snapshot is kept by the GFS garbage collection policy")
+	fs.UintVar(&r.Monthly, "gfs-monthly-backups", r.Monthly, "number of months for which the latest full snapshot is kept by the GFS garbage collection policy")
+	fs.UintVar(&r.Yearly, "gfs-yearly-backups", r.Yearly, "number of years for which the latest full snapshot is kept by the GFS garbage collection policy")
+}
+
+// Validate validates the GFS retention.
+func (r *GFSRetention) Validate() error {
+	keepsBackups := false
+	for _, count := range []uint{r.Hourly, r.Daily, r.Weekly, r.Monthly, r.Yearly} {
+		if count > math.MaxInt {
+			return fmt.Errorf("number of backups %d is greater than %d", count, math.MaxInt)
+		}
+		keepsBackups = keepsBackups || count > 0
+	}
+	if !keepsBackups {
+		return fmt.Errorf("at least one of the hourly, daily, weekly, monthly or yearly backups should be greater than zero")
+	}
 	return nil
 }
+
+// KeyFilter returns the filter for the keys whose events are recorded in delta snapshots.
+func (c *SnapshotterConfig) KeyFilter() KeyPrefixFilter {
+	return KeyPrefixFilter{
+		IncludePrefixes: c.IncludeKeyPrefixes,
+		ExcludePrefixes: c.ExcludeKeyPrefixes,
+	}
+}
+
+// PauseStatus is the status of a pause of the scheduled snapshots and the garbage collection of the snapshotter.
+type PauseStatus struct {
+	Paused bool `json:"paused"`
+	// Since is the time the snapshotting has been paused at.
+	Since *time.Time `json:"since,omitempty"`
+	// Until is the time the snapshotting is resumed at automatically. Without it, the snapshotting stays paused until
+	// it is resumed explicitly.
+	Until *time.Time `json:"until,omitempty"`
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/url"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -62,11 +63,20 @@ const (
 
 	// DefaultSecondaryBackupSyncPeriod is the default period for secondary backup sync operations.
 	DefaultSecondaryBackupSyncPeriod = 1 * time.Hour
+
+	// SnapshotLabelReason is the label of a snapshot holding the reason for taking it.
+	SnapshotLabelReason = "reason"
+	// MaxSnapshotLabels is the maximum number of labels of a snapshot.
+	MaxSnapshotLabels = 16
+	// MaxSnapshotLabelValueLength is the maximum length of the value of a snapshot label.
+	MaxSnapshotLabelValueLength = 256
 )
 
 var (
 	// ErrSnapshotDeleteFailDueToImmutability is the error returned when the Delete call fails due to immutability
 	ErrSnapshotDeleteFailDueToImmutability = fmt.Errorf("ErrSnapshotDeleteFailDueToImmutability")
+	// ErrSnapshotNotFound is the error returned when a snapshot is not found in the store
+	ErrSnapshotNotFound = fmt.Errorf("snapshot not found")
 )
 
 // SnapStore is the interface to be implemented for different
@@ -89,18 +99,23 @@ type SnapStore interface {
 
 // Snapshot structure represents the metadata of snapshot.
 type Snapshot struct {
-	CreatedOn              time.Time `json:"createdOn"`
-	ImmutabilityExpiryTime time.Time `json:"immutabilityExpriyTime"`
-	VersionID              *string   `json:"versionID"` // It is used only for AWS S3 object lock immutability.
-	Kind                   string    `json:"kind"`      // incr:incremental, full:full
-	SnapDir                string    `json:"snapDir"`
-	SnapName               string    `json:"snapName"`
-	Prefix                 string    `json:"prefix"`            // Points to correct prefix of a snapshot in snapstore (Required for Backward Compatibility)
-	CompressionSuffix      string    `json:"compressionSuffix"` // CompressionSuffix depends on compression policy
-	StartRevision          int64     `json:"startRevision"`
-	LastRevision           int64     `json:"lastRevision"` // latest revision of snapshot
-	IsChunk                bool      `json:"isChunk"`
-	IsFinal                bool      `json:"isFinal"`
+	CreatedOn              time.Time         `json:"createdOn"`
+	ImmutabilityExpiryTime time.Time         `json:"immutabilityExpriyTime"`
+	VersionID              *string           `json:"versionID"` // It is used only for AWS S3 object lock immutability.
+	Kind                   string            `json:"kind"`      // incr:incremental, full:full
+	SnapDir                string            `json:"snapDir"`
+	SnapName               string            `json:"snapName"`
+	Prefix                 string            `json:"prefix"`            // Points to correct prefix of a snapshot in snapstore (Required for Backward Compatibility)
+	CompressionSuffix      string            `json:"compressionSuffix"` // CompressionSuffix depends on compression policy
+	StartRevision          int64             `json:"startRevision"`
+	LastRevision           int64             `json:"lastRevision"` // latest revision of snapshot
+	IsChunk                bool              `json:"isChunk"`
+	IsFinal                bool              `json:"isFinal"`
+	Size                   int64             `json:"size,omitempty"` // size of the snapshot object in bytes, zero if not reported by the storage provider
+	Pinned                 bool              `json:"pinned"`
+	PinExpiryTime          time.Time         `json:"pinExpiryTime"` // zero if the snapshot is pinned indefinitely
+	Labels                 map[string]string `json:"labels,omitempty"`
+	HasLabels              bool              `json:"-"` // set by SnapStore.List if labels are stored for the snapshot, they are loaded into Labels on demand
 }
 
 // IsDeletable determines if the snapshot can be deleted.
@@ -115,6 +130,25 @@ func (s *Snapshot) IsDeletable() bool {
 	return time.Now().After(s.ImmutabilityExpiryTime)
 }
 
+// MatchesLabels returns true if the snapshot has all of the given labels.
+func (s *Snapshot) MatchesLabels(labels map[string]string) bool {
+	for key, value := range labels {
+		if v, ok := s.Labels[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPinned determines if the snapshot is protected from garbage collection by a pin.
+// It checks if the snapshot is pinned and whether the pin expiry time is not set or the current time is before it.
+func (s *Snapshot) IsPinned() bool {
+	if !s.Pinned {
+		return false
+	}
+	return s.PinExpiryTime.IsZero() || time.Now().Before(s.PinExpiryTime)
+}
+
 // GenerateSnapshotName prepares the snapshot name from metadata
 func (s *Snapshot) GenerateSnapshotName() {
 	s.SnapName = fmt.Sprintf("%s-%08d-%08d-%d%s%s", s.Kind, s.StartRevision, s.LastRevision, s.CreatedOn.Unix(), s.CompressionSuffix, s.finalSuffix())
@@ -204,6 +238,12 @@ type SnapstoreConfig struct {
 	MinChunkSize int64 `json:"minChunkSize,omitempty"`
 	// IsSource determines if this SnapStore is the source for a copy operation
 	IsSource bool `json:"isSource,omitempty"`
+	// EncryptionKeyFile holds the path to the file containing the key-encryption key used for client-side encryption of snapshots.
+	// Snapshots are not encrypted if it is empty.
+	EncryptionKeyFile string `json:"encryptionKeyFile,omitempty"`
+	// EncryptionAllowPlaintext allows fetching snapshots saved without encryption while the snapshots are encrypted.
+	// It is meant for the migration of an existing store to encryption only.
+	EncryptionAllowPlaintext bool `json:"encryptionAllowPlaintext,omitempty"`
 }
 
 // AddFlags adds the flags to flagset.
@@ -224,6 +264,8 @@ func (c *SnapstoreConfig) addFlags(fs *flag.FlagSet, parameterPrefix string) {
 	fs.UintVar(&c.MaxParallelChunkUploads, parameterPrefix+"max-parallel-chunk-uploads", c.MaxParallelChunkUploads, "maximum number of parallel chunk uploads allowed")
 	fs.Int64Var(&c.MinChunkSize, parameterPrefix+"min-chunk-size", c.MinChunkSize, "Minimum size for multipart chunk upload")
 	fs.StringVar(&c.TempDir, parameterPrefix+"snapstore-temp-directory", c.TempDir, "temporary directory for processing")
+	fs.StringVar(&c.EncryptionKeyFile, parameterPrefix+"encryption-key-file", c.EncryptionKeyFile, "path to the file containing the 32 byte key-encryption key (raw or base64 encoded) used for client-side encryption of snapshots")
+	fs.BoolVar(&c.EncryptionAllowPlaintext, parameterPrefix+"encryption-allow-plaintext", c.EncryptionAllowPlaintext, "allow fetching snapshots saved without encryption while client-side encryption is enabled, e.g. while migrating an existing store to encryption")
 }
 
 // Validate validates the config.
@@ -293,3 +335,41 @@ func (c *SecondarySnapstoreConfig) Complete() {
 	c.StoreConfig.EnvPrefix = "SECONDARY_"
 	c.StoreConfig.Complete()
 }
+
+// snapshotLabelKeyRegexp matches the valid keys of snapshot labels.
+var snapshotLabelKeyRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateSnapshotLabels validates the user labels of a snapshot.
+func ValidateSnapshotLabels(labels map[string]string) error {
+	if len(labels) > MaxSnapshotLabels {
+		return fmt.Errorf("a snapshot can have at most %d labels, got %d", MaxSnapshotLabels, len(labels))
+	}
+	for key, value := range labels {
+		if !snapshotLabelKeyRegexp.MatchString(key) {
+			return fmt.Errorf("invalid snapshot label key %q: must consist of at most 63 alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character", key)
+		}
+		if len(value) > MaxSnapshotLabelValueLength {
+			return fmt.Errorf("value of snapshot label %s must not be longer than %d characters", key, MaxSnapshotLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// ParseSnapshotLabels parses snapshot labels from a comma separated list of key=value pairs, e.g. `reason=upgrade,ticket=OPS-1`.
+func ParseSnapshotLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid snapshot label %q, expected key=value", pair)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := ValidateSnapshotLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
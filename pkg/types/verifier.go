@@ -0,0 +1,39 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+)
+
+// VerifierConfig holds all configuration options related to `verify` subcommand.
+type VerifierConfig struct {
+	TempDir            string `json:"tempDir,omitempty"`
+	SkipIntegrityCheck bool   `json:"skipIntegrityCheck,omitempty"`
+}
+
+// NewVerifierConfig returns the VerifierConfig.
+func NewVerifierConfig() *VerifierConfig {
+	return &VerifierConfig{
+		TempDir: os.TempDir(),
+	}
+}
+
+// AddFlags adds the flags to flagset.
+func (c *VerifierConfig) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.TempDir, "verification-temp-dir", c.TempDir, "path to the temporary directory to store full snapshots while checking their integrity")
+	fs.BoolVar(&c.SkipIntegrityCheck, "skip-integrity-check", c.SkipIntegrityCheck, "only check the revisions of the snapshots, without downloading them to check their SHA256 hash")
+}
+
+// Validate validates the config.
+func (c *VerifierConfig) Validate() error {
+	if !c.SkipIntegrityCheck && len(c.TempDir) == 0 {
+		return fmt.Errorf("verification temp dir must be set to check the integrity of snapshots")
+	}
+	return nil
+}